@@ -106,3 +106,28 @@ type BatchCallData struct {
 }
 
 type BatchCallResults []*CallResult
+
+// AccountProof is the merkle proof of an account and its storage slots, which can be verified
+// against the state root of the block.
+// The account fields are the raw ones stored in the state trie, e.g. energy is the amount at blockTime.
+type AccountProof struct {
+	BlockID      thor.Bytes32          `json:"blockID"`
+	BlockNumber  uint32                `json:"blockNumber"`
+	StateRoot    thor.Bytes32          `json:"stateRoot"`
+	Address      thor.Address          `json:"address"`
+	Balance      *math.HexOrDecimal256 `json:"balance"`
+	Energy       *math.HexOrDecimal256 `json:"energy"`
+	BlockTime    uint64                `json:"blockTime"`
+	Master       hexutil.Bytes         `json:"master"`
+	CodeHash     hexutil.Bytes         `json:"codeHash"`
+	StorageRoot  hexutil.Bytes         `json:"storageRoot"`
+	AccountProof []hexutil.Bytes       `json:"accountProof"`
+	StorageProof []*StorageProof       `json:"storageProof"`
+}
+
+// StorageProof is the merkle proof of a storage slot against the storage root of the account.
+type StorageProof struct {
+	Key   thor.Bytes32    `json:"key"`
+	Value hexutil.Bytes   `json:"value"` // rlp raw value, empty if absent
+	Proof []hexutil.Bytes `json:"proof"`
+}
@@ -11,6 +11,7 @@ import (
 	"math/big"
 	"net/http"
 	"net/url"
+	"strings"
 
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/common/math"
@@ -30,6 +31,9 @@ import (
 	"github.com/vechain/thor/v2/xenv"
 )
 
+// the max number of storage keys allowed in a single proof request.
+const maxProofKeys = 100
+
 type Accounts struct {
 	repo              *chain.Repository
 	stater            *state.Stater
@@ -205,6 +209,79 @@ func (a *Accounts) handleGetRawStorage(w http.ResponseWriter, req *http.Request)
 	return restutil.WriteJSON(w, &api.GetStorageResult{Value: hexutil.Encode(storage)})
 }
 
+func (a *Accounts) handleGetProof(w http.ResponseWriter, req *http.Request) error {
+	addr, err := thor.ParseAddress(mux.Vars(req)["address"])
+	if err != nil {
+		return restutil.BadRequest(errors.WithMessage(err, "address"))
+	}
+	var keys []thor.Bytes32
+	if s := req.URL.Query().Get("keys"); s != "" {
+		for _, k := range strings.Split(s, ",") {
+			key, err := thor.ParseBytes32(k)
+			if err != nil {
+				return restutil.BadRequest(errors.WithMessage(err, "keys"))
+			}
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) > maxProofKeys {
+		return restutil.BadRequest(fmt.Errorf("keys: exceeds the limit of %d", maxProofKeys))
+	}
+	revision, err := restutil.ParseRevision(req.URL.Query().Get("revision"), false)
+	if err != nil {
+		return restutil.BadRequest(errors.WithMessage(err, "revision"))
+	}
+	summary, err := restutil.GetSummary(revision, a.repo, a.bft)
+	if err != nil {
+		if a.repo.IsNotFound(err) {
+			return restutil.BadRequest(errors.WithMessage(err, "revision"))
+		}
+		return err
+	}
+	if a.stater.IsPruned(summary.Root()) {
+		return restutil.BadRequest(fmt.Errorf("state pruned, revision #%v is out of the retention window", summary.Header.Number()))
+	}
+
+	p, err := a.stater.Prove(summary.Root(), addr, keys)
+	if err != nil {
+		return err
+	}
+
+	result := &api.AccountProof{
+		BlockID:      summary.Header.ID(),
+		BlockNumber:  summary.Header.Number(),
+		StateRoot:    summary.Header.StateRoot(),
+		Address:      addr,
+		Balance:      (*math.HexOrDecimal256)(new(big.Int)),
+		Energy:       (*math.HexOrDecimal256)(new(big.Int)),
+		AccountProof: make([]hexutil.Bytes, 0, len(p.Proof)),
+		StorageProof: make([]*api.StorageProof, 0, len(keys)),
+	}
+	if acc := p.Account; acc != nil {
+		result.Balance = (*math.HexOrDecimal256)(acc.Balance)
+		result.Energy = (*math.HexOrDecimal256)(acc.Energy)
+		result.BlockTime = acc.BlockTime
+		result.Master = acc.Master
+		result.CodeHash = acc.CodeHash
+		result.StorageRoot = acc.StorageRoot
+	}
+	for _, node := range p.Proof {
+		result.AccountProof = append(result.AccountProof, node)
+	}
+	for i, key := range keys {
+		sp := &api.StorageProof{
+			Key:   key,
+			Value: p.Storage[i],
+			Proof: make([]hexutil.Bytes, 0, len(p.StorageProof[i])),
+		}
+		for _, node := range p.StorageProof[i] {
+			sp.Proof = append(sp.Proof, node)
+		}
+		result.StorageProof = append(result.StorageProof, sp)
+	}
+	return restutil.WriteJSON(w, result)
+}
+
 func (a *Accounts) handleCallContract(w http.ResponseWriter, req *http.Request) error {
 	callData := &api.CallData{}
 	if err := restutil.ParseJSON(req.Body, &callData); err != nil {
@@ -423,6 +500,10 @@ func (a *Accounts) Mount(root *mux.Router, pathPrefix string) {
 		Methods("GET").
 		Name("GET /accounts/{address}/storage/raw").
 		HandlerFunc(restutil.WrapHandlerFunc(a.handleGetRawStorage))
+	sub.Path("/{address}/proof").
+		Methods(http.MethodGet).
+		Name("GET /accounts/{address}/proof").
+		HandlerFunc(restutil.WrapHandlerFunc(a.handleGetProof))
 
 	// These two methods are currently deprecated
 	callContractHandler := restutil.HandleGone
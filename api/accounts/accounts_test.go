@@ -6,11 +6,13 @@
 package accounts
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"math/big"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -24,6 +26,7 @@ import (
 	"github.com/vechain/thor/v2/api"
 	"github.com/vechain/thor/v2/block"
 	"github.com/vechain/thor/v2/builtin"
+	"github.com/vechain/thor/v2/chain"
 	"github.com/vechain/thor/v2/genesis"
 	"github.com/vechain/thor/v2/test/testchain"
 	"github.com/vechain/thor/v2/thor"
@@ -108,6 +111,7 @@ var (
 	)
 	ts      *httptest.Server
 	tclient *thorclient.Client
+	repo    *chain.Repository
 )
 
 func TestAccount(t *testing.T) {
@@ -124,6 +128,7 @@ func TestAccount(t *testing.T) {
 		"getCodeWithNonExistingRevision":      getCodeWithNonExistingRevision,
 		"getStorage":                          getStorage,
 		"getStorageWithNonExistingRevision":   getStorageWithNonExistingRevision,
+		"getProof":                            getProof,
 		"deployContractWithCall":              deployContractWithCall,
 		"callContract":                        callContract,
 		"callContractWithNonExistingRevision": callContractWithNonExistingRevision,
@@ -290,11 +295,78 @@ func getStorageWithNonExistingRevision(t *testing.T) {
 	assert.Equal(t, "revision: leveldb: not found\n", string(res), "revision not found")
 }
 
+func getProof(t *testing.T) {
+	_, statusCode, err := tclient.RawHTTPClient().RawHTTPGet("/accounts/" + invalidAddr + "/proof")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, statusCode, "bad address")
+
+	_, statusCode, err = tclient.RawHTTPClient().RawHTTPGet("/accounts/" + contractAddr.String() + "/proof?keys=" + invalidBytes32)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, statusCode, "bad storage key")
+
+	tooManyKeys := strings.TrimSuffix(strings.Repeat(storageKey.String()+",", maxProofKeys+1), ",")
+	_, statusCode, err = tclient.RawHTTPClient().RawHTTPGet("/accounts/" + contractAddr.String() + "/proof?keys=" + tooManyKeys)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, statusCode, "too many keys")
+
+	_, statusCode, err = tclient.RawHTTPClient().RawHTTPGet("/accounts/" + contractAddr.String() + "/proof?revision=" + invalidNumberRevision)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, statusCode, "bad revision")
+
+	best := repo.BestBlockSummary().Header
+
+	absentKey := thor.BytesToBytes32([]byte("absent"))
+	keys := []thor.Bytes32{storageKey, absentKey}
+	proof, err := tclient.AccountProof(&contractAddr, keys)
+	require.NoError(t, err)
+	assert.Equal(t, best.ID(), proof.BlockID)
+	assert.Equal(t, best.StateRoot(), proof.StateRoot)
+	assert.NotEmpty(t, proof.CodeHash)
+	require.Len(t, proof.StorageProof, 2)
+	assert.Equal(t, hexutil.Bytes{storageValue}, proof.StorageProof[0].Value)
+	assert.Empty(t, proof.StorageProof[1].Value)
+	assert.NoError(t, thorclient.VerifyAccountProof(best.StateRoot(), contractAddr, keys, proof))
+	assert.Error(t, thorclient.VerifyAccountProof(best.StateRoot(), contractAddr, keys[:1], proof), "extra key")
+	assert.Error(t, thorclient.VerifyAccountProof(best.StateRoot(), contractAddr, append(keys, storageKey), proof), "missing key")
+
+	// proof of absent account
+	absent := thor.BytesToAddress([]byte("absent"))
+	proof, err = tclient.AccountProof(&absent, []thor.Bytes32{storageKey})
+	require.NoError(t, err)
+	assert.Equal(t, 0, (*big.Int)(proof.Balance).Sign())
+	assert.NoError(t, thorclient.VerifyAccountProof(best.StateRoot(), absent, []thor.Bytes32{storageKey}, proof))
+
+	// proof at genesis should not match the best state root
+	proof, err = tclient.AccountProof(&addr, nil, thorclient.Revision("0"))
+	require.NoError(t, err)
+	assert.Equal(t, genesisBlock.Header().StateRoot(), proof.StateRoot)
+	assert.NoError(t, thorclient.VerifyAccountProof(genesisBlock.Header().StateRoot(), addr, nil, proof))
+	assert.Error(t, thorclient.VerifyAccountProof(best.StateRoot(), addr, nil, proof))
+
+	// tampered proofs
+	tamper := func(f func(p *api.AccountProof)) error {
+		p, err := tclient.AccountProof(&contractAddr, keys)
+		require.NoError(t, err)
+		f(p)
+		return thorclient.VerifyAccountProof(best.StateRoot(), contractAddr, keys, p)
+	}
+	assert.Error(t, tamper(func(p *api.AccountProof) { p.Balance = (*math.HexOrDecimal256)(big.NewInt(1)) }))
+	assert.Error(t, tamper(func(p *api.AccountProof) { p.CodeHash = nil }))
+	assert.Error(t, tamper(func(p *api.AccountProof) { p.Address = addr }))
+	assert.Error(t, tamper(func(p *api.AccountProof) { p.AccountProof = p.AccountProof[1:] }))
+	assert.Error(t, tamper(func(p *api.AccountProof) { p.StorageProof[0].Value = hexutil.Bytes{2} }))
+	assert.Error(t, tamper(func(p *api.AccountProof) { p.StorageProof[0].Key = absentKey }))
+	// a storage proof left out, or duplicated in place of another
+	assert.Error(t, tamper(func(p *api.AccountProof) { p.StorageProof = p.StorageProof[:1] }))
+	assert.Error(t, tamper(func(p *api.AccountProof) { p.StorageProof[1] = p.StorageProof[0] }))
+}
+
 func initAccountServer(t *testing.T, enabledDeprecated bool) {
 	thorChain, err := testchain.NewDefault()
 	require.NoError(t, err)
 
 	genesisBlock = thorChain.GenesisBlock()
+	repo = thorChain.Repo()
 	claTransfer := tx.NewClause(&addr).WithValue(value)
 	claDeploy := tx.NewClause(nil).WithData(bytecode)
 	transaction := buildTxWithClauses(tx.TypeLegacy, thorChain.Repo().ChainTag(), claTransfer, claDeploy)
@@ -725,3 +797,27 @@ func TestRawStorageStaker(t *testing.T) {
 	assert.Equal(t, uint64(25000000), validation.LockedVET)
 	assert.Equal(t, uint64(25000000), validation.Weight)
 }
+
+func TestGetProofPruned(t *testing.T) {
+	thorChain, err := testchain.NewDefault()
+	require.NoError(t, err)
+	for range 3 {
+		require.NoError(t, thorChain.MintBlock())
+	}
+	// mem db has hist partition factor 1
+	require.NoError(t, thorChain.Database().DeleteTrieHistoryNodes(context.Background(), 0, 2))
+
+	router := mux.NewRouter()
+	New(thorChain.Repo(), thorChain.Stater(), uint64(gasLimit), &thor.NoFork, thorChain.Engine(), false).
+		Mount(router, "/accounts")
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	blocks, err := thorChain.GetAllBlocks()
+	require.NoError(t, err)
+	body, statusCode, err := thorclient.New(server.URL).RawHTTPClient().
+		RawHTTPGet("/accounts/" + addr.String() + "/proof?revision=" + blocks[1].Header().ID().String())
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, statusCode)
+	assert.Contains(t, string(body), "state pruned")
+}
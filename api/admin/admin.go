@@ -14,19 +14,39 @@ import (
 	"github.com/gorilla/mux"
 
 	"github.com/vechain/thor/v2/api/admin/apilogs"
+	"github.com/vechain/thor/v2/api/admin/blocktemplate"
+	"github.com/vechain/thor/v2/api/admin/caches"
 	"github.com/vechain/thor/v2/api/admin/loglevel"
+	"github.com/vechain/thor/v2/api/admin/peers"
 	"github.com/vechain/thor/v2/cmd/thor/node"
 
 	healthAPI "github.com/vechain/thor/v2/api/admin/health"
 )
 
-func NewHTTPHandler(logLevel *slog.LevelVar, health *healthAPI.Health, apiLogsToggle *atomic.Bool, master *node.Master) http.HandlerFunc {
+func NewHTTPHandler(
+	logLevel *slog.LevelVar,
+	health *healthAPI.Health,
+	apiLogsToggle *atomic.Bool,
+	master *node.Master,
+	blockTemplate *blocktemplate.BlockTemplate,
+	peers *peers.Peers,
+	caches *caches.Caches,
+) http.HandlerFunc {
 	router := mux.NewRouter()
 	subRouter := router.PathPrefix("/admin").Subrouter()
 
 	loglevel.New(logLevel).Mount(subRouter, "/loglevel")
 	healthAPI.NewAPI(health, master).Mount(subRouter, "/health")
 	apilogs.New(apiLogsToggle).Mount(subRouter, "/apilogs")
+	if blockTemplate != nil {
+		blockTemplate.Mount(subRouter, "/blocktemplate")
+	}
+	if peers != nil {
+		peers.Mount(subRouter, "/peers")
+	}
+	if caches != nil {
+		caches.Mount(subRouter, "/caches")
+	}
 
 	handler := handlers.CompressHandler(router)
 	return handler.ServeHTTP
@@ -0,0 +1,163 @@
+// Copyright (c) 2025 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package blocktemplate
+
+import (
+	"math/big"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
+
+	"github.com/vechain/thor/v2/api"
+	"github.com/vechain/thor/v2/api/restutil"
+	"github.com/vechain/thor/v2/chain"
+	"github.com/vechain/thor/v2/packer"
+	"github.com/vechain/thor/v2/thor"
+	"github.com/vechain/thor/v2/txpool"
+)
+
+// BlockTemplate previews the block the node would pack next.
+type BlockTemplate struct {
+	repo     *chain.Repository
+	packer   *packer.Packer
+	txPool   txpool.Pool
+	selector packer.TxSelector
+	mu       sync.Mutex // serializes dry runs
+}
+
+// New creates a BlockTemplate API. The selector is optional, pool order is used if nil.
+func New(repo *chain.Repository, packer *packer.Packer, txPool txpool.Pool, selector packer.TxSelector) *BlockTemplate {
+	return &BlockTemplate{
+		repo:     repo,
+		packer:   packer,
+		txPool:   txPool,
+		selector: selector,
+	}
+}
+
+func (b *BlockTemplate) handleGetBlockTemplate(w http.ResponseWriter, r *http.Request) error {
+	var gasLimit uint64
+	if s := r.URL.Query().Get("gasLimit"); s != "" {
+		gl, err := strconv.ParseUint(s, 10, 64)
+		if err != nil {
+			return restutil.BadRequest(errors.WithMessage(err, "gasLimit"))
+		}
+		gasLimit = gl
+	}
+
+	template, err := b.dryRun(gasLimit)
+	if err != nil {
+		return err
+	}
+	return restutil.WriteJSON(w, template)
+}
+
+// dryRun adopts the executable txs of the pool into a mocked flow upon the best block,
+// the same way the packer loop does, and stages the result without signing.
+func (b *BlockTemplate) dryRun(gasLimit uint64) (*api.BlockTemplate, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	best := b.repo.BestBlockSummary()
+	now := uint64(time.Now().Unix())
+	targetTime := max(now, best.Header.Timestamp()+thor.BlockInterval())
+
+	flow, _, err := b.packer.Mock(best, targetTime, gasLimit)
+	if err != nil {
+		return nil, errors.WithMessage(err, "mock packer")
+	}
+
+	txs := b.txPool.Executables()
+	if b.selector != nil {
+		txs = b.selector.Select(flow, txs)
+	}
+
+	var (
+		results = make([]*api.BlockTemplateTx, 0, len(txs))
+		reward  = new(big.Int)
+		full    bool
+	)
+	for _, t := range txs {
+		origin, _ := t.Origin()
+		result := &api.BlockTemplateTx{
+			ID:     t.ID(),
+			Origin: origin,
+			Gas:    t.Gas(),
+		}
+		results = append(results, result)
+
+		if full {
+			result.Result = api.TxGasLimitReached
+			continue
+		}
+
+		if err := flow.Adopt(t); err != nil {
+			result.Result = adoptionResult(err)
+			result.Error = err.Error()
+			if packer.IsGasLimitReached(err) {
+				full = true
+			}
+			continue
+		}
+
+		receipts := flow.Receipts()
+		receipt := receipts[len(receipts)-1]
+		result.Result = api.TxAdopted
+		result.GasUsed = receipt.GasUsed
+		result.Reward = (*math.HexOrDecimal256)(receipt.Reward)
+		result.Reverted = receipt.Reverted
+		reward.Add(reward, receipt.Reward)
+	}
+
+	stage, err := flow.Stage(0)
+	if err != nil {
+		return nil, errors.WithMessage(err, "stage")
+	}
+
+	return &api.BlockTemplate{
+		ParentID:    best.Header.ID(),
+		Number:      flow.Number(),
+		Timestamp:   flow.When(),
+		Beneficiary: flow.Beneficiary(),
+		GasLimit:    flow.GasLimit(),
+		GasUsed:     flow.GasUsed(),
+		BaseFee:     (*math.HexOrDecimal256)(flow.BaseFee()),
+		Reward:      (*math.HexOrDecimal256)(reward),
+		StateRoot:   stage.Hash(),
+		Txs:         results,
+	}, nil
+}
+
+func adoptionResult(err error) string {
+	switch {
+	case packer.IsGasLimitReached(err):
+		return api.TxGasLimitReached
+	case packer.IsTxNotAdoptableNow(err):
+		return api.TxNotAdoptableNow
+	case packer.IsTxNotAdoptableForever(err):
+		return api.TxNotAdoptableForever
+	case packer.IsKnownTx(err):
+		return api.TxKnown
+	case packer.IsBadTx(err):
+		return api.TxBad
+	default:
+		return api.TxFailed
+	}
+}
+
+func (b *BlockTemplate) Mount(root *mux.Router, pathPrefix string) {
+	sub := root.PathPrefix(pathPrefix).Subrouter()
+
+	sub.Path("").
+		Methods(http.MethodGet).
+		Name("get-block-template").
+		HandlerFunc(restutil.WrapHandlerFunc(b.handleGetBlockTemplate))
+}
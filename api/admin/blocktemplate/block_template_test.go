@@ -0,0 +1,136 @@
+// Copyright (c) 2025 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package blocktemplate
+
+import (
+	"encoding/json"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vechain/thor/v2/api"
+	"github.com/vechain/thor/v2/genesis"
+	"github.com/vechain/thor/v2/packer"
+	"github.com/vechain/thor/v2/test/testchain"
+	"github.com/vechain/thor/v2/thor"
+	"github.com/vechain/thor/v2/tx"
+	"github.com/vechain/thor/v2/txpool"
+)
+
+type mockPool struct {
+	txpool.Pool
+	txs tx.Transactions
+}
+
+func (m *mockPool) Executables() tx.Transactions {
+	return m.txs
+}
+
+func newTx(chainTag byte, acc genesis.DevAccount, nonce uint64, dependsOn *thor.Bytes32) *tx.Transaction {
+	to := thor.BytesToAddress([]byte("to"))
+	trx := tx.NewBuilder(tx.TypeDynamicFee).
+		ChainTag(chainTag).
+		MaxFeePerGas(big.NewInt(thor.InitialBaseFee * 10)).
+		MaxPriorityFeePerGas(big.NewInt(100)).
+		Expiration(100).
+		Gas(21000).
+		Nonce(nonce).
+		DependsOn(dependsOn).
+		Clause(tx.NewClause(&to).WithValue(big.NewInt(1))).
+		Build()
+	return tx.MustSign(trx, acc.PrivateKey)
+}
+
+func TestBlockTemplate(t *testing.T) {
+	chain, err := testchain.NewDefault()
+	require.NoError(t, err)
+
+	validator, ok := chain.NextValidator()
+	require.True(t, ok)
+
+	accs := genesis.DevAccounts()
+	tx1 := newTx(chain.ChainTag(), accs[1], 1, nil)
+	tx2 := newTx(chain.ChainTag(), accs[2], 1, nil)
+	missingDep := newTx(chain.ChainTag(), accs[3], 1, &thor.Bytes32{1})
+	badChainTag := newTx(chain.ChainTag()+1, accs[4], 1, nil)
+	pool := &mockPool{txs: tx.Transactions{tx1, missingDep, tx2, badChainTag, tx1}}
+
+	pkr := packer.New(chain.Repo(), chain.Stater(), validator.Address, nil, chain.GetForkConfig(), 0)
+	router := mux.NewRouter()
+	New(chain.Repo(), pkr, pool, nil).Mount(router, "/blocktemplate")
+	ts := httptest.NewServer(router)
+	defer ts.Close()
+
+	best := chain.Repo().BestBlockSummary()
+
+	res, err := http.Get(ts.URL + "/blocktemplate")
+	require.NoError(t, err)
+	defer res.Body.Close()
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	body, err := io.ReadAll(res.Body)
+	require.NoError(t, err)
+
+	var template api.BlockTemplate
+	require.NoError(t, json.Unmarshal(body, &template))
+
+	assert.Equal(t, best.Header.ID(), template.ParentID)
+	assert.Equal(t, best.Header.Number()+1, template.Number)
+	assert.Equal(t, best.Header.GasLimit(), template.GasLimit)
+	assert.Equal(t, uint64(2*21000), template.GasUsed)
+	assert.NotEqual(t, thor.Bytes32{}, template.StateRoot)
+	assert.NotEqual(t, best.Header.StateRoot(), template.StateRoot)
+
+	expected := []struct {
+		id     thor.Bytes32
+		result string
+	}{
+		{tx1.ID(), api.TxAdopted},
+		{missingDep.ID(), api.TxNotAdoptableNow},
+		{tx2.ID(), api.TxAdopted},
+		{badChainTag.ID(), api.TxBad},
+		{tx1.ID(), api.TxKnown},
+	}
+	require.Len(t, template.Txs, len(expected))
+
+	reward := new(big.Int)
+	for i, e := range expected {
+		assert.Equal(t, e.id, template.Txs[i].ID)
+		assert.Equal(t, e.result, template.Txs[i].Result)
+		if e.result == api.TxAdopted {
+			assert.Equal(t, uint64(21000), template.Txs[i].GasUsed)
+			assert.Empty(t, template.Txs[i].Error)
+			reward.Add(reward, (*big.Int)(template.Txs[i].Reward))
+		} else {
+			assert.NotEmpty(t, template.Txs[i].Error)
+		}
+	}
+	assert.Equal(t, reward, (*big.Int)(template.Reward))
+
+	// dry run leaves the chain untouched
+	assert.Equal(t, best.Header.ID(), chain.Repo().BestBlockSummary().Header.ID())
+
+	// custom gas limit
+	res, err = http.Get(ts.URL + "/blocktemplate?gasLimit=21000")
+	require.NoError(t, err)
+	defer res.Body.Close()
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	require.NoError(t, json.NewDecoder(res.Body).Decode(&template))
+	assert.Equal(t, uint64(21000), template.GasLimit)
+	assert.Equal(t, api.TxAdopted, template.Txs[0].Result)
+	assert.Equal(t, api.TxGasLimitReached, template.Txs[1].Result)
+	assert.Equal(t, api.TxGasLimitReached, template.Txs[4].Result)
+
+	res, err = http.Get(ts.URL + "/blocktemplate?gasLimit=abc")
+	require.NoError(t, err)
+	defer res.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+}
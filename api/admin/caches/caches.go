@@ -0,0 +1,141 @@
+// Copyright (c) 2025 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package caches
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
+
+	"github.com/vechain/thor/v2/api"
+	"github.com/vechain/thor/v2/api/restutil"
+	"github.com/vechain/thor/v2/chain"
+	"github.com/vechain/thor/v2/log"
+	"github.com/vechain/thor/v2/muxdb"
+)
+
+// minTrieNodeCacheSizeMB is the min size of the trie node cache, the same as the --cache flag.
+const minTrieNodeCacheSizeMB = 128
+
+var logger = log.WithContext("pkg", "caches")
+
+// TrieDB is the database whose trie node cache is managed.
+type TrieDB interface {
+	TrieCacheStats() muxdb.CacheStats
+	ResizeTrieCache(sizeMB int)
+}
+
+// Repository is the chain repository whose caches are managed.
+type Repository interface {
+	CacheStats() map[string]chain.CacheStats
+	ResizeCache(name string, size int) error
+}
+
+// Caches reports statistics of caches, and resizes them at runtime.
+type Caches struct {
+	db                     TrieDB
+	repo                   Repository
+	maxTrieNodeCacheSizeMB int
+}
+
+// New creates a Caches API. The trie node cache can't be resized beyond maxTrieNodeCacheSizeMB.
+func New(db TrieDB, repo Repository, maxTrieNodeCacheSizeMB int) *Caches {
+	return &Caches{
+		db:                     db,
+		repo:                   repo,
+		maxTrieNodeCacheSizeMB: maxTrieNodeCacheSizeMB,
+	}
+}
+
+func convertHitMiss(hit, miss int64) *api.CacheHitMiss {
+	hm := &api.CacheHitMiss{Hit: hit, Miss: miss}
+	if lookups := hit + miss; lookups > 0 {
+		hm.HitRate = float64(hit) / float64(lookups)
+	}
+	return hm
+}
+
+func (c *Caches) stats() *api.CacheStats {
+	trieStats := c.db.TrieCacheStats()
+	stats := &api.CacheStats{
+		Trie: &api.TrieCacheStats{
+			SizeMB:    trieStats.SizeMB,
+			Nodes:     make(map[string]*api.CacheHitMiss, len(trieStats.Nodes)),
+			Roots:     convertHitMiss(trieStats.Roots.Hit, trieStats.Roots.Miss),
+			RootCount: trieStats.RootCount,
+			Evictions: trieStats.Evictions,
+		},
+		Chain: make(map[string]*api.ChainCacheStats),
+	}
+	for kind, hm := range trieStats.Nodes {
+		stats.Trie.Nodes[kind] = convertHitMiss(hm.Hit, hm.Miss)
+	}
+	for name, s := range c.repo.CacheStats() {
+		stats.Chain[name] = &api.ChainCacheStats{
+			Size:         s.Size,
+			Len:          s.Len,
+			CacheHitMiss: *convertHitMiss(s.Hit, s.Miss),
+		}
+	}
+	return stats
+}
+
+func (c *Caches) handleGetStats(w http.ResponseWriter, _ *http.Request) error {
+	return restutil.WriteJSON(w, c.stats())
+}
+
+func (c *Caches) handleResize(w http.ResponseWriter, r *http.Request) error {
+	var req api.CacheResizeRequest
+	if err := restutil.ParseJSON(r.Body, &req); err != nil {
+		return restutil.BadRequest(errors.WithMessage(err, "body"))
+	}
+
+	// validate all before resizing any
+	if size := req.TrieNodeCacheSizeMB; size != nil {
+		if *size < minTrieNodeCacheSizeMB {
+			return restutil.BadRequest(fmt.Errorf("trieNodeCacheSizeMB: should be no less than %v", minTrieNodeCacheSizeMB))
+		}
+		if *size > c.maxTrieNodeCacheSizeMB {
+			return restutil.BadRequest(fmt.Errorf("trieNodeCacheSizeMB: should be no greater than %v", c.maxTrieNodeCacheSizeMB))
+		}
+	}
+	current := c.repo.CacheStats()
+	for name, size := range req.Chain {
+		if _, ok := current[name]; !ok {
+			return restutil.BadRequest(fmt.Errorf("chain: unknown cache %v", name))
+		}
+		if size <= 0 {
+			return restutil.BadRequest(fmt.Errorf("chain: size of %v should be positive", name))
+		}
+	}
+
+	if req.TrieNodeCacheSizeMB != nil {
+		c.db.ResizeTrieCache(*req.TrieNodeCacheSizeMB)
+		logger.Info("trie node cache resized", "sizeMB", *req.TrieNodeCacheSizeMB)
+	}
+	for name, size := range req.Chain {
+		if err := c.repo.ResizeCache(name, size); err != nil {
+			return err
+		}
+		logger.Info("chain cache resized", "name", name, "size", size)
+	}
+	return restutil.WriteJSON(w, c.stats())
+}
+
+func (c *Caches) Mount(root *mux.Router, pathPrefix string) {
+	sub := root.PathPrefix(pathPrefix).Subrouter()
+
+	sub.Path("").
+		Methods(http.MethodGet).
+		Name("get-cache-stats").
+		HandlerFunc(restutil.WrapHandlerFunc(c.handleGetStats))
+	sub.Path("").
+		Methods(http.MethodPost).
+		Name("resize-caches").
+		HandlerFunc(restutil.WrapHandlerFunc(c.handleResize))
+}
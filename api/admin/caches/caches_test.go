@@ -0,0 +1,122 @@
+// Copyright (c) 2025 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package caches
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vechain/thor/v2/api"
+	"github.com/vechain/thor/v2/chain"
+	"github.com/vechain/thor/v2/muxdb"
+	"github.com/vechain/thor/v2/test/testchain"
+)
+
+type mockTrieDB struct {
+	stats muxdb.CacheStats
+}
+
+func (m *mockTrieDB) TrieCacheStats() muxdb.CacheStats { return m.stats }
+func (m *mockTrieDB) ResizeTrieCache(sizeMB int)       { m.stats.SizeMB = sizeMB }
+
+func request(t *testing.T, ts *httptest.Server, method string, body any) (int, []byte) {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		require.NoError(t, err)
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequest(method, ts.URL+"/caches", reader)
+	require.NoError(t, err)
+	res, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer res.Body.Close()
+	data, err := io.ReadAll(res.Body)
+	require.NoError(t, err)
+	return res.StatusCode, data
+}
+
+func TestCaches(t *testing.T) {
+	thorChain, err := testchain.NewDefault()
+	require.NoError(t, err)
+	repo := thorChain.Repo()
+
+	db := &mockTrieDB{stats: muxdb.CacheStats{
+		SizeMB: 256,
+		Nodes: map[string]muxdb.CacheHitMiss{
+			muxdb.TrieKindAccount: {Hit: 3, Miss: 1},
+			muxdb.TrieKindStorage: {},
+		},
+		Roots:     muxdb.CacheHitMiss{Hit: 1, Miss: 1},
+		RootCount: 2,
+		Evictions: 5,
+	}}
+
+	router := mux.NewRouter()
+	New(db, repo, 1024).Mount(router, "/caches")
+	ts := httptest.NewServer(router)
+	defer ts.Close()
+
+	// stats
+	status, data := request(t, ts, http.MethodGet, nil)
+	require.Equal(t, http.StatusOK, status)
+	var stats api.CacheStats
+	require.NoError(t, json.Unmarshal(data, &stats))
+	assert.Equal(t, 256, stats.Trie.SizeMB)
+	assert.Equal(t, &api.CacheHitMiss{Hit: 3, Miss: 1, HitRate: 0.75}, stats.Trie.Nodes[muxdb.TrieKindAccount])
+	assert.Equal(t, &api.CacheHitMiss{}, stats.Trie.Nodes[muxdb.TrieKindStorage])
+	assert.Equal(t, 0.5, stats.Trie.Roots.HitRate)
+	assert.Equal(t, 2, stats.Trie.RootCount)
+	assert.Equal(t, int64(5), stats.Trie.Evictions)
+	assert.Len(t, stats.Chain, 3)
+	assert.Positive(t, stats.Chain[chain.CacheTxs].Size)
+
+	// invalid requests change nothing
+	small, large := 64, 2048
+	for _, req := range []*api.CacheResizeRequest{
+		{TrieNodeCacheSizeMB: &small},
+		{TrieNodeCacheSizeMB: &large},
+		{Chain: map[string]int{"unknown": 10}},
+		{Chain: map[string]int{chain.CacheTxs: 10, chain.CacheReceipts: 0}},
+	} {
+		status, _ = request(t, ts, http.MethodPost, req)
+		assert.Equal(t, http.StatusBadRequest, status)
+	}
+	status, _ = request(t, ts, http.MethodPost, "bad")
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.Equal(t, 256, db.stats.SizeMB)
+	assert.Equal(t, stats.Chain[chain.CacheTxs].Size, repo.CacheStats()[chain.CacheTxs].Size)
+
+	// resized
+	size := 512
+	status, data = request(t, ts, http.MethodPost, &api.CacheResizeRequest{
+		TrieNodeCacheSizeMB: &size,
+		Chain:               map[string]int{chain.CacheTxs: 10},
+	})
+	require.Equal(t, http.StatusOK, status)
+	require.NoError(t, json.Unmarshal(data, &stats))
+	assert.Equal(t, 512, stats.Trie.SizeMB)
+	assert.Equal(t, 10, stats.Chain[chain.CacheTxs].Size)
+	assert.Equal(t, 512, db.stats.SizeMB)
+	assert.Equal(t, 10, repo.CacheStats()[chain.CacheTxs].Size)
+
+	// only the chain cache
+	status, data = request(t, ts, http.MethodPost, &api.CacheResizeRequest{
+		Chain: map[string]int{chain.CacheSummaries: 20},
+	})
+	require.Equal(t, http.StatusOK, status)
+	require.NoError(t, json.Unmarshal(data, &stats))
+	assert.Equal(t, 512, stats.Trie.SizeMB)
+	assert.Equal(t, 20, stats.Chain[chain.CacheSummaries].Size)
+}
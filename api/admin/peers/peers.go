@@ -0,0 +1,215 @@
+// Copyright (c) 2025 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package peers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
+
+	"github.com/vechain/thor/v2/api"
+	"github.com/vechain/thor/v2/api/restutil"
+	"github.com/vechain/thor/v2/log"
+	"github.com/vechain/thor/v2/p2p"
+	"github.com/vechain/thor/v2/p2p/discover"
+	"github.com/vechain/thor/v2/p2psrv"
+)
+
+const maxBanDuration = 10 * 365 * 24 * 3600 // 10 years in seconds
+
+var logger = log.WithContext("pkg", "peers")
+
+// Server is the p2p server whose peers are managed.
+type Server interface {
+	PeersInfo() []*p2p.PeerInfo
+	AddStatic(node *discover.Node)
+	RemoveStatic(node *discover.Node)
+	AddTrusted(node *discover.Node)
+	RemoveTrusted(node *discover.Node)
+	Ban(target string, duration time.Duration) error
+	Unban(target string) (bool, error)
+	Bans() []*p2psrv.Ban
+}
+
+// Peers manages peers of the p2p server at runtime.
+type Peers struct {
+	server  Server
+	network api.Network
+}
+
+// New creates a Peers API.
+func New(server Server, network api.Network) *Peers {
+	return &Peers{
+		server:  server,
+		network: network,
+	}
+}
+
+func (p *Peers) handleGetPeers(w http.ResponseWriter, _ *http.Request) error {
+	stats := make(map[string]*api.PeerStats)
+	for _, s := range api.ConvertPeersStats(p.network.PeersStats()) {
+		stats[s.PeerID] = s
+	}
+
+	infos := p.server.PeersInfo()
+	peers := make([]*api.AdminPeer, 0, len(infos))
+	for _, info := range infos {
+		peer := &api.AdminPeer{
+			PeerID:    info.ID,
+			Name:      info.Name,
+			Caps:      info.Caps,
+			NetAddr:   info.Network.RemoteAddress,
+			LocalAddr: info.Network.LocalAddress,
+			Inbound:   info.Network.Inbound,
+			Trusted:   info.Network.Trusted,
+			Static:    info.Network.Static,
+		}
+		if s, ok := stats[info.ID]; ok {
+			peer.BestBlockID = &s.BestBlockID
+			peer.TotalScore = s.TotalScore
+			peer.Duration = s.Duration
+		}
+		peers = append(peers, peer)
+	}
+	return restutil.WriteJSON(w, peers)
+}
+
+func parseNode(r *http.Request) (*discover.Node, error) {
+	var req api.PeerRequest
+	if err := restutil.ParseJSON(r.Body, &req); err != nil {
+		return nil, restutil.BadRequest(errors.WithMessage(err, "body"))
+	}
+	node, err := discover.ParseNode(req.Enode)
+	if err != nil {
+		return nil, restutil.BadRequest(errors.WithMessage(err, "enode"))
+	}
+	return node, nil
+}
+
+func (p *Peers) handleAddStatic(w http.ResponseWriter, r *http.Request) error {
+	node, err := parseNode(r)
+	if err != nil {
+		return err
+	}
+	p.server.AddStatic(node)
+	logger.Info("static peer added", "node", node)
+	return restutil.WriteJSON(w, nil)
+}
+
+func (p *Peers) handleRemoveStatic(w http.ResponseWriter, r *http.Request) error {
+	node, err := parseNode(r)
+	if err != nil {
+		return err
+	}
+	p.server.RemoveStatic(node)
+	logger.Info("static peer removed", "node", node)
+	return restutil.WriteJSON(w, nil)
+}
+
+func (p *Peers) handleAddTrusted(w http.ResponseWriter, r *http.Request) error {
+	node, err := parseNode(r)
+	if err != nil {
+		return err
+	}
+	p.server.AddTrusted(node)
+	logger.Info("trusted peer added", "node", node)
+	return restutil.WriteJSON(w, nil)
+}
+
+func (p *Peers) handleRemoveTrusted(w http.ResponseWriter, r *http.Request) error {
+	node, err := parseNode(r)
+	if err != nil {
+		return err
+	}
+	p.server.RemoveTrusted(node)
+	logger.Info("trusted peer removed", "node", node)
+	return restutil.WriteJSON(w, nil)
+}
+
+func (p *Peers) handleGetBans(w http.ResponseWriter, _ *http.Request) error {
+	bans := p.server.Bans()
+	result := make([]*api.PeerBan, 0, len(bans))
+	for _, ban := range bans {
+		result = append(result, &api.PeerBan{
+			Target: ban.Target,
+			Expiry: time.Unix(int64(ban.Expiry), 0).UTC(),
+		})
+	}
+	return restutil.WriteJSON(w, result)
+}
+
+func (p *Peers) handleBan(w http.ResponseWriter, r *http.Request) error {
+	var req api.BanRequest
+	if err := restutil.ParseJSON(r.Body, &req); err != nil {
+		return restutil.BadRequest(errors.WithMessage(err, "body"))
+	}
+	if req.Duration == 0 {
+		return restutil.BadRequest(errors.New("duration: should be positive"))
+	}
+	if req.Duration > maxBanDuration {
+		return restutil.BadRequest(errors.New("duration: too long"))
+	}
+	if err := p.server.Ban(req.Target, time.Duration(req.Duration)*time.Second); err != nil {
+		return restutil.BadRequest(errors.WithMessage(err, "target"))
+	}
+	logger.Info("peer banned", "target", req.Target, "duration", req.Duration)
+	return restutil.WriteJSON(w, nil)
+}
+
+func (p *Peers) handleUnban(w http.ResponseWriter, r *http.Request) error {
+	var req api.UnbanRequest
+	if err := restutil.ParseJSON(r.Body, &req); err != nil {
+		return restutil.BadRequest(errors.WithMessage(err, "body"))
+	}
+	ok, err := p.server.Unban(req.Target)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return restutil.HTTPError(errors.New("target not banned"), http.StatusNotFound)
+	}
+	logger.Info("peer unbanned", "target", req.Target)
+	return restutil.WriteJSON(w, nil)
+}
+
+func (p *Peers) Mount(root *mux.Router, pathPrefix string) {
+	sub := root.PathPrefix(pathPrefix).Subrouter()
+
+	sub.Path("").
+		Methods(http.MethodGet).
+		Name("get-peers").
+		HandlerFunc(restutil.WrapHandlerFunc(p.handleGetPeers))
+	sub.Path("/static").
+		Methods(http.MethodPost).
+		Name("add-static-peer").
+		HandlerFunc(restutil.WrapHandlerFunc(p.handleAddStatic))
+	sub.Path("/static").
+		Methods(http.MethodDelete).
+		Name("remove-static-peer").
+		HandlerFunc(restutil.WrapHandlerFunc(p.handleRemoveStatic))
+	sub.Path("/trusted").
+		Methods(http.MethodPost).
+		Name("add-trusted-peer").
+		HandlerFunc(restutil.WrapHandlerFunc(p.handleAddTrusted))
+	sub.Path("/trusted").
+		Methods(http.MethodDelete).
+		Name("remove-trusted-peer").
+		HandlerFunc(restutil.WrapHandlerFunc(p.handleRemoveTrusted))
+	sub.Path("/bans").
+		Methods(http.MethodGet).
+		Name("get-peer-bans").
+		HandlerFunc(restutil.WrapHandlerFunc(p.handleGetBans))
+	sub.Path("/bans").
+		Methods(http.MethodPost).
+		Name("ban-peer").
+		HandlerFunc(restutil.WrapHandlerFunc(p.handleBan))
+	sub.Path("/bans").
+		Methods(http.MethodDelete).
+		Name("unban-peer").
+		HandlerFunc(restutil.WrapHandlerFunc(p.handleUnban))
+}
@@ -0,0 +1,142 @@
+// Copyright (c) 2025 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package peers
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vechain/thor/v2/api"
+	"github.com/vechain/thor/v2/comm"
+	"github.com/vechain/thor/v2/p2p"
+	"github.com/vechain/thor/v2/p2p/discover"
+	"github.com/vechain/thor/v2/p2psrv"
+	"github.com/vechain/thor/v2/thor"
+)
+
+const testEnode = "enode://1234cf28ab5f0255a3923ac094d0168ce884a9fa5f3998b1844986b4a2b1eac52fcccd8f2916be9b8b0f7798147ee5592ec3c83518925fac50f812577515d6ad@10.3.58.6:30303"
+
+type mockServer struct {
+	infos   []*p2p.PeerInfo
+	static  map[discover.NodeID]bool
+	trusted map[discover.NodeID]bool
+	srv     *p2psrv.Server
+}
+
+func (m *mockServer) PeersInfo() []*p2p.PeerInfo               { return m.infos }
+func (m *mockServer) AddStatic(node *discover.Node)            { m.static[node.ID] = true }
+func (m *mockServer) RemoveStatic(node *discover.Node)         { delete(m.static, node.ID) }
+func (m *mockServer) AddTrusted(node *discover.Node)           { m.trusted[node.ID] = true }
+func (m *mockServer) RemoveTrusted(node *discover.Node)        { delete(m.trusted, node.ID) }
+func (m *mockServer) Ban(target string, d time.Duration) error { return m.srv.Ban(target, d) }
+func (m *mockServer) Unban(target string) (bool, error)        { return m.srv.Unban(target) }
+func (m *mockServer) Bans() []*p2psrv.Ban                      { return m.srv.Bans() }
+
+type mockNetwork struct {
+	stats []*comm.PeerStats
+}
+
+func (m *mockNetwork) PeersStats() []*comm.PeerStats { return m.stats }
+
+func request(t *testing.T, ts *httptest.Server, method, path string, body any) (int, []byte) {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		require.NoError(t, err)
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequest(method, ts.URL+"/peers"+path, reader)
+	require.NoError(t, err)
+	res, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer res.Body.Close()
+	data, err := io.ReadAll(res.Body)
+	require.NoError(t, err)
+	return res.StatusCode, data
+}
+
+func TestPeers(t *testing.T) {
+	info := &p2p.PeerInfo{ID: "peer1", Name: "thor/v2", Caps: []string{"thor/3"}}
+	info.Network.RemoteAddress = "1.2.3.4:11235"
+	info.Network.Trusted = true
+	server := &mockServer{
+		infos: []*p2p.PeerInfo{
+			info,
+			{ID: "peer2"}, // handshaking
+		},
+		static:  make(map[discover.NodeID]bool),
+		trusted: make(map[discover.NodeID]bool),
+		srv:     p2psrv.New(&p2psrv.Options{}),
+	}
+	network := &mockNetwork{stats: []*comm.PeerStats{
+		{PeerID: "peer1", BestBlockID: thor.Bytes32{1}, TotalScore: 10, Duration: 5},
+	}}
+
+	router := mux.NewRouter()
+	New(server, network).Mount(router, "/peers")
+	ts := httptest.NewServer(router)
+	defer ts.Close()
+
+	// list
+	status, data := request(t, ts, http.MethodGet, "", nil)
+	require.Equal(t, http.StatusOK, status)
+	var peers []*api.AdminPeer
+	require.NoError(t, json.Unmarshal(data, &peers))
+	require.Len(t, peers, 2)
+	assert.Equal(t, "1.2.3.4:11235", peers[0].NetAddr)
+	assert.True(t, peers[0].Trusted)
+	assert.Equal(t, &thor.Bytes32{1}, peers[0].BestBlockID)
+	assert.Equal(t, uint64(10), peers[0].TotalScore)
+	assert.Nil(t, peers[1].BestBlockID)
+
+	// static and trusted
+	node := discover.MustParseNode(testEnode)
+	for _, path := range []string{"/static", "/trusted"} {
+		status, _ = request(t, ts, http.MethodPost, path, api.PeerRequest{Enode: "bad"})
+		assert.Equal(t, http.StatusBadRequest, status)
+
+		status, _ = request(t, ts, http.MethodPost, path, api.PeerRequest{Enode: testEnode})
+		assert.Equal(t, http.StatusOK, status)
+	}
+	assert.True(t, server.static[node.ID])
+	assert.True(t, server.trusted[node.ID])
+	for _, path := range []string{"/static", "/trusted"} {
+		status, _ = request(t, ts, http.MethodDelete, path, api.PeerRequest{Enode: testEnode})
+		assert.Equal(t, http.StatusOK, status)
+	}
+	assert.Empty(t, server.static)
+	assert.Empty(t, server.trusted)
+
+	// bans
+	status, _ = request(t, ts, http.MethodPost, "/bans", api.BanRequest{Target: "10.0.0.0/8"})
+	assert.Equal(t, http.StatusBadRequest, status, "zero duration")
+	status, _ = request(t, ts, http.MethodPost, "/bans", api.BanRequest{Target: "bad", Duration: 60})
+	assert.Equal(t, http.StatusBadRequest, status)
+	status, _ = request(t, ts, http.MethodPost, "/bans", api.BanRequest{Target: "10.0.0.0/8", Duration: 60})
+	assert.Equal(t, http.StatusOK, status)
+
+	status, data = request(t, ts, http.MethodGet, "/bans", nil)
+	require.Equal(t, http.StatusOK, status)
+	var bans []*api.PeerBan
+	require.NoError(t, json.Unmarshal(data, &bans))
+	require.Len(t, bans, 1)
+	assert.Equal(t, "10.0.0.0/8", bans[0].Target)
+	assert.WithinDuration(t, time.Now().Add(time.Minute), bans[0].Expiry, 2*time.Second)
+
+	status, _ = request(t, ts, http.MethodDelete, "/bans", api.UnbanRequest{Target: "10.0.0.0/8"})
+	assert.Equal(t, http.StatusOK, status)
+	status, _ = request(t, ts, http.MethodDelete, "/bans", api.UnbanRequest{Target: "10.0.0.0/8"})
+	assert.Equal(t, http.StatusNotFound, status)
+}
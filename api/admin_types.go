@@ -7,6 +7,10 @@ package api
 
 import (
 	"time"
+
+	"github.com/ethereum/go-ethereum/common/math"
+
+	"github.com/vechain/thor/v2/thor"
 )
 
 type LogStatus struct {
@@ -29,3 +33,112 @@ type LogLevelRequest struct {
 type LogLevelResponse struct {
 	CurrentLevel string `json:"currentLevel"`
 }
+
+// Adoption results of a tx in a block template.
+const (
+	TxAdopted             = "adopted"
+	TxNotAdoptableNow     = "notAdoptableNow"
+	TxNotAdoptableForever = "notAdoptableForever"
+	TxGasLimitReached     = "gasLimitReached"
+	TxKnown               = "known"
+	TxBad                 = "bad"
+	TxFailed              = "failed"
+)
+
+// BlockTemplate is the result of packing the pending txs on top of the best block without signing.
+type BlockTemplate struct {
+	ParentID    thor.Bytes32          `json:"parentID"`
+	Number      uint32                `json:"number"`
+	Timestamp   uint64                `json:"timestamp"`
+	Beneficiary thor.Address          `json:"beneficiary"`
+	GasLimit    uint64                `json:"gasLimit"`
+	GasUsed     uint64                `json:"gasUsed"`
+	BaseFee     *math.HexOrDecimal256 `json:"baseFee,omitempty"`
+	Reward      *math.HexOrDecimal256 `json:"reward"`
+	StateRoot   thor.Bytes32          `json:"stateRoot"`
+	Txs         []*BlockTemplateTx    `json:"txs"`
+}
+
+// BlockTemplateTx is a candidate tx of a block template along with its adoption result.
+type BlockTemplateTx struct {
+	ID       thor.Bytes32          `json:"id"`
+	Origin   thor.Address          `json:"origin"`
+	Gas      uint64                `json:"gas"`
+	Result   string                `json:"result"`
+	Error    string                `json:"error,omitempty"`
+	GasUsed  uint64                `json:"gasUsed,omitempty"`
+	Reward   *math.HexOrDecimal256 `json:"reward,omitempty"`
+	Reverted bool                  `json:"reverted,omitempty"`
+}
+
+// AdminPeer is a connected peer with its detailed stats.
+type AdminPeer struct {
+	PeerID      string        `json:"peerID"`
+	Name        string        `json:"name"`
+	Caps        []string      `json:"caps"`
+	NetAddr     string        `json:"netAddr"`
+	LocalAddr   string        `json:"localAddr"`
+	Inbound     bool          `json:"inbound"`
+	Trusted     bool          `json:"trusted"`
+	Static      bool          `json:"static"`
+	BestBlockID *thor.Bytes32 `json:"bestBlockID"` // nil if the handshake is not done
+	TotalScore  uint64        `json:"totalScore"`
+	Duration    uint64        `json:"duration"`
+}
+
+// PeerRequest is the request to add or remove a static or trusted peer.
+type PeerRequest struct {
+	Enode string `json:"enode"`
+}
+
+// BanRequest is the request to ban a node ID, an IP address or an IP range in CIDR notation.
+type BanRequest struct {
+	Target   string `json:"target"`
+	Duration uint64 `json:"duration"` // in seconds
+}
+
+// UnbanRequest is the request to lift a ban.
+type UnbanRequest struct {
+	Target string `json:"target"`
+}
+
+// PeerBan is a ban in effect.
+type PeerBan struct {
+	Target string    `json:"target"`
+	Expiry time.Time `json:"expiry"`
+}
+
+// CacheHitMiss is the number of hits and misses of cache lookups.
+type CacheHitMiss struct {
+	Hit     int64   `json:"hit"`
+	Miss    int64   `json:"miss"`
+	HitRate float64 `json:"hitRate"`
+}
+
+// TrieCacheStats is the statistics of the trie node cache.
+type TrieCacheStats struct {
+	SizeMB    int                      `json:"sizeMB"`
+	Nodes     map[string]*CacheHitMiss `json:"nodes"` // lookups of node blobs by trie kind
+	Roots     *CacheHitMiss            `json:"roots"` // lookups of root nodes
+	RootCount int                      `json:"rootCount"`
+	Evictions int64                    `json:"evictions"`
+}
+
+// ChainCacheStats is the statistics of a cache of the chain repository.
+type ChainCacheStats struct {
+	Size int `json:"size"`
+	Len  int `json:"len"`
+	CacheHitMiss
+}
+
+// CacheStats is the statistics of the trie node cache and the chain caches.
+type CacheStats struct {
+	Trie  *TrieCacheStats             `json:"trie"`
+	Chain map[string]*ChainCacheStats `json:"chain"`
+}
+
+// CacheResizeRequest is the request to resize caches, and absent ones are unchanged.
+type CacheResizeRequest struct {
+	TrieNodeCacheSizeMB *int           `json:"trieNodeCacheSizeMB"`
+	Chain               map[string]int `json:"chain"` // sizes of chain caches by name
+}
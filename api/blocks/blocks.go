@@ -87,10 +87,16 @@ func (b *Blocks) handleGetBlock(w http.ResponseWriter, req *http.Request) error
 	if expanded {
 		txs, err := b.repo.GetBlockTransactions(summary.Header.ID())
 		if err != nil {
+			if b.repo.IsExpired(err) {
+				return restutil.Gone(fmt.Errorf("txs of block #%v expired", summary.Header.Number()))
+			}
 			return err
 		}
 		receipts, err := b.repo.GetBlockReceipts(summary.Header.ID())
 		if err != nil {
+			if b.repo.IsExpired(err) {
+				return restutil.Gone(fmt.Errorf("receipts of block #%v expired", summary.Header.Number()))
+			}
 			return err
 		}
 
@@ -318,3 +318,44 @@ func checkExpandedBlock(t *testing.T, expBl *block.Block, actBl *api.JSONExpande
 	}
 	assert.Equal(t, (*hexMath.HexOrDecimal256)(header.BaseFee()), actBl.BaseFeePerGas, "BaseFee should be equal")
 }
+
+func TestExpiredBlock(t *testing.T) {
+	thorChain, err := testchain.NewDefault()
+	require.NoError(t, err)
+
+	to := thor.BytesToAddress([]byte("to"))
+	trx := tx.NewBuilder(tx.TypeLegacy).
+		ChainTag(thorChain.Repo().ChainTag()).
+		Expiration(10).
+		Gas(21000).
+		Clause(tx.NewClause(&to).WithValue(big.NewInt(1))).
+		Build()
+	trx = tx.MustSign(trx, genesis.DevAccounts()[0].PrivateKey)
+	require.NoError(t, thorChain.MintBlock(trx))
+	require.NoError(t, thorChain.MintBlock())
+	require.NoError(t, thorChain.Repo().ExpireHistory(t.Context(), 2))
+
+	router := mux.NewRouter()
+	New(thorChain.Repo(), thorChain.Engine()).Mount(router, "/blocks")
+	srv := httptest.NewServer(router)
+	defer srv.Close()
+
+	// collapsed block still available
+	res, err := http.Get(srv.URL + "/blocks/1")
+	require.NoError(t, err)
+	defer res.Body.Close()
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	var collapsed api.JSONCollapsedBlock
+	require.NoError(t, json.NewDecoder(res.Body).Decode(&collapsed))
+	assert.Equal(t, []thor.Bytes32{trx.ID()}, collapsed.Transactions)
+
+	res, err = http.Get(srv.URL + "/blocks/1?expanded=true")
+	require.NoError(t, err)
+	defer res.Body.Close()
+	assert.Equal(t, http.StatusGone, res.StatusCode)
+
+	res, err = http.Get(srv.URL + "/blocks/2?expanded=true")
+	require.NoError(t, err)
+	defer res.Body.Close()
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+}
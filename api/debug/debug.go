@@ -84,6 +84,16 @@ func (d *Debug) prepareClauseEnv(
 	txID thor.Bytes32,
 	clauseIndex uint32,
 ) (*runtime.Runtime, *runtime.TransactionExecutor, thor.Bytes32, error) {
+	// the block is replayed on the state of its parent
+	parent, err := d.repo.GetBlockSummary(block.Header().ParentID())
+	if err != nil {
+		return nil, nil, thor.Bytes32{}, err
+	}
+	if d.stater.IsPruned(parent.Root()) {
+		return nil, nil, thor.Bytes32{}, restutil.BadRequest(
+			fmt.Errorf("state pruned, block #%v is out of the retention window", block.Header().Number()))
+	}
+
 	rt, err := consensus.New(
 		d.repo,
 		d.stater,
@@ -410,6 +420,9 @@ func (d *Debug) parseTarget(target string) (block *block.Block, txID thor.Bytes3
 		}
 		block, err = bestChain.GetBlock(txMeta.BlockNum)
 		if err != nil {
+			if d.repo.IsExpired(err) {
+				return nil, thor.Bytes32{}, 0, restutil.Gone(fmt.Errorf("txs of block #%v expired", txMeta.BlockNum))
+			}
 			return nil, thor.Bytes32{}, 0, err
 		}
 	} else {
@@ -419,6 +432,9 @@ func (d *Debug) parseTarget(target string) (block *block.Block, txID thor.Bytes3
 		}
 		block, err = d.repo.GetBlock(blockID)
 		if err != nil {
+			if d.repo.IsExpired(err) {
+				return nil, thor.Bytes32{}, 0, restutil.Gone(fmt.Errorf("txs of block %v expired", blockID))
+			}
 			return nil, thor.Bytes32{}, 0, err
 		}
 		if len(parts[1]) == 64 || len(parts[1]) == 66 {
@@ -6,6 +6,7 @@
 package debug
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"math/big"
@@ -637,3 +638,79 @@ func TestCreateTracer(t *testing.T) {
 	_, err = debug.createTracer("{result:()=>{}, fault:()=>{}}", nil)
 	assert.Nil(t, err)
 }
+
+func TestTraceClausePruned(t *testing.T) {
+	thorChain, err := testchain.NewDefault()
+	require.NoError(t, err)
+
+	to := thor.BytesToAddress([]byte("to"))
+	trx := tx.NewBuilder(tx.TypeLegacy).
+		ChainTag(thorChain.Repo().ChainTag()).
+		Expiration(10).
+		Gas(21000).
+		Clause(tx.NewClause(&to).WithValue(big.NewInt(10000))).
+		Build()
+	trx = tx.MustSign(trx, genesis.DevAccounts()[0].PrivateKey)
+	require.NoError(t, thorChain.MintBlock(trx))
+	for range 2 {
+		require.NoError(t, thorChain.MintBlock())
+	}
+	// mem db has hist partition factor 1
+	require.NoError(t, thorChain.Database().DeleteTrieHistoryNodes(context.Background(), 0, 2))
+
+	router := mux.NewRouter()
+	New(thorChain.Repo(), thorChain.Stater(), thorChain.GetForkConfig(), thorChain.Engine(), 21000, true, []string{"all"}, false).
+		Mount(router, "/debug")
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	blocks, err := thorChain.GetAllBlocks()
+	require.NoError(t, err)
+	body, status, err := thorclient.New(server.URL).RawHTTPClient().RawHTTPPost("/debug/tracers", &api.TraceClauseOption{
+		Name:   "structLogger",
+		Target: fmt.Sprintf("%s/%s/0", blocks[1].Header().ID(), trx.ID()),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 400, status)
+	assert.Contains(t, string(body), "state pruned")
+}
+
+func TestTraceClauseExpired(t *testing.T) {
+	thorChain, err := testchain.NewDefault()
+	require.NoError(t, err)
+
+	to := thor.BytesToAddress([]byte("to"))
+	trx := tx.NewBuilder(tx.TypeLegacy).
+		ChainTag(thorChain.Repo().ChainTag()).
+		Expiration(10).
+		Gas(21000).
+		Clause(tx.NewClause(&to).WithValue(big.NewInt(10000))).
+		Build()
+	trx = tx.MustSign(trx, genesis.DevAccounts()[0].PrivateKey)
+	require.NoError(t, thorChain.MintBlock(trx))
+	require.NoError(t, thorChain.MintBlock())
+	blockID := thorChain.Repo().BestBlockSummary().Header.ParentID()
+	require.NoError(t, thorChain.Repo().ExpireHistory(t.Context(), 2))
+
+	router := mux.NewRouter()
+	New(thorChain.Repo(), thorChain.Stater(), thorChain.GetForkConfig(), thorChain.Engine(), 21000, true, []string{"all"}, false).
+		Mount(router, "/debug")
+	server := httptest.NewServer(router)
+	defer server.Close()
+	client := thorclient.New(server.URL).RawHTTPClient()
+
+	for _, target := range []string{
+		fmt.Sprintf("%s/%s/0", blockID, trx.ID()),
+		fmt.Sprintf("%s/0/0", blockID),
+		fmt.Sprintf("%s/0", trx.ID()),
+	} {
+		body, status, err := client.RawHTTPPost("/debug/tracers", &api.TraceClauseOption{Name: "structLogger", Target: target})
+		require.NoError(t, err)
+		assert.Equal(t, 410, status, target)
+		assert.Contains(t, string(body), "expired", target)
+
+		_, status, err = client.RawHTTPPost("/debug/storage-range", &api.StorageRangeOption{Address: to, Target: target})
+		require.NoError(t, err)
+		assert.Equal(t, 410, status, target)
+	}
+}
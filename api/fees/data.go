@@ -6,11 +6,13 @@
 package fees
 
 import (
+	"fmt"
 	"math/big"
 	"slices"
 
 	"github.com/ethereum/go-ethereum/common/hexutil"
 
+	"github.com/vechain/thor/v2/api/restutil"
 	"github.com/vechain/thor/v2/block"
 	"github.com/vechain/thor/v2/cache"
 	"github.com/vechain/thor/v2/chain"
@@ -141,6 +143,9 @@ func (fd *FeesData) getOrLoadFees(blockID thor.Bytes32) (*FeeCacheEntry, error)
 func (fd *FeesData) getRewardsForCache(header *block.Header) (*rewards, error) {
 	receipts, err := fd.repo.GetBlockReceipts(header.ID())
 	if err != nil {
+		if fd.repo.IsExpired(err) {
+			return nil, restutil.Gone(fmt.Errorf("receipts of block #%v expired", header.Number()))
+		}
 		return nil, err
 	}
 
@@ -0,0 +1,166 @@
+// Copyright (c) 2025 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+// Package light serves the reduced api of a light node, which keeps block headers only.
+package light
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
+
+	"github.com/vechain/thor/v2/api"
+	"github.com/vechain/thor/v2/api/restutil"
+	"github.com/vechain/thor/v2/block"
+	"github.com/vechain/thor/v2/chain"
+	"github.com/vechain/thor/v2/thor"
+	"github.com/vechain/thor/v2/thorclient"
+	"github.com/vechain/thor/v2/tx"
+)
+
+type Light struct {
+	repo     *chain.Repository
+	finality restutil.Finality
+	nw       api.Network
+}
+
+func New(repo *chain.Repository, finality restutil.Finality, nw api.Network) *Light {
+	return &Light{
+		repo,
+		finality,
+		nw,
+	}
+}
+
+func (l *Light) handleGetBlock(w http.ResponseWriter, req *http.Request) error {
+	revision, err := restutil.ParseRevision(mux.Vars(req)["revision"], false)
+	if err != nil {
+		return restutil.BadRequest(errors.WithMessage(err, "revision"))
+	}
+	summary, err := restutil.GetSummary(revision, l.repo, l.finality)
+	if err != nil {
+		if l.repo.IsNotFound(err) {
+			return restutil.WriteJSON(w, nil)
+		}
+		return err
+	}
+	isTrunk, isFinalized, err := l.status(summary.Header)
+	if err != nil {
+		return err
+	}
+	return restutil.WriteJSON(w, api.BuildJSONBlockSummary(summary, isTrunk, isFinalized))
+}
+
+func (l *Light) handleVerifyAccountProof(w http.ResponseWriter, req *http.Request) error {
+	// the storage keys requested from the full node, in the same form as GET /accounts/{address}/proof
+	var keys []thor.Bytes32
+	if s := req.URL.Query().Get("keys"); s != "" {
+		for _, k := range strings.Split(s, ",") {
+			key, err := thor.ParseBytes32(k)
+			if err != nil {
+				return restutil.BadRequest(errors.WithMessage(err, "keys"))
+			}
+			keys = append(keys, key)
+		}
+	}
+	var proof api.AccountProof
+	if err := restutil.ParseJSON(req.Body, &proof); err != nil {
+		return restutil.BadRequest(errors.WithMessage(err, "body"))
+	}
+	return l.verify(w, proof.BlockID, func(header *block.Header) error {
+		return thorclient.VerifyAccountProof(header.StateRoot(), proof.Address, keys, &proof)
+	})
+}
+
+func (l *Light) handleVerifyTxProof(w http.ResponseWriter, req *http.Request) error {
+	var proof api.InclusionProof
+	if err := restutil.ParseJSON(req.Body, &proof); err != nil {
+		return restutil.BadRequest(errors.WithMessage(err, "body"))
+	}
+	return l.verify(w, proof.Meta.BlockID, func(header *block.Header) error {
+		var trx tx.Transaction
+		if err := trx.UnmarshalBinary(proof.Raw); err != nil {
+			return errors.WithMessage(err, "decode tx")
+		}
+		_, err := thorclient.VerifyTxProof(header, trx.ID(), &proof)
+		return err
+	})
+}
+
+func (l *Light) handleVerifyReceiptProof(w http.ResponseWriter, req *http.Request) error {
+	var proof api.InclusionProof
+	if err := restutil.ParseJSON(req.Body, &proof); err != nil {
+		return restutil.BadRequest(errors.WithMessage(err, "body"))
+	}
+	return l.verify(w, proof.Meta.BlockID, func(header *block.Header) error {
+		_, err := thorclient.VerifyReceiptProof(header, &proof)
+		return err
+	})
+}
+
+func (l *Light) handleNetwork(w http.ResponseWriter, _ *http.Request) error {
+	return restutil.WriteJSON(w, api.ConvertPeersStats(l.nw.PeersStats()))
+}
+
+// verify verifies the proof against the stored header of the block.
+func (l *Light) verify(w http.ResponseWriter, blockID thor.Bytes32, verify func(header *block.Header) error) error {
+	summary, err := l.repo.GetBlockSummary(blockID)
+	if err != nil {
+		if l.repo.IsNotFound(err) {
+			return restutil.WriteJSON(w, &api.ProofVerification{Error: "unknown block"})
+		}
+		return err
+	}
+	isTrunk, isFinalized, err := l.status(summary.Header)
+	if err != nil {
+		return err
+	}
+	result := api.ProofVerification{
+		Valid:       true,
+		IsTrunk:     isTrunk,
+		IsFinalized: isFinalized,
+	}
+	if err := verify(summary.Header); err != nil {
+		result.Valid = false
+		result.Error = err.Error()
+	}
+	return restutil.WriteJSON(w, &result)
+}
+
+func (l *Light) status(header *block.Header) (isTrunk, isFinalized bool, err error) {
+	id, err := l.repo.NewBestChain().GetBlockID(header.Number())
+	if err != nil {
+		return false, false, err
+	}
+	isTrunk = id == header.ID()
+	isFinalized = isTrunk && block.Number(l.finality.Finalized()) >= header.Number()
+	return
+}
+
+func (l *Light) Mount(root *mux.Router, pathPrefix string) {
+	sub := root.PathPrefix(pathPrefix).Subrouter()
+	sub.Path("/blocks/{revision}").
+		Methods(http.MethodGet).
+		Name("GET /blocks/{revision}").
+		HandlerFunc(restutil.WrapHandlerFunc(l.handleGetBlock))
+	sub.Path("/proofs/account").
+		Methods(http.MethodPost).
+		Name("POST /proofs/account").
+		HandlerFunc(restutil.WrapHandlerFunc(l.handleVerifyAccountProof))
+	sub.Path("/proofs/transaction").
+		Methods(http.MethodPost).
+		Name("POST /proofs/transaction").
+		HandlerFunc(restutil.WrapHandlerFunc(l.handleVerifyTxProof))
+	sub.Path("/proofs/receipt").
+		Methods(http.MethodPost).
+		Name("POST /proofs/receipt").
+		HandlerFunc(restutil.WrapHandlerFunc(l.handleVerifyReceiptProof))
+	sub.Path("/node/network/peers").
+		Methods(http.MethodGet).
+		Name("GET /node/network/peers").
+		HandlerFunc(restutil.WrapHandlerFunc(l.handleNetwork))
+}
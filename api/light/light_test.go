@@ -0,0 +1,221 @@
+// Copyright (c) 2025 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package light
+
+import (
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vechain/thor/v2/api"
+	"github.com/vechain/thor/v2/api/accounts"
+	"github.com/vechain/thor/v2/api/transactions"
+	"github.com/vechain/thor/v2/builtin"
+	"github.com/vechain/thor/v2/genesis"
+	"github.com/vechain/thor/v2/test/testchain"
+	"github.com/vechain/thor/v2/thor"
+	"github.com/vechain/thor/v2/thorclient"
+	"github.com/vechain/thor/v2/tx"
+)
+
+type testServer struct {
+	chain *testchain.Chain
+	trx   *tx.Transaction
+	full  *thorclient.Client // serves the proofs
+	light *thorclient.Client
+}
+
+func newTestServer(t *testing.T) *testServer {
+	chain, err := testchain.NewDefault()
+	require.NoError(t, err)
+
+	to := thor.BytesToAddress([]byte("to"))
+	trx := tx.NewBuilder(tx.TypeLegacy).
+		ChainTag(chain.Repo().ChainTag()).
+		Expiration(10).
+		Gas(21000).
+		Clause(tx.NewClause(&to).WithValue(big.NewInt(1))).
+		Build()
+	trx = tx.MustSign(trx, genesis.DevAccounts()[0].PrivateKey)
+	require.NoError(t, chain.MintBlock(trx))
+
+	fullRouter := mux.NewRouter()
+	accounts.New(chain.Repo(), chain.Stater(), 10_000_000, &thor.NoFork, chain.Engine(), false).
+		Mount(fullRouter, "/accounts")
+	transactions.New(chain.Repo(), nil).Mount(fullRouter, "/transactions")
+	full := httptest.NewServer(fullRouter)
+	t.Cleanup(full.Close)
+
+	lightRouter := mux.NewRouter()
+	New(chain.Repo(), chain.Engine(), nil).Mount(lightRouter, "")
+	light := httptest.NewServer(lightRouter)
+	t.Cleanup(light.Close)
+
+	return &testServer{
+		chain: chain,
+		trx:   trx,
+		full:  thorclient.New(full.URL),
+		light: thorclient.New(light.URL),
+	}
+}
+
+func (ts *testServer) verify(t *testing.T, path string, proof any) *api.ProofVerification {
+	body, statusCode, err := ts.light.RawHTTPClient().RawHTTPPost(path, proof)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, statusCode, string(body))
+
+	var result api.ProofVerification
+	require.NoError(t, json.Unmarshal(body, &result))
+	return &result
+}
+
+func TestGetBlock(t *testing.T) {
+	ts := newTestServer(t)
+	best := ts.chain.Repo().BestBlockSummary().Header
+
+	for _, revision := range []string{"best", "1", best.ID().String()} {
+		body, statusCode, err := ts.light.RawHTTPClient().RawHTTPGet("/blocks/" + revision)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, statusCode, string(body))
+
+		var block api.JSONBlockSummary
+		require.NoError(t, json.Unmarshal(body, &block))
+		assert.Equal(t, best.ID(), block.ID, revision)
+		assert.Equal(t, best.TxsRoot(), block.TxsRoot, revision)
+		assert.True(t, block.IsTrunk, revision)
+	}
+
+	// unknown block
+	for _, revision := range []string{"100", thor.BytesToBytes32([]byte("unknown")).String()} {
+		body, statusCode, err := ts.light.RawHTTPClient().RawHTTPGet("/blocks/" + revision)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, statusCode, revision)
+		assert.Equal(t, "null\n", string(body), revision)
+	}
+
+	_, statusCode, err := ts.light.RawHTTPClient().RawHTTPGet("/blocks/bad")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, statusCode)
+}
+
+func TestVerifyAccountProof(t *testing.T) {
+	ts := newTestServer(t)
+	best := ts.chain.Repo().BestBlockSummary().Header
+
+	addr := builtin.Params.Address
+	keys := []thor.Bytes32{thor.KeyExecutorAddress, thor.BytesToBytes32([]byte("absent"))}
+	path := "/proofs/account?keys=" + keys[0].String() + "," + keys[1].String()
+
+	proof, err := ts.full.AccountProof(&addr, keys)
+	require.NoError(t, err)
+	require.Equal(t, best.ID(), proof.BlockID)
+
+	result := ts.verify(t, path, proof)
+	assert.True(t, result.Valid, result.Error)
+	assert.True(t, result.IsTrunk)
+
+	// the proof must cover the requested keys
+	result = ts.verify(t, "/proofs/account?keys="+keys[0].String(), proof)
+	assert.False(t, result.Valid)
+	assert.Contains(t, result.Error, "unexpected storage proof")
+
+	tampered := *proof
+	tampered.Balance = (*math.HexOrDecimal256)(big.NewInt(1))
+	result = ts.verify(t, path, &tampered)
+	assert.False(t, result.Valid)
+	assert.Equal(t, "account fields mismatch", result.Error)
+
+	tampered = *proof
+	tampered.StateRoot = thor.Bytes32{}
+	tampered.BlockID = ts.chain.GenesisBlock().Header().ID()
+	result = ts.verify(t, path, &tampered)
+	assert.False(t, result.Valid, "state root of the stored header is used")
+
+	tampered = *proof
+	tampered.BlockID = thor.BytesToBytes32([]byte("unknown"))
+	result = ts.verify(t, path, &tampered)
+	assert.False(t, result.Valid)
+	assert.Equal(t, "unknown block", result.Error)
+
+	_, statusCode, err := ts.light.RawHTTPClient().RawHTTPPost("/proofs/account?keys=bad", proof)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, statusCode)
+}
+
+func TestVerifyTxProof(t *testing.T) {
+	ts := newTestServer(t)
+	id := ts.trx.ID()
+
+	proof, err := ts.full.TransactionProof(&id)
+	require.NoError(t, err)
+
+	result := ts.verify(t, "/proofs/transaction", proof)
+	assert.True(t, result.Valid, result.Error)
+	assert.True(t, result.IsTrunk)
+
+	tampered := *proof
+	tampered.Raw = append(hexutil.Bytes(nil), proof.Raw...)
+	tampered.Raw[len(tampered.Raw)-1]++
+	result = ts.verify(t, "/proofs/transaction", &tampered)
+	assert.False(t, result.Valid)
+
+	tampered = *proof
+	tampered.Proof = nil
+	result = ts.verify(t, "/proofs/transaction", &tampered)
+	assert.False(t, result.Valid)
+
+	tampered = *proof
+	tampered.Meta.BlockID = thor.BytesToBytes32([]byte("unknown"))
+	result = ts.verify(t, "/proofs/transaction", &tampered)
+	assert.False(t, result.Valid)
+	assert.Equal(t, "unknown block", result.Error)
+
+	_, statusCode, err := ts.light.RawHTTPClient().RawHTTPPost("/proofs/transaction", []byte("bad"))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, statusCode)
+}
+
+func TestVerifyReceiptProof(t *testing.T) {
+	ts := newTestServer(t)
+	id := ts.trx.ID()
+
+	proof, err := ts.full.ReceiptProof(&id)
+	require.NoError(t, err)
+
+	result := ts.verify(t, "/proofs/receipt", proof)
+	assert.True(t, result.Valid, result.Error)
+	assert.True(t, result.IsTrunk)
+
+	// a tx proof is not a receipt proof
+	txProof, err := ts.full.TransactionProof(&id)
+	require.NoError(t, err)
+	result = ts.verify(t, "/proofs/receipt", txProof)
+	assert.False(t, result.Valid)
+
+	tampered := *proof
+	tampered.Raw = append(hexutil.Bytes(nil), proof.Raw...)
+	tampered.Raw[len(tampered.Raw)-1]++
+	result = ts.verify(t, "/proofs/receipt", &tampered)
+	assert.False(t, result.Valid)
+
+	tampered = *proof
+	tampered.Meta.BlockID = thor.BytesToBytes32([]byte("unknown"))
+	result = ts.verify(t, "/proofs/receipt", &tampered)
+	assert.False(t, result.Valid)
+	assert.Equal(t, "unknown block", result.Error)
+
+	_, statusCode, err := ts.light.RawHTTPClient().RawHTTPPost("/proofs/receipt", []byte("bad"))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, statusCode)
+}
@@ -0,0 +1,14 @@
+// Copyright (c) 2025 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package api
+
+// ProofVerification is the result of verifying a proof against the header stored by a light node.
+type ProofVerification struct {
+	Valid       bool   `json:"valid"`
+	Error       string `json:"error,omitempty"` // the reason if invalid
+	IsTrunk     bool   `json:"isTrunk"`
+	IsFinalized bool   `json:"isFinalized"`
+}
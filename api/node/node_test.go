@@ -73,6 +73,8 @@ func initCommServer(t *testing.T) {
 
 	communicator := comm.New(
 		thorChain.Repo(),
+		thorChain.Stater(),
+		nil,
 		txpool.New(thorChain.Repo(), thorChain.Stater(), txpool.Options{
 			Limit:           10000,
 			LimitPerAccount: 128,
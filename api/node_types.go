@@ -26,6 +26,7 @@ type PeerStats struct {
 	NetAddr     string       `json:"netAddr"`
 	Inbound     bool         `json:"inbound"`
 	Duration    uint64       `json:"duration"`
+	Score       int          `json:"score"`
 }
 
 func ConvertPeersStats(ss []*comm.PeerStats) []*PeerStats {
@@ -42,6 +43,7 @@ func ConvertPeersStats(ss []*comm.PeerStats) []*PeerStats {
 			NetAddr:     peerStats.NetAddr,
 			Inbound:     peerStats.Inbound,
 			Duration:    peerStats.Duration,
+			Score:       peerStats.Score,
 		}
 	}
 	return peersStats
@@ -75,6 +75,14 @@ func Forbidden(cause error) error {
 	}
 }
 
+// Gone convenience method to create http gone error, for data no longer available.
+func Gone(cause error) error {
+	return &httpError{
+		cause:  cause,
+		status: http.StatusGone,
+	}
+}
+
 // HandlerFunc like http.HandlerFunc, bu it returns an error.
 // If the returned error is httpError type, httpError.status will be responded,
 // otherwise http.StatusInternalServerError responded.
@@ -70,6 +70,19 @@ func TestWrapHandlerFuncWithForbiddenError(t *testing.T) {
 	assert.Equal(t, forbiddenMsg, strings.TrimSpace(response.Body.String()))
 }
 
+func TestWrapHandlerFuncWithGoneError(t *testing.T) {
+	goneMsg := "This is an expired data"
+	handlerFunc := func(w http.ResponseWriter, r *http.Request) error {
+		return restutil.Gone(errors.New(goneMsg))
+	}
+	wrapped := restutil.WrapHandlerFunc(handlerFunc)
+
+	response := callWrappedFunc(&wrapped)
+
+	assert.Equal(t, http.StatusGone, response.Code)
+	assert.Equal(t, goneMsg, strings.TrimSpace(response.Body.String()))
+}
+
 func TestWrapHandlerFuncWithNilCauseError(t *testing.T) {
 	errorStatus := http.StatusTeapot
 	handlerFunc := func(w http.ResponseWriter, r *http.Request) error {
@@ -7,6 +7,7 @@ package restutil
 
 import (
 	"errors"
+	"fmt"
 	"math"
 	"strconv"
 
@@ -16,6 +17,7 @@ import (
 	"github.com/vechain/thor/v2/consensus/upgrade/galactica"
 	"github.com/vechain/thor/v2/state"
 	"github.com/vechain/thor/v2/thor"
+	"github.com/vechain/thor/v2/trie"
 	"github.com/vechain/thor/v2/tx"
 )
 
@@ -72,9 +74,15 @@ func ParseRevision(revision string, allowNext bool) (*Revision, error) {
 	return &Revision{uint32(n)}, err
 }
 
+// Finality provides the checkpoints to resolve the "finalized" and "justified" revisions.
+type Finality interface {
+	Finalized() thor.Bytes32
+	Justified() (thor.Bytes32, error)
+}
+
 // GetSummary returns the block summary for the given revision,
 // revision required to be a deterministic block other than "next".
-func GetSummary(rev *Revision, repo *chain.Repository, bft bft.Committer) (sum *chain.BlockSummary, err error) {
+func GetSummary(rev *Revision, repo *chain.Repository, bft Finality) (sum *chain.BlockSummary, err error) {
 	var id thor.Bytes32
 	switch rev := rev.val.(type) {
 	case thor.Bytes32:
@@ -162,7 +170,24 @@ func GetSummaryAndState(
 	if err != nil {
 		return nil, nil, err
 	}
+	if stater.IsPruned(sum.Root()) {
+		return nil, nil, BadRequest(fmt.Errorf("state pruned, revision #%v is out of the retention window", sum.Header.Number()))
+	}
 
-	st := stater.NewState(sum.Root())
+	// read from the archive if available, where the blocks of forked heights are checked against the chain
+	chain := repo.NewChain(sum.Header.ID())
+	st, err := stater.NewHistoricalState(sum.Root(), func(ver trie.Version) (bool, error) {
+		s, err := chain.GetBlockSummary(ver.Major)
+		if err != nil {
+			if chain.IsNotFound(err) {
+				return false, nil
+			}
+			return false, err
+		}
+		return s.Conflicts == ver.Minor, nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
 	return sum, st, nil
 }
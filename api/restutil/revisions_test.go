@@ -6,6 +6,7 @@
 package restutil
 
 import (
+	"context"
 	"fmt"
 	"math"
 	"testing"
@@ -188,3 +189,23 @@ func TestGetSummaryAndState(t *testing.T) {
 	assert.NotNil(t, err)
 	assert.True(t, signer.IsZero())
 }
+
+func TestGetSummaryAndStatePruned(t *testing.T) {
+	thorChain, err := testchain.NewDefault()
+	require.NoError(t, err)
+
+	for range 3 {
+		require.NoError(t, thorChain.MintBlock())
+	}
+	blocks, err := thorChain.GetAllBlocks()
+	require.NoError(t, err)
+	// mem db has hist partition factor 1
+	require.NoError(t, thorChain.Database().DeleteTrieHistoryNodes(context.Background(), 0, 2))
+
+	_, _, err = GetSummaryAndState(&Revision{blocks[1].Header().ID()}, thorChain.Repo(), thorChain.Engine(), thorChain.Stater(), thorChain.GetForkConfig())
+	assert.ErrorContains(t, err, "state pruned")
+
+	summary, _, err := GetSummaryAndState(&Revision{revBest}, thorChain.Repo(), thorChain.Engine(), thorChain.Stater(), thorChain.GetForkConfig())
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(3), summary.Header.Number())
+}
@@ -7,11 +7,13 @@ package subscriptions
 
 import (
 	"bytes"
+	"fmt"
 
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/common/math"
 
 	"github.com/vechain/thor/v2/api"
+	"github.com/vechain/thor/v2/api/restutil"
 	"github.com/vechain/thor/v2/chain"
 	"github.com/vechain/thor/v2/thor"
 	"github.com/vechain/thor/v2/thor/bloom"
@@ -63,6 +65,9 @@ func (br *beat2Reader) generateBeat2Message(block *chain.ExtendedBlock) func() (
 		header := block.Header()
 		receipts, err := br.repo.GetBlockReceipts(header.ID())
 		if err != nil {
+			if br.repo.IsExpired(err) {
+				return api.Beat2Message{}, restutil.Gone(fmt.Errorf("receipts of block #%v expired", header.Number()))
+			}
 			return api.Beat2Message{}, err
 		}
 		txs := block.Transactions()
@@ -7,10 +7,12 @@ package subscriptions
 
 import (
 	"bytes"
+	"fmt"
 
 	"github.com/ethereum/go-ethereum/common/hexutil"
 
 	"github.com/vechain/thor/v2/api"
+	"github.com/vechain/thor/v2/api/restutil"
 	"github.com/vechain/thor/v2/chain"
 	"github.com/vechain/thor/v2/thor"
 	"github.com/vechain/thor/v2/thor/bloom"
@@ -51,6 +53,9 @@ func (br *beatReader) generateBeatMessage(block *chain.ExtendedBlock) func() (ap
 		header := block.Header()
 		receipts, err := br.repo.GetBlockReceipts(header.ID())
 		if err != nil {
+			if br.repo.IsExpired(err) {
+				return api.BeatMessage{}, restutil.Gone(fmt.Errorf("receipts of block #%v expired", header.Number()))
+			}
 			return api.BeatMessage{}, err
 		}
 		txs := block.Transactions()
@@ -6,7 +6,10 @@
 package subscriptions
 
 import (
+	"fmt"
+
 	"github.com/vechain/thor/v2/api"
+	"github.com/vechain/thor/v2/api/restutil"
 	"github.com/vechain/thor/v2/chain"
 	"github.com/vechain/thor/v2/thor"
 )
@@ -34,6 +37,9 @@ func (er *eventReader) Read() ([]any, bool, error) {
 	for _, block := range blocks {
 		receipts, err := er.repo.GetBlockReceipts(block.Header().ID())
 		if err != nil {
+			if er.repo.IsExpired(err) {
+				return nil, false, restutil.Gone(fmt.Errorf("receipts of block #%v expired", block.Header().Number()))
+			}
 			return nil, false, err
 		}
 		txs := block.Transactions()
@@ -6,12 +6,15 @@
 package subscriptions
 
 import (
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/vechain/thor/v2/api"
+	"github.com/vechain/thor/v2/api/restutil"
 	"github.com/vechain/thor/v2/chain"
 )
 
@@ -68,3 +71,42 @@ type mockBlockReaderWithError struct{}
 func (m *mockBlockReaderWithError) Read() ([]*chain.ExtendedBlock, error) {
 	return nil, assert.AnError
 }
+
+func TestEventReader_Expired(t *testing.T) {
+	thorChain := initChain(t)
+	allBlocks, err := thorChain.GetAllBlocks()
+	require.NoError(t, err)
+	bestBlk := allBlocks[len(allBlocks)-1]
+	repo := thorChain.Repo()
+	require.NoError(t, repo.ExpireHistory(t.Context(), bestBlk.Header().Number()+1))
+
+	er := &eventReader{
+		repo:        repo,
+		filter:      &api.SubscriptionEventFilter{},
+		blockReader: &mockBlockReader{[]*chain.ExtendedBlock{{Block: bestBlk}}},
+	}
+	status := func(f func() error) int {
+		rec := httptest.NewRecorder()
+		restutil.WrapHandlerFunc(func(http.ResponseWriter, *http.Request) error { return f() })(rec, nil)
+		return rec.Code
+	}
+	assert.Equal(t, http.StatusGone, status(func() error {
+		_, _, err := er.Read()
+		return err
+	}))
+
+	// expired position rejected
+	s := &Subscriptions{repo: repo, backtraceLimit: 100}
+	assert.Equal(t, http.StatusGone, status(func() error {
+		_, err := s.parsePosition(allBlocks[1].Header().ID().String())
+		return err
+	}))
+}
+
+type mockBlockReader struct {
+	blocks []*chain.ExtendedBlock
+}
+
+func (m *mockBlockReader) Read() ([]*chain.ExtendedBlock, error) {
+	return m.blocks, nil
+}
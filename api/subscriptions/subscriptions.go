@@ -320,6 +320,9 @@ func (s *Subscriptions) parsePosition(posStr string) (thor.Bytes32, error) {
 	if block.Number(bestID)-block.Number(pos) > s.backtraceLimit {
 		return thor.Bytes32{}, restutil.Forbidden(errors.New("pos: backtrace limit exceeded"))
 	}
+	if block.Number(pos) < s.repo.HistoryExpiredBefore() {
+		return thor.Bytes32{}, restutil.Gone(errors.New("pos: history expired"))
+	}
 	return pos, nil
 }
 
@@ -49,6 +49,9 @@ func (t *Transactions) getRawTransaction(txID thor.Bytes32, head thor.Bytes32, a
 			}
 			return nil, nil
 		}
+		if t.repo.IsExpired(err) {
+			return nil, restutil.Gone(errors.New("tx expired"))
+		}
 		return nil, err
 	}
 
@@ -82,6 +85,9 @@ func (t *Transactions) getTransactionByID(txID thor.Bytes32, head thor.Bytes32,
 			}
 			return nil, nil
 		}
+		if t.repo.IsExpired(err) {
+			return nil, restutil.Gone(errors.New("tx expired"))
+		}
 		return nil, err
 	}
 
@@ -100,11 +106,17 @@ func (t *Transactions) getTransactionReceiptByID(txID thor.Bytes32, head thor.By
 		if t.repo.IsNotFound(err) {
 			return nil, nil
 		}
+		if t.repo.IsExpired(err) {
+			return nil, restutil.Gone(errors.New("tx expired"))
+		}
 		return nil, err
 	}
 
 	receipt, err := chain.GetTransactionReceipt(txID)
 	if err != nil {
+		if t.repo.IsExpired(err) {
+			return nil, restutil.Gone(errors.New("receipt expired"))
+		}
 		return nil, err
 	}
 
@@ -116,6 +128,74 @@ func (t *Transactions) getTransactionReceiptByID(txID thor.Bytes32, head thor.By
 	return api.ConvertReceipt(receipt, header, tx)
 }
 
+// getInclusionProof builds the merkle proof of the tx or its receipt included in the block.
+// Nil returned if the tx is not found.
+func (t *Transactions) getInclusionProof(txID thor.Bytes32, head thor.Bytes32, ofReceipt bool) (*api.InclusionProof, error) {
+	chain := t.repo.NewChain(head)
+	meta, err := chain.GetTransactionMeta(txID)
+	if err != nil {
+		if t.repo.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	header, err := chain.GetBlockHeader(meta.BlockNum)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		raw   []byte
+		proof [][]byte
+	)
+	if ofReceipt {
+		receipts, err := t.repo.GetBlockReceipts(header.ID())
+		if err != nil {
+			if t.repo.IsExpired(err) {
+				return nil, restutil.Gone(errors.New("receipt expired"))
+			}
+			return nil, err
+		}
+		if raw, err = receipts[meta.Index].MarshalBinary(); err != nil {
+			return nil, err
+		}
+		proof, err = receipts.Proof(int(meta.Index))
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		txs, err := t.repo.GetBlockTransactions(header.ID())
+		if err != nil {
+			if t.repo.IsExpired(err) {
+				return nil, restutil.Gone(errors.New("tx expired"))
+			}
+			return nil, err
+		}
+		if raw, err = txs[meta.Index].MarshalBinary(); err != nil {
+			return nil, err
+		}
+		proof, err = txs.Proof(int(meta.Index))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	result := &api.InclusionProof{
+		Meta: api.TxMeta{
+			BlockID:        header.ID(),
+			BlockNumber:    header.Number(),
+			BlockTimestamp: header.Timestamp(),
+		},
+		Index: meta.Index,
+		Raw:   raw,
+		Proof: make([]hexutil.Bytes, 0, len(proof)),
+	}
+	for _, node := range proof {
+		result.Proof = append(result.Proof, node)
+	}
+	return result, nil
+}
+
 func (t *Transactions) handleSendTransaction(w http.ResponseWriter, req *http.Request) error {
 	var rawTx *api.RawTx
 	if err := restutil.ParseJSON(req.Body, &rawTx); err != nil {
@@ -207,6 +287,32 @@ func (t *Transactions) handleGetTransactionReceiptByID(w http.ResponseWriter, re
 	return restutil.WriteJSON(w, receipt)
 }
 
+func (t *Transactions) handleGetProof(ofReceipt bool) restutil.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) error {
+		txID, err := thor.ParseBytes32(mux.Vars(req)["id"])
+		if err != nil {
+			return restutil.BadRequest(errors.WithMessage(err, "id"))
+		}
+
+		head, err := t.parseHead(req.URL.Query().Get("head"))
+		if err != nil {
+			return restutil.BadRequest(errors.WithMessage(err, "head"))
+		}
+		if _, err := t.repo.GetBlockSummary(head); err != nil {
+			if t.repo.IsNotFound(err) {
+				return restutil.BadRequest(errors.WithMessage(err, "head"))
+			}
+			return err
+		}
+
+		proof, err := t.getInclusionProof(txID, head, ofReceipt)
+		if err != nil {
+			return err
+		}
+		return restutil.WriteJSON(w, proof)
+	}
+}
+
 func (t *Transactions) parseHead(head string) (thor.Bytes32, error) {
 	if head == "" {
 		return t.repo.BestBlockSummary().Header.ID(), nil
@@ -233,4 +339,12 @@ func (t *Transactions) Mount(root *mux.Router, pathPrefix string) {
 		Methods(http.MethodGet).
 		Name("GET /transactions/{id}/receipt").
 		HandlerFunc(restutil.WrapHandlerFunc(t.handleGetTransactionReceiptByID))
+	sub.Path("/{id}/proof").
+		Methods(http.MethodGet).
+		Name("GET /transactions/{id}/proof").
+		HandlerFunc(restutil.WrapHandlerFunc(t.handleGetProof(false)))
+	sub.Path("/{id}/receipt/proof").
+		Methods(http.MethodGet).
+		Name("GET /transactions/{id}/receipt/proof").
+		HandlerFunc(restutil.WrapHandlerFunc(t.handleGetProof(true)))
 }
@@ -9,6 +9,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"math/big"
+	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
@@ -27,6 +28,7 @@ import (
 	"github.com/vechain/thor/v2/test/testchain"
 	"github.com/vechain/thor/v2/thor"
 	"github.com/vechain/thor/v2/thorclient"
+	"github.com/vechain/thor/v2/thorclient/httpclient"
 	"github.com/vechain/thor/v2/tx"
 	"github.com/vechain/thor/v2/txpool"
 )
@@ -468,3 +470,128 @@ func httpGetAndCheckResponseStatus(t *testing.T, url string, responseStatusCode
 
 	return body
 }
+
+func TestInclusionProof(t *testing.T) {
+	chain, err := testchain.NewDefault()
+	require.NoError(t, err)
+
+	// a block with many txs of both types, to have a deep trie
+	var txs tx.Transactions
+	to := thor.BytesToAddress([]byte("to"))
+	for i := range 150 {
+		txType := tx.TypeLegacy
+		if i%2 == 1 {
+			txType = tx.TypeDynamicFee
+		}
+		trx := tx.NewBuilder(txType).
+			ChainTag(chain.Repo().ChainTag()).
+			MaxFeePerGas(big.NewInt(thor.InitialBaseFee * 10)).
+			Expiration(10).
+			Gas(21000).
+			Nonce(uint64(i)).
+			Clause(tx.NewClause(&to).WithValue(big.NewInt(int64(i + 1)))).
+			Build()
+		txs = append(txs, tx.MustSign(trx, genesis.DevAccounts()[i%len(genesis.DevAccounts())].PrivateKey))
+	}
+	require.NoError(t, chain.MintBlock(txs...))
+	header := chain.Repo().BestBlockSummary().Header
+	require.Equal(t, txs.RootHash(), header.TxsRoot())
+
+	router := mux.NewRouter()
+	transactions.New(chain.Repo(), nil).Mount(router, "/transactions")
+	srv := httptest.NewServer(router)
+	defer srv.Close()
+	c := thorclient.New(srv.URL)
+
+	for i, trx := range txs {
+		id := trx.ID()
+		txProof, err := c.TransactionProof(&id)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(i), txProof.Index)
+		assert.Equal(t, header.ID(), txProof.Meta.BlockID)
+		proved, err := thorclient.VerifyTxProof(header, id, txProof)
+		require.NoError(t, err)
+		assert.Equal(t, id, proved.ID())
+
+		receiptProof, err := c.ReceiptProof(&id)
+		require.NoError(t, err)
+		assert.Equal(t, txProof.Index, receiptProof.Index)
+		receipt, err := thorclient.VerifyReceiptProof(header, receiptProof)
+		require.NoError(t, err)
+		assert.Equal(t, trx.Gas(), receipt.GasUsed)
+		assert.Equal(t, trx.Type(), receipt.Type)
+	}
+
+	id := txs[7].ID()
+	txProof, err := c.TransactionProof(&id)
+	require.NoError(t, err)
+	receiptProof, err := c.ReceiptProof(&id)
+	require.NoError(t, err)
+
+	// proofs are bound to the roots of the header
+	_, err = thorclient.VerifyTxProof(header, txs[8].ID(), txProof)
+	assert.Error(t, err, "tx id mismatch")
+	_, err = thorclient.VerifyReceiptProof(header, txProof)
+	assert.Error(t, err, "tx proof is not a receipt proof")
+	_, err = thorclient.VerifyTxProof(chain.GenesisBlock().Header(), id, txProof)
+	assert.Error(t, err, "block mismatch")
+
+	tampered := *txProof
+	tampered.Index++
+	_, err = thorclient.VerifyTxProof(header, id, &tampered)
+	assert.Error(t, err, "index mismatch")
+
+	tampered = *receiptProof
+	tampered.Raw = append(hexutil.Bytes(nil), receiptProof.Raw...)
+	tampered.Raw[len(tampered.Raw)-1]++
+	_, err = thorclient.VerifyReceiptProof(header, &tampered)
+	assert.Error(t, err, "raw mismatch")
+
+	tampered = *receiptProof
+	tampered.Proof = receiptProof.Proof[:len(receiptProof.Proof)-1]
+	_, err = thorclient.VerifyReceiptProof(header, &tampered)
+	assert.Error(t, err, "incomplete proof")
+
+	// not found and bad requests
+	unknown := thor.BytesToBytes32([]byte("unknown"))
+	_, err = c.TransactionProof(&unknown)
+	assert.ErrorIs(t, err, httpclient.ErrNotFound)
+	_, err = c.ReceiptProof(&unknown)
+	assert.ErrorIs(t, err, httpclient.ErrNotFound)
+
+	_, status, err := c.RawHTTPClient().RawHTTPGet("/transactions/bad/proof")
+	require.NoError(t, err)
+	assert.Equal(t, 400, status)
+	_, status, err = c.RawHTTPClient().RawHTTPGet("/transactions/" + id.String() + "/receipt/proof?head=0xffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff")
+	require.NoError(t, err)
+	assert.Equal(t, 400, status)
+}
+
+func TestExpiredTransaction(t *testing.T) {
+	chain, err := testchain.NewDefault()
+	require.NoError(t, err)
+
+	to := thor.BytesToAddress([]byte("to"))
+	trx := tx.NewBuilder(tx.TypeLegacy).
+		ChainTag(chain.Repo().ChainTag()).
+		Expiration(10).
+		Gas(21000).
+		Clause(tx.NewClause(&to).WithValue(big.NewInt(1))).
+		Build()
+	trx = tx.MustSign(trx, genesis.DevAccounts()[0].PrivateKey)
+	require.NoError(t, chain.MintBlock(trx))
+	require.NoError(t, chain.MintBlock())
+	require.NoError(t, chain.Repo().ExpireHistory(t.Context(), 2))
+
+	router := mux.NewRouter()
+	transactions.New(chain.Repo(), nil).Mount(router, "/transactions")
+	srv := httptest.NewServer(router)
+	defer srv.Close()
+
+	for _, path := range []string{"", "?raw=true", "/receipt", "/proof", "/receipt/proof"} {
+		res, err := http.Get(srv.URL + "/transactions/" + trx.ID().String() + path)
+		require.NoError(t, err)
+		res.Body.Close()
+		assert.Equal(t, http.StatusGone, res.StatusCode, path)
+	}
+}
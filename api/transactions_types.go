@@ -132,3 +132,12 @@ func ConvertReceipt(txReceipt *tx.Receipt, header *block.Header, tx *tx.Transact
 type SendTxResult struct {
 	ID *thor.Bytes32 `json:"id"`
 }
+
+// InclusionProof is the merkle proof of a tx or receipt included in a block.
+// It's verified against the txs root or receipts root of the block header.
+type InclusionProof struct {
+	Meta  TxMeta          `json:"meta"`
+	Index uint64          `json:"index"` // the position in the block
+	Raw   hexutil.Bytes   `json:"raw"`   // the encoded tx or receipt
+	Proof []hexutil.Bytes `json:"proof"`
+}
@@ -24,7 +24,10 @@ import (
 
 const dataStoreName = "bft.engine"
 
-var finalizedKey = []byte("finalized")
+var (
+	finalizedKey = []byte("finalized")
+	bootstrapKey = []byte("bootstrap")
+)
 
 type Committer interface {
 	Finalized() thor.Bytes32
@@ -51,6 +54,7 @@ type Engine struct {
 	casts      casts
 	finalized  atomic.Value
 	justified  atomic.Value
+	bootstrap  thor.Bytes32 // the block where the engine bootstrapped, zero if not bootstrapped
 	caches     struct {
 		state     *lru.Cache
 		quality   *lru.Cache
@@ -82,9 +86,53 @@ func NewEngine(repo *chain.Repository, mainDB *muxdb.MuxDB, forkConfig *thor.For
 		engine.finalized.Store(thor.BytesToBytes32(val))
 	}
 
+	if val, err := engine.data.Get(bootstrapKey); err != nil {
+		if !engine.data.IsNotFound(err) {
+			return nil, err
+		}
+	} else {
+		engine.bootstrap = thor.BytesToBytes32(val)
+	}
+
 	return &engine, nil
 }
 
+// Bootstrap initializes the engine at the given block, which is the last block of a round,
+// and its state is synced from peers rather than computed by replaying the history.
+// The given quality is trusted, and the checkpoint of the round is marked as finalized.
+func (engine *Engine) Bootstrap(id thor.Bytes32, quality uint32) error {
+	num := block.Number(id)
+	if num < engine.forkConfig.FINALITY || getStorePoint(num) != num {
+		return errors.New("not the last block of a round")
+	}
+
+	checkpoint, err := engine.repo.NewChain(id).GetBlockID(getCheckPoint(num))
+	if err != nil {
+		return err
+	}
+
+	if err := saveQuality(engine.data, id, quality); err != nil {
+		return err
+	}
+	if err := engine.data.Put(bootstrapKey, id[:]); err != nil {
+		return err
+	}
+	if err := engine.data.Put(finalizedKey, checkpoint[:]); err != nil {
+		return err
+	}
+
+	engine.caches.quality.Add(id, quality)
+	engine.bootstrap = id
+	engine.finalized.Store(checkpoint)
+	return nil
+}
+
+// Quality returns the quality of the given block, which should be the last block of a round.
+// Zero returned if the quality is not computed.
+func (engine *Engine) Quality(id thor.Bytes32) (uint32, error) {
+	return engine.getQuality(id)
+}
+
 // Finalized returns the finalized checkpoint.
 func (engine *Engine) Finalized() thor.Bytes32 {
 	return engine.finalized.Load().(thor.Bytes32)
@@ -300,6 +348,15 @@ func (engine *Engine) computeState(header *block.Header) (*bftState, error) {
 		return &bftState{}, nil
 	}
 
+	// votes before the bootstrap block are unavailable, only the quality is known
+	if header.ID() == engine.bootstrap {
+		quality, err := engine.getQuality(header.ID())
+		if err != nil {
+			return nil, err
+		}
+		return &bftState{Quality: quality}, nil
+	}
+
 	var (
 		js  *justifier
 		end uint32
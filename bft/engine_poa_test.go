@@ -1071,3 +1071,44 @@ func TestJustified(t *testing.T) {
 		})
 	}
 }
+
+func TestBootstrap(t *testing.T) {
+	testBFT, err := newTestBft(defaultFC)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = testBFT.fastForward(thor.EpochLength()*2 - 1); err != nil {
+		t.Fatal(err)
+	}
+
+	best := testBFT.repo.BestBlockSummary().Header
+	quality, err := testBFT.engine.Quality(best.ID())
+	assert.Nil(t, err)
+	st, err := testBFT.engine.computeState(best)
+	assert.Nil(t, err)
+	assert.Equal(t, st.Quality, quality)
+
+	// not the last block of a round
+	assert.NotNil(t, testBFT.engine.Bootstrap(best.ParentID(), quality))
+	assert.Nil(t, testBFT.engine.Bootstrap(best.ID(), quality))
+
+	checkpoint, err := testBFT.repo.NewBestChain().GetBlockID(thor.EpochLength())
+	assert.Nil(t, err)
+	assert.Equal(t, checkpoint, testBFT.engine.Finalized())
+
+	// bootstrap info should be persisted
+	assert.Nil(t, testBFT.reCreateEngine())
+	assert.Equal(t, checkpoint, testBFT.engine.Finalized())
+	st, err = testBFT.engine.computeState(best)
+	assert.Nil(t, err)
+	assert.Equal(t, quality, st.Quality)
+
+	// go on finalizing
+	if err = testBFT.fastForward(thor.EpochLength() * 2); err != nil {
+		t.Fatal(err)
+	}
+	finalized, err := testBFT.repo.NewBestChain().GetBlockID(thor.EpochLength() * 2)
+	assert.Nil(t, err)
+	assert.Equal(t, finalized, testBFT.engine.Finalized())
+}
@@ -18,6 +18,12 @@ func (cs *Stats) Hit() int64 { return cs.hit.Add(1) }
 // Miss records a miss.
 func (cs *Stats) Miss() int64 { return cs.miss.Add(1) }
 
+// Counts returns the number of hits and misses, without affecting
+// the hit rate change reported by Stats.
+func (cs *Stats) Counts() (int64, int64) {
+	return cs.hit.Load(), cs.miss.Load()
+}
+
 // Stats returns the number of hits and misses and whether
 // the hit rate was changed comparing to the last call.
 func (cs *Stats) Stats() (bool, int64, int64) {
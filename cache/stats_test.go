@@ -33,3 +33,50 @@ func TestCacheStats(t *testing.T) {
 	assert.Equal(t, int64(2), miss)
 	assert.True(t, changed)
 }
+
+func TestCacheStatsChanged(t *testing.T) {
+	cs := Stats{}
+
+	changed, hits, misses := cs.Stats()
+	assert.Equal(t, int64(0), hits)
+	assert.Equal(t, int64(0), misses)
+	assert.False(t, changed)
+
+	cs.hit.Store(100)
+	changed, hits, misses = cs.Stats()
+	assert.Equal(t, int64(100), hits)
+	assert.Equal(t, int64(0), misses)
+	assert.True(t, changed)
+
+	changed, hits, misses = cs.Stats()
+	assert.Equal(t, int64(100), hits)
+	assert.Equal(t, int64(0), misses)
+	assert.False(t, changed)
+
+	cs.miss.Store(100)
+	changed, hits, misses = cs.Stats()
+	assert.Equal(t, int64(100), hits)
+	assert.Equal(t, int64(100), misses)
+	assert.True(t, changed)
+
+	cs = Stats{}
+	cs.miss.Store(100)
+	changed, hits, misses = cs.Stats()
+	assert.Equal(t, int64(0), hits)
+	assert.Equal(t, int64(100), misses)
+	assert.False(t, changed)
+}
+
+func TestCacheStatsCounts(t *testing.T) {
+	cs := Stats{}
+	cs.Hit()
+	cs.Miss()
+
+	hit, miss := cs.Counts()
+	assert.Equal(t, int64(1), hit)
+	assert.Equal(t, int64(1), miss)
+
+	// the change is still reported
+	changed, _, _ := cs.Stats()
+	assert.True(t, changed)
+}
@@ -6,16 +6,71 @@
 package chain
 
 import (
+	"sync/atomic"
+
 	lru "github.com/hashicorp/golang-lru"
+	"github.com/pkg/errors"
+
+	cache2 "github.com/vechain/thor/v2/cache"
+)
+
+// Names of the repository caches.
+const (
+	CacheSummaries = "summaries"
+	CacheTxs       = "txs"
+	CacheReceipts  = "receipts"
 )
 
+// CacheStats is the statistics of a repository cache.
+type CacheStats struct {
+	Size int // max number of entries
+	Len  int // number of cached entries
+	Hit  int64
+	Miss int64
+}
+
 type cache struct {
-	*lru.ARCCache
+	arc  atomic.Pointer[lru.ARCCache]
+	size atomic.Int64
 }
 
 func newCache(maxSize int) *cache {
-	c, _ := lru.NewARC(maxSize)
-	return &cache{c}
+	c := &cache{}
+	c.Resize(maxSize)
+	return c
+}
+
+func (c *cache) Get(key any) (any, bool) {
+	return c.arc.Load().Get(key)
+}
+
+func (c *cache) Add(key, value any) {
+	c.arc.Load().Add(key, value)
+}
+
+func (c *cache) Len() int {
+	return c.arc.Load().Len()
+}
+
+func (c *cache) Size() int {
+	return int(c.size.Load())
+}
+
+// Resize resizes the cache, keeping the entries that fit. Since keys of ARC are listed as the recently used
+// ones followed by the frequently used ones, each from oldest to newest, the frequently used entries are kept
+// first, and then the recently used ones.
+func (c *cache) Resize(maxSize int) {
+	arc, _ := lru.NewARC(maxSize)
+	if old := c.arc.Load(); old != nil {
+		keys := old.Keys()
+		for _, key := range keys[max(0, len(keys)-maxSize):] {
+			if value, ok := old.Peek(key); ok {
+				arc.Add(key, value)
+			}
+		}
+	}
+	c.arc.Store(arc)
+	c.size.Store(int64(maxSize))
 }
 
 func (c *cache) GetOrLoad(key any, load func() (any, error)) (any, bool, error) {
@@ -29,3 +84,48 @@ func (c *cache) GetOrLoad(key any, load func() (any, error)) (any, bool, error)
 	c.Add(key, value)
 	return value, false, nil
 }
+
+// namedCaches returns caches along with their stats by name.
+func (r *Repository) namedCaches() map[string]struct {
+	c     *cache
+	stats *cache2.Stats
+} {
+	type entry = struct {
+		c     *cache
+		stats *cache2.Stats
+	}
+	return map[string]entry{
+		CacheSummaries: {r.caches.summaries, &r.caches.stats.summaries},
+		CacheTxs:       {r.caches.txs, &r.caches.stats.txs},
+		CacheReceipts:  {r.caches.receipts, &r.caches.stats.receipts},
+	}
+}
+
+// CacheStats returns the statistics of caches by name.
+func (r *Repository) CacheStats() map[string]CacheStats {
+	caches := r.namedCaches()
+	stats := make(map[string]CacheStats, len(caches))
+	for name, entry := range caches {
+		hit, miss := entry.stats.Counts()
+		stats[name] = CacheStats{
+			Size: entry.c.Size(),
+			Len:  entry.c.Len(),
+			Hit:  hit,
+			Miss: miss,
+		}
+	}
+	return stats
+}
+
+// ResizeCache resizes the cache of the given name at runtime.
+func (r *Repository) ResizeCache(name string, size int) error {
+	entry, ok := r.namedCaches()[name]
+	if !ok {
+		return errors.Errorf("unknown cache %v", name)
+	}
+	if size <= 0 {
+		return errors.Errorf("invalid size %v of cache %v", size, name)
+	}
+	entry.c.Resize(size)
+	return nil
+}
@@ -6,16 +6,32 @@
 package chain
 
 import (
+	"os"
 	"testing"
 
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/vechain/thor/v2/block"
+	"github.com/vechain/thor/v2/muxdb"
 	"github.com/vechain/thor/v2/thor"
 	"github.com/vechain/thor/v2/tx"
 )
 
+// testEngine is the engine of DBs created by newTestRepo.
+var testEngine = muxdb.EngineLevelDB
+
+// TestMain runs tests against all engines.
+func TestMain(m *testing.M) {
+	for _, name := range []string{muxdb.EngineLevelDB, muxdb.EnginePebble} {
+		testEngine = name
+		if code := m.Run(); code != 0 {
+			os.Exit(code)
+		}
+	}
+	os.Exit(0)
+}
+
 func newTx(txType tx.Type) *tx.Transaction {
 	tx := tx.NewBuilder(txType).Build()
 	pk, _ := crypto.GenerateKey()
@@ -6,6 +6,7 @@
 package chain
 
 import (
+	"context"
 	"encoding/binary"
 	"sync/atomic"
 
@@ -35,8 +36,10 @@ const (
 )
 
 var (
-	errNotFound    = errors.New("not found")
-	bestBlockIDKey = []byte("best-block-id")
+	errNotFound       = errors.New("not found")
+	errExpired        = errors.New("expired")
+	bestBlockIDKey    = []byte("best-block-id")
+	historyExpiredKey = []byte("history-expired-before")
 )
 
 // Repository stores block headers, txs and receipts.
@@ -54,6 +57,7 @@ type Repository struct {
 	tag     byte
 
 	bestSummary atomic.Value
+	histExpired atomic.Uint32 // the block number before which txs and receipts expired
 	tick        co.Signal
 
 	caches struct {
@@ -122,6 +126,13 @@ func NewRepository(db *muxdb.MuxDB, genesis *block.Block) (*Repository, error) {
 		repo.bestSummary.Store(summary)
 	}
 
+	if val, err := repo.propStore.Get(historyExpiredKey); err != nil {
+		if !repo.propStore.IsNotFound(err) {
+			return nil, err
+		}
+	} else if len(val) == 4 {
+		repo.histExpired.Store(binary.BigEndian.Uint32(val))
+	}
 	return repo, nil
 }
 
@@ -142,41 +153,21 @@ func (r *Repository) BestBlockSummary() *BlockSummary {
 
 func (r *Repository) saveBlock(block *block.Block, receipts tx.Receipts, conflicts uint32, asBest bool) (*BlockSummary, error) {
 	var (
-		header        = block.Header()
-		id            = header.ID()
-		num           = header.Number()
-		txs           = block.Transactions()
-		txIDs         = []thor.Bytes32{}
-		bulk          = r.db.NewStore("").Bulk()
-		hdrPutter     = kv.Bucket(hdrStoreName).NewPutter(bulk)
-		bodyPutter    = kv.Bucket(bodyStoreName).NewPutter(bulk)
-		propPutter    = kv.Bucket(propStoreName).NewPutter(bulk)
-		headPutter    = kv.Bucket(headStoreName).NewPutter(bulk)
-		txIndexPutter = kv.Bucket(txIndexStoreName).NewPutter(bulk)
-		keyBuf        []byte
+		header     = block.Header()
+		num        = header.Number()
+		txs        = block.Transactions()
+		txIDs      = []thor.Bytes32{}
+		reverted   = make([]bool, 0, len(txs))
+		bulk       = r.db.NewStore("").Bulk()
+		bodyPutter = kv.Bucket(bodyStoreName).NewPutter(bulk)
+		keyBuf     []byte
 	)
 
 	if len(txs) > 0 {
-		// index and save txs
+		// save txs
 		for i, tx := range txs {
-			txid := tx.ID()
-			txIDs = append(txIDs, txid)
-
-			// write the filter key
-			if err := txIndexPutter.Put(txid[:txFilterKeyLen], nil); err != nil {
-				return nil, err
-			}
-			// write tx metadata
-			keyBuf = append(keyBuf[:0], txid[:]...)
-			keyBuf = binary.AppendUvarint(keyBuf, uint64(header.Number()))
-			keyBuf = binary.AppendUvarint(keyBuf, uint64(conflicts))
-
-			if err := saveRLP(txIndexPutter, keyBuf, &storageTxMeta{
-				Index:    uint64(i),
-				Reverted: receipts[i].Reverted,
-			}); err != nil {
-				return nil, err
-			}
+			txIDs = append(txIDs, tx.ID())
+			reverted = append(reverted, receipts[i].Reverted)
 
 			// write the tx blob
 			keyBuf = appendTxKey(keyBuf[:0], num, conflicts, uint64(i), txFlag)
@@ -195,30 +186,67 @@ func (r *Repository) saveBlock(block *block.Block, receipts tx.Receipts, conflic
 			r.caches.receipts.Add(string(keyBuf), receipt)
 		}
 	}
-	if err := indexChainHead(headPutter, header); err != nil {
-		return nil, err
-	}
 
 	summary := BlockSummary{header, txIDs, uint64(block.Size()), conflicts}
-	if err := saveBlockSummary(hdrPutter, &summary); err != nil {
+	if err := r.writeSummary(bulk, &summary, reverted, asBest); err != nil {
 		return nil, err
 	}
+	return &summary, nil
+}
+
+// writeSummary puts the block summary along with the index of its txs into the bulk, and writes the bulk.
+func (r *Repository) writeSummary(bulk kv.Bulk, summary *BlockSummary, reverted []bool, asBest bool) error {
+	var (
+		header        = summary.Header
+		id            = header.ID()
+		hdrPutter     = kv.Bucket(hdrStoreName).NewPutter(bulk)
+		propPutter    = kv.Bucket(propStoreName).NewPutter(bulk)
+		headPutter    = kv.Bucket(headStoreName).NewPutter(bulk)
+		txIndexPutter = kv.Bucket(txIndexStoreName).NewPutter(bulk)
+		keyBuf        []byte
+	)
+
+	// index txs
+	for i, txid := range summary.Txs {
+		// write the filter key
+		if err := txIndexPutter.Put(txid[:txFilterKeyLen], nil); err != nil {
+			return err
+		}
+		// write tx metadata
+		keyBuf = append(keyBuf[:0], txid[:]...)
+		keyBuf = binary.AppendUvarint(keyBuf, uint64(header.Number()))
+		keyBuf = binary.AppendUvarint(keyBuf, uint64(summary.Conflicts))
+
+		if err := saveRLP(txIndexPutter, keyBuf, &storageTxMeta{
+			Index:    uint64(i),
+			Reverted: reverted[i],
+		}); err != nil {
+			return err
+		}
+	}
+	if err := indexChainHead(headPutter, header); err != nil {
+		return err
+	}
+
+	if err := saveBlockSummary(hdrPutter, summary); err != nil {
+		return err
+	}
 
 	if asBest {
 		if err := propPutter.Put(bestBlockIDKey, id[:]); err != nil {
-			return nil, err
+			return err
 		}
 	}
 
 	if err := bulk.Write(); err != nil {
-		return nil, err
+		return err
 	}
-	r.caches.summaries.Add(id, &summary)
+	r.caches.summaries.Add(id, summary)
 	if asBest {
-		r.bestSummary.Store(&summary)
+		r.bestSummary.Store(summary)
 		r.tick.Broadcast()
 	}
-	return &summary, nil
+	return nil
 }
 
 // AddBlock add a new block with its receipts into repository.
@@ -240,6 +268,30 @@ func (r *Repository) AddBlock(newBlock *block.Block, receipts tx.Receipts, confl
 	return nil
 }
 
+// AddExpiredBlock adds a block of which txs and receipts expired, with only the summary and the reverted
+// flags of txs. It's used to bootstrap the chain from a snapshot, and the block should be before the
+// history expiry boundary.
+func (r *Repository) AddExpiredBlock(summary *BlockSummary, reverted []bool) error {
+	header := summary.Header
+	if header.Number() >= r.histExpired.Load() {
+		return errors.Errorf("history of block #%v not expired", header.Number())
+	}
+	if len(reverted) != len(summary.Txs) {
+		return errors.New("reverted flags count mismatch")
+	}
+	parentSummary, err := r.GetBlockSummary(header.ParentID())
+	if err != nil {
+		if r.IsNotFound(err) {
+			return errors.New("parent missing")
+		}
+		return err
+	}
+	if err := r.indexBlock(parentSummary.IndexRoot(), header.ID(), summary.Conflicts); err != nil {
+		return err
+	}
+	return r.writeSummary(r.db.NewStore("").Bulk(), summary, reverted, false)
+}
+
 // ScanConflicts returns the count of saved blocks with the given blockNum.
 func (r *Repository) ScanConflicts(blockNum uint32) (uint32, error) {
 	prefix := binary.BigEndian.AppendUint32(nil, blockNum)
@@ -327,7 +379,36 @@ func (r *Repository) GetBlockSummary(id thor.Bytes32) (*BlockSummary, error) {
 	return blk.(*BlockSummary), nil
 }
 
+// ExpireHistory deletes txs and receipts of blocks before the given block number.
+// Block summaries and tx metadata are kept, and the expired data is reported by IsExpired.
+func (r *Repository) ExpireHistory(ctx context.Context, limit uint32) error {
+	if limit > r.histExpired.Load() {
+		// marked before deletion, so that partially deleted blocks are never served
+		if err := r.propStore.Put(historyExpiredKey, binary.BigEndian.AppendUint32(nil, limit)); err != nil {
+			return err
+		}
+		r.histExpired.Store(limit)
+	}
+	// always starts from the beginning, to clean up the leftover of the interrupted deletion
+	return r.bodyStore.DeleteRange(ctx, kv.Range{
+		Limit: binary.BigEndian.AppendUint32(nil, r.histExpired.Load()),
+	})
+}
+
+// HistoryExpiredBefore returns the block number before which txs and receipts are expired.
+func (r *Repository) HistoryExpiredBefore() uint32 {
+	return r.histExpired.Load()
+}
+
+// isExpired returns whether the tx or receipt of the given key is expired.
+func (r *Repository) isExpired(key []byte) bool {
+	return len(key) >= 4 && binary.BigEndian.Uint32(key) < r.histExpired.Load()
+}
+
 func (r *Repository) getTransaction(key []byte) (*tx.Transaction, error) {
+	if r.isExpired(key) {
+		return nil, errExpired
+	}
 	trx, cached, err := r.caches.txs.GetOrLoad(string(key), func() (any, error) {
 		return loadTransaction(r.bodyStore, key)
 	})
@@ -391,6 +472,9 @@ func (r *Repository) GetBlock(id thor.Bytes32) (*block.Block, error) {
 }
 
 func (r *Repository) getReceipt(key []byte) (*tx.Receipt, error) {
+	if r.isExpired(key) {
+		return nil, errExpired
+	}
 	receipt, cached, err := r.caches.receipts.GetOrLoad(string(key), func() (any, error) {
 		return loadReceipt(r.bodyStore, key)
 	})
@@ -444,6 +528,11 @@ func (r *Repository) IsNotFound(err error) bool {
 	return err == errNotFound || r.db.IsNotFound(err)
 }
 
+// IsExpired returns if the given error means the requested txs or receipts are expired.
+func (r *Repository) IsExpired(err error) bool {
+	return err == errExpired
+}
+
 // NewTicker create a signal Waiter to receive event that the best block changed.
 func (r *Repository) NewTicker() co.Waiter {
 	return r.tick.NewWaiter()
@@ -25,7 +25,7 @@ func M(args ...any) []any {
 }
 
 func newTestRepo() (*muxdb.MuxDB, *Repository) {
-	db := muxdb.NewMem()
+	db := muxdb.NewMemWithEngine(testEngine)
 	b0 := new(block.Builder).
 		ParentID(thor.Bytes32{0xff, 0xff, 0xff, 0xff}).
 		Build()
@@ -270,3 +270,117 @@ func TestRepository_ErrorBranches(t *testing.T) {
 		assert.Error(t, err)
 	})
 }
+
+func TestExpireHistory(t *testing.T) {
+	db, repo := newTestRepo()
+
+	tx1 := new(tx.Builder).Nonce(1).Build()
+	tx2 := new(tx.Builder).Nonce(2).Build()
+	b1 := newBlock(repo.GenesisBlock(), 10, tx1)
+	b2 := newBlock(b1, 20, tx2)
+	assert.Nil(t, repo.AddBlock(b1, tx.Receipts{&tx.Receipt{}}, 0, false))
+	assert.Nil(t, repo.AddBlock(b2, tx.Receipts{&tx.Receipt{}}, 0, true))
+
+	assert.Nil(t, repo.ExpireHistory(context.Background(), 2))
+	assert.Equal(t, uint32(2), repo.HistoryExpiredBefore())
+
+	repo2, _ := NewRepository(db, repo.GenesisBlock())
+	for _, repo := range []*Repository{repo, repo2} {
+		assert.Equal(t, uint32(2), repo.HistoryExpiredBefore())
+
+		// summary and tx meta kept
+		s, err := repo.GetBlockSummary(b1.Header().ID())
+		assert.Nil(t, err)
+		assert.Equal(t, []thor.Bytes32{tx1.ID()}, s.Txs)
+		meta, err := repo.NewBestChain().GetTransactionMeta(tx1.ID())
+		assert.Nil(t, err)
+		assert.Equal(t, uint32(1), meta.BlockNum)
+
+		_, err = repo.GetBlock(b1.Header().ID())
+		assert.True(t, repo.IsExpired(err))
+		assert.False(t, repo.IsNotFound(err))
+		_, err = repo.GetBlockReceipts(b1.Header().ID())
+		assert.True(t, repo.IsExpired(err))
+		_, _, err = repo.NewBestChain().GetTransaction(tx1.ID())
+		assert.True(t, repo.IsExpired(err))
+
+		// newer blocks untouched
+		txs, err := repo.GetBlockTransactions(b2.Header().ID())
+		assert.Nil(t, err)
+		assert.Equal(t, tx2.ID(), txs[0].ID())
+	}
+
+	// deleted from the store
+	has, err := repo.bodyStore.Has(appendTxKey(nil, 1, 0, 0, txFlag))
+	assert.Nil(t, err)
+	assert.False(t, has)
+
+	// never goes backward
+	assert.Nil(t, repo.ExpireHistory(context.Background(), 1))
+	assert.Equal(t, uint32(2), repo.HistoryExpiredBefore())
+}
+
+func TestAddExpiredBlock(t *testing.T) {
+	_, repo := newTestRepo()
+
+	tx1 := new(tx.Builder).Nonce(1).Build()
+	b1 := newBlock(repo.GenesisBlock(), 10, tx1)
+	b2 := newBlock(b1, 20)
+	s1 := &BlockSummary{b1.Header(), []thor.Bytes32{tx1.ID()}, uint64(b1.Size()), 0}
+
+	// not expired yet
+	assert.Error(t, repo.AddExpiredBlock(s1, []bool{true}))
+
+	assert.Nil(t, repo.ExpireHistory(context.Background(), 2))
+	assert.Error(t, repo.AddExpiredBlock(s1, nil))
+	assert.Nil(t, repo.AddExpiredBlock(s1, []bool{true}))
+	assert.Nil(t, repo.AddBlock(b2, nil, 0, true))
+
+	s, err := repo.GetBlockSummary(b1.Header().ID())
+	assert.Nil(t, err)
+	assert.Equal(t, s1.Txs, s.Txs)
+
+	bestChain := repo.NewBestChain()
+	id, err := bestChain.GetBlockID(1)
+	assert.Nil(t, err)
+	assert.Equal(t, b1.Header().ID(), id)
+	meta, err := bestChain.GetTransactionMeta(tx1.ID())
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(1), meta.BlockNum)
+	assert.True(t, meta.Reverted)
+	has, err := bestChain.HasTransaction(tx1.ID(), 0)
+	assert.Nil(t, err)
+	assert.True(t, has)
+
+	_, err = repo.GetBlock(b1.Header().ID())
+	assert.True(t, repo.IsExpired(err))
+}
+
+func TestResizeCache(t *testing.T) {
+	_, repo := newTestRepo()
+	b0 := repo.GenesisBlock()
+
+	b1 := newBlock(b0, 10)
+	repo.AddBlock(b1, nil, 0, true)
+
+	prev := repo.CacheStats()[CacheSummaries]
+	_, err := repo.GetBlockSummary(b1.Header().ID())
+	assert.Nil(t, err)
+
+	stats := repo.CacheStats()[CacheSummaries]
+	assert.Equal(t, 512, stats.Size)
+	assert.Equal(t, 2, stats.Len)
+	assert.Equal(t, prev.Hit+1, stats.Hit)
+
+	// the most recent entries kept
+	assert.Nil(t, repo.ResizeCache(CacheSummaries, 1))
+	stats = repo.CacheStats()[CacheSummaries]
+	assert.Equal(t, 1, stats.Size)
+	assert.Equal(t, 1, stats.Len)
+	_, err = repo.GetBlockSummary(b1.Header().ID())
+	assert.Nil(t, err)
+	assert.Equal(t, stats.Hit+1, repo.CacheStats()[CacheSummaries].Hit)
+
+	assert.Error(t, repo.ResizeCache("unknown", 1))
+	assert.Error(t, repo.ResizeCache(CacheTxs, 0))
+}
@@ -0,0 +1,55 @@
+// Copyright (c) 2025 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package bandwidth
+
+import (
+	"net"
+	"sync/atomic"
+)
+
+// Traffic accounts bytes exchanged with peers on the wire, which are RLPx frames
+// encrypted and compressed by the transport, along with handshakes.
+type Traffic struct {
+	in, out atomic.Uint64
+}
+
+// In returns the total bytes received.
+func (t *Traffic) In() uint64 {
+	return t.in.Load()
+}
+
+// Out returns the total bytes sent.
+func (t *Traffic) Out() uint64 {
+	return t.out.Load()
+}
+
+// MeterConn returns the connection with traffic metered.
+func (t *Traffic) MeterConn(conn net.Conn) net.Conn {
+	return &meteredConn{conn, t}
+}
+
+type meteredConn struct {
+	net.Conn
+	traffic *Traffic
+}
+
+func (c *meteredConn) Read(b []byte) (int, error) {
+	n, err := c.Conn.Read(b)
+	if n > 0 {
+		c.traffic.in.Add(uint64(n))
+		metricTrafficBytes().AddWithLabel(int64(n), map[string]string{"dir": "in"})
+	}
+	return n, err
+}
+
+func (c *meteredConn) Write(b []byte) (int, error) {
+	n, err := c.Conn.Write(b)
+	if n > 0 {
+		c.traffic.out.Add(uint64(n))
+		metricTrafficBytes().AddWithLabel(int64(n), map[string]string{"dir": "out"})
+	}
+	return n, err
+}
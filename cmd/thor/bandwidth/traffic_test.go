@@ -0,0 +1,40 @@
+// Copyright (c) 2025 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package bandwidth
+
+import (
+	"io"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTraffic(t *testing.T) {
+	var traffic Traffic
+	c1, c2 := net.Pipe()
+	defer c2.Close()
+	conn := traffic.MeterConn(c1)
+	defer conn.Close()
+
+	go func() {
+		buf := make([]byte, 5)
+		if _, err := io.ReadFull(c2, buf); err == nil {
+			c2.Write([]byte("world!"))
+		}
+	}()
+
+	_, err := conn.Write([]byte("hello"))
+	require.NoError(t, err)
+	buf := make([]byte, 6)
+	_, err = io.ReadFull(conn, buf)
+	require.NoError(t, err)
+	assert.Equal(t, "world!", string(buf))
+
+	assert.Equal(t, uint64(5), traffic.Out())
+	assert.Equal(t, uint64(6), traffic.In())
+}
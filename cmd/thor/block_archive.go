@@ -0,0 +1,294 @@
+// Copyright (c) 2025 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"hash"
+	"io"
+
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/pkg/errors"
+
+	"github.com/vechain/thor/v2/block"
+	"github.com/vechain/thor/v2/comm/proto"
+	"github.com/vechain/thor/v2/thor"
+)
+
+const (
+	blockArchiveMagic = "THORBLKS"
+	archiveVersion    = byte(1)
+)
+
+// Archive files, such as block archives and state snapshots, share the framing:
+//
+//	header:  magic | version | genesis id
+//	records: (size uint32 | data)...
+//	end:     0 uint32 | extra
+//	trailer: sha256 of all above
+//
+// Integers are big-endian. An archive file may be gzip compressed as a whole.
+
+// archiveWriter writes an archive file.
+type archiveWriter struct {
+	out    io.Writer
+	w      io.Writer // writes to both the output and the hasher
+	hasher hash.Hash
+	offset uint64
+}
+
+// newArchiveWriter creates the writer and writes the header.
+func newArchiveWriter(out io.Writer, magic string, genesisID thor.Bytes32) (*archiveWriter, error) {
+	hasher := sha256.New()
+	aw := &archiveWriter{
+		out:    out,
+		w:      io.MultiWriter(out, hasher),
+		hasher: hasher,
+	}
+	header := append([]byte(magic), archiveVersion)
+	if err := aw.write(append(header, genesisID[:]...)); err != nil {
+		return nil, err
+	}
+	return aw, nil
+}
+
+func (aw *archiveWriter) write(data []byte) error {
+	n, err := aw.w.Write(data)
+	aw.offset += uint64(n)
+	return err
+}
+
+// WriteRecord writes a non-empty record, and returns its offset.
+func (aw *archiveWriter) WriteRecord(data []byte) (uint64, error) {
+	offset := aw.offset
+	if err := aw.write(binary.BigEndian.AppendUint32(nil, uint32(len(data)))); err != nil {
+		return 0, err
+	}
+	return offset, aw.write(data)
+}
+
+// Finish writes the end of records with the extra data, and the trailer. The output is not flushed or closed.
+func (aw *archiveWriter) Finish(extra []byte) error {
+	if err := aw.write(append(binary.BigEndian.AppendUint32(nil, 0), extra...)); err != nil {
+		return err
+	}
+	_, err := aw.out.Write(aw.hasher.Sum(nil))
+	return err
+}
+
+// archiveReader reads an archive file sequentially.
+type archiveReader struct {
+	r         io.Reader // reads from the input, and writes to the hasher
+	hasher    hash.Hash
+	genesisID thor.Bytes32
+	offset    uint64
+}
+
+// newArchiveReader creates the reader and reads the header. Gzip compressed input is detected.
+func newArchiveReader(in io.Reader, magic string) (*archiveReader, error) {
+	br := bufio.NewReader(in)
+	if head, err := br.Peek(2); err == nil && head[0] == 0x1f && head[1] == 0x8b {
+		zr, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, err
+		}
+		in = zr
+	} else {
+		in = br
+	}
+
+	hasher := sha256.New()
+	ar := &archiveReader{
+		r:      io.TeeReader(in, hasher),
+		hasher: hasher,
+	}
+	header := make([]byte, len(magic)+1+len(ar.genesisID))
+	if m := header[:len(magic)]; ar.read(m) != nil || string(m) != magic {
+		return nil, errors.New("magic mismatch")
+	}
+	if err := ar.read(header[len(magic):]); err != nil {
+		return nil, errors.Wrap(err, "read header")
+	}
+	if ver := header[len(magic)]; ver != archiveVersion {
+		return nil, fmt.Errorf("unsupported version %v", ver)
+	}
+	copy(ar.genesisID[:], header[len(magic)+1:])
+	return ar, nil
+}
+
+// GenesisID returns the genesis id of the chain which the archived data belongs to.
+func (ar *archiveReader) GenesisID() thor.Bytes32 {
+	return ar.genesisID
+}
+
+func (ar *archiveReader) read(buf []byte) error {
+	n, err := io.ReadFull(ar.r, buf)
+	ar.offset += uint64(n)
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
+
+// ReadRecord returns the next record and its offset. io.EOF returned at the end of records, and then
+// the extra data should be read by ReadExtra.
+func (ar *archiveReader) ReadRecord() ([]byte, uint64, error) {
+	offset := ar.offset
+
+	var sizeBuf [4]byte
+	if err := ar.read(sizeBuf[:]); err != nil {
+		return nil, 0, err
+	}
+	size := binary.BigEndian.Uint32(sizeBuf[:])
+	if size == 0 {
+		return nil, 0, io.EOF
+	}
+	// records are bounded by the size of p2p messages, which are larger than any block
+	if size > proto.MaxMsgSize {
+		return nil, 0, fmt.Errorf("record too large: %v", size)
+	}
+
+	data := make([]byte, size)
+	if err := ar.read(data); err != nil {
+		return nil, 0, err
+	}
+	return data, offset, nil
+}
+
+// ReadExtra reads the extra data following the end of records.
+func (ar *archiveReader) ReadExtra(buf []byte) error {
+	return ar.read(buf)
+}
+
+// Verify reads the trailer and verifies the checksum. It should be called after all extra data read.
+func (ar *archiveReader) Verify() error {
+	sum := ar.hasher.Sum(nil)
+	checksum := make([]byte, len(sum))
+	if err := ar.read(checksum); err != nil {
+		return err
+	}
+	if !bytes.Equal(checksum, sum) {
+		return errors.New("checksum mismatch")
+	}
+	// reading to the end also verifies the gzip checksum
+	if _, err := io.ReadFull(ar.r, checksum[:1]); err != io.EOF {
+		if err == nil {
+			return errors.New("unexpected data after trailer")
+		}
+		return err
+	}
+	return nil
+}
+
+// The block archive is the archive file of exported blocks. Each record is an RLP encoded block, and
+// the extra data is the index, which holds the offset of each block record for random access to
+// uncompressed archives:
+//
+//	index: count uint32 | (offset uint64)...
+
+// blockArchiveWriter writes blocks into the block archive.
+type blockArchiveWriter struct {
+	*archiveWriter
+	index []uint64
+}
+
+// newBlockArchiveWriter creates the writer and writes the header.
+func newBlockArchiveWriter(out io.Writer, genesisID thor.Bytes32) (*blockArchiveWriter, error) {
+	aw, err := newArchiveWriter(out, blockArchiveMagic, genesisID)
+	if err != nil {
+		return nil, err
+	}
+	return &blockArchiveWriter{archiveWriter: aw}, nil
+}
+
+// Write writes a block record.
+func (aw *blockArchiveWriter) Write(blk *block.Block) error {
+	data, err := rlp.EncodeToBytes(blk)
+	if err != nil {
+		return err
+	}
+	offset, err := aw.WriteRecord(data)
+	if err != nil {
+		return err
+	}
+	aw.index = append(aw.index, offset)
+	return nil
+}
+
+// Finish writes the index and the trailer. The output is not flushed or closed.
+func (aw *blockArchiveWriter) Finish() error {
+	buf := binary.BigEndian.AppendUint32(nil, uint32(len(aw.index)))
+	for _, offset := range aw.index {
+		buf = binary.BigEndian.AppendUint64(buf, offset)
+	}
+	return aw.archiveWriter.Finish(buf)
+}
+
+// blockArchiveReader reads blocks from the block archive sequentially, and verifies the index and
+// the checksum after all blocks read.
+type blockArchiveReader struct {
+	*archiveReader
+	index []uint64
+	done  bool
+}
+
+// newBlockArchiveReader creates the reader and reads the header.
+func newBlockArchiveReader(in io.Reader) (*blockArchiveReader, error) {
+	ar, err := newArchiveReader(in, blockArchiveMagic)
+	if err != nil {
+		return nil, errors.WithMessage(err, "not a block archive")
+	}
+	return &blockArchiveReader{archiveReader: ar}, nil
+}
+
+// Next returns the next block. io.EOF returned after all blocks read and the archive verified.
+func (ar *blockArchiveReader) Next() (*block.Block, error) {
+	if ar.done {
+		return nil, io.EOF
+	}
+	data, offset, err := ar.ReadRecord()
+	if err != nil {
+		if err == io.EOF {
+			if err := ar.verify(); err != nil {
+				return nil, err
+			}
+			ar.done = true
+		}
+		return nil, err
+	}
+
+	var blk block.Block
+	if err := rlp.DecodeBytes(data, &blk); err != nil {
+		return nil, errors.Wrapf(err, "decode block at offset %v", offset)
+	}
+	ar.index = append(ar.index, offset)
+	return &blk, nil
+}
+
+// verify verifies the index and the checksum.
+func (ar *blockArchiveReader) verify() error {
+	var buf [8]byte
+	if err := ar.ReadExtra(buf[:4]); err != nil {
+		return err
+	}
+	if count := binary.BigEndian.Uint32(buf[:4]); int(count) != len(ar.index) {
+		return fmt.Errorf("index mismatch, %v blocks indexed but %v read", count, len(ar.index))
+	}
+	for i, offset := range ar.index {
+		if err := ar.ReadExtra(buf[:]); err != nil {
+			return err
+		}
+		if binary.BigEndian.Uint64(buf[:]) != offset {
+			return fmt.Errorf("index mismatch at block %v", i)
+		}
+	}
+	return ar.Verify()
+}
@@ -0,0 +1,527 @@
+// Copyright (c) 2025 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/pkg/errors"
+	"gopkg.in/urfave/cli.v1"
+
+	"github.com/vechain/thor/v2/chain"
+	"github.com/vechain/thor/v2/genesis"
+	"github.com/vechain/thor/v2/log"
+	"github.com/vechain/thor/v2/muxdb"
+	"github.com/vechain/thor/v2/state"
+	"github.com/vechain/thor/v2/thor"
+	"github.com/vechain/thor/v2/trie"
+)
+
+const (
+	migrationSampleHeights = 8    // count of block heights sampled to verify the migrated database.
+	migrationSampleNodes   = 1000 // count of nodes compared of each sampled state trie.
+
+	checkLogInterval = 10 * time.Second // interval of logging the progress of database check.
+)
+
+func dbMigrateAction(ctx *cli.Context) error {
+	exitSignal := handleExitSignal()
+	defer func() { log.Info("exited") }()
+
+	if _, err := initLogger(ctx); err != nil {
+		return err
+	}
+
+	from, to := ctx.String(fromEngineFlag.Name), ctx.String(toEngineFlag.Name)
+	if from == to {
+		return fmt.Errorf("can't migrate from %v to the same engine", from)
+	}
+
+	gene, _, err := selectGenesis(ctx)
+	if err != nil {
+		return err
+	}
+	instanceDir, err := makeInstanceDir(ctx, gene)
+	if err != nil {
+		return err
+	}
+
+	var (
+		srcPath    = filepath.Join(instanceDir, "main.db")
+		dstPath    = srcPath + ".migrating"
+		backupPath = srcPath + "." + from
+	)
+	if _, err := os.Stat(backupPath); err == nil {
+		return fmt.Errorf("backup path [%v] already exists", backupPath)
+	}
+	// avoid creating an empty one
+	if _, err := os.Stat(srcPath); err != nil {
+		return errors.Wrap(err, "main database")
+	}
+
+	src, err := openMainDBWithEngine(ctx, srcPath, from)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if src != nil {
+			src.Close()
+		}
+	}()
+	dst, err := openMainDBWithEngine(ctx, dstPath, to)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if dst != nil {
+			dst.Close()
+		}
+	}()
+
+	log.Info("start migrating main database", "from", from, "to", to)
+	if err := src.MigrateTo(exitSignal, dst); err != nil {
+		return errors.Wrap(err, "migrate")
+	}
+	if err := verifyMigration(gene, src, dst); err != nil {
+		return errors.Wrap(err, "verify migration")
+	}
+	if err := dst.FinishMigration(); err != nil {
+		return err
+	}
+
+	err = src.Close()
+	src = nil
+	if err != nil {
+		return errors.Wrap(err, "close source database")
+	}
+	err = dst.Close()
+	dst = nil
+	if err != nil {
+		return errors.Wrap(err, "close migrated database")
+	}
+
+	if err := os.Rename(srcPath, backupPath); err != nil {
+		return errors.Wrap(err, "backup source database")
+	}
+	if err := os.Rename(dstPath, srcPath); err != nil {
+		return errors.Wrap(err, "replace source database")
+	}
+	log.Info("main database migrated, the source one is kept as backup",
+		"backup", backupPath,
+		"hint", fmt.Sprintf("run with --%v %v, and remove the backup once things go well", dbEngineFlag.Name, to))
+	return nil
+}
+
+// openRepository opens the chain repository in the existing database.
+func openRepository(gene *genesis.Genesis, db *muxdb.MuxDB) (*chain.Repository, error) {
+	genesisBlock, _, _, err := gene.Build(state.NewStater(muxdb.NewMem()))
+	if err != nil {
+		return nil, errors.Wrap(err, "build genesis block")
+	}
+	return chain.NewRepository(db, genesisBlock)
+}
+
+// verifyMigration compares the migrated database with the source one, by sampling block summaries
+// and state tries at several heights.
+func verifyMigration(gene *genesis.Genesis, src, dst *muxdb.MuxDB) error {
+	srcRepo, err := openRepository(gene, src)
+	if err != nil {
+		return err
+	}
+	dstRepo, err := openRepository(gene, dst)
+	if err != nil {
+		return err
+	}
+
+	best := srcRepo.BestBlockSummary()
+	if dstBest := dstRepo.BestBlockSummary(); dstBest.Header.ID() != best.Header.ID() {
+		return fmt.Errorf("best block mismatch, want %v got %v", best.Header.ID(), dstBest.Header.ID())
+	}
+
+	var (
+		srcChain = srcRepo.NewBestChain()
+		dstChain = dstRepo.NewBestChain()
+		bestNum  = best.Header.Number()
+		verified = 0
+	)
+	for i := range migrationSampleHeights {
+		num := bestNum - uint32(uint64(bestNum)*uint64(i)/migrationSampleHeights)
+
+		summary, err := srcChain.GetBlockSummary(num)
+		if err != nil {
+			return err
+		}
+		dstSummary, err := dstChain.GetBlockSummary(num)
+		if err != nil {
+			return errors.Wrapf(err, "get block summary #%v", num)
+		}
+		if dstSummary.Header.ID() != summary.Header.ID() {
+			return fmt.Errorf("block #%v mismatch", num)
+		}
+
+		ok, err := compareStateTries(src, dst, summary.Root())
+		if err != nil {
+			return errors.Wrapf(err, "state of block #%v", num)
+		}
+		if !ok {
+			log.Debug("state pruned, verification skipped", "block", num)
+			continue
+		}
+		log.Info("state verified", "block", num, "root", summary.Header.StateRoot())
+		verified++
+	}
+	if verified == 0 {
+		return errors.New("no state verified")
+	}
+	return nil
+}
+
+// compareStateTries compares leading nodes of the account trie, and of the first storage trie met in them,
+// in the two databases. The migrated nodes are hash checked, so that the account trie is anchored to the
+// state root. False returned if the state is not available in the source database.
+func compareStateTries(src, dst *muxdb.MuxDB, root trie.Root) (bool, error) {
+	var (
+		acc  state.Account
+		meta state.AccountMetadata
+	)
+	ok, err := compareTries(src, dst, muxdb.AccountTrieName, root, func(leaf *trie.Leaf) error {
+		if len(acc.StorageRoot) > 0 {
+			return nil
+		}
+		if err := rlp.DecodeBytes(leaf.Value, &acc); err != nil {
+			return errors.Wrap(err, "decode account")
+		}
+		if len(acc.StorageRoot) == 0 {
+			return nil
+		}
+		return errors.Wrap(rlp.DecodeBytes(leaf.Meta, &meta), "decode account metadata")
+	})
+	if !ok || err != nil || len(acc.StorageRoot) == 0 {
+		return ok, err
+	}
+
+	ok, err = compareTries(src, dst, state.StorageTrieName(meta.StorageID), trie.Root{
+		Hash: thor.BytesToBytes32(acc.StorageRoot),
+		Ver:  trie.Version{Major: meta.StorageMajorVer, Minor: meta.StorageMinorVer},
+	}, nil)
+	return ok, errors.WithMessage(err, "storage trie")
+}
+
+// compareTries compares leading nodes of the named trie in the two databases, and checks the hashes of
+// the nodes in the destination one. False returned if the root node is not available in the source database.
+func compareTries(src, dst *muxdb.MuxDB, name string, root trie.Root, handleLeaf func(leaf *trie.Leaf) error) (bool, error) {
+	srcTrie, dstTrie := src.NewTrie(name, root), dst.NewTrie(name, root)
+	srcTrie.SetNoFillCache(true)
+	dstTrie.SetNoFillCache(true)
+
+	srcIter, dstIter := srcTrie.NodeIterator(nil, 0), dstTrie.NodeIterator(nil, 0)
+	for i := range migrationSampleNodes {
+		srcNext, dstNext := srcIter.Next(true), dstIter.Next(true)
+		if err := srcIter.Error(); err != nil {
+			if i == 0 {
+				// root node pruned
+				return false, nil
+			}
+			return false, err
+		}
+		if err := dstIter.Error(); err != nil {
+			return false, err
+		}
+		if srcNext != dstNext {
+			return false, errors.New("node count mismatch")
+		}
+		if !srcNext {
+			break
+		}
+
+		srcBlob, srcVer, err := srcIter.Blob()
+		if err != nil {
+			return false, err
+		}
+		dstBlob, dstVer, err := dstIter.Blob()
+		if err != nil {
+			return false, err
+		}
+		if !bytes.Equal(srcIter.Path(), dstIter.Path()) || srcVer != dstVer || !bytes.Equal(srcBlob, dstBlob) {
+			return false, fmt.Errorf("node mismatch at path %x", srcIter.Path())
+		}
+		// the hash of the root node is the given one, and the others are referenced by their verified parents
+		if hash := dstIter.Hash(); !hash.IsZero() {
+			if got, err := trie.HashBlob(dstBlob); err != nil || got != hash {
+				return false, fmt.Errorf("node hash mismatch at path %x", dstIter.Path())
+			}
+		}
+		if leaf := srcIter.Leaf(); leaf != nil && handleLeaf != nil {
+			if err := handleLeaf(leaf); err != nil {
+				return false, err
+			}
+		}
+	}
+	return true, nil
+}
+
+func dbCheckAction(ctx *cli.Context) error {
+	exitSignal := handleExitSignal()
+	defer func() { log.Info("exited") }()
+
+	if _, err := initLogger(ctx); err != nil {
+		return err
+	}
+
+	gene, _, err := selectGenesis(ctx)
+	if err != nil {
+		return err
+	}
+	instanceDir, err := makeInstanceDir(ctx, gene)
+	if err != nil {
+		return err
+	}
+	// avoid creating an empty one
+	if _, err := os.Stat(filepath.Join(instanceDir, "main.db")); err != nil {
+		return errors.Wrap(err, "main database")
+	}
+
+	db, err := openMainDB(ctx, instanceDir)
+	if err != nil {
+		return err
+	}
+	defer func() { log.Info("closing main database..."); db.Close() }()
+
+	if ctx.IsSet(compactFlag.Name) {
+		var spaces []string
+		if val := ctx.String(compactFlag.Name); val != "all" {
+			spaces = strings.Split(val, ",")
+		}
+		log.Info("compacting main database", "spaces", ctx.String(compactFlag.Name))
+		startTime := time.Now()
+		if err := db.Compact(spaces...); err != nil {
+			return errors.Wrap(err, "compact")
+		}
+		log.Info("main database compacted", "et", time.Since(startTime))
+	}
+
+	repo, err := openRepository(gene, db)
+	if err != nil {
+		return err
+	}
+	head := repo.BestBlockSummary()
+	if ctx.IsSet(checkHeightFlag.Name) {
+		height := ctx.Uint64(checkHeightFlag.Name)
+		if height > uint64(head.Header.Number()) {
+			return fmt.Errorf("height %v beyond the best block #%v", height, head.Header.Number())
+		}
+		if head, err = repo.NewBestChain().GetBlockSummary(uint32(height)); err != nil {
+			return errors.Wrapf(err, "get block summary #%v", height)
+		}
+	}
+	if head.Header.Number() < db.TrieHistoryPrunedBefore() {
+		return fmt.Errorf("state of block #%v pruned, check a newer one", head.Header.Number())
+	}
+
+	checker := newDBChecker(exitSignal, db, repo)
+	log.Info("checking chain", "head", head.Header.Number())
+	if err := checker.CheckChain(head); err != nil {
+		return errors.Wrap(err, "check chain")
+	}
+	log.Info("checking state", "block", head.Header.Number(), "root", head.Header.StateRoot())
+	if err := checker.CheckState(head.Root()); err != nil {
+		return errors.Wrap(err, "check state")
+	}
+
+	if checker.issues > 0 {
+		return fmt.Errorf("%v issue(s) found", checker.issues)
+	}
+	log.Info("no issue found")
+	return nil
+}
+
+// dbChecker checks the integrity of the main database, and counts the issues found.
+type dbChecker struct {
+	ctx     context.Context
+	db      *muxdb.MuxDB
+	repo    *chain.Repository
+	issues  int
+	lastLog time.Time
+}
+
+func newDBChecker(ctx context.Context, db *muxdb.MuxDB, repo *chain.Repository) *dbChecker {
+	return &dbChecker{ctx: ctx, db: db, repo: repo, lastLog: time.Now()}
+}
+
+// report reports an issue.
+func (c *dbChecker) report(msg string, ctx ...any) {
+	c.issues++
+	log.Warn(msg, ctx...)
+}
+
+// tick checks the context, and logs the progress periodically.
+func (c *dbChecker) tick(msg string, ctx ...any) error {
+	select {
+	case <-c.ctx.Done():
+		return c.ctx.Err()
+	default:
+	}
+	if time.Since(c.lastLog) >= checkLogInterval {
+		c.lastLog = time.Now()
+		log.Info(msg, ctx...)
+	}
+	return nil
+}
+
+// CheckChain verifies block summaries from genesis to the head block, and the index and bodies of their txs.
+func (c *dbChecker) CheckChain(head *chain.BlockSummary) error {
+	var (
+		ch            = c.repo.NewChain(head.Header.ID())
+		expiredBefore = c.repo.HistoryExpiredBefore()
+		parentID      thor.Bytes32
+	)
+	for num := uint32(0); num <= head.Header.Number(); num++ {
+		if err := c.tick("checking chain", "block", num); err != nil {
+			return err
+		}
+		summary, err := ch.GetBlockSummary(num)
+		if err != nil {
+			c.report("failed to get block summary", "block", num, "err", err)
+			parentID = thor.Bytes32{}
+			continue
+		}
+		header := summary.Header
+		if header.Number() != num {
+			c.report("block number mismatch", "block", num, "got", header.Number())
+		}
+		if num > 0 && !parentID.IsZero() && header.ParentID() != parentID {
+			c.report("parent id mismatch", "block", num, "want", parentID, "got", header.ParentID())
+		}
+		parentID = header.ID()
+
+		c.checkTxs(ch, summary, num >= expiredBefore)
+	}
+	return nil
+}
+
+// checkTxs verifies tx metadata indexed for the block, and txs and receipts if not expired.
+func (c *dbChecker) checkTxs(ch *chain.Chain, summary *chain.BlockSummary, withBodies bool) {
+	header := summary.Header
+	for i, txID := range summary.Txs {
+		meta, err := ch.GetTransactionMeta(txID)
+		if err != nil {
+			c.report("failed to get tx meta", "block", header.Number(), "tx", txID, "err", err)
+			continue
+		}
+		if meta.BlockNum != header.Number() || meta.BlockConflicts != summary.Conflicts || meta.Index != uint64(i) {
+			c.report("tx meta mismatch", "block", header.Number(), "tx", txID)
+		}
+	}
+	if !withBodies || len(summary.Txs) == 0 {
+		return
+	}
+
+	txs, err := c.repo.GetBlockTransactions(header.ID())
+	if err != nil {
+		c.report("failed to get txs", "block", header.Number(), "err", err)
+	} else if txs.RootHash() != header.TxsRoot() {
+		c.report("txs root mismatch", "block", header.Number())
+	}
+	receipts, err := c.repo.GetBlockReceipts(header.ID())
+	if err != nil {
+		c.report("failed to get receipts", "block", header.Number(), "err", err)
+	} else if receipts.RootHash() != header.ReceiptsRoot() {
+		c.report("receipts root mismatch", "block", header.Number())
+	}
+}
+
+// CheckState walks the account trie and all storage tries of the state root.
+// Dangling nodes are out of scope, since nodes of other blocks, which the state doesn't reference,
+// are legitimately kept until pruned.
+func (c *dbChecker) CheckState(root trie.Root) error {
+	var accounts, storages int
+	accTrie := c.db.NewTrie(muxdb.AccountTrieName, root)
+	accTrie.SetNoFillCache(true)
+
+	if err := c.checkTrie("account", accTrie, func(key []byte, leaf *trie.Leaf) error {
+		accounts++
+		if err := c.tick("checking state", "accounts", accounts, "storages", storages); err != nil {
+			return err
+		}
+
+		var (
+			acc  state.Account
+			meta state.AccountMetadata
+		)
+		if err := rlp.DecodeBytes(leaf.Value, &acc); err != nil {
+			c.report("failed to decode account", "key", fmt.Sprintf("%x", key), "err", err)
+			return nil
+		}
+		if len(acc.StorageRoot) == 0 {
+			return nil
+		}
+		if err := rlp.DecodeBytes(leaf.Meta, &meta); err != nil {
+			c.report("failed to decode account metadata", "key", fmt.Sprintf("%x", key), "err", err)
+			return nil
+		}
+
+		storages++
+		sTrie := c.db.NewTrie(
+			state.StorageTrieName(meta.StorageID),
+			trie.Root{
+				Hash: thor.BytesToBytes32(acc.StorageRoot),
+				Ver: trie.Version{
+					Major: meta.StorageMajorVer,
+					Minor: meta.StorageMinorVer,
+				},
+			})
+		sTrie.SetNoFillCache(true)
+		return c.checkTrie(fmt.Sprintf("storage(%x)", key), sTrie, nil)
+	}); err != nil {
+		return err
+	}
+	log.Info("state checked", "accounts", accounts, "storages", storages)
+	return nil
+}
+
+// checkTrie walks all nodes of the trie, and checks whether nodes are missing or mismatch hashes referenced
+// by their parents. The walking of a trie stops at the first missing node.
+func (c *dbChecker) checkTrie(name string, tr *muxdb.Trie, handleLeaf func(key []byte, leaf *trie.Leaf) error) error {
+	// undecodable node panics
+	defer func() {
+		if e := recover(); e != nil {
+			c.report("corrupted trie node", "trie", name, "err", e)
+		}
+	}()
+
+	iter := tr.NodeIterator(nil, 0)
+	for iter.Next(true) {
+		if hash := iter.Hash(); !hash.IsZero() {
+			blob, _, err := iter.Blob()
+			if err != nil {
+				return err
+			}
+			if got, err := trie.HashBlob(blob); err != nil || got != hash {
+				c.report("trie node hash mismatch", "trie", name, "path", fmt.Sprintf("%x", iter.Path()))
+			}
+		}
+		if leaf := iter.Leaf(); leaf != nil && handleLeaf != nil {
+			if err := handleLeaf(iter.LeafKey(), leaf); err != nil {
+				return err
+			}
+		}
+	}
+	if err := iter.Error(); err != nil {
+		var missing *trie.MissingNodeError
+		if !errors.As(err, &missing) {
+			return err
+		}
+		c.report("missing trie node", "trie", name, "err", err)
+	}
+	return nil
+}
@@ -0,0 +1,147 @@
+// Copyright (c) 2025 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package main
+
+import (
+	"context"
+	"encoding/binary"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/syndtr/goleveldb/leveldb/util"
+
+	"github.com/vechain/thor/v2/chain"
+	"github.com/vechain/thor/v2/genesis"
+	"github.com/vechain/thor/v2/kv"
+	"github.com/vechain/thor/v2/muxdb"
+	"github.com/vechain/thor/v2/state"
+	"github.com/vechain/thor/v2/test/testchain"
+	"github.com/vechain/thor/v2/thor"
+	"github.com/vechain/thor/v2/trie"
+	"github.com/vechain/thor/v2/tx"
+)
+
+func TestVerifyMigration(t *testing.T) {
+	chain, err := testchain.NewDefault()
+	require.NoError(t, err)
+	for range 10 {
+		require.NoError(t, chain.MintBlock())
+	}
+	src := chain.Database()
+
+	dst := muxdb.NewMemWithEngine(muxdb.EnginePebble)
+	defer dst.Close()
+
+	// nothing migrated
+	assert.Error(t, verifyMigration(chain.Genesis(), src, dst))
+
+	require.NoError(t, src.MigrateTo(context.Background(), dst))
+	assert.NoError(t, verifyMigration(chain.Genesis(), src, dst))
+}
+
+func TestCompareStateTries(t *testing.T) {
+	chain, err := testchain.NewDefault()
+	require.NoError(t, err)
+	require.NoError(t, chain.MintBlock())
+	root := chain.Repo().BestBlockSummary().Root()
+
+	// overwrites the root node of the named trie with the one of another trie
+	tamper := func(db *muxdb.MuxDB, name string, ver trie.Version) {
+		tr := db.NewTrie(name, trie.Root{})
+		tr.SetNoFillCache(true)
+		require.NoError(t, tr.Update([]byte("key"), []byte("value"), nil))
+		require.NoError(t, tr.Commit(ver, false))
+	}
+	migrate := func(src *muxdb.MuxDB) *muxdb.MuxDB {
+		dst := muxdb.NewMemWithEngine(muxdb.EnginePebble)
+		t.Cleanup(func() { dst.Close() })
+		require.NoError(t, src.MigrateTo(context.Background(), dst))
+		return dst
+	}
+
+	src := migrate(chain.Database())
+	ok, err := compareStateTries(src, migrate(src), root)
+	assert.True(t, ok)
+	assert.NoError(t, err)
+
+	// storage trie of the first contract met
+	var (
+		acc  state.Account
+		meta state.AccountMetadata
+	)
+	iter := src.NewTrie(muxdb.AccountTrieName, root).NodeIterator(nil, 0)
+	for len(acc.StorageRoot) == 0 && iter.Next(true) {
+		if leaf := iter.Leaf(); leaf != nil {
+			require.NoError(t, rlp.DecodeBytes(leaf.Value, &acc))
+			if len(acc.StorageRoot) > 0 {
+				require.NoError(t, rlp.DecodeBytes(leaf.Meta, &meta))
+			}
+		}
+	}
+	require.NotEmpty(t, acc.StorageRoot)
+	dst := migrate(src)
+	tamper(dst, state.StorageTrieName(meta.StorageID), trie.Version{Major: meta.StorageMajorVer, Minor: meta.StorageMinorVer})
+	_, err = compareStateTries(src, dst, root)
+	assert.ErrorContains(t, err, "storage trie")
+
+	// same in both, but not the one of the state root
+	tamper(src, muxdb.AccountTrieName, root.Ver)
+	_, err = compareStateTries(src, migrate(src), root)
+	assert.ErrorContains(t, err, "node hash mismatch")
+}
+
+func TestDBChecker(t *testing.T) {
+	thorChain, err := testchain.NewDefault()
+	require.NoError(t, err)
+	to := thor.BytesToAddress([]byte("to"))
+	for i := range 10 {
+		trx := tx.NewBuilder(tx.TypeLegacy).
+			ChainTag(thorChain.Repo().ChainTag()).
+			Expiration(100).
+			Gas(21000).
+			Nonce(uint64(i)).
+			Clause(tx.NewClause(&to).WithValue(big.NewInt(1))).
+			Build()
+		require.NoError(t, thorChain.MintBlock(tx.MustSign(trx, genesis.DevAccounts()[0].PrivateKey)))
+	}
+	db, repo := thorChain.Database(), thorChain.Repo()
+	best := repo.BestBlockSummary()
+
+	checker := newDBChecker(context.Background(), db, repo)
+	require.NoError(t, checker.CheckChain(best))
+	require.NoError(t, checker.CheckState(best.Root()))
+	assert.Zero(t, checker.issues)
+
+	// expired txs are skipped
+	require.NoError(t, repo.ExpireHistory(context.Background(), 5))
+	require.NoError(t, checker.CheckChain(best))
+	assert.Zero(t, checker.issues)
+
+	// txs of a block lost
+	summary, err := repo.NewBestChain().GetBlockSummary(6)
+	require.NoError(t, err)
+	bodies := db.NewStore("chain.body")
+	iter := bodies.Iterate(kv.Range(*util.BytesPrefix(binary.BigEndian.AppendUint32(nil, 6))))
+	for iter.Next() {
+		require.NoError(t, bodies.Delete(iter.Key()))
+	}
+	iter.Release()
+	// reopened to drop cached txs
+	repo, err = chain.NewRepository(db, thorChain.GenesisBlock())
+	require.NoError(t, err)
+	checker = newDBChecker(context.Background(), db, repo)
+	require.NoError(t, checker.CheckChain(best))
+	assert.Equal(t, 2, checker.issues, "txs and receipts of %v", summary.Header.ID())
+
+	// state nodes lost
+	checker = newDBChecker(context.Background(), db, repo)
+	require.NoError(t, db.DeleteTrieHistoryNodes(context.Background(), 0, best.Header.Number()+1))
+	require.NoError(t, checker.CheckState(best.Root()))
+	assert.Equal(t, 1, checker.issues)
+}
@@ -0,0 +1,348 @@
+// Copyright (c) 2025 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package main
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"gopkg.in/urfave/cli.v1"
+
+	"github.com/vechain/thor/v2/bft"
+	"github.com/vechain/thor/v2/block"
+	"github.com/vechain/thor/v2/chain"
+	"github.com/vechain/thor/v2/consensus"
+	"github.com/vechain/thor/v2/log"
+	"github.com/vechain/thor/v2/state"
+	"github.com/vechain/thor/v2/thor"
+)
+
+const blockArchiveLogInterval = 10 * time.Second // interval of logging the progress of export and import.
+
+func exportAction(ctx *cli.Context) error {
+	exitSignal := handleExitSignal()
+	defer func() { log.Info("exited") }()
+
+	if _, err := initLogger(ctx); err != nil {
+		return err
+	}
+
+	out := ctx.String(exportOutFlag.Name)
+	if out == "" {
+		return fmt.Errorf("missing output file, use --%v to specify", exportOutFlag.Name)
+	}
+
+	gene, _, err := selectGenesis(ctx)
+	if err != nil {
+		return err
+	}
+	instanceDir, err := makeInstanceDir(ctx, gene)
+	if err != nil {
+		return err
+	}
+	// avoid creating an empty one
+	if _, err := os.Stat(filepath.Join(instanceDir, "main.db")); err != nil {
+		return errors.Wrap(err, "main database")
+	}
+
+	db, err := openMainDB(ctx, instanceDir)
+	if err != nil {
+		return err
+	}
+	defer func() { log.Info("closing main database..."); db.Close() }()
+
+	repo, err := openRepository(gene, db)
+	if err != nil {
+		return err
+	}
+
+	bestNum := repo.BestBlockSummary().Header.Number()
+	from, to := ctx.Uint64(exportFromFlag.Name), uint64(bestNum)
+	if ctx.IsSet(exportToFlag.Name) {
+		if to = ctx.Uint64(exportToFlag.Name); to > uint64(bestNum) {
+			return fmt.Errorf("block #%v beyond the best block #%v", to, bestNum)
+		}
+	}
+	if from > to {
+		return fmt.Errorf("invalid block range #%v-#%v", from, to)
+	}
+	if expired := repo.HistoryExpiredBefore(); from < uint64(expired) {
+		return fmt.Errorf("txs of blocks before #%v expired", expired)
+	}
+
+	log.Info("exporting blocks", "from", from, "to", to, "out", out)
+	if err := writeArchiveFile(out, func(w io.Writer) error {
+		return exportBlocks(exitSignal, repo, uint32(from), uint32(to), w)
+	}); err != nil {
+		return err
+	}
+	log.Info("blocks exported", "count", to-from+1)
+	return nil
+}
+
+// writeArchiveFile creates the file of the given path, and writes it by fn. The file is gzip compressed if
+// the path ends with .gz, and removed if anything failed.
+func writeArchiveFile(path string, fn func(w io.Writer) error) (err error) {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		f.Close()
+		if err != nil {
+			os.Remove(path)
+		}
+	}()
+
+	var (
+		bw = bufio.NewWriter(f)
+		w  = io.Writer(bw)
+		zw *gzip.Writer
+	)
+	if strings.HasSuffix(path, ".gz") {
+		zw = gzip.NewWriter(bw)
+		w = zw
+	}
+	if err := fn(w); err != nil {
+		return err
+	}
+	if zw != nil {
+		if err := zw.Close(); err != nil {
+			return err
+		}
+	}
+	if err := bw.Flush(); err != nil {
+		return err
+	}
+	return f.Sync()
+}
+
+// exportBlocks writes blocks of the best chain in the given range into the block archive.
+func exportBlocks(ctx context.Context, repo *chain.Repository, from, to uint32, w io.Writer) error {
+	aw, err := newBlockArchiveWriter(w, repo.GenesisBlock().Header().ID())
+	if err != nil {
+		return err
+	}
+
+	bestChain := repo.NewBestChain()
+	lastLog := time.Now()
+	for num := uint64(from); num <= uint64(to); num++ {
+		blk, err := bestChain.GetBlock(uint32(num))
+		if err != nil {
+			return errors.Wrapf(err, "get block #%v", num)
+		}
+		if err := aw.Write(blk); err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+		if time.Since(lastLog) > blockArchiveLogInterval {
+			log.Info("exporting blocks", "block", num)
+			lastLog = time.Now()
+		}
+	}
+	return aw.Finish()
+}
+
+func importAction(ctx *cli.Context) error {
+	exitSignal := handleExitSignal()
+	defer func() { log.Info("exited") }()
+
+	if _, err := initLogger(ctx); err != nil {
+		return err
+	}
+
+	path := ctx.Args().First()
+	if path == "" {
+		return errors.New("missing block archive file")
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	ar, err := newBlockArchiveReader(f)
+	if err != nil {
+		return errors.Wrapf(err, "open block archive [%v]", path)
+	}
+
+	gene, forkConfig, err := selectGenesis(ctx)
+	if err != nil {
+		return err
+	}
+	if ar.GenesisID() != gene.ID() {
+		return fmt.Errorf("genesis mismatch, blocks of %v found in the archive", ar.GenesisID())
+	}
+	instanceDir, err := makeInstanceDir(ctx, gene)
+	if err != nil {
+		return err
+	}
+
+	mainDB, err := openMainDB(ctx, instanceDir)
+	if err != nil {
+		return err
+	}
+	defer func() { log.Info("closing main database..."); mainDB.Close() }()
+
+	logDB, err := openLogDB(instanceDir, false)
+	if err != nil {
+		return err
+	}
+	defer func() { log.Info("closing log database..."); logDB.Close() }()
+
+	repo, err := initChainRepository(gene, mainDB, logDB)
+	if err != nil {
+		return err
+	}
+	if err := checkSyncMode(syncModeFull, repo); err != nil {
+		return err
+	}
+
+	stater := state.NewStater(mainDB)
+	if ctx.Bool(archiveFlag.Name) {
+		archive := state.NewArchive(mainDB)
+		if err := initStateArchive(exitSignal, repo, archive); err != nil {
+			return err
+		}
+		stater = state.NewArchiveStater(mainDB, archive)
+	}
+
+	bftEngine, err := bft.NewEngine(repo, mainDB, forkConfig, thor.Address{})
+	if err != nil {
+		return errors.Wrap(err, "init bft engine")
+	}
+
+	importer := newBlockImporter(repo, consensus.New(repo, stater, forkConfig), bftEngine, forkConfig)
+	log.Info("importing blocks", "file", path, "best", repo.BestBlockSummary().Header.Number())
+	if err := importBlocks(exitSignal, ar, importer); err != nil {
+		return err
+	}
+	// logs are written by the log db sync at startup
+	log.Info("blocks imported", "best", repo.BestBlockSummary().Header.Number())
+	return nil
+}
+
+// blockImporter processes blocks the way the node does when syncing, except that event logs are not written.
+type blockImporter struct {
+	repo       *chain.Repository
+	cons       *consensus.Consensus
+	bft        bft.Committer
+	forkConfig *thor.ForkConfig
+}
+
+func newBlockImporter(repo *chain.Repository, cons *consensus.Consensus, bft bft.Committer, forkConfig *thor.ForkConfig) *blockImporter {
+	return &blockImporter{repo, cons, bft, forkConfig}
+}
+
+// Import processes and adds the block. False returned if the block is already in the chain.
+func (im *blockImporter) Import(blk *block.Block) (bool, error) {
+	header := blk.Header()
+	if _, err := im.repo.GetBlockSummary(header.ID()); err == nil {
+		return false, nil
+	} else if !im.repo.IsNotFound(err) {
+		return false, err
+	}
+
+	parent, err := im.repo.GetBlockSummary(header.ParentID())
+	if err != nil {
+		if im.repo.IsNotFound(err) {
+			return false, fmt.Errorf("parent of block #%v missing", header.Number())
+		}
+		return false, err
+	}
+	if ok, err := im.bft.Accepts(header.ParentID()); err != nil {
+		return false, errors.Wrap(err, "bft accepts")
+	} else if !ok {
+		return false, fmt.Errorf("block #%v rejected by bft engine", header.Number())
+	}
+
+	conflicts, err := im.repo.ScanConflicts(header.Number())
+	if err != nil {
+		return false, err
+	}
+	stage, receipts, err := im.cons.Process(parent, blk, uint64(time.Now().Unix()), conflicts)
+	if err != nil {
+		return false, errors.Wrapf(err, "process block #%v", header.Number())
+	}
+
+	var (
+		prevBest   = im.repo.BestBlockSummary().Header
+		becomeBest bool
+	)
+	// let bft engine decide the best block after fork FINALITY
+	if header.Number() >= im.forkConfig.FINALITY && prevBest.Number() >= im.forkConfig.FINALITY {
+		if becomeBest, err = im.bft.Select(header); err != nil {
+			return false, errors.Wrap(err, "bft select")
+		}
+	} else {
+		becomeBest = header.BetterThan(prevBest)
+	}
+
+	if _, err := stage.Commit(); err != nil {
+		return false, errors.Wrap(err, "commit state")
+	}
+	if err := im.repo.AddBlock(blk, receipts, conflicts, becomeBest); err != nil {
+		return false, errors.Wrap(err, "add block")
+	}
+	if header.Number() >= im.forkConfig.FINALITY {
+		if err := im.bft.CommitBlock(header, false); err != nil {
+			return false, errors.Wrap(err, "bft commits")
+		}
+	}
+	return true, nil
+}
+
+// importBlocks imports all blocks in the block archive. Blocks already in the chain are skipped,
+// so that an interrupted import can be resumed by running again.
+func importBlocks(ctx context.Context, ar *blockArchiveReader, importer *blockImporter) error {
+	var (
+		imported, skipped int
+		lastLog           = time.Now()
+	)
+	for {
+		blk, err := ar.Next()
+		if err != nil {
+			if err == io.EOF {
+				break
+			}
+			return errors.Wrap(err, "read block archive")
+		}
+		ok, err := importer.Import(blk)
+		if err != nil {
+			return err
+		}
+		if ok {
+			imported++
+		} else {
+			skipped++
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+		if time.Since(lastLog) > blockArchiveLogInterval {
+			log.Info("importing blocks", "block", blk.Header().Number(), "imported", imported, "skipped", skipped)
+			lastLog = time.Now()
+		}
+	}
+	log.Info("block archive verified", "imported", imported, "skipped", skipped)
+	return nil
+}
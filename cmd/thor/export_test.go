@@ -0,0 +1,92 @@
+// Copyright (c) 2025 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vechain/thor/v2/consensus"
+	"github.com/vechain/thor/v2/genesis"
+	"github.com/vechain/thor/v2/test/testchain"
+	"github.com/vechain/thor/v2/thor"
+	"github.com/vechain/thor/v2/tx"
+)
+
+func TestExportImport(t *testing.T) {
+	// launched in the past, so that minted blocks are not in the future
+	src, err := testchain.NewIntegrationTestChain(genesis.DevConfig{
+		ForkConfig: &testchain.DefaultForkConfig,
+		LaunchTime: uint64(time.Now().Unix()) - 1000,
+	}, 180)
+	require.NoError(t, err)
+	to := thor.BytesToAddress([]byte("to"))
+	for i := range 10 {
+		trx := tx.NewBuilder(tx.TypeLegacy).
+			ChainTag(src.Repo().ChainTag()).
+			Expiration(100).
+			Gas(21000).
+			Nonce(uint64(i)).
+			Clause(tx.NewClause(&to).WithValue(big.NewInt(1))).
+			Build()
+		require.NoError(t, src.MintBlock(tx.MustSign(trx, genesis.DevAccounts()[0].PrivateKey)))
+	}
+	best := src.Repo().BestBlockSummary()
+
+	export := func(from, to uint32, compress bool) []byte {
+		var buf bytes.Buffer
+		if compress {
+			zw := gzip.NewWriter(&buf)
+			require.NoError(t, exportBlocks(context.Background(), src.Repo(), from, to, zw))
+			require.NoError(t, zw.Close())
+		} else {
+			require.NoError(t, exportBlocks(context.Background(), src.Repo(), from, to, &buf))
+		}
+		return buf.Bytes()
+	}
+
+	dst, err := testchain.NewIntegrationTestChainWithGenesis(src.Genesis(), src.GetForkConfig(), 180)
+	require.NoError(t, err)
+	importer := newBlockImporter(
+		dst.Repo(),
+		consensus.New(dst.Repo(), dst.Stater(), dst.GetForkConfig()),
+		dst.Engine(),
+		dst.GetForkConfig(),
+	)
+	importData := func(data []byte) error {
+		ar, err := newBlockArchiveReader(bytes.NewReader(data))
+		if err != nil {
+			return err
+		}
+		assert.Equal(t, src.Repo().GenesisBlock().Header().ID(), ar.GenesisID())
+		return importBlocks(context.Background(), ar, importer)
+	}
+
+	// interrupted
+	require.NoError(t, importData(export(0, 5, false)))
+	assert.Equal(t, uint32(5), dst.Repo().BestBlockSummary().Header.Number())
+
+	// resumed from a compressed one
+	require.NoError(t, importData(export(0, best.Header.Number(), true)))
+	assert.Equal(t, best.Header.ID(), dst.Repo().BestBlockSummary().Header.ID())
+	assert.Equal(t, best.Header.StateRoot(), dst.Repo().BestBlockSummary().Header.StateRoot())
+
+	// corrupted
+	data := export(1, 3, false)
+	data[len(data)-1] ^= 1
+	assert.ErrorContains(t, importData(data), "checksum mismatch")
+	assert.ErrorContains(t, importData(data[:len(data)-1]), "unexpected EOF")
+	assert.ErrorContains(t, importData(append(export(1, 3, false), 0)), "unexpected data")
+	_, err = newBlockArchiveReader(bytes.NewReader([]byte("not an archive, just some text")))
+	assert.ErrorContains(t, err, "not a block archive")
+}
@@ -9,6 +9,9 @@ import (
 	cli "gopkg.in/urfave/cli.v1"
 
 	"github.com/vechain/thor/v2/log"
+	"github.com/vechain/thor/v2/muxdb"
+	"github.com/vechain/thor/v2/packer"
+	"github.com/vechain/thor/v2/thor"
 )
 
 var (
@@ -172,6 +175,63 @@ var (
 		Usage: "megabytes of ram allocated to trie nodes cache",
 		Value: 4096,
 	}
+	dbEngineFlag = cli.StringFlag{
+		Name:  "db-engine",
+		Value: muxdb.EngineLevelDB,
+		Usage: "storage engine of the database (leveldb|pebble), can't be changed once the database created",
+	}
+	pruneRetainBlocksFlag = cli.Uint64Flag{
+		Name:  "prune-retain-blocks",
+		Usage: "number of recent blocks whose states are kept by the pruner, no less than the default",
+		Value: thor.MaxStateHistory,
+	}
+	historyExpiryFlag = cli.Uint64Flag{
+		Name:  "history-expiry",
+		Usage: "number of recent blocks whose txs and receipts are kept by the pruner, 0 to keep all, no less than the default retained states if set",
+	}
+	archiveFlag = cli.BoolFlag{
+		Name:  "archive",
+		Usage: "archive mode, which keeps all history and indexes state changes of each block for fast historical state queries",
+	}
+	stateSnapshotFlag = cli.BoolFlag{
+		Name:  "state-snapshot",
+		Usage: "maintain a flat snapshot of the head state to accelerate account and storage reads",
+	}
+	fromEngineFlag = cli.StringFlag{
+		Name:  "from-engine",
+		Value: muxdb.EngineLevelDB,
+		Usage: "storage engine of the database to be migrated (leveldb|pebble)",
+	}
+	toEngineFlag = cli.StringFlag{
+		Name:  "to-engine",
+		Value: muxdb.EnginePebble,
+		Usage: "storage engine of the database migrated to (leveldb|pebble)",
+	}
+	checkHeightFlag = cli.Uint64Flag{
+		Name:  "height",
+		Usage: "block height of the state to be checked (default: the best block)",
+	}
+	compactFlag = cli.StringFlag{
+		Name:  "compact",
+		Usage: "key spaces to be compacted before checking, comma separated (all|trie-hist|trie-deduped|named-store)",
+	}
+	exportFromFlag = cli.Uint64Flag{
+		Name:  "from",
+		Usage: "number of the first block to be exported",
+	}
+	exportToFlag = cli.Uint64Flag{
+		Name:  "to",
+		Usage: "number of the last block to be exported (default: the best block)",
+	}
+	exportOutFlag = cli.StringFlag{
+		Name:  "out",
+		Usage: "path of the file to be written, gzip compressed if ending with .gz",
+	}
+	snapshotBlockFlag = cli.StringFlag{
+		Name:  "block",
+		Value: "finalized",
+		Usage: "block of the state to be exported, finalized or the number of the last block of a round",
+	}
 	disablePrunerFlag = cli.BoolFlag{
 		Name:  "disable-pruner",
 		Usage: "disable state pruner to keep all history",
@@ -212,6 +272,28 @@ var (
 		Usage: "set a minimum effective priority fee for transactions to be included in the block proposed by the block proposer",
 	}
 
+	txSelectorFlag = cli.StringFlag{
+		Name:  "tx-selector",
+		Value: packer.TxSelectorDefault,
+		Usage: "strategy to select txs when packing a block (default, priority-fee, fair-share)",
+	}
+
+	parallelExecFlag = cli.BoolFlag{
+		Name:  "parallel-exec",
+		Usage: "execute txs of imported blocks speculatively in parallel (experimental)",
+	}
+
+	syncModeFlag = cli.StringFlag{
+		Name:  "sync-mode",
+		Value: "full",
+		Usage: "blockchain sync mode (full, snap), snap mode fetches the state at the trusted checkpoint from peers instead of replaying history",
+	}
+
+	trustedCheckpointFlag = cli.StringFlag{
+		Name:  "trusted-checkpoint",
+		Usage: "id of a trusted checkpoint block, which the chain synced by light or snap mode must include, required by snap mode",
+	}
+
 	// solo mode only flags
 	onDemandFlag = cli.BoolFlag{
 		Name:  "on-demand",
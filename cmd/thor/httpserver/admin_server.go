@@ -16,7 +16,10 @@ import (
 	"github.com/pkg/errors"
 
 	"github.com/vechain/thor/v2/api/admin"
+	"github.com/vechain/thor/v2/api/admin/blocktemplate"
+	"github.com/vechain/thor/v2/api/admin/caches"
 	"github.com/vechain/thor/v2/api/admin/health"
+	"github.com/vechain/thor/v2/api/admin/peers"
 	"github.com/vechain/thor/v2/chain"
 	"github.com/vechain/thor/v2/cmd/thor/node"
 	"github.com/vechain/thor/v2/comm"
@@ -29,13 +32,16 @@ func StartAdminServer(
 	p2p *comm.Communicator,
 	apiLogs *atomic.Bool,
 	master *node.Master,
+	blockTemplate *blocktemplate.BlockTemplate,
+	peers *peers.Peers,
+	caches *caches.Caches,
 ) (string, func(), error) {
 	listener, err := net.Listen("tcp", addr)
 	if err != nil {
 		return "", nil, errors.Wrapf(err, "listen admin API addr [%v]", addr)
 	}
 
-	adminHandler := admin.NewHTTPHandler(logLevel, health.New(repo, p2p), apiLogs, master)
+	adminHandler := admin.NewHTTPHandler(logLevel, health.New(repo, p2p), apiLogs, master, blockTemplate, peers, caches)
 
 	srv := &http.Server{Handler: adminHandler, ReadHeaderTimeout: time.Second, ReadTimeout: 5 * time.Second}
 	var goes sync.WaitGroup
@@ -0,0 +1,67 @@
+// Copyright (c) 2025 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package httpserver
+
+import (
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/handlers"
+	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
+
+	"github.com/vechain/thor/v2/api"
+	"github.com/vechain/thor/v2/api/light"
+	"github.com/vechain/thor/v2/api/middleware"
+	"github.com/vechain/thor/v2/api/restutil"
+	"github.com/vechain/thor/v2/chain"
+)
+
+// StartLightAPIServer starts the reduced api server of a light node.
+func StartLightAPIServer(
+	addr string,
+	repo *chain.Repository,
+	finality restutil.Finality,
+	nw api.Network,
+	allowedOrigins string,
+) (string, func(), error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return "", nil, errors.Wrapf(err, "listen API addr [%v]", addr)
+	}
+
+	origins := strings.Split(strings.TrimSpace(allowedOrigins), ",")
+	for i, o := range origins {
+		origins[i] = strings.ToLower(strings.TrimSpace(o))
+	}
+
+	router := mux.NewRouter()
+	light.New(repo, finality, nw).Mount(router, "")
+
+	router.Use(middleware.HandleRequestBodyLimit(defaultRequestBodyLimit))
+	router.Use(middleware.HandlePanics(false))
+	router.Use(middleware.HandleXGenesisID(repo.GenesisBlock().Header().ID()))
+	router.Use(middleware.HandleXThorestVersion)
+
+	router.Use(handlers.CompressHandler)
+	handler := handlers.CORS(
+		handlers.AllowedOrigins(origins),
+		handlers.AllowedHeaders([]string{"content-type", "x-genesis-id"}),
+		handlers.ExposedHeaders([]string{"x-genesis-id", "x-thorest-ver"}),
+	)(router)
+	srv := &http.Server{Handler: handler, ReadHeaderTimeout: time.Second, ReadTimeout: 5 * time.Second}
+	var goes sync.WaitGroup
+	goes.Go(func() {
+		srv.Serve(listener)
+	})
+	return "http://" + listener.Addr().String() + "/", func() {
+		srv.Close()
+		goes.Wait()
+	}, nil
+}
@@ -0,0 +1,132 @@
+// Copyright (c) 2025 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package main
+
+import (
+	"fmt"
+	"math"
+	"path/filepath"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+	"gopkg.in/urfave/cli.v1"
+
+	"github.com/vechain/thor/v2/chain"
+	"github.com/vechain/thor/v2/cmd/thor/httpserver"
+	"github.com/vechain/thor/v2/comm"
+	"github.com/vechain/thor/v2/light"
+	"github.com/vechain/thor/v2/log"
+	"github.com/vechain/thor/v2/muxdb"
+	"github.com/vechain/thor/v2/state"
+)
+
+const lightStoreName = "light.chain"
+
+func lightAction(ctx *cli.Context) error {
+	exitSignal := handleExitSignal()
+	defer func() { log.Info("exited") }()
+
+	if _, err := initLogger(ctx); err != nil {
+		return err
+	}
+
+	trusted, err := parseTrustedCheckpoint(ctx)
+	if err != nil {
+		return err
+	}
+
+	gene, forkConfig, err := selectGenesis(ctx)
+	if err != nil {
+		return err
+	}
+	instanceDir, err := makeInstanceDir(ctx, gene)
+	if err != nil {
+		return err
+	}
+
+	db, err := openLightDB(instanceDir, ctx.String(dbEngineFlag.Name))
+	if err != nil {
+		return err
+	}
+	defer func() { log.Info("closing light database..."); db.Close() }()
+
+	// the genesis state is only needed to build the genesis block
+	genesisBlock, _, _, err := gene.Build(state.NewStater(muxdb.NewMem()))
+	if err != nil {
+		return errors.Wrap(err, "build genesis block")
+	}
+	repo, err := chain.NewRepository(db, genesisBlock)
+	if err != nil {
+		return errors.Wrap(err, "initialize block chain")
+	}
+
+	lightComm := comm.NewLight(repo)
+	lightChain, err := light.New(repo, db.NewStore(lightStoreName), forkConfig, lightComm, trusted)
+	if err != nil {
+		return errors.Wrap(err, "init light chain")
+	}
+
+	p2pCommunicator, err := newP2PCommunicator(ctx, lightComm, instanceDir)
+	if err != nil {
+		return err
+	}
+
+	apiURL, srvCloser, err := httpserver.StartLightAPIServer(
+		ctx.String(apiAddrFlag.Name),
+		repo,
+		lightChain,
+		lightComm,
+		ctx.String(apiCorsFlag.Name),
+	)
+	if err != nil {
+		return err
+	}
+	defer func() { log.Info("stopping API server..."); srvCloser() }()
+
+	best := repo.BestBlockSummary().Header
+	fmt.Printf(`Starting %v
+    Network      [ %v %v ]
+    Best block   [ %v #%v @%v ]
+    Forks        [ %v ]
+    Instance dir [ %v ]
+`,
+		common.MakeName("Thor light", fullVersion()),
+		gene.ID(), gene.Name(),
+		best.ID(), best.Number(), time.Unix(int64(best.Timestamp()), 0),
+		forkConfig,
+		instanceDir,
+	)
+	printStartupMessage2(gene, apiURL, p2pCommunicator.Enode(), "", "", false)
+
+	if err := p2pCommunicator.Start(); err != nil {
+		return err
+	}
+	defer p2pCommunicator.Stop()
+
+	lightComm.Sync(exitSignal, lightChain, lightChain.HandleHeaders)
+	return nil
+}
+
+// openLightDB opens the database of a light node, which keeps headers and indices only.
+func openLightDB(dir string, engine string) (*muxdb.MuxDB, error) {
+	opts := muxdb.Options{
+		TrieNodeCacheSizeMB:        16,
+		TrieCachedNodeTTL:          30, // 5min
+		TrieDedupedPartitionFactor: math.MaxUint32,
+		TrieHistPartitionFactor:    524288,
+		OpenFilesCacheCapacity:     64,
+		ReadCacheMB:                16,
+		WriteBufferMB:              16,
+		Engine:                     engine,
+	}
+	path := filepath.Join(dir, "light.db")
+	db, err := muxdb.Open(path, &opts)
+	if err != nil {
+		return nil, errors.Wrapf(err, "open light database [%v]", path)
+	}
+	return db, nil
+}
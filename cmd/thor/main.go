@@ -22,12 +22,15 @@ import (
 	"github.com/pkg/errors"
 	"gopkg.in/urfave/cli.v1"
 
+	"github.com/vechain/thor/v2/api/admin/blocktemplate"
+	"github.com/vechain/thor/v2/api/admin/peers"
 	"github.com/vechain/thor/v2/api/doc"
 	"github.com/vechain/thor/v2/bft"
 	"github.com/vechain/thor/v2/cmd/thor/httpserver"
 	"github.com/vechain/thor/v2/cmd/thor/node"
 	"github.com/vechain/thor/v2/cmd/thor/pruner"
 	"github.com/vechain/thor/v2/cmd/thor/solo"
+	"github.com/vechain/thor/v2/comm"
 	"github.com/vechain/thor/v2/consensus"
 	"github.com/vechain/thor/v2/genesis"
 	"github.com/vechain/thor/v2/log"
@@ -119,6 +122,11 @@ func main() {
 			pprofFlag,
 			verifyLogsFlag,
 			disablePrunerFlag,
+			pruneRetainBlocksFlag,
+			historyExpiryFlag,
+			archiveFlag,
+			stateSnapshotFlag,
+			dbEngineFlag,
 			enableMetricsFlag,
 			metricsAddrFlag,
 			adminAddrFlag,
@@ -126,6 +134,10 @@ func main() {
 			txPoolLimitPerAccountFlag,
 			allowedTracersFlag,
 			minEffectivePriorityFeeFlag,
+			txSelectorFlag,
+			parallelExecFlag,
+			syncModeFlag,
+			trustedCheckpointFlag,
 		},
 		Action: defaultAction,
 		Commands: []cli.Command{
@@ -163,15 +175,40 @@ func main() {
 					txPoolLimitFlag,
 					txPoolLimitPerAccountFlag,
 					disablePrunerFlag,
+					pruneRetainBlocksFlag,
+					historyExpiryFlag,
+					dbEngineFlag,
 					enableMetricsFlag,
 					metricsAddrFlag,
 					adminAddrFlag,
 					enableAdminFlag,
 					allowedTracersFlag,
 					minEffectivePriorityFeeFlag,
+					txSelectorFlag,
 				},
 				Action: soloAction,
 			},
+			{
+				Name:  "light",
+				Usage: "client runs in light mode, which follows headers and bft finality only",
+				Flags: []cli.Flag{
+					networkFlag,
+					configDirFlag,
+					dataDirFlag,
+					apiAddrFlag,
+					apiCorsFlag,
+					maxPeersFlag,
+					p2pPortFlag,
+					natFlag,
+					bootNodeFlag,
+					allowedPeersFlag,
+					verbosityFlag,
+					jsonLogsFlag,
+					dbEngineFlag,
+					trustedCheckpointFlag,
+				},
+				Action: lightAction,
+			},
 			{
 				Name:  "master-key",
 				Usage: "master key management",
@@ -182,6 +219,124 @@ func main() {
 				},
 				Action: masterKeyAction,
 			},
+			{
+				Name:  "db",
+				Usage: "offline database management",
+				Subcommands: []cli.Command{
+					{
+						Name:  "migrate",
+						Usage: "migrate the main database to another storage engine",
+						Flags: []cli.Flag{
+							networkFlag,
+							configDirFlag,
+							dataDirFlag,
+							cacheFlag,
+							disablePrunerFlag,
+							archiveFlag,
+							fromEngineFlag,
+							toEngineFlag,
+							verbosityFlag,
+							jsonLogsFlag,
+						},
+						Action: dbMigrateAction,
+					},
+					{
+						Name:  "check",
+						Usage: "check the integrity of the chain data and the state in the main database",
+						Flags: []cli.Flag{
+							networkFlag,
+							configDirFlag,
+							dataDirFlag,
+							cacheFlag,
+							disablePrunerFlag,
+							archiveFlag,
+							dbEngineFlag,
+							checkHeightFlag,
+							compactFlag,
+							verbosityFlag,
+							jsonLogsFlag,
+						},
+						Action: dbCheckAction,
+					},
+				},
+			},
+			{
+				Name:  "export",
+				Usage: "export blocks of the best chain into a block archive file",
+				Flags: []cli.Flag{
+					networkFlag,
+					configDirFlag,
+					dataDirFlag,
+					cacheFlag,
+					disablePrunerFlag,
+					archiveFlag,
+					dbEngineFlag,
+					exportFromFlag,
+					exportToFlag,
+					exportOutFlag,
+					verbosityFlag,
+					jsonLogsFlag,
+				},
+				Action: exportAction,
+			},
+			{
+				Name:      "import",
+				Usage:     "import blocks from a block archive file",
+				ArgsUsage: "<file>",
+				Flags: []cli.Flag{
+					networkFlag,
+					configDirFlag,
+					dataDirFlag,
+					cacheFlag,
+					disablePrunerFlag,
+					archiveFlag,
+					dbEngineFlag,
+					verbosityFlag,
+					jsonLogsFlag,
+				},
+				Action: importAction,
+			},
+			{
+				Name:  "snapshot",
+				Usage: "state snapshot management",
+				Subcommands: []cli.Command{
+					{
+						Name:  "export",
+						Usage: "export the state at a finalized block along with the chain into a snapshot file",
+						Flags: []cli.Flag{
+							networkFlag,
+							configDirFlag,
+							dataDirFlag,
+							cacheFlag,
+							disablePrunerFlag,
+							archiveFlag,
+							dbEngineFlag,
+							snapshotBlockFlag,
+							exportOutFlag,
+							verbosityFlag,
+							jsonLogsFlag,
+						},
+						Action: snapshotExportAction,
+					},
+					{
+						Name:      "import",
+						Usage:     "bootstrap an empty data dir from a snapshot file",
+						ArgsUsage: "<file>",
+						Flags: []cli.Flag{
+							networkFlag,
+							configDirFlag,
+							dataDirFlag,
+							cacheFlag,
+							disablePrunerFlag,
+							archiveFlag,
+							dbEngineFlag,
+							verbosityFlag,
+							jsonLogsFlag,
+						},
+						Action: snapshotImportAction,
+					},
+				},
+			},
 		},
 	}
 
@@ -251,6 +406,43 @@ func defaultAction(ctx *cli.Context) error {
 
 	printStartupMessage1(gene, repo, master, instanceDir, forkConfig)
 
+	syncMode := ctx.String(syncModeFlag.Name)
+	if err := checkSyncMode(syncMode, repo); err != nil {
+		return err
+	}
+	trusted, err := parseTrustedCheckpoint(ctx)
+	if err != nil {
+		return err
+	}
+	// the pivot of snap sync is only verified to descend from the trusted checkpoint
+	if syncMode == syncModeSnap && trusted.IsZero() {
+		return errors.New("snap sync requires '--trusted-checkpoint'")
+	}
+
+	stater := state.NewStater(mainDB)
+	if ctx.Bool(archiveFlag.Name) {
+		// states fetched by snap sync are not committed block by block
+		if syncMode == syncModeSnap {
+			return errors.New("archive mode can't work with snap sync")
+		}
+		archive := state.NewArchive(mainDB)
+		if err := initStateArchive(exitSignal, repo, archive); err != nil {
+			return err
+		}
+		stater = state.NewArchiveStater(mainDB, archive)
+	}
+	if ctx.Bool(stateSnapshotFlag.Name) {
+		snapshot, err := state.NewSnapshot(mainDB)
+		if err != nil {
+			return errors.Wrap(err, "open state snapshot")
+		}
+		if err := initStateSnapshot(exitSignal, repo, snapshot); err != nil {
+			return err
+		}
+		stater.EnableSnapshot(snapshot)
+		defer func() { log.Info("flattening state snapshot..."); flattenStateSnapshot(repo, snapshot) }()
+	}
+
 	skipLogs := ctx.Bool(skipLogsFlag.Name)
 	if !skipLogs {
 		if err := syncLogDB(exitSignal, repo, logDB, ctx.Bool(verifyLogsFlag.Name)); err != nil {
@@ -263,10 +455,27 @@ func defaultAction(ctx *cli.Context) error {
 	if err != nil {
 		return errors.Wrap(err, "parse txpool-limit-per-account flag")
 	}
-	txPool := txpool.New(repo, state.NewStater(mainDB), txpoolOpt, forkConfig)
+	txPool := txpool.New(repo, stater, txpoolOpt, forkConfig)
 	defer func() { log.Info("closing tx pool..."); txPool.Close() }()
 
-	p2pCommunicator, err := newP2PCommunicator(ctx, repo, txPool, instanceDir)
+	bftEngine, err := bft.NewEngine(repo, mainDB, forkConfig, master.Address())
+	if err != nil {
+		return errors.Wrap(err, "init bft engine")
+	}
+
+	communicator := comm.New(repo, stater, bftEngine, txPool)
+	communicator.SetMaxPeers(ctx.Int(maxPeersFlag.Name))
+	p2pCommunicator, err := newP2PCommunicator(ctx, communicator, instanceDir)
+	if err != nil {
+		return err
+	}
+
+	minTxPriorityFee := ctx.Uint64(minEffectivePriorityFeeFlag.Name)
+	if minTxPriorityFee > 0 {
+		log.Info(fmt.Sprintf("the minimum effective priority fee required in transactions is %d wei", minTxPriorityFee))
+	}
+
+	txSelector, err := packer.NewTxSelector(ctx.String(txSelectorFlag.Name))
 	if err != nil {
 		return err
 	}
@@ -282,6 +491,14 @@ func defaultAction(ctx *cli.Context) error {
 			p2pCommunicator.Communicator(),
 			logAPIRequests,
 			master,
+			blocktemplate.New(
+				repo,
+				packer.New(repo, stater, master.Address(), master.Beneficiary, forkConfig, minTxPriorityFee),
+				txPool,
+				txSelector,
+			),
+			peers.New(p2pCommunicator.Server(), p2pCommunicator.Communicator()),
+			newCachesAPI(mainDB, repo),
 		)
 		if err != nil {
 			return fmt.Errorf("unable to start admin server - %w", err)
@@ -290,15 +507,10 @@ func defaultAction(ctx *cli.Context) error {
 		defer func() { log.Info("stopping admin server..."); closeFunc() }()
 	}
 
-	bftEngine, err := bft.NewEngine(repo, mainDB, forkConfig, master.Address())
-	if err != nil {
-		return errors.Wrap(err, "init bft engine")
-	}
-
 	apiURL, srvCloser, err := httpserver.StartAPIServer(
 		ctx.String(apiAddrFlag.Name),
 		repo,
-		state.NewStater(mainDB),
+		stater,
 		txPool,
 		logDB,
 		bftEngine,
@@ -318,35 +530,57 @@ func defaultAction(ctx *cli.Context) error {
 	}
 	defer p2pCommunicator.Stop()
 
-	if !ctx.Bool(disablePrunerFlag.Name) {
-		pruner := pruner.New(mainDB, repo, bftEngine, *forkConfig)
-		defer func() { log.Info("stopping pruner..."); pruner.Stop() }()
+	cons := consensus.New(repo, stater, forkConfig)
+	if ctx.Bool(parallelExecFlag.Name) {
+		cons.EnableParallelExecution()
 	}
+	// headers are validated in sync routines, concurrently with blocks processed by the node
+	communicator.SetHeaderValidator(consensus.New(repo, stater, forkConfig))
 
-	minTxPriorityFee := ctx.Uint64(minEffectivePriorityFeeFlag.Name)
-	if minTxPriorityFee > 0 {
-		log.Info(fmt.Sprintf("the minimum effective priority fee required in transactions is %d wei", minTxPriorityFee))
+	if syncMode == syncModeSnap {
+		if err := snapSync(exitSignal, repo, communicator, bftEngine, mainDB, trusted); err != nil {
+			return err
+		}
+		// the synced state is committed without going through the snapshot
+		if snapshot := stater.Snapshot(); snapshot != nil {
+			if err := initStateSnapshot(exitSignal, repo, snapshot); err != nil {
+				return err
+			}
+		}
+		if !skipLogs {
+			if err := syncLogDB(exitSignal, repo, logDB, false); err != nil {
+				return err
+			}
+		}
+	}
+
+	if !prunerDisabled(ctx) {
+		opts, err := prunerOptions(ctx)
+		if err != nil {
+			return err
+		}
+		pruner := pruner.New(mainDB, repo, bftEngine, *forkConfig, opts)
+		defer func() { log.Info("stopping pruner..."); pruner.Stop() }()
 	}
 
 	options := node.Options{
 		SkipLogs:         skipLogs,
 		MinTxPriorityFee: minTxPriorityFee,
 		TargetGasLimit:   ctx.Uint64(targetGasLimitFlag.Name),
+		TxSelector:       txSelector,
 	}
-	stater := state.NewStater(mainDB)
-
 	return node.New(
 		master,
 		repo,
 		bftEngine,
-		state.NewStater(mainDB),
+		stater,
 		logDB,
 		txPool,
 		filepath.Join(instanceDir, "tx.stash"),
 		p2pCommunicator.Communicator(),
 		forkConfig,
 		options,
-		consensus.New(repo, stater, forkConfig),
+		cons,
 		packer.New(repo, stater, master.Address(), master.Beneficiary, forkConfig, options.MinTxPriorityFee),
 	).Run(exitSignal)
 }
@@ -439,6 +673,9 @@ func soloAction(ctx *cli.Context) error {
 			nil,
 			logAPIRequests,
 			nil,
+			nil,
+			nil,
+			newCachesAPI(mainDB, repo),
 		)
 		if err != nil {
 			return fmt.Errorf("unable to start admin server - %w", err)
@@ -460,11 +697,17 @@ func soloAction(ctx *cli.Context) error {
 	if minTxPriorityFee > 0 {
 		log.Info(fmt.Sprintf("the minimum effective priority fee required in transactions is %d wei", minTxPriorityFee))
 	}
+	txSelector, err := packer.NewTxSelector(ctx.String(txSelectorFlag.Name))
+	if err != nil {
+		return err
+	}
+
 	options := solo.Options{
 		GasLimit:         ctx.Uint64(gasLimitFlag.Name),
 		SkipLogs:         skipLogs,
 		MinTxPriorityFee: minTxPriorityFee,
 		OnDemand:         onDemandBlockProduction,
+		TxSelector:       txSelector,
 	}
 
 	stater := state.NewStater(mainDB)
@@ -509,7 +752,11 @@ func soloAction(ctx *cli.Context) error {
 	printStartupMessage2(gene, apiURL, "", metricsURL, adminURL, isDevnet)
 
 	if !ctx.Bool(disablePrunerFlag.Name) {
-		pruner := pruner.New(mainDB, repo, bftMockedEngine, *forkConfig)
+		opts, err := prunerOptions(ctx)
+		if err != nil {
+			return err
+		}
+		pruner := pruner.New(mainDB, repo, bftMockedEngine, *forkConfig, opts)
 		defer func() { log.Info("stopping pruner..."); pruner.Stop() }()
 	}
 
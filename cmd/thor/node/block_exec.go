@@ -19,9 +19,13 @@ import (
 	"github.com/vechain/thor/v2/logdb"
 	"github.com/vechain/thor/v2/state"
 	"github.com/vechain/thor/v2/thor"
+	"github.com/vechain/thor/v2/trie"
 	"github.com/vechain/thor/v2/tx"
 )
 
+// maxStateSnapshotLayers is the max number of diff layers kept in the state snapshot.
+const maxStateSnapshotLayers = 128
+
 type blockExecContext struct {
 	prevBest   *block.Header
 	newBlock   *block.Block
@@ -194,6 +198,7 @@ func (n *Node) commitBlock(ctx *blockExecContext) error {
 
 	if ctx.becomeBest {
 		n.processFork(ctx.newBlock, ctx.prevBest.ID())
+		n.capStateSnapshot(ctx.newBlock.Header(), ctx.conflicts)
 	}
 
 	commitElapsed := mclock.Now() - ctx.startTime - execElapsed
@@ -375,3 +380,26 @@ side-chain:   %v  %v`,
 		}
 	}
 }
+
+// capStateSnapshot flattens diff layers of the state snapshot up to the finalized block, keeping at most
+// maxStateSnapshotLayers layers for the new best block.
+func (n *Node) capStateSnapshot(best *block.Header, conflicts uint32) {
+	if n.stater == nil {
+		return
+	}
+	snapshot := n.stater.Snapshot()
+	if snapshot == nil {
+		return
+	}
+	layers := uint32(0)
+	if finalized := block.Number(n.bft.Finalized()); best.Number() > finalized {
+		layers = min(best.Number()-finalized, maxStateSnapshotLayers)
+	}
+	root := trie.Root{
+		Hash: best.StateRoot(),
+		Ver:  trie.Version{Major: best.Number(), Minor: conflicts},
+	}
+	if err := snapshot.Cap(root, int(layers)); err != nil {
+		logger.Warn("failed to cap state snapshot", "err", err)
+	}
+}
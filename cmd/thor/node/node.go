@@ -23,6 +23,7 @@ import (
 	"github.com/vechain/thor/v2/cmd/thor/bandwidth"
 	"github.com/vechain/thor/v2/log"
 	"github.com/vechain/thor/v2/logdb"
+	"github.com/vechain/thor/v2/packer"
 	"github.com/vechain/thor/v2/state"
 	"github.com/vechain/thor/v2/thor"
 	"github.com/vechain/thor/v2/txpool"
@@ -43,6 +44,7 @@ type Options struct {
 	TargetGasLimit   uint64
 	SkipLogs         bool
 	MinTxPriorityFee uint64
+	TxSelector       packer.TxSelector // decides the order of txs to be packed, pool order if nil
 }
 
 type Node struct {
@@ -6,6 +6,7 @@
 package node
 
 import (
+	"context"
 	"crypto/ecdsa"
 	"crypto/rand"
 	"fmt"
@@ -65,7 +66,7 @@ func BenchmarkBlockProcess_RandomSigners_ManyClausesPerTx_RealDB(b *testing.B) {
 	require.NoError(b, err)
 
 	// run the benchmark
-	benchmarkBlockProcess(b, db, accounts, blocks)
+	benchmarkBlockProcess(b, db, accounts, blocks, false)
 }
 
 func BenchmarkBlockProcess_RandomSigners_OneClausePerTx_RealDB(b *testing.B) {
@@ -83,7 +84,7 @@ func BenchmarkBlockProcess_RandomSigners_OneClausePerTx_RealDB(b *testing.B) {
 	require.NoError(b, err)
 
 	// run the benchmark
-	benchmarkBlockProcess(b, db, accounts, blocks)
+	benchmarkBlockProcess(b, db, accounts, blocks, false)
 }
 
 func BenchmarkBlockProcess_ManyClausesPerTx_RealDB(b *testing.B) {
@@ -101,7 +102,7 @@ func BenchmarkBlockProcess_ManyClausesPerTx_RealDB(b *testing.B) {
 	require.NoError(b, err)
 
 	// run the benchmark
-	benchmarkBlockProcess(b, db, accounts, blocks)
+	benchmarkBlockProcess(b, db, accounts, blocks, false)
 }
 
 func BenchmarkBlockProcess_OneClausePerTx_RealDB(b *testing.B) {
@@ -119,7 +120,7 @@ func BenchmarkBlockProcess_OneClausePerTx_RealDB(b *testing.B) {
 	require.NoError(b, err)
 
 	// run the benchmark
-	benchmarkBlockProcess(b, db, accounts, blocks)
+	benchmarkBlockProcess(b, db, accounts, blocks, false)
 }
 
 func BenchmarkBlockProcess_RandomSigners_ManyClausesPerTx(b *testing.B) {
@@ -136,7 +137,7 @@ func BenchmarkBlockProcess_RandomSigners_ManyClausesPerTx(b *testing.B) {
 	db := muxdb.NewMem()
 
 	// run the benchmark
-	benchmarkBlockProcess(b, db, accounts, blocks)
+	benchmarkBlockProcess(b, db, accounts, blocks, false)
 }
 
 func BenchmarkBlockProcess_RandomSigners_OneClausePerTx(b *testing.B) {
@@ -153,7 +154,7 @@ func BenchmarkBlockProcess_RandomSigners_OneClausePerTx(b *testing.B) {
 	db := muxdb.NewMem()
 
 	// run the benchmark
-	benchmarkBlockProcess(b, db, accounts, blocks)
+	benchmarkBlockProcess(b, db, accounts, blocks, false)
 }
 
 func BenchmarkBlockProcess_ManyClausesPerTx(b *testing.B) {
@@ -170,7 +171,7 @@ func BenchmarkBlockProcess_ManyClausesPerTx(b *testing.B) {
 	db := muxdb.NewMem()
 
 	// run the benchmark
-	benchmarkBlockProcess(b, db, accounts, blocks)
+	benchmarkBlockProcess(b, db, accounts, blocks, false)
 }
 
 func BenchmarkBlockProcess_OneClausePerTx(b *testing.B) {
@@ -187,14 +188,57 @@ func BenchmarkBlockProcess_OneClausePerTx(b *testing.B) {
 	db := muxdb.NewMem()
 
 	// run the benchmark
-	benchmarkBlockProcess(b, db, accounts, blocks)
+	benchmarkBlockProcess(b, db, accounts, blocks, false)
 }
 
-func benchmarkBlockProcess(b *testing.B, db *muxdb.MuxDB, accounts []genesis.DevAccount, blocks []*block.Block) {
+func BenchmarkBlockProcess_RandomSigners_ManyClausesPerTx_RealDB_Snapshot(b *testing.B) {
+	// create state accounts
+	accounts := getCachedAccounts(b)
+
+	// randomly pick a signer for signing the transactions
+	randomSignerFunc := randomPickSignerFunc(accounts, createOneClausePerTx)
+
+	// create blocks
+	blocks := createBlocks(b, blockCount, accounts, randomSignerFunc)
+
+	// create test db - will be automagically removed when the benchmark ends
+	db, err := openTempMainDB(b.TempDir())
+	require.NoError(b, err)
+
+	// run the benchmark with state reads from the snapshot
+	benchmarkBlockProcess(b, db, accounts, blocks, true)
+}
+
+func BenchmarkBlockProcess_RandomSigners_OneClausePerTx_RealDB_Snapshot(b *testing.B) {
+	// create state accounts
+	accounts := getCachedAccounts(b)
+
+	// randomly pick a signer for signing the transactions
+	randomSignerFunc := randomPickSignerFunc(accounts, createManyClausesPerTx)
+
+	// create blocks
+	blocks := createBlocks(b, blockCount, accounts, randomSignerFunc)
+
+	// create test db - will be automagically removed when the benchmark ends
+	db, err := openTempMainDB(b.TempDir())
+	require.NoError(b, err)
+
+	// run the benchmark with state reads from the snapshot
+	benchmarkBlockProcess(b, db, accounts, blocks, true)
+}
+
+func benchmarkBlockProcess(b *testing.B, db *muxdb.MuxDB, accounts []genesis.DevAccount, blocks []*block.Block, withSnapshot bool) {
 	// Initialize the test chain and dependencies
 	thorChain, err := createChain(db, accounts)
 	require.NoError(b, err)
 
+	if withSnapshot {
+		snapshot, err := state.NewSnapshot(db)
+		require.NoError(b, err)
+		require.NoError(b, snapshot.Generate(context.Background(), thorChain.Repo().BestBlockSummary().Root()))
+		thorChain.Stater().EnableSnapshot(snapshot)
+	}
+
 	proposer := &accounts[0]
 
 	engine, err := bft.NewEngine(thorChain.Repo(), thorChain.Database(), thorChain.GetForkConfig(), proposer.Address)
@@ -212,14 +256,14 @@ func benchmarkBlockProcess(b *testing.B, db *muxdb.MuxDB, accounts []genesis.Dev
 		nil,
 		"",
 		nil,
-		&thor.NoFork,
+		thorChain.GetForkConfig(),
 		Options{
 			SkipLogs:         true,
 			MinTxPriorityFee: 0,
 			TargetGasLimit:   10_000_000,
 		},
-		consensus.New(thorChain.Repo(), thorChain.Stater(), &thor.NoFork),
-		packer.New(thorChain.Repo(), thorChain.Stater(), masterAddr.Address(), masterAddr.Beneficiary, &thor.NoFork, 10_000_000),
+		consensus.New(thorChain.Repo(), thorChain.Stater(), thorChain.GetForkConfig()),
+		packer.New(thorChain.Repo(), thorChain.Stater(), masterAddr.Address(), masterAddr.Beneficiary, thorChain.GetForkConfig(), 10_000_000),
 	)
 
 	stats := &blockStats{}
@@ -81,6 +81,8 @@ func (n *Node) packerLoop(ctx context.Context) {
 			logger.Info("prepared to pack block")
 		}
 		logger.Info("scheduled to pack block", "after", time.Duration(flow.When()-now)*time.Second, "score", flow.TotalScore()-flow.ParentHeader().TotalScore())
+		// warm up state while waiting
+		flow.Prefetch(n.txPool.Executables())
 
 		for {
 			if uint64(time.Now().Unix())+thor.BlockInterval()/2 > flow.When() {
@@ -142,6 +144,10 @@ func (n *Node) proposeAndCommit(flow *packer.Flow, conflicts uint32) (err error)
 	}
 
 	txs := n.txPool.Executables()
+	if n.options.TxSelector != nil {
+		txs = n.options.TxSelector.Select(flow, txs)
+	}
+	flow.Prefetch(txs)
 	// adopt txs
 	for _, tx := range txs {
 		if err := flow.Adopt(tx); err != nil {
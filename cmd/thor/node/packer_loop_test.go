@@ -61,7 +61,7 @@ func getFlowAndNode(t *testing.T, forkConfig *thor.ForkConfig) (*packer.Flow, *N
 	logdb, err := logdb.NewMem()
 	assert.NoError(t, err)
 
-	comm := comm2.New(repo, pool)
+	comm := comm2.New(repo, nil, nil, pool)
 
 	n := &Node{
 		txPool:     pool,
@@ -14,23 +14,35 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/pkg/errors"
+	"github.com/vechain/thor/v2/p2p"
 	"github.com/vechain/thor/v2/p2p/discover"
+	"github.com/vechain/thor/v2/p2p/discv5"
 	"github.com/vechain/thor/v2/p2p/nat"
 
+	"github.com/vechain/thor/v2/cmd/thor/bandwidth"
 	"github.com/vechain/thor/v2/comm"
 	"github.com/vechain/thor/v2/log"
 	"github.com/vechain/thor/v2/p2psrv"
 )
 
+// Handler handles the thor protocol over p2p connections, e.g. comm.Communicator and comm.Light.
+type Handler interface {
+	Protocols() []*p2p.Protocol
+	DiscTopic() discv5.Topic
+	Start()
+	Stop()
+}
+
 type P2P struct {
-	comm           *comm.Communicator
+	comm           Handler
 	p2pSrv         *p2psrv.Server
 	peersCachePath string
 	enode          string
+	traffic        bandwidth.Traffic
 }
 
 func New(
-	communicator *comm.Communicator,
+	communicator Handler,
 	privateKey *ecdsa.PrivateKey,
 	instanceDir string,
 	nat nat.Interface,
@@ -56,6 +68,7 @@ func New(
 		DiscoveryNodes:      fallbackDiscoveryNodes,
 		RemoteDiscoveryList: remoteDiscoveryNodesList,
 		NAT:                 nat,
+		BanListPath:         filepath.Join(instanceDir, "peers.bans"),
 	}
 
 	// allowed peers flag will only allow p2psrv to connect to the designated peers
@@ -77,12 +90,14 @@ func New(
 		}
 	}
 
-	return &P2P{
+	p := &P2P{
 		comm:           communicator,
-		p2pSrv:         p2psrv.New(opts),
 		peersCachePath: peersCachePath,
 		enode:          fmt.Sprintf("enode://%x@[extip]:%v", discover.PubkeyID(&privateKey.PublicKey).Bytes(), listenPort),
 	}
+	opts.MeterConn = p.traffic.MeterConn
+	p.p2pSrv = p2psrv.New(opts)
+	return p
 }
 
 func (p *P2P) Start() error {
@@ -113,8 +128,14 @@ func (p *P2P) Stop() {
 	}
 }
 
+// Communicator returns the handler as a full node communicator, nil if it's not.
 func (p *P2P) Communicator() *comm.Communicator {
-	return p.comm
+	c, _ := p.comm.(*comm.Communicator)
+	return c
+}
+
+func (p *P2P) Server() *p2psrv.Server {
+	return p.p2pSrv
 }
 
 func (p *P2P) Enode() string {
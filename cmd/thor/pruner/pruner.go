@@ -33,6 +33,16 @@ const (
 	statusKey      = "status"
 )
 
+// Options optional parameters for the pruner.
+type Options struct {
+	// RetainBlocks is the count of recent blocks whose states are retained.
+	// thor.MaxStateHistory is applied if less than it.
+	RetainBlocks uint32
+	// HistoryExpiryBlocks is the count of recent blocks whose txs and receipts are retained, 0 to keep all.
+	// thor.MaxStateHistory is applied if less than it.
+	HistoryExpiryBlocks uint32
+}
+
 // Pruner is a background task to prune tries.
 type Pruner struct {
 	db       *muxdb.MuxDB
@@ -42,10 +52,11 @@ type Pruner struct {
 	cancel   func()
 	goes     sync.WaitGroup
 	fc       *thor.ForkConfig
+	opts     Options
 }
 
 // New creates and starts the pruner.
-func New(db *muxdb.MuxDB, repo *chain.Repository, commiter bft.Committer, fc thor.ForkConfig) *Pruner {
+func New(db *muxdb.MuxDB, repo *chain.Repository, commiter bft.Committer, fc thor.ForkConfig, opts Options) *Pruner {
 	ctx, cancel := context.WithCancel(context.Background())
 	o := &Pruner{
 		db:       db,
@@ -54,6 +65,7 @@ func New(db *muxdb.MuxDB, repo *chain.Repository, commiter bft.Committer, fc tho
 		commiter: commiter,
 		cancel:   cancel,
 		fc:       &fc,
+		opts:     opts,
 	}
 	o.goes.Go(func() {
 		if err := o.loop(); err != nil {
@@ -65,6 +77,19 @@ func New(db *muxdb.MuxDB, repo *chain.Repository, commiter bft.Committer, fc tho
 	return o
 }
 
+// SetBase sets the block number where the pruner starts from, tries before it are left untouched.
+// It's used when the chain is bootstrapped from a snapshot, and should be called before the pruner created.
+func SetBase(db *muxdb.MuxDB, base uint32) error {
+	propsStore := db.NewStore(propsStoreName)
+
+	var status status
+	if err := status.Load(propsStore); err != nil {
+		return err
+	}
+	status.Base = base
+	return status.Save(propsStore)
+}
+
 // Stop stops the pruner.
 func (p *Pruner) Stop() {
 	p.cancel()
@@ -93,9 +118,9 @@ func (p *Pruner) loop() error {
 		// select target
 		target := status.Base + period
 
-		// adding thor.MaxStateHistory here since we need to ensure that defined range of history is required
-		// to be kept for EVM accessibility. It's defined in thor/params.go(thor.MaxStateHistory ~7 days).
-		targetChain, err := p.awaitUntilPrunable(target + thor.MaxStateHistory)
+		// adding the retention window here since we need to ensure that defined range of history is required
+		// to be kept for EVM accessibility. It's at least thor.MaxStateHistory(~7 days) defined in thor/params.go.
+		targetChain, err := p.awaitUntilPrunable(target + p.retainBlocks())
 		if err != nil {
 			return errors.Wrap(err, "awaitUntilPrunable")
 		}
@@ -111,6 +136,10 @@ func (p *Pruner) loop() error {
 			"et", time.Duration(time.Now().UnixNano()-startTime),
 		)
 
+		if err := p.expireHistory(target + p.retainBlocks()); err != nil {
+			return errors.Wrap(err, "expire history")
+		}
+
 		status.Base = target
 		if err := status.Save(propsStore); err != nil {
 			return errors.Wrap(err, "save status")
@@ -118,6 +147,36 @@ func (p *Pruner) loop() error {
 	}
 }
 
+// retainBlocks returns the count of recent blocks whose states are retained.
+func (p *Pruner) retainBlocks() uint32 {
+	return max(p.opts.RetainBlocks, thor.MaxStateHistory)
+}
+
+// expireHistory expires txs and receipts out of the expiry window, which ends at the given prunable block number.
+func (p *Pruner) expireHistory(prunable uint32) error {
+	if p.opts.HistoryExpiryBlocks == 0 {
+		return nil
+	}
+	expiry := max(p.opts.HistoryExpiryBlocks, thor.MaxStateHistory)
+	if prunable <= expiry {
+		return nil
+	}
+	limit := prunable - expiry
+	if limit <= p.repo.HistoryExpiredBefore() {
+		return nil
+	}
+
+	startTime := time.Now().UnixNano()
+	if err := p.repo.ExpireHistory(p.ctx, limit); err != nil {
+		return err
+	}
+	logger.Info("expire history",
+		"before", limit,
+		"et", time.Duration(time.Now().UnixNano()-startTime),
+	)
+	return nil
+}
+
 // newStorageTrieIfUpdated creates a storage trie object from the account leaf if the storage trie updated since base.
 func (p *Pruner) newStorageTrieIfUpdated(accLeaf *trie.Leaf, base uint32) *muxdb.Trie {
 	if len(accLeaf.Meta) == 0 {
@@ -115,10 +115,43 @@ func TestNewPruner(t *testing.T) {
 	repo, _ := chain.NewRepository(db, b0)
 
 	bftMockedEngine := bft.NewMockedEngine(repo.GenesisBlock().Header().ID())
-	pr := New(db, repo, bftMockedEngine, *fc)
+	pr := New(db, repo, bftMockedEngine, *fc, Options{})
 	pr.Stop()
 }
 
+func TestRetainBlocks(t *testing.T) {
+	p := &Pruner{}
+	assert.Equal(t, uint32(thor.MaxStateHistory), p.retainBlocks())
+
+	p.opts.RetainBlocks = 100
+	assert.Equal(t, uint32(thor.MaxStateHistory), p.retainBlocks(), "at least MaxStateHistory")
+
+	p.opts.RetainBlocks = 30 * 24 * 360
+	assert.Equal(t, uint32(30*24*360), p.retainBlocks())
+}
+
+func TestExpireHistory(t *testing.T) {
+	db := muxdb.NewMem()
+	gene, _ := genesis.NewDevnet()
+	b0, _, _, _ := gene.Build(state.NewStater(db))
+	repo, _ := chain.NewRepository(db, b0)
+
+	p := &Pruner{repo: repo, ctx: context.Background()}
+	assert.Nil(t, p.expireHistory(thor.MaxStateHistory+100))
+	assert.Equal(t, uint32(0), repo.HistoryExpiredBefore(), "disabled")
+
+	p.opts.HistoryExpiryBlocks = 100
+	assert.Nil(t, p.expireHistory(thor.MaxStateHistory))
+	assert.Equal(t, uint32(0), repo.HistoryExpiredBefore(), "at least MaxStateHistory")
+
+	assert.Nil(t, p.expireHistory(thor.MaxStateHistory+100))
+	assert.Equal(t, uint32(100), repo.HistoryExpiredBefore())
+
+	p.opts.HistoryExpiryBlocks = thor.MaxStateHistory * 2
+	assert.Nil(t, p.expireHistory(thor.MaxStateHistory*2+200))
+	assert.Equal(t, uint32(200), repo.HistoryExpiredBefore())
+}
+
 func newTempFileDB() (*muxdb.MuxDB, func() error, error) {
 	dir := os.TempDir()
 
@@ -0,0 +1,595 @@
+// Copyright (c) 2025 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/pkg/errors"
+	"gopkg.in/urfave/cli.v1"
+
+	"github.com/vechain/thor/v2/bft"
+	"github.com/vechain/thor/v2/block"
+	"github.com/vechain/thor/v2/chain"
+	"github.com/vechain/thor/v2/cmd/thor/pruner"
+	"github.com/vechain/thor/v2/log"
+	"github.com/vechain/thor/v2/muxdb"
+	"github.com/vechain/thor/v2/state"
+	"github.com/vechain/thor/v2/thor"
+	"github.com/vechain/thor/v2/trie"
+	"github.com/vechain/thor/v2/tx"
+)
+
+// The state snapshot is the archive file of the state at a pivot block, along with the chain up to it.
+// Each record is a kind byte followed by the RLP encoded payload, in the order of:
+//
+//	pivot:   the pivot block with its receipts and bft quality
+//	blocks:  summaries of blocks before the pivot, without txs and receipts
+//	state:   (account leaf | code | storage leaf of the last account)...
+const (
+	snapshotMagic = "THORSNAP"
+
+	snapshotPivotRecord   = byte(0)
+	snapshotBlockRecord   = byte(1)
+	snapshotAccountRecord = byte(2)
+	snapshotCodeRecord    = byte(3)
+	snapshotStorageRecord = byte(4)
+)
+
+type snapshotPivot struct {
+	Block    *block.Block
+	Receipts tx.Receipts
+	Quality  uint32
+}
+
+type snapshotBlock struct {
+	Header   *block.Header
+	Txs      []thor.Bytes32
+	Reverted []bool
+	Size     uint64
+}
+
+type snapshotLeaf struct {
+	Key   []byte
+	Value []byte
+	Meta  []byte
+}
+
+type snapshotCode struct {
+	Hash thor.Bytes32
+	Code []byte
+}
+
+func snapshotExportAction(ctx *cli.Context) error {
+	exitSignal := handleExitSignal()
+	defer func() { log.Info("exited") }()
+
+	if _, err := initLogger(ctx); err != nil {
+		return err
+	}
+
+	out := ctx.String(exportOutFlag.Name)
+	if out == "" {
+		return fmt.Errorf("missing output file, use --%v to specify", exportOutFlag.Name)
+	}
+
+	gene, forkConfig, err := selectGenesis(ctx)
+	if err != nil {
+		return err
+	}
+	instanceDir, err := makeInstanceDir(ctx, gene)
+	if err != nil {
+		return err
+	}
+	// avoid creating an empty one
+	if _, err := os.Stat(filepath.Join(instanceDir, "main.db")); err != nil {
+		return errors.Wrap(err, "main database")
+	}
+
+	db, err := openMainDB(ctx, instanceDir)
+	if err != nil {
+		return err
+	}
+	defer func() { log.Info("closing main database..."); db.Close() }()
+
+	repo, err := openRepository(gene, db)
+	if err != nil {
+		return err
+	}
+	bftEngine, err := bft.NewEngine(repo, db, forkConfig, thor.Address{})
+	if err != nil {
+		return errors.Wrap(err, "init bft engine")
+	}
+
+	var pivotNum uint32
+	if val := ctx.String(snapshotBlockFlag.Name); val == "finalized" {
+		// the same as the snap sync pivot, the last block of the round before the finalized checkpoint
+		finalized := block.Number(bftEngine.Finalized())
+		if finalized == 0 {
+			return errors.New("no finalized block")
+		}
+		pivotNum = finalized - 1
+	} else {
+		num, err := strconv.ParseUint(val, 10, 32)
+		if err != nil {
+			return fmt.Errorf("invalid %v: %v", snapshotBlockFlag.Name, val)
+		}
+		pivotNum = uint32(num)
+	}
+
+	pivot, err := repo.NewBestChain().GetBlockSummary(pivotNum)
+	if err != nil {
+		return errors.Wrapf(err, "get block summary #%v", pivotNum)
+	}
+	quality, err := bftEngine.Quality(pivot.Header.ID())
+	if err != nil {
+		return errors.Wrap(err, "get bft quality")
+	}
+	if quality == 0 {
+		return fmt.Errorf("quality of block #%v unknown, it should be the last block of a round", pivotNum)
+	}
+	if pivotNum < repo.HistoryExpiredBefore() {
+		return fmt.Errorf("txs of block #%v expired", pivotNum)
+	}
+	if state.NewStater(db).IsPruned(pivot.Root()) {
+		return fmt.Errorf("state of block #%v pruned", pivotNum)
+	}
+
+	log.Info("exporting snapshot", "pivot", pivot.Header.ID(), "block", pivotNum, "out", out)
+	if err := writeArchiveFile(out, func(w io.Writer) error {
+		return exportSnapshot(exitSignal, db, repo, pivot, quality, w)
+	}); err != nil {
+		return err
+	}
+	log.Info("snapshot exported")
+	return nil
+}
+
+// snapshotWriter writes records of the state snapshot.
+type snapshotWriter struct {
+	*archiveWriter
+	buf []byte
+}
+
+func (sw *snapshotWriter) put(kind byte, payload any) error {
+	data, err := rlp.EncodeToBytes(payload)
+	if err != nil {
+		return err
+	}
+	sw.buf = append(append(sw.buf[:0], kind), data...)
+	_, err = sw.WriteRecord(sw.buf)
+	return err
+}
+
+// exportSnapshot writes the state snapshot at the pivot block.
+func exportSnapshot(
+	ctx context.Context,
+	db *muxdb.MuxDB,
+	repo *chain.Repository,
+	pivot *chain.BlockSummary,
+	quality uint32,
+	w io.Writer,
+) error {
+	aw, err := newArchiveWriter(w, snapshotMagic, repo.GenesisBlock().Header().ID())
+	if err != nil {
+		return err
+	}
+	var (
+		sw       = &snapshotWriter{archiveWriter: aw}
+		pivotNum = pivot.Header.Number()
+		lastLog  = time.Now()
+	)
+	tick := func(msg string, logCtx ...any) error {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+		if time.Since(lastLog) > blockArchiveLogInterval {
+			log.Info(msg, logCtx...)
+			lastLog = time.Now()
+		}
+		return nil
+	}
+
+	// pivot
+	blk, err := repo.GetBlock(pivot.Header.ID())
+	if err != nil {
+		return errors.Wrap(err, "get pivot block")
+	}
+	receipts, err := repo.GetBlockReceipts(pivot.Header.ID())
+	if err != nil {
+		return errors.Wrap(err, "get pivot receipts")
+	}
+	if err := sw.put(snapshotPivotRecord, &snapshotPivot{blk, receipts, quality}); err != nil {
+		return err
+	}
+
+	// blocks
+	pivotChain := repo.NewChain(pivot.Header.ID())
+	for num := uint32(1); num < pivotNum; num++ {
+		summary, err := pivotChain.GetBlockSummary(num)
+		if err != nil {
+			return errors.Wrapf(err, "get block summary #%v", num)
+		}
+		reverted := make([]bool, 0, len(summary.Txs))
+		for _, txID := range summary.Txs {
+			meta, err := pivotChain.GetTransactionMeta(txID)
+			if err != nil {
+				return errors.Wrapf(err, "get tx meta %v", txID)
+			}
+			reverted = append(reverted, meta.Reverted)
+		}
+		if err := sw.put(snapshotBlockRecord, &snapshotBlock{summary.Header, summary.Txs, reverted, summary.Size}); err != nil {
+			return err
+		}
+		if err := tick("exporting blocks", "block", num); err != nil {
+			return err
+		}
+	}
+
+	// state
+	var (
+		stater   = state.NewStater(db)
+		codes    = make(map[thor.Bytes32]bool) // code hash => written
+		accounts int
+		accTrie  = db.NewTrie(muxdb.AccountTrieName, pivot.Root())
+	)
+	accTrie.SetNoFillCache(true)
+	accIter := trie.NewIterator(accTrie.NodeIterator(nil, 0))
+	for accIter.Next() {
+		if err := sw.put(snapshotAccountRecord, &snapshotLeaf{Key: accIter.Key, Value: accIter.Value}); err != nil {
+			return err
+		}
+		if accounts++; accounts%1000 == 0 {
+			if err := tick("exporting state", "accounts", accounts); err != nil {
+				return err
+			}
+		}
+
+		var (
+			acc state.Account
+			am  state.AccountMetadata
+		)
+		if err := rlp.DecodeBytes(accIter.Value, &acc); err != nil {
+			return err
+		}
+		if len(acc.CodeHash) > 0 {
+			if hash := thor.BytesToBytes32(acc.CodeHash); !codes[hash] {
+				code, err := stater.Code(hash)
+				if err != nil {
+					return err
+				}
+				if code == nil {
+					return fmt.Errorf("code %v missing", hash)
+				}
+				if err := sw.put(snapshotCodeRecord, &snapshotCode{hash, code}); err != nil {
+					return err
+				}
+				codes[hash] = true
+			}
+		}
+		if len(acc.StorageRoot) == 0 || len(accIter.Meta) == 0 {
+			continue
+		}
+		if err := rlp.DecodeBytes(accIter.Meta, &am); err != nil {
+			return err
+		}
+		sTrie := db.NewTrie(
+			state.StorageTrieName(am.StorageID),
+			trie.Root{
+				Hash: thor.BytesToBytes32(acc.StorageRoot),
+				Ver: trie.Version{
+					Major: am.StorageMajorVer,
+					Minor: am.StorageMinorVer,
+				},
+			})
+		sTrie.SetNoFillCache(true)
+		sIter := trie.NewIterator(sTrie.NodeIterator(nil, 0))
+		for sIter.Next() {
+			if err := sw.put(snapshotStorageRecord, &snapshotLeaf{sIter.Key, sIter.Value, sIter.Meta}); err != nil {
+				return err
+			}
+		}
+		if sIter.Err != nil {
+			return sIter.Err
+		}
+	}
+	if accIter.Err != nil {
+		return accIter.Err
+	}
+	return sw.Finish(nil)
+}
+
+func snapshotImportAction(ctx *cli.Context) error {
+	exitSignal := handleExitSignal()
+	defer func() { log.Info("exited") }()
+
+	if _, err := initLogger(ctx); err != nil {
+		return err
+	}
+
+	path := ctx.Args().First()
+	if path == "" {
+		return errors.New("missing snapshot file")
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	ar, err := newArchiveReader(f, snapshotMagic)
+	if err != nil {
+		return errors.WithMessagef(err, "open snapshot [%v]", path)
+	}
+
+	gene, forkConfig, err := selectGenesis(ctx)
+	if err != nil {
+		return err
+	}
+	if ar.GenesisID() != gene.ID() {
+		return fmt.Errorf("genesis mismatch, snapshot of %v found", ar.GenesisID())
+	}
+	instanceDir, err := makeInstanceDir(ctx, gene)
+	if err != nil {
+		return err
+	}
+
+	mainDB, err := openMainDB(ctx, instanceDir)
+	if err != nil {
+		return err
+	}
+	defer func() { log.Info("closing main database..."); mainDB.Close() }()
+
+	logDB, err := openLogDB(instanceDir, false)
+	if err != nil {
+		return err
+	}
+	defer func() { log.Info("closing log database..."); logDB.Close() }()
+
+	repo, err := initChainRepository(gene, mainDB, logDB)
+	if err != nil {
+		return err
+	}
+	if maxNum, err := repo.GetMaxBlockNum(); err != nil {
+		return err
+	} else if maxNum > 0 {
+		return fmt.Errorf("blocks found in the data dir [%v], remove it before importing", instanceDir)
+	}
+
+	bftEngine, err := bft.NewEngine(repo, mainDB, forkConfig, thor.Address{})
+	if err != nil {
+		return errors.Wrap(err, "init bft engine")
+	}
+
+	log.Info("importing snapshot", "file", path)
+	if err := importSnapshot(exitSignal, ar, repo, state.NewStater(mainDB), func(pivot thor.Bytes32, quality uint32) error {
+		if err := bftEngine.Bootstrap(pivot, quality); err != nil {
+			return errors.Wrap(err, "bootstrap bft engine")
+		}
+		// tries before the pivot are absent
+		return pruner.SetBase(mainDB, block.Number(pivot))
+	}); err != nil {
+		return err
+	}
+	// receipts before the pivot are expired, so the log db sync at startup writes logs since the pivot
+	best := repo.BestBlockSummary().Header
+	log.Info("snapshot imported", "best", best.ID(), "block", best.Number())
+	return nil
+}
+
+// snapshotReader reads records of the state snapshot.
+type snapshotReader struct {
+	*archiveReader
+}
+
+// next returns the kind of the next record, and decodes the payload by the given function.
+func (sr *snapshotReader) next() (byte, func(payload any) error, error) {
+	data, offset, err := sr.ReadRecord()
+	if err != nil {
+		return 0, nil, err
+	}
+	return data[0], func(payload any) error {
+		if err := rlp.DecodeBytes(data[1:], payload); err != nil {
+			return errors.Wrapf(err, "decode record at offset %v", offset)
+		}
+		return nil
+	}, nil
+}
+
+// importSnapshot imports the state snapshot into the repository, which should have no block other than
+// the genesis. The state root of the pivot is verified, and txs and receipts of blocks before the pivot
+// are marked expired. onImported is called before the pivot becomes the best block.
+func importSnapshot(
+	ctx context.Context,
+	ar *archiveReader,
+	repo *chain.Repository,
+	stater *state.Stater,
+	onImported func(pivot thor.Bytes32, quality uint32) error,
+) error {
+	var (
+		sr      = &snapshotReader{ar}
+		pivot   snapshotPivot
+		lastLog = time.Now()
+	)
+	tick := func(msg string, logCtx ...any) error {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+		if time.Since(lastLog) > blockArchiveLogInterval {
+			log.Info(msg, logCtx...)
+			lastLog = time.Now()
+		}
+		return nil
+	}
+
+	// pivot
+	kind, decode, err := sr.next()
+	if err != nil {
+		return errors.Wrap(err, "read pivot")
+	}
+	if kind != snapshotPivotRecord {
+		return errors.New("pivot missing")
+	}
+	if err := decode(&pivot); err != nil {
+		return err
+	}
+	header := pivot.Block.Header()
+	if header.Number() == 0 {
+		return errors.New("invalid pivot")
+	}
+	if pivot.Block.Transactions().RootHash() != header.TxsRoot() {
+		return errors.New("txs root of the pivot mismatch")
+	}
+	if pivot.Receipts.RootHash() != header.ReceiptsRoot() {
+		return errors.New("receipts root of the pivot mismatch")
+	}
+	log.Info("snapshot pivot", "id", header.ID(), "block", header.Number(), "quality", pivot.Quality)
+
+	if err := repo.ExpireHistory(ctx, header.Number()); err != nil {
+		return err
+	}
+
+	var (
+		builder    = stater.NewSnapBuilder(trie.Root{Hash: header.StateRoot(), Ver: trie.Version{Major: header.Number()}})
+		storage    *state.StorageBuilder
+		codes      = make(map[thor.Bytes32]bool) // code hash => added
+		nextNum    = uint32(1)
+		pivotAdded bool
+		accounts   int
+	)
+	// the pivot is added once all blocks before it imported, and before the state to be built
+	addPivot := func() error {
+		if pivotAdded {
+			return nil
+		}
+		if nextNum != header.Number() {
+			return fmt.Errorf("blocks since #%v missing", nextNum)
+		}
+		if err := repo.AddBlock(pivot.Block, pivot.Receipts, 0, false); err != nil {
+			return errors.Wrap(err, "add pivot")
+		}
+		pivotAdded = true
+		return nil
+	}
+	commitStorage := func() error {
+		if storage != nil {
+			if err := storage.Commit(); err != nil {
+				return err
+			}
+			storage = nil
+		}
+		return nil
+	}
+
+	for {
+		kind, decode, err := sr.next()
+		if err != nil {
+			if err == io.EOF {
+				break
+			}
+			return errors.Wrap(err, "read snapshot")
+		}
+
+		switch kind {
+		case snapshotBlockRecord:
+			var b snapshotBlock
+			if err := decode(&b); err != nil {
+				return err
+			}
+			if pivotAdded || b.Header.Number() != nextNum {
+				return fmt.Errorf("unexpected block #%v", b.Header.Number())
+			}
+			if err := repo.AddExpiredBlock(&chain.BlockSummary{Header: b.Header, Txs: b.Txs, Size: b.Size}, b.Reverted); err != nil {
+				return errors.Wrapf(err, "add block #%v", nextNum)
+			}
+			nextNum++
+			if err := tick("importing blocks", "block", b.Header.Number()); err != nil {
+				return err
+			}
+		case snapshotAccountRecord:
+			if err := addPivot(); err != nil {
+				return err
+			}
+			var leaf snapshotLeaf
+			if err := decode(&leaf); err != nil {
+				return err
+			}
+			if err := commitStorage(); err != nil {
+				return err
+			}
+			codeHash, sb, err := builder.AddAccount(leaf.Key, leaf.Value)
+			if err != nil {
+				return err
+			}
+			if hash := thor.BytesToBytes32(codeHash); len(codeHash) > 0 && !codes[hash] {
+				codes[hash] = false
+			}
+			storage = sb
+			if accounts++; accounts%1000 == 0 {
+				if err := tick("importing state", "accounts", accounts); err != nil {
+					return err
+				}
+			}
+		case snapshotCodeRecord:
+			var code snapshotCode
+			if err := decode(&code); err != nil {
+				return err
+			}
+			if err := builder.AddCode(code.Hash[:], code.Code); err != nil {
+				return err
+			}
+			codes[code.Hash] = true
+		case snapshotStorageRecord:
+			if storage == nil {
+				return errors.New("unexpected storage leaf")
+			}
+			var leaf snapshotLeaf
+			if err := decode(&leaf); err != nil {
+				return err
+			}
+			if err := storage.Add([][]byte{leaf.Key}, [][]byte{leaf.Value}, [][]byte{leaf.Meta}); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("unknown record kind %v", kind)
+		}
+	}
+	if err := sr.Verify(); err != nil {
+		return err
+	}
+
+	if err := addPivot(); err != nil {
+		return err
+	}
+	if err := commitStorage(); err != nil {
+		return err
+	}
+	if err := builder.Commit(); err != nil {
+		return err
+	}
+	for hash, added := range codes {
+		if !added {
+			return fmt.Errorf("code %v missing", hash)
+		}
+	}
+
+	if err := onImported(header.ID(), pivot.Quality); err != nil {
+		return err
+	}
+	// re-add the pivot to make it the best
+	return repo.AddBlock(pivot.Block, pivot.Receipts, 0, true)
+}
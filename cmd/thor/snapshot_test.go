@@ -0,0 +1,142 @@
+// Copyright (c) 2025 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vechain/thor/v2/consensus"
+	"github.com/vechain/thor/v2/genesis"
+	"github.com/vechain/thor/v2/logdb"
+	"github.com/vechain/thor/v2/test/testchain"
+	"github.com/vechain/thor/v2/thor"
+	"github.com/vechain/thor/v2/tx"
+)
+
+func TestSnapshotExportImport(t *testing.T) {
+	// launched in the past, so that minted blocks are not in the future
+	src, err := testchain.NewIntegrationTestChain(genesis.DevConfig{
+		ForkConfig: &testchain.DefaultForkConfig,
+		LaunchTime: uint64(time.Now().Unix()) - 1000,
+	}, 180)
+	require.NoError(t, err)
+	to := thor.BytesToAddress([]byte("to"))
+	var txIDs []thor.Bytes32
+	for i := range 6 {
+		value := big.NewInt(1)
+		if i == 1 {
+			// reverted due to insufficient balance
+			value = new(big.Int).Lsh(big.NewInt(1), 128)
+		}
+		trx := tx.MustSign(tx.NewBuilder(tx.TypeLegacy).
+			ChainTag(src.Repo().ChainTag()).
+			Expiration(100).
+			Gas(50000).
+			Nonce(uint64(i)).
+			Clause(tx.NewClause(&to).WithValue(value)).
+			Build(), genesis.DevAccounts()[0].PrivateKey)
+		require.NoError(t, src.MintBlock(trx))
+		txIDs = append(txIDs, trx.ID())
+	}
+	pivot, err := src.Repo().NewBestChain().GetBlockSummary(4)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, exportSnapshot(context.Background(), src.Database(), src.Repo(), pivot, 7, &buf))
+	data := buf.Bytes()
+
+	newDst := func() *testchain.Chain {
+		dst, err := testchain.NewIntegrationTestChainWithGenesis(src.Genesis(), src.GetForkConfig(), 180)
+		require.NoError(t, err)
+		return dst
+	}
+	importData := func(dst *testchain.Chain, data []byte, onImported func(thor.Bytes32, uint32) error) error {
+		ar, err := newArchiveReader(bytes.NewReader(data), snapshotMagic)
+		if err != nil {
+			return err
+		}
+		assert.Equal(t, src.Repo().GenesisBlock().Header().ID(), ar.GenesisID())
+		return importSnapshot(context.Background(), ar, dst.Repo(), dst.Stater(), onImported)
+	}
+
+	dst := newDst()
+	var (
+		importedPivot   thor.Bytes32
+		importedQuality uint32
+	)
+	require.NoError(t, importData(dst, data, func(pivot thor.Bytes32, quality uint32) error {
+		importedPivot, importedQuality = pivot, quality
+		return nil
+	}))
+	assert.Equal(t, pivot.Header.ID(), importedPivot)
+	assert.Equal(t, uint32(7), importedQuality)
+
+	repo := dst.Repo()
+	best := repo.BestBlockSummary()
+	assert.Equal(t, pivot.Header.ID(), best.Header.ID())
+	assert.Equal(t, uint32(4), repo.HistoryExpiredBefore())
+
+	// state and chain are complete
+	checker := newDBChecker(context.Background(), dst.Database(), repo)
+	require.NoError(t, checker.CheckChain(best))
+	require.NoError(t, checker.CheckState(best.Root()))
+	assert.Zero(t, checker.issues)
+
+	srcBalance, err := src.Stater().NewState(pivot.Root()).GetBalance(to)
+	require.NoError(t, err)
+	balance, err := dst.Stater().NewState(best.Root()).GetBalance(to)
+	require.NoError(t, err)
+	assert.Equal(t, srcBalance, balance)
+
+	// txs before the pivot expired, but indexed
+	bestChain := repo.NewBestChain()
+	meta, err := bestChain.GetTransactionMeta(txIDs[1])
+	require.NoError(t, err)
+	assert.Equal(t, uint32(2), meta.BlockNum)
+	assert.True(t, meta.Reverted)
+	_, _, err = bestChain.GetTransaction(txIDs[1])
+	assert.True(t, repo.IsExpired(err))
+	_, _, err = bestChain.GetTransaction(txIDs[3])
+	assert.NoError(t, err)
+
+	// logs synced at startup since the pivot
+	logDB, err := logdb.NewMem()
+	require.NoError(t, err)
+	defer logDB.Close()
+	require.NoError(t, syncLogDB(context.Background(), repo, logDB, true))
+	transfers, err := logDB.FilterTransfers(context.Background(), &logdb.TransferFilter{})
+	require.NoError(t, err)
+	require.Len(t, transfers, 1)
+	assert.Equal(t, txIDs[3], transfers[0].TxID)
+
+	// continues with later blocks
+	var archive bytes.Buffer
+	require.NoError(t, exportBlocks(context.Background(), src.Repo(), 5, 6, &archive))
+	ar, err := newBlockArchiveReader(&archive)
+	require.NoError(t, err)
+	importer := newBlockImporter(repo, consensus.New(repo, dst.Stater(), dst.GetForkConfig()), dst.Engine(), dst.GetForkConfig())
+	require.NoError(t, importBlocks(context.Background(), ar, importer))
+	assert.Equal(t, src.Repo().BestBlockSummary().Header.ID(), repo.BestBlockSummary().Header.ID())
+
+	require.NoError(t, syncLogDB(context.Background(), repo, logDB, true))
+	transfers, err = logDB.FilterTransfers(context.Background(), &logdb.TransferFilter{})
+	require.NoError(t, err)
+	assert.Len(t, transfers, 3)
+
+	// corrupted
+	data = append([]byte(nil), data...)
+	data[len(data)-1] ^= 1
+	dst = newDst()
+	assert.ErrorContains(t, importData(dst, data, func(thor.Bytes32, uint32) error { return nil }), "checksum mismatch")
+	assert.Zero(t, dst.Repo().BestBlockSummary().Header.Number())
+}
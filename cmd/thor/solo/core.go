@@ -90,6 +90,11 @@ func (c *Core) Pack(pendingTxs tx.Transactions, onDemand bool) ([]*tx.Transactio
 		return nil, errors.WithMessage(err, "mock packer")
 	}
 
+	if c.options.TxSelector != nil {
+		pendingTxs = c.options.TxSelector.Select(flow, pendingTxs)
+	}
+	flow.Prefetch(pendingTxs)
+
 	startTime := mclock.Now()
 	for _, tx := range pendingTxs {
 		if err := flow.Adopt(tx); err != nil {
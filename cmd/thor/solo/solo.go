@@ -19,6 +19,7 @@ import (
 	"github.com/vechain/thor/v2/chain"
 	"github.com/vechain/thor/v2/genesis"
 	"github.com/vechain/thor/v2/log"
+	"github.com/vechain/thor/v2/packer"
 	"github.com/vechain/thor/v2/state"
 	"github.com/vechain/thor/v2/thor"
 	"github.com/vechain/thor/v2/tx"
@@ -35,6 +36,7 @@ type Options struct {
 	SkipLogs         bool
 	MinTxPriorityFee uint64
 	OnDemand         bool
+	TxSelector       packer.TxSelector // decides the order of txs to be packed, pool order if nil
 }
 
 // Solo mode is the standalone client without p2p server
@@ -20,18 +20,23 @@ import (
 	"github.com/vechain/thor/v2/block"
 	"github.com/vechain/thor/v2/chain"
 	"github.com/vechain/thor/v2/co"
+	"github.com/vechain/thor/v2/log"
 	"github.com/vechain/thor/v2/logdb"
 	"github.com/vechain/thor/v2/thor"
 	"github.com/vechain/thor/v2/tx"
 )
 
+// syncLogDB writes logs of blocks missing in the log db, up to the best block.
+// Blocks whose receipts expired are skipped, since their logs can't be derived any more.
 func syncLogDB(ctx context.Context, repo *chain.Repository, logDB *logdb.LogDB, verify bool) error {
 	startPos, err := seekLogDBSyncPosition(repo, logDB)
 	if err != nil {
 		return errors.Wrap(err, "seek log db sync position")
 	}
-	if verify && startPos > 0 {
-		if err := verifyLogDB(ctx, startPos-1, repo, logDB); err != nil {
+	// block 0 can be skipped
+	expiredBefore := max(repo.HistoryExpiredBefore(), 1)
+	if verify && startPos > expiredBefore {
+		if err := verifyLogDB(ctx, expiredBefore, startPos-1, repo, logDB); err != nil {
 			return errors.Wrap(err, "verify log db")
 		}
 	}
@@ -46,10 +51,15 @@ func syncLogDB(ctx context.Context, repo *chain.Repository, logDB *logdb.LogDB,
 
 	if startPos == 0 {
 		fmt.Println(">> Rebuilding log db <<")
-		startPos = 1 // block 0 can be skipped
 	} else {
 		fmt.Println(">> Syncing log db <<")
 	}
+	if startPos < expiredBefore {
+		if startPos > 1 {
+			log.Warn("logs of expired blocks can't be synced", "from", startPos, "to", expiredBefore-1)
+		}
+		startPos = expiredBefore
+	}
 
 	pb := pb.New64(int64(bestNum)).
 		Set64(int64(startPos - 1)).
@@ -157,10 +167,11 @@ func seekLogDBSyncPosition(repo *chain.Repository, logDB *logdb.LogDB) (uint32,
 	return block.Number(header.ID()) + 1, nil
 }
 
-func verifyLogDB(ctx context.Context, endBlockNum uint32, repo *chain.Repository, logDB *logdb.LogDB) error {
+// verifyLogDB verifies logs of blocks in the range [from, to] against receipts.
+func verifyLogDB(ctx context.Context, from, to uint32, repo *chain.Repository, logDB *logdb.LogDB) error {
 	fmt.Println(">> Verifying log db <<")
-	pb := pb.New64(int64(endBlockNum)).
-		Set64(0).
+	pb := pb.New64(int64(to)).
+		Set64(int64(from - 1)).
 		SetMaxWidth(90).
 		Start()
 	defer func() { pb.NotPrint = true }()
@@ -219,7 +230,7 @@ func verifyLogDB(ctx context.Context, endBlockNum uint32, repo *chain.Repository
 	defer goes.Wait()
 	goes.Go(func() {
 		defer close(ch)
-		pumpErr = pumpBlockAndReceipts(ctx, repo, best.Header.ID(), 1, endBlockNum, ch)
+		pumpErr = pumpBlockAndReceipts(ctx, repo, best.Header.ID(), from, to, ch)
 	})
 
 	defer cancel()
@@ -229,7 +240,7 @@ func verifyLogDB(ctx context.Context, endBlockNum uint32, repo *chain.Repository
 		num := b.Header().Number()
 		if num > logLimit {
 			var err error
-			logLimit += logStep
+			logLimit = num - 1 + logStep
 			evLogs, err = logDB.FilterEvents(context.TODO(), &logdb.EventFilter{
 				Range: &logdb.Range{
 					From: num,
@@ -0,0 +1,54 @@
+// Copyright (c) 2025 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package main
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vechain/thor/v2/genesis"
+	"github.com/vechain/thor/v2/logdb"
+	"github.com/vechain/thor/v2/test/testchain"
+	"github.com/vechain/thor/v2/thor"
+	"github.com/vechain/thor/v2/tx"
+)
+
+func TestSyncLogDBExpired(t *testing.T) {
+	thorChain, err := testchain.NewDefault()
+	require.NoError(t, err)
+	to := thor.BytesToAddress([]byte("to"))
+	for i := range 10 {
+		trx := tx.NewBuilder(tx.TypeLegacy).
+			ChainTag(thorChain.Repo().ChainTag()).
+			Expiration(100).
+			Gas(21000).
+			Nonce(uint64(i)).
+			Clause(tx.NewClause(&to).WithValue(big.NewInt(1))).
+			Build()
+		require.NoError(t, thorChain.MintBlock(tx.MustSign(trx, genesis.DevAccounts()[0].PrivateKey)))
+	}
+	repo := thorChain.Repo()
+	require.NoError(t, repo.ExpireHistory(context.Background(), 5))
+
+	logDB, err := logdb.NewMem()
+	require.NoError(t, err)
+	defer logDB.Close()
+
+	// rebuilt since the expiry
+	require.NoError(t, syncLogDB(context.Background(), repo, logDB, false))
+	transfers, err := logDB.FilterTransfers(context.Background(), &logdb.TransferFilter{})
+	require.NoError(t, err)
+	assert.Len(t, transfers, 6)
+	assert.Equal(t, uint32(5), transfers[0].BlockNumber)
+
+	// verified since the expiry
+	require.NoError(t, repo.ExpireHistory(context.Background(), 8))
+	require.NoError(t, syncLogDB(context.Background(), repo, logDB, true))
+}
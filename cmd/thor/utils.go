@@ -37,13 +37,18 @@ import (
 	"github.com/pkg/errors"
 	"gopkg.in/urfave/cli.v1"
 
+	"github.com/vechain/thor/v2/api/admin/caches"
+	"github.com/vechain/thor/v2/bft"
+	"github.com/vechain/thor/v2/block"
 	"github.com/vechain/thor/v2/builtin/staker"
 	"github.com/vechain/thor/v2/chain"
 	"github.com/vechain/thor/v2/cmd/thor/httpserver"
 	"github.com/vechain/thor/v2/cmd/thor/node"
 	"github.com/vechain/thor/v2/cmd/thor/p2p"
+	"github.com/vechain/thor/v2/cmd/thor/pruner"
 	"github.com/vechain/thor/v2/comm"
 	"github.com/vechain/thor/v2/genesis"
+	"github.com/vechain/thor/v2/light"
 	"github.com/vechain/thor/v2/log"
 	"github.com/vechain/thor/v2/logdb"
 	"github.com/vechain/thor/v2/muxdb"
@@ -53,7 +58,6 @@ import (
 	"github.com/vechain/thor/v2/state"
 	"github.com/vechain/thor/v2/thor"
 	"github.com/vechain/thor/v2/tx"
-	"github.com/vechain/thor/v2/txpool"
 )
 
 func initLogger(ctx *cli.Context) (*slog.LevelVar, error) {
@@ -292,7 +296,7 @@ func makeInstanceDir(ctx *cli.Context, gene *genesis.Genesis) (string, error) {
 	}
 
 	suffix := ""
-	if ctx.Bool(disablePrunerFlag.Name) {
+	if prunerDisabled(ctx) {
 		suffix = "-full"
 	}
 
@@ -303,7 +307,32 @@ func makeInstanceDir(ctx *cli.Context, gene *genesis.Genesis) (string, error) {
 	return instanceDir, nil
 }
 
+// prunerDisabled returns whether the state pruner is disabled, which is implied by archive mode.
+func prunerDisabled(ctx *cli.Context) bool {
+	return ctx.Bool(disablePrunerFlag.Name) || ctx.Bool(archiveFlag.Name)
+}
+
+func prunerOptions(ctx *cli.Context) (pruner.Options, error) {
+	retainBlocks := ctx.Uint64(pruneRetainBlocksFlag.Name)
+	if retainBlocks > math.MaxUint32 {
+		return pruner.Options{}, fmt.Errorf("invalid %v: %v", pruneRetainBlocksFlag.Name, retainBlocks)
+	}
+	historyExpiry := ctx.Uint64(historyExpiryFlag.Name)
+	if historyExpiry > math.MaxUint32 {
+		return pruner.Options{}, fmt.Errorf("invalid %v: %v", historyExpiryFlag.Name, historyExpiry)
+	}
+	return pruner.Options{
+		RetainBlocks:        uint32(retainBlocks),
+		HistoryExpiryBlocks: uint32(historyExpiry),
+	}, nil
+}
+
 func openMainDB(ctx *cli.Context, dir string) (*muxdb.MuxDB, error) {
+	return openMainDBWithEngine(ctx, filepath.Join(dir, "main.db"), ctx.String(dbEngineFlag.Name))
+}
+
+// openMainDBWithEngine opens the main database at the given path with the given storage engine.
+func openMainDBWithEngine(ctx *cli.Context, path string, engine string) (*muxdb.MuxDB, error) {
 	cacheMB := normalizeCacheSize(ctx.Int(cacheFlag.Name))
 	log.Debug("cache size(MB)", "size", cacheMB)
 
@@ -314,10 +343,11 @@ func openMainDB(ctx *cli.Context, dir string) (*muxdb.MuxDB, error) {
 		TrieNodeCacheSizeMB:        cacheMB,
 		TrieCachedNodeTTL:          30, // 5min
 		TrieDedupedPartitionFactor: math.MaxUint32,
-		TrieWillCleanHistory:       !ctx.Bool(disablePrunerFlag.Name),
+		TrieWillCleanHistory:       !prunerDisabled(ctx),
 		OpenFilesCacheCapacity:     fdCache,
 		ReadCacheMB:                256, // rely on os page cache other than huge db read cache.
 		WriteBufferMB:              128,
+		Engine:                     engine,
 	}
 
 	// go-ethereum stuff
@@ -334,7 +364,6 @@ func openMainDB(ctx *cli.Context, dir string) (*muxdb.MuxDB, error) {
 		opts.TrieHistPartitionFactor = 524288
 	}
 
-	path := filepath.Join(dir, "main.db")
 	db, err := muxdb.Open(path, &opts)
 	if err != nil {
 		return nil, errors.Wrapf(err, "open main database [%v]", path)
@@ -347,22 +376,31 @@ func normalizeCacheSize(sizeMB int) int {
 		sizeMB = 128
 	}
 
+	if limitMB := cacheSizeLimit(); limitMB > 0 && sizeMB > limitMB {
+		sizeMB = limitMB
+		log.Warn("cache size(MB) limited", "limit", limitMB)
+	}
+	return sizeMB
+}
+
+// cacheSizeLimit returns the max cache size(MB) allowed by the total memory, 0 if the total memory unknown.
+func cacheSizeLimit() int {
 	var mem gosigar.Mem
 	if err := mem.Get(); err != nil {
 		log.Warn("failed to get total mem:", "err", err)
-	} else {
-		total := int(mem.Total / 1024 / 1024)
-		half := total / 2
+		return 0
+	}
+	total := int(mem.Total / 1024 / 1024)
+	half := total / 2
 
-		// limit to not less than total/2 and up to total-2GB
-		limitMB := max(total-2048, half)
+	// limit to not less than total/2 and up to total-2GB
+	return max(total-2048, half)
+}
 
-		if sizeMB > limitMB {
-			sizeMB = limitMB
-			log.Warn("cache size(MB) limited", "limit", limitMB)
-		}
-	}
-	return sizeMB
+// newCachesAPI creates the caches API. The trie node cache can't be resized beyond the limit of the
+// --cache flag, or the size at startup if the limit unknown.
+func newCachesAPI(mainDB *muxdb.MuxDB, repo *chain.Repository) *caches.Caches {
+	return caches.New(mainDB, repo, max(cacheSizeLimit(), mainDB.TrieCacheStats().SizeMB))
 }
 
 func suggestFDCache() int {
@@ -391,6 +429,56 @@ func openLogDB(dir string, createAdditionalIndexes bool) (*logdb.LogDB, error) {
 	return db, nil
 }
 
+// initStateArchive initializes the state archive with the state of the best block, if not initialized.
+// The archive is reinitialized if its head is behind the best block, which means blocks were committed
+// without archiving, e.g. by a previous run without --archive sharing the same instance dir.
+func initStateArchive(ctx context.Context, repo *chain.Repository, archive *state.Archive) error {
+	best := repo.BestBlockSummary()
+	_, ok, err := archive.Start()
+	if err != nil {
+		return err
+	}
+	if ok {
+		head, ok, err := archive.Head()
+		if err != nil {
+			return err
+		}
+		if ok && head.Major >= best.Header.Number() {
+			return nil
+		}
+		log.Warn("state archive is behind the best block, reinitializing", "head", head.Major, "best", best.Header.Number())
+	}
+	log.Info("initializing state archive, it may take a while...", "block", best.Header.Number())
+	if err := archive.Init(ctx, best.Root()); err != nil {
+		return errors.Wrap(err, "init state archive")
+	}
+	log.Info("state archive initialized")
+	return nil
+}
+
+// initStateSnapshot regenerates the state snapshot with the state of the best block, if the snapshot is not
+// generated, or not at the best block, e.g. the node was not shut down gracefully.
+func initStateSnapshot(ctx context.Context, repo *chain.Repository, snapshot *state.Snapshot) error {
+	best := repo.BestBlockSummary()
+	if root, ok := snapshot.Root(); ok && root == best.Root() {
+		return nil
+	}
+	log.Info("generating state snapshot, it may take a while...", "block", best.Header.Number())
+	if err := snapshot.Generate(ctx, best.Root()); err != nil {
+		return errors.Wrap(err, "generate state snapshot")
+	}
+	log.Info("state snapshot generated")
+	return nil
+}
+
+// flattenStateSnapshot flattens all diff layers of the state snapshot into disk, so that the snapshot is
+// at the best block on the next startup.
+func flattenStateSnapshot(repo *chain.Repository, snapshot *state.Snapshot) {
+	if err := snapshot.Cap(repo.BestBlockSummary().Root(), 0); err != nil {
+		log.Warn("failed to flatten state snapshot", "err", err)
+	}
+}
+
 func initChainRepository(gene *genesis.Genesis, mainDB *muxdb.MuxDB, logDB *logdb.LogDB) (*chain.Repository, error) {
 	genesisBlock, genesisEvents, genesisTransfers, err := gene.Build(state.NewStater(mainDB))
 	if err != nil {
@@ -484,7 +572,7 @@ func loadNodeMaster(ctx *cli.Context) (*node.Master, error) {
 	return master, nil
 }
 
-func newP2PCommunicator(ctx *cli.Context, repo *chain.Repository, txPool *txpool.TxPool, instanceDir string) (*p2p.P2P, error) {
+func newP2PCommunicator(ctx *cli.Context, handler p2p.Handler, instanceDir string) (*p2p.P2P, error) {
 	// known peers will be loaded/stored from/in this file
 	peersCachePath := filepath.Join(instanceDir, "peers.cache")
 
@@ -524,7 +612,7 @@ func newP2PCommunicator(ctx *cli.Context, repo *chain.Repository, txPool *txpool
 	}
 
 	return p2p.New(
-		comm.New(repo, txPool),
+		handler,
 		key,
 		instanceDir,
 		userNAT,
@@ -538,6 +626,86 @@ func newP2PCommunicator(ctx *cli.Context, repo *chain.Repository, txPool *txpool
 	), nil
 }
 
+const (
+	syncModeFull = "full"
+	syncModeSnap = "snap"
+)
+
+// checkSyncMode checks the sync mode, and whether the chain data is compatible with it.
+func checkSyncMode(mode string, repo *chain.Repository) error {
+	switch mode {
+	case syncModeFull:
+		// blocks imported by an interrupted snap sync have no state
+		if repo.BestBlockSummary().Header.Number() == 0 {
+			maxNum, err := repo.GetMaxBlockNum()
+			if err != nil {
+				return err
+			}
+			if maxNum > 0 {
+				return errors.New("incomplete snap sync data found, continue with '--sync-mode snap' or remove the data dir")
+			}
+		}
+		return nil
+	case syncModeSnap:
+		return nil
+	default:
+		return fmt.Errorf("invalid sync mode: %v", mode)
+	}
+}
+
+// parseTrustedCheckpoint parses the trusted checkpoint flag, zero returned if not set.
+func parseTrustedCheckpoint(ctx *cli.Context) (thor.Bytes32, error) {
+	s := ctx.String(trustedCheckpointFlag.Name)
+	if s == "" {
+		return thor.Bytes32{}, nil
+	}
+	trusted, err := thor.ParseBytes32(s)
+	if err != nil {
+		return thor.Bytes32{}, errors.Wrap(err, "parse trusted-checkpoint flag")
+	}
+	return trusted, nil
+}
+
+// snapSync syncs the chain to the last block of the trusted checkpoint's round with the state fetched
+// from peers, if no block synced yet. It retries until succeeded or the context canceled.
+func snapSync(
+	ctx context.Context,
+	repo *chain.Repository,
+	communicator *comm.Communicator,
+	bftEngine *bft.Engine,
+	mainDB *muxdb.MuxDB,
+	trusted thor.Bytes32,
+) error {
+	if repo.BestBlockSummary().Header.Number() > 0 {
+		return nil
+	}
+
+	fmt.Println(">> Snap syncing <<")
+	proposers := light.NewProposerValidator(repo, communicator)
+	for {
+		err := communicator.SnapSync(ctx, trusted, proposers, func(pivot thor.Bytes32, quality uint32) error {
+			if err := bftEngine.Bootstrap(pivot, quality); err != nil {
+				return errors.Wrap(err, "bootstrap bft engine")
+			}
+			// tries before the pivot are incomplete
+			return pruner.SetBase(mainDB, block.Number(pivot))
+		})
+		if err == nil {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		log.Warn("snap sync failed, will retry", "err", err)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(10 * time.Second):
+		}
+	}
+}
+
 func printStartupMessage1(
 	gene *genesis.Genesis,
 	repo *chain.Repository,
@@ -6,8 +6,15 @@
 package main
 
 import (
+	"context"
 	"math"
 	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vechain/thor/v2/state"
+	"github.com/vechain/thor/v2/test/testchain"
 )
 
 func TestReadIntFromUInt64Flag_WithinRange(t *testing.T) {
@@ -37,3 +44,31 @@ func TestReadIntFromUInt64Flag_TooLarge(t *testing.T) {
 		t.Fatalf("expected error for value > MaxInt")
 	}
 }
+
+func TestInitStateArchive(t *testing.T) {
+	thorChain, err := testchain.NewDefault()
+	require.NoError(t, err)
+	require.NoError(t, thorChain.MintBlock())
+	repo := thorChain.Repo()
+	archive := state.NewArchive(thorChain.Database())
+
+	require.NoError(t, initStateArchive(context.Background(), repo, archive))
+	start, ok, err := archive.Start()
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, uint32(1), start.Major)
+
+	// blocks committed without archiving
+	require.NoError(t, thorChain.MintBlock())
+	require.NoError(t, thorChain.MintBlock())
+	require.NoError(t, initStateArchive(context.Background(), repo, archive))
+	start, _, _ = archive.Start()
+	head, _, _ := archive.Head()
+	assert.Equal(t, uint32(3), start.Major)
+	assert.Equal(t, start, head)
+
+	// up to date
+	require.NoError(t, initStateArchive(context.Background(), repo, archive))
+	start, _, _ = archive.Start()
+	assert.Equal(t, uint32(3), start.Major)
+}
@@ -6,6 +6,8 @@
 package comm
 
 import (
+	"time"
+
 	"github.com/ethereum/go-ethereum/rlp"
 
 	"github.com/vechain/thor/v2/block"
@@ -17,6 +19,7 @@ import (
 type announcement struct {
 	newBlockID thor.Bytes32
 	peer       *Peer
+	received   time.Time
 }
 
 func (c *Communicator) announcementLoop() {
@@ -50,7 +53,7 @@ func (c *Communicator) announcementLoop() {
 						case <-c.ctx.Done():
 						}
 					}()
-					c.fetchBlockByID(ann.peer, ann.newBlockID)
+					c.fetchBlockByID(ann)
 				})
 			} else {
 				ann.peer.logger.Debug("skip new block ID announcement")
@@ -59,13 +62,16 @@ func (c *Communicator) announcementLoop() {
 	}
 }
 
-func (c *Communicator) fetchBlockByID(peer *Peer, newBlockID thor.Bytes32) {
-	if _, err := c.repo.GetBlockSummary(newBlockID); err != nil {
+func (c *Communicator) fetchBlockByID(ann *announcement) {
+	peer, newBlockID := ann.peer, ann.newBlockID
+	if summary, err := c.repo.GetBlockSummary(newBlockID); err != nil {
 		if !c.repo.IsNotFound(err) {
 			peer.logger.Error("failed to get block header", "err", err)
 		}
 	} else {
 		// already in chain
+		peer.UpdateHead(newBlockID, summary.Header.TotalScore())
+		peer.RecordAnnouncement(summary.Header.Timestamp(), ann.received)
 		return
 	}
 
@@ -82,8 +88,16 @@ func (c *Communicator) fetchBlockByID(peer *Peer, newBlockID thor.Bytes32) {
 	var blk block.Block
 	if err := rlp.DecodeBytes(result, &blk); err != nil {
 		peer.logger.Debug("failed to decode block got by id", "err", err)
+		peer.RecordInvalidMsg()
+		return
+	}
+	if blk.Header().ID() != newBlockID {
+		peer.logger.Debug("block got by id mismatches")
+		peer.RecordInvalidMsg()
 		return
 	}
+	peer.UpdateHead(newBlockID, blk.Header().TotalScore())
+	peer.RecordAnnouncement(blk.Header().Timestamp(), ann.received)
 
 	c.newBlockFeed.Send(&NewBlockEvent{
 		Block: &blk,
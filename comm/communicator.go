@@ -14,6 +14,7 @@ import (
 	"time"
 
 	"github.com/ethereum/go-ethereum/event"
+	"github.com/pkg/errors"
 
 	"github.com/vechain/thor/v2/block"
 	"github.com/vechain/thor/v2/chain"
@@ -21,6 +22,7 @@ import (
 	"github.com/vechain/thor/v2/log"
 	"github.com/vechain/thor/v2/p2p"
 	"github.com/vechain/thor/v2/p2p/discv5"
+	"github.com/vechain/thor/v2/state"
 	"github.com/vechain/thor/v2/thor"
 	"github.com/vechain/thor/v2/tx"
 	"github.com/vechain/thor/v2/txpool"
@@ -28,9 +30,19 @@ import (
 
 var logger = log.WithContext("pkg", "comm")
 
+// Finality provides the bft finality to serve snap sync.
+type Finality interface {
+	Finalized() thor.Bytes32
+	Quality(id thor.Bytes32) (uint32, error)
+}
+
 // Communicator communicates with remote p2p peers to exchange blocks and txs, etc.
 type Communicator struct {
 	repo           *chain.Repository
+	stater         *state.Stater
+	finality       Finality
+	headerVal      HeaderValidator
+	maxPeers       int
 	txPool         *txpool.TxPool
 	ctx            context.Context
 	cancel         context.CancelFunc
@@ -38,16 +50,20 @@ type Communicator struct {
 	syncedCh       chan struct{}
 	newBlockFeed   event.Feed
 	announcementCh chan *announcement
+	reconstructing sync.Map // IDs of compact blocks being reconstructed
 	feedScope      event.SubscriptionScope
 	goes           sync.WaitGroup
 	onceSynced     sync.Once
 }
 
 // New create a new Communicator instance.
-func New(repo *chain.Repository, txPool *txpool.TxPool) *Communicator {
+// Snap sync is not served if stater or finality is nil.
+func New(repo *chain.Repository, stater *state.Stater, finality Finality, txPool *txpool.TxPool) *Communicator {
 	ctx, cancel := context.WithCancel(context.Background())
 	return &Communicator{
 		repo:           repo,
+		stater:         stater,
+		finality:       finality,
 		txPool:         txPool,
 		ctx:            ctx,
 		cancel:         cancel,
@@ -57,6 +73,20 @@ func New(repo *chain.Repository, txPool *txpool.TxPool) *Communicator {
 	}
 }
 
+// SetHeaderValidator enables header-first sync. Headers of the peer chain are validated before bodies
+// downloaded, and peer chains forking before the local finalized checkpoint are rejected. Finality of the
+// peer chain itself is not tracked. It's also required by SnapSync to validate the history.
+// It should be called before Sync and SnapSync.
+func (c *Communicator) SetHeaderValidator(validator HeaderValidator) {
+	c.headerVal = validator
+}
+
+// SetMaxPeers enables evicting the lowest scored peers periodically, when the peer count reaches maxPeers.
+// It should be called before Start.
+func (c *Communicator) SetMaxPeers(maxPeers int) {
+	c.maxPeers = maxPeers
+}
+
 // Synced returns a channel indicates if synchronization process passed.
 func (c *Communicator) Synced() <-chan struct{} {
 	return c.syncedCh
@@ -94,12 +124,12 @@ func (c *Communicator) Sync(ctx context.Context, handler HandleBlockStream) {
 			logger.Debug("synchronization start")
 
 			best := c.repo.BestBlockSummary().Header
-			// choose peer which has the head block with higher total score
-			peer := c.peerSet.Slice().Find(func(peer *Peer) bool {
+			// choose peers which have the head block with higher total score
+			peers := c.peerSet.Slice().Filter(func(peer *Peer) bool {
 				_, totalScore := peer.Head()
 				return totalScore >= best.TotalScore()
 			})
-			if peer == nil {
+			if len(peers) == 0 {
 				if c.peerSet.Len() < 3 {
 					logger.Debug("no suitable peer to sync")
 					break
@@ -107,11 +137,23 @@ func (c *Communicator) Sync(ctx context.Context, handler HandleBlockStream) {
 				// if more than 3 peers connected, we are assumed to be the best
 				logger.Debug("synchronization done, best assumed")
 			} else {
-				if err := download(ctx, c.repo, peer, best.Number(), handler); err != nil {
-					peer.logger.Debug("synchronization failed", "err", err)
+				sort.Slice(peers, func(i, j int) bool {
+					_, si := peers[i].Head()
+					_, sj := peers[j].Head()
+					return si > sj
+				})
+				var checker *headerChecker
+				if c.headerVal != nil {
+					checker = &headerChecker{validator: c.headerVal}
+					if c.finality != nil {
+						checker.finalized = c.finality.Finalized()
+					}
+				}
+				if err := download(ctx, c.repo, peers, best.Number(), checker, handler); err != nil {
+					peers[0].logger.Debug("synchronization failed", "err", err, "peers", len(peers))
 					break
 				}
-				peer.logger.Debug("synchronization done")
+				peers[0].logger.Debug("synchronization done", "peers", len(peers))
 			}
 			syncCount++
 
@@ -135,6 +177,18 @@ func (c *Communicator) Protocols() []*p2p.Protocol {
 			Length:  proto.Length,
 			Run:     c.servePeer,
 		},
+		{
+			Name:    proto.Name,
+			Version: proto.Version2,
+			Length:  proto.Length2,
+			Run:     c.servePeer,
+		},
+		{
+			Name:    proto.Name,
+			Version: proto.Version1,
+			Length:  proto.Length1,
+			Run:     c.servePeer,
+		},
 	}
 }
 
@@ -148,6 +202,9 @@ func (c *Communicator) DiscTopic() discv5.Topic {
 func (c *Communicator) Start() {
 	c.goes.Go(c.txsLoop)
 	c.goes.Go(c.announcementLoop)
+	if c.maxPeers > 0 {
+		c.goes.Go(c.evictLoop)
+	}
 }
 
 // Stop stop the communicator.
@@ -182,24 +239,9 @@ func (c *Communicator) runPeer(peer *Peer) {
 	ctx, cancel := context.WithTimeout(c.ctx, time.Second*5)
 	defer cancel()
 
-	status, err := proto.GetStatus(ctx, peer)
+	status, err := handshake(ctx, c.repo.GenesisBlock().Header().ID(), peer)
 	if err != nil {
-		peer.logger.Debug("failed to get status", "err", err)
-		return
-	}
-	if status.GenesisBlockID != c.repo.GenesisBlock().Header().ID() {
-		peer.logger.Debug("failed to handshake", "err", "genesis id mismatch")
-		return
-	}
-	localClock := uint64(time.Now().Unix())
-	remoteClock := status.SysTimestamp
-
-	diff := localClock - remoteClock
-	if localClock < remoteClock {
-		diff = remoteClock - localClock
-	}
-	if diff > thor.BlockInterval()*2 {
-		peer.logger.Debug("failed to handshake", "err", "sys time diff too large")
+		peer.logger.Debug("failed to handshake", "err", err)
 		return
 	}
 
@@ -224,6 +266,28 @@ func (c *Communicator) runPeer(peer *Peer) {
 	}
 }
 
+// handshake gets the status of the peer, and checks if it's on the same network with synced clock.
+func handshake(ctx context.Context, genesisID thor.Bytes32, peer *Peer) (*proto.Status, error) {
+	status, err := proto.GetStatus(ctx, peer)
+	if err != nil {
+		return nil, errors.WithMessage(err, "get status")
+	}
+	if status.GenesisBlockID != genesisID {
+		return nil, errors.New("genesis id mismatch")
+	}
+	localClock := uint64(time.Now().Unix())
+	remoteClock := status.SysTimestamp
+
+	diff := localClock - remoteClock
+	if localClock < remoteClock {
+		diff = remoteClock - localClock
+	}
+	if diff > thor.BlockInterval()*2 {
+		return nil, errors.New("sys time diff too large")
+	}
+	return status, nil
+}
+
 // SubscribeBlock subscribe the event that new block received.
 func (c *Communicator) SubscribeBlock(ch chan *NewBlockEvent) event.Subscription {
 	return c.feedScope.Track(c.newBlockFeed.Subscribe(ch))
@@ -235,6 +299,24 @@ func (c *Communicator) BroadcastBlock(blk *block.Block) {
 		return !p.IsBlockKnown(blk.Header().ID())
 	})
 
+	// compact blocks are about the size of block ID announcements plus tx IDs, and are reconstructed
+	// mostly from tx pools, so they are sent to all peers supporting them
+	compactPeers := peers.Filter(func(p *Peer) bool {
+		return p.ProtoVersion() >= proto.Version
+	})
+	peers = peers.Filter(func(p *Peer) bool {
+		return p.ProtoVersion() < proto.Version
+	})
+
+	for _, peer := range compactPeers {
+		peer.MarkBlock(blk.Header().ID())
+		c.goes.Go(func() {
+			if err := proto.NotifyNewCompactBlock(c.ctx, peer, blk); err != nil {
+				peer.logger.Debug("failed to broadcast new compact block", "err", err)
+			}
+		})
+	}
+
 	p := int(math.Sqrt(float64(len(peers))))
 	toPropagate := peers[:p]
 	toAnnounce := peers[p:]
@@ -265,21 +347,5 @@ func (c *Communicator) PeerCount() int {
 
 // PeersStats returns all peers' stats
 func (c *Communicator) PeersStats() []*PeerStats {
-	var stats []*PeerStats
-	for _, peer := range c.peerSet.Slice() {
-		bestID, totalScore := peer.Head()
-		stats = append(stats, &PeerStats{
-			Name:        peer.Name(),
-			BestBlockID: bestID,
-			TotalScore:  totalScore,
-			PeerID:      peer.ID().String(),
-			NetAddr:     peer.RemoteAddr().String(),
-			Inbound:     peer.Inbound(),
-			Duration:    uint64(time.Duration(peer.Duration()) / time.Second),
-		})
-	}
-	sort.Slice(stats, func(i, j int) bool {
-		return stats[i].Duration < stats[j].Duration
-	})
-	return stats
+	return peersStats(c.peerSet.Slice(), c.repo.BestBlockSummary().Header.TotalScore())
 }
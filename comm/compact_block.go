@@ -0,0 +1,95 @@
+// Copyright (c) 2025 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package comm
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/vechain/thor/v2/block"
+	"github.com/vechain/thor/v2/comm/proto"
+	"github.com/vechain/thor/v2/tx"
+)
+
+// reconstructBlock reconstructs the block of a compact block with txs in the tx pool, and the missing ones
+// fetched from the peer. If failed, the full block is fetched as it's announced by ID.
+func (c *Communicator) reconstructBlock(peer *Peer, compact *proto.CompactBlock, received time.Time) {
+	id := compact.Header.ID()
+	// the same block may be received from many peers
+	if _, loaded := c.reconstructing.LoadOrStore(id, struct{}{}); loaded {
+		return
+	}
+	defer c.reconstructing.Delete(id)
+
+	if _, err := c.repo.GetBlockSummary(id); err != nil {
+		if !c.repo.IsNotFound(err) {
+			peer.logger.Error("failed to get block header", "err", err)
+			return
+		}
+	} else {
+		// already in chain
+		return
+	}
+
+	blk, fetched, err := c.assembleBlock(peer, compact)
+	if err != nil {
+		peer.logger.Debug("failed to reconstruct compact block", "err", err)
+		metricCompactBlockCount().AddWithLabel(1, map[string]string{"result": "failed"})
+		select {
+		case <-c.ctx.Done():
+		case c.announcementCh <- &announcement{id, peer, received}:
+		}
+		return
+	}
+	if fetched > 0 {
+		metricCompactBlockCount().AddWithLabel(1, map[string]string{"result": "fetched"})
+	} else {
+		metricCompactBlockCount().AddWithLabel(1, map[string]string{"result": "pooled"})
+	}
+	c.newBlockFeed.Send(&NewBlockEvent{Block: blk})
+}
+
+// assembleBlock assembles the block of a compact block, and returns the count of txs fetched from the peer.
+func (c *Communicator) assembleBlock(peer *Peer, compact *proto.CompactBlock) (*block.Block, int, error) {
+	txs := make(tx.Transactions, len(compact.TxIDs))
+	var missing []uint32
+	for i, id := range compact.TxIDs {
+		if c.txPool != nil {
+			if trx := c.txPool.Get(id); trx != nil {
+				txs[i] = trx
+				continue
+			}
+		}
+		missing = append(missing, uint32(i))
+	}
+
+	if len(missing) > 0 {
+		fetched, err := proto.GetBlockTxs(c.ctx, peer, &proto.BlockTxsRequest{
+			BlockID: compact.Header.ID(),
+			Indexes: missing,
+		})
+		if err != nil {
+			return nil, 0, errors.WithMessage(err, "get block txs")
+		}
+		if len(fetched) != len(missing) {
+			return nil, 0, errors.New("missing txs not fetched")
+		}
+		for i, index := range missing {
+			if fetched[i].ID() != compact.TxIDs[index] {
+				peer.RecordInvalidMsg()
+				return nil, 0, errors.New("fetched tx mismatches")
+			}
+			txs[index] = fetched[i]
+		}
+	}
+
+	// pooled txs might differ from those of the block with the same ID, e.g. signed again
+	if txs.RootHash() != compact.Header.TxsRoot() {
+		return nil, 0, errors.New("txs root mismatch")
+	}
+	return block.Compose(compact.Header, txs), len(missing), nil
+}
@@ -0,0 +1,142 @@
+// Copyright (c) 2025 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package comm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vechain/thor/v2/block"
+	"github.com/vechain/thor/v2/comm/proto"
+	"github.com/vechain/thor/v2/genesis"
+	"github.com/vechain/thor/v2/muxdb"
+	"github.com/vechain/thor/v2/p2p"
+	"github.com/vechain/thor/v2/state"
+	"github.com/vechain/thor/v2/test/datagen"
+	"github.com/vechain/thor/v2/test/testchain"
+	"github.com/vechain/thor/v2/thor"
+	"github.com/vechain/thor/v2/tx"
+	"github.com/vechain/thor/v2/txpool"
+)
+
+func newCompactTestChain(t *testing.T) (*testchain.Chain, *block.Block) {
+	chain, err := testchain.NewDefault()
+	require.NoError(t, err)
+
+	var txs tx.Transactions
+	for _, acc := range genesis.DevAccounts()[:3] {
+		to := datagen.RandAddress()
+		trx := tx.NewBuilder(tx.TypeLegacy).
+			ChainTag(chain.Repo().ChainTag()).
+			Expiration(1000).
+			Gas(21000).
+			Nonce(datagen.RandUint64()).
+			Clause(tx.NewClause(&to)).
+			Build()
+		txs = append(txs, tx.MustSign(trx, acc.PrivateKey))
+	}
+	require.NoError(t, chain.MintBlock(txs...))
+	blk, err := chain.BestBlock()
+	require.NoError(t, err)
+	require.Len(t, blk.Transactions(), 3)
+	return chain, blk
+}
+
+func compactOf(blk *block.Block) *proto.CompactBlock {
+	compact := &proto.CompactBlock{Header: blk.Header()}
+	for _, trx := range blk.Transactions() {
+		compact.TxIDs = append(compact.TxIDs, trx.ID())
+	}
+	return compact
+}
+
+func TestReconstructBlock(t *testing.T) {
+	remote, blk := newCompactTestChain(t)
+	server := New(remote.Repo(), nil, nil, nil)
+	peer := newPipedPeer(t, func(peer *Peer, msg *p2p.Msg, write func(any)) error {
+		return server.handleRPC(peer, msg, write, &txsToSync{})
+	}, blk.Header())
+
+	repo := newLocalRepo(t, remote)
+	pool := txpool.New(repo, state.NewStater(muxdb.NewMem()), txpool.Options{
+		Limit:           100,
+		LimitPerAccount: 100,
+		MaxLifetime:     time.Hour,
+	}, remote.GetForkConfig())
+	defer pool.Close()
+	// one of txs missing in the pool
+	pool.Fill(blk.Transactions()[:2])
+
+	local := New(repo, nil, nil, pool)
+	defer local.Stop()
+	ch := make(chan *NewBlockEvent, 1)
+	sub := local.SubscribeBlock(ch)
+	defer sub.Unsubscribe()
+
+	local.reconstructBlock(peer, compactOf(blk), time.Now())
+	select {
+	case ev := <-ch:
+		assert.Equal(t, blk.Header().ID(), ev.Block.Header().ID())
+		assert.Equal(t, blk.Transactions().RootHash(), ev.Block.Transactions().RootHash())
+	case <-time.After(5 * time.Second):
+		t.Fatal("block not reconstructed")
+	}
+
+	// txs reordered, fall back to fetch the full block
+	compact := compactOf(blk)
+	compact.TxIDs[0], compact.TxIDs[1] = compact.TxIDs[1], compact.TxIDs[0]
+	go local.reconstructBlock(peer, compact, time.Now())
+	select {
+	case ann := <-local.announcementCh:
+		assert.Equal(t, blk.Header().ID(), ann.newBlockID)
+	case <-time.After(5 * time.Second):
+		t.Fatal("not fell back")
+	}
+
+	// the peer doesn't have the block
+	empty := New(newLocalRepo(t, remote), nil, nil, nil)
+	peer = newPipedPeer(t, func(peer *Peer, msg *p2p.Msg, write func(any)) error {
+		return empty.handleRPC(peer, msg, write, &txsToSync{})
+	}, blk.Header())
+	go local.reconstructBlock(peer, compactOf(blk), time.Now())
+	select {
+	case ann := <-local.announcementCh:
+		assert.Equal(t, blk.Header().ID(), ann.newBlockID)
+	case <-time.After(5 * time.Second):
+		t.Fatal("not fell back")
+	}
+}
+
+func TestServeBlockTxs(t *testing.T) {
+	remote, blk := newCompactTestChain(t)
+	server := New(remote.Repo(), nil, nil, nil)
+	peer := newPipedPeer(t, func(peer *Peer, msg *p2p.Msg, write func(any)) error {
+		return server.handleRPC(peer, msg, write, &txsToSync{})
+	}, blk.Header())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	txs, err := proto.GetBlockTxs(ctx, peer, &proto.BlockTxsRequest{BlockID: blk.Header().ID(), Indexes: []uint32{2, 0}})
+	require.NoError(t, err)
+	require.Len(t, txs, 2)
+	assert.Equal(t, blk.Transactions()[2].ID(), txs[0].ID())
+	assert.Equal(t, blk.Transactions()[0].ID(), txs[1].ID())
+
+	txs, err = proto.GetBlockTxs(ctx, peer, &proto.BlockTxsRequest{BlockID: thor.Bytes32{1}, Indexes: []uint32{0}})
+	require.NoError(t, err)
+	assert.Empty(t, txs)
+
+	// rejected, and the peer is disconnected
+	ctx, cancel = context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	_, err = proto.GetBlockTxs(ctx, peer, &proto.BlockTxsRequest{BlockID: blk.Header().ID(), Indexes: []uint32{3}})
+	assert.Error(t, err)
+}
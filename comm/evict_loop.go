@@ -0,0 +1,66 @@
+// Copyright (c) 2025 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package comm
+
+import (
+	"sort"
+	"time"
+
+	"github.com/vechain/thor/v2/p2p"
+)
+
+const (
+	evictInterval       = time.Minute
+	evictGracePeriod    = 5 * time.Minute // peers connected shorter are not evicted
+	evictScoreThreshold = 50              // peers scored lower are candidates to be evicted
+	evictMaxRatio       = 10              // at most 1/10 of peers evicted each round
+)
+
+// evictLoop periodically disconnects the worst peers when the peer count reaches the limit,
+// to make room for better ones.
+func (c *Communicator) evictLoop() {
+	ticker := time.NewTicker(evictInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.ctx.Done():
+			return
+		case <-ticker.C:
+			for _, peer := range c.peersToEvict() {
+				peer.logger.Debug("evict peer", "score", peer.Score(c.repo.BestBlockSummary().Header.TotalScore()))
+				metricEvictedPeers().Add(1)
+				peer.Disconnect(p2p.DiscUselessPeer)
+			}
+		}
+	}
+}
+
+// peersToEvict returns the lowest scored peers to be evicted.
+func (c *Communicator) peersToEvict() Peers {
+	peers := c.peerSet.Slice()
+	if c.maxPeers <= 0 || len(peers) < c.maxPeers {
+		return nil
+	}
+
+	bestTotalScore := c.repo.BestBlockSummary().Header.TotalScore()
+	scores := make(map[*Peer]int, len(peers))
+	candidates := peers.Filter(func(p *Peer) bool {
+		if p.Trusted() || time.Duration(p.Duration()) < evictGracePeriod {
+			return false
+		}
+		scores[p] = p.Score(bestTotalScore)
+		return scores[p] < evictScoreThreshold
+	})
+	sort.Slice(candidates, func(i, j int) bool {
+		return scores[candidates[i]] < scores[candidates[j]]
+	})
+
+	if n := max(len(peers)/evictMaxRatio, 1); len(candidates) > n {
+		candidates = candidates[:n]
+	}
+	return candidates
+}
@@ -17,6 +17,14 @@ import (
 	"github.com/vechain/thor/v2/p2p"
 	"github.com/vechain/thor/v2/thor"
 	"github.com/vechain/thor/v2/tx"
+	"github.com/vechain/thor/v2/txpool"
+)
+
+const (
+	maxSnapRangeSize = 512 * 1024 // max size of a snap sync response
+	maxSnapCodes     = 256        // max count of codes per request
+	maxSnapReceipts  = 256        // max count of blocks' receipts per request
+	maxAccountProofs = 64         // max count of account proofs per request
 )
 
 // peer will be disconnected if error returned
@@ -50,6 +58,7 @@ func (c *Communicator) handleRPC(peer *Peer, msg *p2p.Msg, write func(any), txsT
 
 		peer.MarkBlock(newBlock.Header().ID())
 		peer.UpdateHead(newBlock.Header().ID(), newBlock.Header().TotalScore())
+		peer.RecordAnnouncement(newBlock.Header().Timestamp(), time.Now())
 		c.newBlockFeed.Send(&NewBlockEvent{Block: newBlock})
 		write(&struct{}{})
 	case proto.MsgNewBlockID:
@@ -60,16 +69,55 @@ func (c *Communicator) handleRPC(peer *Peer, msg *p2p.Msg, write func(any), txsT
 		peer.MarkBlock(newBlockID)
 		select {
 		case <-c.ctx.Done():
-		case c.announcementCh <- &announcement{newBlockID, peer}:
+		case c.announcementCh <- &announcement{newBlockID, peer, time.Now()}:
 		}
 		write(&struct{}{})
+	case proto.MsgNewCompactBlock:
+		var compact proto.CompactBlock
+		if err := msg.Decode(&compact); err != nil {
+			return errors.WithMessage(err, "decode msg")
+		}
+		if compact.Header == nil {
+			return errors.New("nil header")
+		}
+		received := time.Now()
+		peer.MarkBlock(compact.Header.ID())
+		peer.UpdateHead(compact.Header.ID(), compact.Header.TotalScore())
+		peer.RecordAnnouncement(compact.Header.Timestamp(), received)
+		c.goes.Go(func() {
+			c.reconstructBlock(peer, &compact, received)
+		})
+		write(&struct{}{})
 	case proto.MsgNewTx:
 		var newTx *tx.Transaction
 		if err := msg.Decode(&newTx); err != nil {
 			return errors.WithMessage(err, "decode msg")
 		}
 		peer.MarkTransaction(newTx.Hash())
-		_ = c.txPool.Add(newTx)
+		if !peer.txGate.Allow() {
+			metricRemoteTxCount().AddWithLabel(1, map[string]string{"result": "dropped"})
+			write(&struct{}{})
+			break
+		}
+		switch err := c.txPool.Add(newTx); {
+		case err == nil:
+			metricRemoteTxCount().AddWithLabel(1, map[string]string{"result": "accepted"})
+			peer.txGate.Record(true)
+		case txpool.IsBadTx(err):
+			// invalid whatever the chain state is, so the peer should not have relayed it
+			metricRemoteTxCount().AddWithLabel(1, map[string]string{"result": "rejected"})
+			peer.txGate.Record(false)
+			if peer.txGate.IsHarmful() {
+				metricHarmfulPeerDisconnects().Add(1)
+				return errors.New("too many rejected txs")
+			}
+		case txpool.IsPoolFull(err):
+			// local pool pressure, not the peer's fault
+			metricRemoteTxCount().AddWithLabel(1, map[string]string{"result": "pool_full"})
+		default:
+			// rejected by the local chain state or pool quota, e.g. already packed, which the peer may not know yet
+			metricRemoteTxCount().AddWithLabel(1, map[string]string{"result": "ignored"})
+		}
 		write(&struct{}{})
 	case proto.MsgGetBlockByID:
 		var blockID thor.Bytes32
@@ -79,7 +127,7 @@ func (c *Communicator) handleRPC(peer *Peer, msg *p2p.Msg, write func(any), txsT
 		var result []rlp.RawValue
 		b, err := c.repo.GetBlock(blockID)
 		if err != nil {
-			if !c.repo.IsNotFound(err) {
+			if !c.repo.IsNotFound(err) && !c.repo.IsExpired(err) {
 				log.Error("failed to get block", "err", err)
 			}
 		} else {
@@ -87,6 +135,31 @@ func (c *Communicator) handleRPC(peer *Peer, msg *p2p.Msg, write func(any), txsT
 			result = append(result, rlp.RawValue(raw))
 		}
 		write(result)
+	case proto.MsgGetBlockTxs:
+		var req proto.BlockTxsRequest
+		if err := msg.Decode(&req); err != nil {
+			return errors.WithMessage(err, "decode msg")
+		}
+		var result tx.Transactions
+		b, err := c.repo.GetBlock(req.BlockID)
+		if err != nil {
+			if !c.repo.IsNotFound(err) && !c.repo.IsExpired(err) {
+				log.Error("failed to get block", "err", err)
+			}
+		} else {
+			txs := b.Transactions()
+			if len(req.Indexes) > len(txs) {
+				return fmt.Errorf("too many txs requested (%v)", len(req.Indexes))
+			}
+			result = make(tx.Transactions, 0, len(req.Indexes))
+			for _, i := range req.Indexes {
+				if int(i) >= len(txs) {
+					return fmt.Errorf("tx index out of range (%v)", i)
+				}
+				result = append(result, txs[i])
+			}
+		}
+		write(result)
 	case proto.MsgGetBlockIDByNumber:
 		var num uint32
 		if err := msg.Decode(&num); err != nil {
@@ -113,10 +186,12 @@ func (c *Communicator) handleRPC(peer *Peer, msg *p2p.Msg, write func(any), txsT
 		result := make([]rlp.RawValue, 0, maxBlocks)
 		var size thor.StorageSize
 		chain := c.repo.NewBestChain()
-		for size < maxSize && len(result) < maxBlocks {
+		// blocks with expired txs are never served, even if they have no tx
+		expiredBefore := c.repo.HistoryExpiredBefore()
+		for num >= expiredBefore && size < maxSize && len(result) < maxBlocks {
 			b, err := chain.GetBlock(num)
 			if err != nil {
-				if !c.repo.IsNotFound(err) {
+				if !c.repo.IsNotFound(err) && !c.repo.IsExpired(err) {
 					log.Error("failed to get block raw by number", "err", err)
 				}
 				break
@@ -166,6 +241,164 @@ func (c *Communicator) handleRPC(peer *Peer, msg *p2p.Msg, write func(any), txsT
 			}
 			write(toSend)
 		}
+	case proto.MsgGetSnapPivot:
+		if err := msg.Decode(&struct{}{}); err != nil {
+			return errors.WithMessage(err, "decode msg")
+		}
+		var pivot proto.SnapPivot
+		if c.stater != nil && c.finality != nil {
+			// the pivot is the last block of the round before the finalized checkpoint,
+			// whose quality is known and the state is kept by the pruner.
+			if num := block.Number(c.finality.Finalized()); num > 0 {
+				id, err := c.repo.NewBestChain().GetBlockID(num - 1)
+				if err != nil {
+					log.Error("failed to get snap pivot", "err", err)
+				} else if quality, err := c.finality.Quality(id); err != nil {
+					log.Error("failed to get snap pivot quality", "err", err)
+				} else if quality > 0 {
+					pivot = proto.SnapPivot{BlockID: id, Quality: quality}
+				}
+			}
+		}
+		write(&pivot)
+	case proto.MsgGetAccountRange:
+		var req proto.AccountRangeRequest
+		if err := msg.Decode(&req); err != nil {
+			return errors.WithMessage(err, "decode msg")
+		}
+		var result proto.TrieRange
+		if c.stater != nil {
+			if summary, err := c.repo.GetBlockSummary(req.BlockID); err != nil {
+				if !c.repo.IsNotFound(err) {
+					log.Error("failed to get block summary", "err", err)
+				}
+			} else if r, err := c.stater.AccountRange(summary.Root(), req.Origin, maxSnapRangeSize); err != nil {
+				log.Debug("failed to get account range", "err", err)
+			} else {
+				result = proto.TrieRange{Keys: r.Keys, Values: r.Values, Proof: r.Proof}
+			}
+		}
+		write(&result)
+	case proto.MsgGetStorageRange:
+		var req proto.StorageRangeRequest
+		if err := msg.Decode(&req); err != nil {
+			return errors.WithMessage(err, "decode msg")
+		}
+		var result proto.TrieRange
+		if c.stater != nil {
+			if summary, err := c.repo.GetBlockSummary(req.BlockID); err != nil {
+				if !c.repo.IsNotFound(err) {
+					log.Error("failed to get block summary", "err", err)
+				}
+			} else if r, err := c.stater.StorageRange(summary.Root(), req.Account, req.Origin, maxSnapRangeSize); err != nil {
+				log.Debug("failed to get storage range", "err", err)
+			} else {
+				result = proto.TrieRange{Keys: r.Keys, Values: r.Values, Metas: r.Metas, Proof: r.Proof}
+			}
+		}
+		write(&result)
+	case proto.MsgGetCodes:
+		var hashes []thor.Bytes32
+		if err := msg.Decode(&hashes); err != nil {
+			return errors.WithMessage(err, "decode msg")
+		}
+		if len(hashes) > maxSnapCodes {
+			return fmt.Errorf("too many codes requested (%v)", len(hashes))
+		}
+		codes := make([][]byte, 0, len(hashes))
+		var size int
+		if c.stater != nil {
+			for _, hash := range hashes {
+				code, err := c.stater.Code(hash)
+				if err != nil {
+					log.Error("failed to get code", "err", err)
+					break
+				}
+				codes = append(codes, code)
+				if size += len(code); size >= maxSnapRangeSize {
+					break
+				}
+			}
+		}
+		write(codes)
+	case proto.MsgGetReceipts:
+		var ids []thor.Bytes32
+		if err := msg.Decode(&ids); err != nil {
+			return errors.WithMessage(err, "decode msg")
+		}
+		if len(ids) > maxSnapReceipts {
+			return fmt.Errorf("too many receipts requested (%v)", len(ids))
+		}
+		result := make([]rlp.RawValue, 0, len(ids))
+		var size int
+		for _, id := range ids {
+			receipts, err := c.repo.GetBlockReceipts(id)
+			if err != nil {
+				if !c.repo.IsNotFound(err) && !c.repo.IsExpired(err) {
+					log.Error("failed to get receipts", "err", err)
+				}
+				break
+			}
+			raw, _ := rlp.EncodeToBytes(receipts)
+			result = append(result, rlp.RawValue(raw))
+			if size += len(raw); size >= maxSnapRangeSize {
+				break
+			}
+		}
+		write(result)
+	case proto.MsgGetHeadersFromNumber:
+		var num uint32
+		if err := msg.Decode(&num); err != nil {
+			return errors.WithMessage(err, "decode msg")
+		}
+
+		const maxHeaders = 1024
+		result := make([]rlp.RawValue, 0, maxHeaders)
+		chain := c.repo.NewBestChain()
+		for len(result) < maxHeaders {
+			id, err := chain.GetBlockID(num)
+			if err != nil {
+				if !c.repo.IsNotFound(err) {
+					log.Error("failed to get block id by number", "err", err)
+				}
+				break
+			}
+			summary, err := c.repo.GetBlockSummary(id)
+			if err != nil {
+				log.Error("failed to get block summary", "err", err)
+				break
+			}
+			raw, _ := rlp.EncodeToBytes(summary.Header)
+			result = append(result, rlp.RawValue(raw))
+			num++
+		}
+		write(result)
+	case proto.MsgGetAccountProofs:
+		var req proto.AccountProofsRequest
+		if err := msg.Decode(&req); err != nil {
+			return errors.WithMessage(err, "decode msg")
+		}
+		if len(req.Addresses) > maxAccountProofs {
+			return fmt.Errorf("too many account proofs requested (%v)", len(req.Addresses))
+		}
+		proofs := make([][][]byte, 0, len(req.Addresses))
+		if c.stater != nil {
+			if summary, err := c.repo.GetBlockSummary(req.BlockID); err != nil {
+				if !c.repo.IsNotFound(err) {
+					log.Error("failed to get block summary", "err", err)
+				}
+			} else {
+				for _, addr := range req.Addresses {
+					p, err := c.stater.Prove(summary.Root(), addr, nil)
+					if err != nil {
+						log.Debug("failed to prove account", "err", err)
+						break
+					}
+					proofs = append(proofs, p.Proof)
+				}
+			}
+		}
+		write(proofs)
 	default:
 		return fmt.Errorf("unknown message (%v)", msg.Code)
 	}
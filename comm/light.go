@@ -0,0 +1,383 @@
+// Copyright (c) 2025 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package comm
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/pkg/errors"
+
+	"github.com/vechain/thor/v2/block"
+	"github.com/vechain/thor/v2/chain"
+	"github.com/vechain/thor/v2/comm/proto"
+	"github.com/vechain/thor/v2/muxdb"
+	"github.com/vechain/thor/v2/p2p"
+	"github.com/vechain/thor/v2/p2p/discv5"
+	"github.com/vechain/thor/v2/state"
+	"github.com/vechain/thor/v2/thor"
+	"github.com/vechain/thor/v2/trie"
+	"github.com/vechain/thor/v2/tx"
+)
+
+// HandleHeaders handles a segment of validated headers, which are in sequence.
+type HandleHeaders func(ctx context.Context, headers []*block.Header) error
+
+// Light communicates with remote peers for a light node, which follows the chain by headers,
+// and fetches parts of the state with merkle proofs on demand.
+// It serves nothing but the status to peers, and claims no blocks to be synced from.
+type Light struct {
+	repo    *chain.Repository
+	ctx     context.Context
+	cancel  context.CancelFunc
+	peerSet *PeerSet
+	syncCh  chan struct{}
+	goes    sync.WaitGroup
+}
+
+// NewLight creates a new Light instance.
+func NewLight(repo *chain.Repository) *Light {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Light{
+		repo:    repo,
+		ctx:     ctx,
+		cancel:  cancel,
+		peerSet: newPeerSet(),
+		syncCh:  make(chan struct{}, 1),
+	}
+}
+
+// Protocols returns the supported protocols.
+// Only the latest version is supported, since account proofs are required.
+func (l *Light) Protocols() []*p2p.Protocol {
+	return []*p2p.Protocol{
+		{
+			Name:    proto.Name,
+			Version: proto.Version,
+			Length:  proto.Length,
+			Run:     l.servePeer,
+		},
+	}
+}
+
+// DiscTopic returns the topic for p2p network discovery.
+func (l *Light) DiscTopic() discv5.Topic {
+	genesisID := l.repo.GenesisBlock().Header().ID()
+	return discv5.Topic(fmt.Sprintf("%v1@%x", proto.Name, genesisID[24:]))
+}
+
+// Start starts the light communicator.
+func (l *Light) Start() {}
+
+// Stop stops the light communicator.
+func (l *Light) Stop() {
+	l.cancel()
+	l.goes.Wait()
+}
+
+// PeerCount returns count of peers.
+func (l *Light) PeerCount() int {
+	return l.peerSet.Len()
+}
+
+// PeersStats returns all peers' stats.
+func (l *Light) PeersStats() []*PeerStats {
+	return peersStats(l.peerSet.Slice(), l.repo.BestBlockSummary().Header.TotalScore())
+}
+
+// Sync starts the synchronization process of headers, which is triggered periodically, or by
+// block announcements. Headers are fetched from the peer with the highest total score, and validated
+// by the validator before passed to the handler.
+// The peer is disconnected if the validator or the handler rejects its headers.
+func (l *Light) Sync(ctx context.Context, validator HeaderValidator, handler HandleHeaders) {
+	const syncInterval = 10 * time.Second
+
+	ticker := time.NewTicker(syncInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-l.syncCh:
+		}
+
+		best := l.repo.BestBlockSummary().Header
+		peers := l.peerSet.Slice().Filter(func(peer *Peer) bool {
+			_, totalScore := peer.Head()
+			return totalScore > best.TotalScore()
+		})
+		if len(peers) == 0 {
+			continue
+		}
+		sort.Slice(peers, func(i, j int) bool {
+			_, si := peers[i].Head()
+			_, sj := peers[j].Head()
+			return si > sj
+		})
+		peer := peers[0]
+		if err := l.syncHeaders(ctx, peer, best, validator, handler); err != nil {
+			peer.logger.Debug("failed to sync headers", "err", err)
+			continue
+		}
+		peer.logger.Debug("header synchronization done")
+	}
+}
+
+func (l *Light) syncHeaders(ctx context.Context, peer *Peer, best *block.Header, validator HeaderValidator, handler HandleHeaders) error {
+	ancestor, err := findCommonAncestor(ctx, l.repo, peer, best.Number())
+	if err != nil {
+		return errors.WithMessage(err, "find common ancestor")
+	}
+	parent, err := l.repo.NewBestChain().GetBlockHeader(ancestor)
+	if err != nil {
+		return errors.WithMessage(err, "get ancestor header")
+	}
+
+	for {
+		headers, err := fetchHeaders(ctx, peer, parent, validator)
+		if err != nil {
+			if ctx.Err() == nil {
+				peer.Disconnect(p2p.DiscUselessPeer)
+			}
+			return errors.WithMessage(err, "fetch headers")
+		}
+		if len(headers) == 0 {
+			return nil
+		}
+		if err := handler(ctx, headers); err != nil {
+			if ctx.Err() == nil {
+				peer.Disconnect(p2p.DiscUselessPeer)
+			}
+			return errors.WithMessage(err, "handle headers")
+		}
+		parent = headers[len(headers)-1]
+	}
+}
+
+// FetchState fetches the accounts of the given addresses in the state of the given block, along with
+// their full storage, and returns an in-memory state containing the storage.
+// All data fetched is verified against the state root of the header.
+func (l *Light) FetchState(ctx context.Context, header *block.Header, addrs []thor.Address) (*state.State, error) {
+	peers := l.peerSet.Slice().Filter(func(peer *Peer) bool {
+		id, _ := peer.Head()
+		return block.Number(id) >= header.Number()
+	})
+	return fetchState(ctx, newSnapPeerSet(peers), header, addrs)
+}
+
+// fetchState fetches the accounts along with their full storage from the peer set, see Light.FetchState.
+func fetchState(ctx context.Context, ps *snapPeerSet, header *block.Header, addrs []thor.Address) (*state.State, error) {
+	if len(addrs) > maxAccountProofs {
+		return nil, fmt.Errorf("too many accounts (%v)", len(addrs))
+	}
+
+	var accounts []*state.Account
+	if err := ps.request(ctx, "get account proofs", func(ctx context.Context, peer *Peer) error {
+		proofs, err := proto.GetAccountProofs(ctx, peer, &proto.AccountProofsRequest{BlockID: header.ID(), Addresses: addrs})
+		if err != nil {
+			return err
+		}
+		if len(proofs) != len(addrs) {
+			return errors.New("state unavailable")
+		}
+		accounts = make([]*state.Account, 0, len(addrs))
+		for i, addr := range addrs {
+			data, err := trie.VerifyProof(header.StateRoot(), thor.Blake2b(addr[:]).Bytes(), proofs[i])
+			if err != nil {
+				return errors.WithMessage(err, "verify account proof")
+			}
+			var acc state.Account
+			if len(data) > 0 {
+				if err := rlp.DecodeBytes(data, &acc); err != nil {
+					return errors.WithMessage(err, "decode account")
+				}
+			}
+			accounts = append(accounts, &acc)
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	st := state.New(muxdb.NewMem(), trie.Root{})
+	for i, addr := range addrs {
+		if len(accounts[i].StorageRoot) == 0 {
+			continue
+		}
+		accountKey := thor.Blake2b(addr[:])
+		if err := syncSnapRange(ctx, ps, thor.BytesToBytes32(accounts[i].StorageRoot), thor.Bytes32{}, thor.Bytes32{}, func(ctx context.Context, peer *Peer, origin thor.Bytes32) (*proto.TrieRange, error) {
+			r, err := proto.GetStorageRange(ctx, peer, &proto.StorageRangeRequest{BlockID: header.ID(), Account: accountKey, Origin: origin})
+			if err != nil {
+				return nil, err
+			}
+			if err := state.VerifyStorageKeys(r.Keys, r.Metas); err != nil {
+				return nil, err
+			}
+			return r, nil
+		}, func(r *proto.TrieRange) error {
+			for i, meta := range r.Metas {
+				st.SetRawStorage(addr, thor.BytesToBytes32(meta), r.Values[i])
+			}
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+	}
+	return st, nil
+}
+
+func (l *Light) servePeer(p *p2p.Peer, rw p2p.MsgReadWriter) error {
+	peer := newPeer(p, rw)
+	l.goes.Go(func() {
+		l.runPeer(peer)
+	})
+
+	return peer.Serve(func(msg *p2p.Msg, w func(any)) error {
+		return l.handleRPC(peer, msg, w)
+	}, proto.MaxMsgSize)
+}
+
+func (l *Light) runPeer(peer *Peer) {
+	defer peer.Disconnect(p2p.DiscRequested)
+
+	// 5sec timeout for handshake
+	ctx, cancel := context.WithTimeout(l.ctx, time.Second*5)
+	defer cancel()
+
+	status, err := handshake(ctx, l.repo.GenesisBlock().Header().ID(), peer)
+	if err != nil {
+		peer.logger.Debug("failed to handshake", "err", err)
+		return
+	}
+
+	peer.UpdateHead(status.BestBlockID, status.TotalScore)
+	l.peerSet.Add(peer)
+	peer.logger.Debug(fmt.Sprintf("peer added (%v)", l.peerSet.Len()))
+	l.triggerSync()
+
+	defer func() {
+		l.peerSet.Remove(peer.ID())
+		peer.logger.Debug(fmt.Sprintf("peer removed (%v)", l.peerSet.Len()))
+	}()
+
+	select {
+	case <-peer.Done():
+	case <-l.ctx.Done():
+	}
+}
+
+func (l *Light) triggerSync() {
+	select {
+	case l.syncCh <- struct{}{}:
+	default:
+	}
+}
+
+// updatePeerHead updates the head of the peer by its status, since the announced block id carries no total score.
+func (l *Light) updatePeerHead(peer *Peer) {
+	ctx, cancel := context.WithTimeout(l.ctx, syncRequestTimeout)
+	defer cancel()
+
+	status, err := proto.GetStatus(ctx, peer)
+	if err != nil {
+		peer.logger.Debug("failed to get status", "err", err)
+		return
+	}
+	peer.UpdateHead(status.BestBlockID, status.TotalScore)
+	l.triggerSync()
+}
+
+// peer will be disconnected if error returned
+func (l *Light) handleRPC(peer *Peer, msg *p2p.Msg, write func(any)) (err error) {
+	log := peer.logger.New("msg", proto.MsgName(msg.Code))
+	log.Trace("received RPC call")
+	defer func() {
+		if err != nil {
+			log.Debug("failed to handle RPC call", "err", err)
+		}
+	}()
+
+	switch msg.Code {
+	case proto.MsgGetStatus:
+		if err := msg.Decode(&struct{}{}); err != nil {
+			return errors.WithMessage(err, "decode msg")
+		}
+		// claims the genesis as the best, so that no one syncs from a light node
+		genesisID := l.repo.GenesisBlock().Header().ID()
+		write(&proto.Status{
+			GenesisBlockID: genesisID,
+			SysTimestamp:   uint64(time.Now().Unix()),
+			TotalScore:     0,
+			BestBlockID:    genesisID,
+		})
+	case proto.MsgNewBlock:
+		var newBlock *block.Block
+		if err := msg.Decode(&newBlock); err != nil {
+			return errors.WithMessage(err, "decode msg")
+		}
+		peer.MarkBlock(newBlock.Header().ID())
+		peer.UpdateHead(newBlock.Header().ID(), newBlock.Header().TotalScore())
+		l.triggerSync()
+		write(&struct{}{})
+	case proto.MsgNewBlockID:
+		var newBlockID thor.Bytes32
+		if err := msg.Decode(&newBlockID); err != nil {
+			return errors.WithMessage(err, "decode msg")
+		}
+		if !peer.IsBlockKnown(newBlockID) {
+			peer.MarkBlock(newBlockID)
+			l.goes.Go(func() {
+				l.updatePeerHead(peer)
+			})
+		}
+		write(&struct{}{})
+	case proto.MsgNewCompactBlock:
+		var compact proto.CompactBlock
+		if err := msg.Decode(&compact); err != nil {
+			return errors.WithMessage(err, "decode msg")
+		}
+		if compact.Header == nil {
+			return errors.New("nil header")
+		}
+		peer.MarkBlock(compact.Header.ID())
+		peer.UpdateHead(compact.Header.ID(), compact.Header.TotalScore())
+		l.triggerSync()
+		write(&struct{}{})
+	case proto.MsgNewTx:
+		var newTx *tx.Transaction
+		if err := msg.Decode(&newTx); err != nil {
+			return errors.WithMessage(err, "decode msg")
+		}
+		peer.MarkTransaction(newTx.Hash())
+		write(&struct{}{})
+	case proto.MsgGetBlockByID,
+		proto.MsgGetBlocksFromNumber,
+		proto.MsgGetReceipts,
+		proto.MsgGetHeadersFromNumber:
+		write([]rlp.RawValue{})
+	case proto.MsgGetBlockIDByNumber:
+		write(thor.Bytes32{})
+	case proto.MsgGetTxs, proto.MsgGetBlockTxs:
+		write(tx.Transactions(nil))
+	case proto.MsgGetSnapPivot:
+		write(&proto.SnapPivot{})
+	case proto.MsgGetAccountRange, proto.MsgGetStorageRange:
+		write(&proto.TrieRange{})
+	case proto.MsgGetCodes:
+		write([][]byte{})
+	case proto.MsgGetAccountProofs:
+		write([][][]byte{})
+	default:
+		return fmt.Errorf("unknown message (%v)", msg.Code)
+	}
+	return nil
+}
@@ -0,0 +1,156 @@
+// Copyright (c) 2025 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package comm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vechain/thor/v2/block"
+	"github.com/vechain/thor/v2/builtin"
+	"github.com/vechain/thor/v2/comm/proto"
+	"github.com/vechain/thor/v2/p2p"
+	"github.com/vechain/thor/v2/thor"
+)
+
+func TestLightFetchState(t *testing.T) {
+	remote := newSnapTestChain(t, 10)
+	head := remote.Repo().BestBlockSummary().Header
+	server := New(remote.Repo(), remote.Stater(), nil, nil)
+
+	// responds tampered account proofs
+	bad := func(peer *Peer, msg *p2p.Msg, write func(any)) error {
+		if msg.Code == proto.MsgGetAccountProofs {
+			var req proto.AccountProofsRequest
+			if err := msg.Decode(&req); err != nil {
+				return err
+			}
+			proofs := make([][][]byte, 0, len(req.Addresses))
+			for range req.Addresses {
+				proofs = append(proofs, [][]byte{{0x1}})
+			}
+			write(proofs)
+			return nil
+		}
+		return server.handleRPC(peer, msg, write, &txsToSync{})
+	}
+	// has no state
+	empty := func(peer *Peer, msg *p2p.Msg, write func(any)) error {
+		return New(remote.Repo(), nil, nil, nil).handleRPC(peer, msg, write, &txsToSync{})
+	}
+	good := func(peer *Peer, msg *p2p.Msg, write func(any)) error {
+		return server.handleRPC(peer, msg, write, &txsToSync{})
+	}
+
+	local := NewLight(newLocalRepo(t, remote))
+	local.peerSet.Add(newPipedPeer(t, bad, head))
+	local.peerSet.Add(newPipedPeer(t, empty, head))
+	local.peerSet.Add(newPipedPeer(t, good, head))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	st, err := local.FetchState(ctx, head, []thor.Address{builtin.Authority.Address, builtin.Params.Address, {}})
+	require.NoError(t, err)
+
+	want := remote.Stater().NewState(remote.Repo().BestBlockSummary().Root())
+	wantCandidates, err := builtin.Authority.Native(want).AllCandidates()
+	require.NoError(t, err)
+	gotCandidates, err := builtin.Authority.Native(st).AllCandidates()
+	require.NoError(t, err)
+	assert.NotEmpty(t, gotCandidates)
+	assert.Equal(t, wantCandidates, gotCandidates)
+
+	wantMBP, err := thor.GetMaxBlockProposers(builtin.Params.Native(want), true)
+	require.NoError(t, err)
+	gotMBP, err := thor.GetMaxBlockProposers(builtin.Params.Native(st), true)
+	require.NoError(t, err)
+	assert.Equal(t, wantMBP, gotMBP)
+
+	// no peer has the state
+	local = NewLight(newLocalRepo(t, remote))
+	local.peerSet.Add(newPipedPeer(t, empty, head))
+	_, err = local.FetchState(ctx, head, []thor.Address{builtin.Authority.Address})
+	assert.Error(t, err)
+}
+
+func TestLightSyncHeaders(t *testing.T) {
+	remote, ids := newSyncTestChain(t, 50)
+	head := remote.Repo().BestBlockSummary().Header
+	server := New(remote.Repo(), nil, nil, nil)
+	peer := newPipedPeer(t, func(peer *Peer, msg *p2p.Msg, write func(any)) error {
+		return server.handleRPC(peer, msg, write, &txsToSync{})
+	}, head)
+
+	repo := newLocalRepo(t, remote)
+	local := NewLight(repo)
+	handler := func(_ context.Context, headers []*block.Header) error {
+		for _, h := range headers {
+			if err := repo.AddBlock(block.Compose(h, nil), nil, 0, true); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	require.NoError(t, local.syncHeaders(context.Background(), peer, repo.BestBlockSummary().Header, &linkValidator{}, handler))
+	assert.Equal(t, head.ID(), repo.BestBlockSummary().Header.ID())
+	for i, id := range ids {
+		got, err := repo.NewBestChain().GetBlockID(uint32(i + 1))
+		require.NoError(t, err)
+		assert.Equal(t, id, got)
+	}
+
+	// rejected headers
+	repo = newLocalRepo(t, remote)
+	local = NewLight(repo)
+	err := local.syncHeaders(context.Background(), peer, repo.BestBlockSummary().Header, &linkValidator{badNum: 10}, handler)
+	assert.Error(t, err)
+}
+
+func TestLightServe(t *testing.T) {
+	remote, _ := newSyncTestChain(t, 5)
+	local := NewLight(remote.Repo())
+	genesisID := remote.GenesisBlock().Header().ID()
+
+	peer := newPipedPeer(t, func(peer *Peer, msg *p2p.Msg, write func(any)) error {
+		return local.handleRPC(peer, msg, write)
+	}, remote.Repo().BestBlockSummary().Header)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// claims nothing to be synced
+	status, err := proto.GetStatus(ctx, peer)
+	require.NoError(t, err)
+	assert.Equal(t, genesisID, status.GenesisBlockID)
+	assert.Equal(t, genesisID, status.BestBlockID)
+	assert.Zero(t, status.TotalScore)
+
+	blocks, err := proto.GetBlocksFromNumber(ctx, peer, 1)
+	require.NoError(t, err)
+	assert.Empty(t, blocks)
+
+	headers, err := proto.GetHeadersFromNumber(ctx, peer, 1)
+	require.NoError(t, err)
+	assert.Empty(t, headers)
+
+	id, err := proto.GetBlockIDByNumber(ctx, peer, 1)
+	require.NoError(t, err)
+	assert.True(t, id.IsZero())
+
+	proofs, err := proto.GetAccountProofs(ctx, peer, &proto.AccountProofsRequest{BlockID: genesisID, Addresses: []thor.Address{{}}})
+	require.NoError(t, err)
+	assert.Empty(t, proofs)
+
+	raw, err := proto.GetBlockByID(ctx, peer, genesisID)
+	require.NoError(t, err)
+	assert.Empty(t, raw)
+}
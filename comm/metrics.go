@@ -0,0 +1,18 @@
+// Copyright (c) 2025 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package comm
+
+import (
+	"github.com/vechain/thor/v2/metrics"
+)
+
+var (
+	metricRemoteTxCount          = metrics.LazyLoadCounterVec("comm_remote_tx_count", []string{"result"})
+	metricHarmfulPeerDisconnects = metrics.LazyLoadCounter("comm_harmful_peer_disconnect_count")
+	metricSyncRangeCount         = metrics.LazyLoadCounterVec("comm_sync_range_count", []string{"result"})
+	metricEvictedPeers           = metrics.LazyLoadCounter("comm_evicted_peer_count")
+	metricCompactBlockCount      = metrics.LazyLoadCounterVec("comm_compact_block_count", []string{"result"})
+)
@@ -13,6 +13,7 @@ import (
 	"github.com/ethereum/go-ethereum/common/mclock"
 	lru "github.com/hashicorp/golang-lru"
 
+	"github.com/vechain/thor/v2/comm/proto"
 	"github.com/vechain/thor/v2/log"
 	"github.com/vechain/thor/v2/p2p"
 	"github.com/vechain/thor/v2/p2p/discover"
@@ -34,10 +35,13 @@ type Peer struct {
 	createdTime mclock.AbsTime
 	knownTxs    *lru.Cache
 	knownBlocks *lru.Cache
+	txGate      *txGate
+	score       peerScore
 	head        struct {
 		sync.Mutex
 		id         thor.Bytes32
 		totalScore uint64
+		updated    mclock.AbsTime // when the head last advanced
 	}
 }
 
@@ -52,14 +56,28 @@ func newPeer(peer *p2p.Peer, rw p2p.MsgReadWriter) *Peer {
 	}
 	knownTxs, _ := lru.New(maxKnownTxs)
 	knownBlocks, _ := lru.New(maxKnownBlocks)
-	return &Peer{
+	p := &Peer{
 		Peer:        peer,
 		RPC:         rpc.New(peer, rw),
 		logger:      logger.New(ctx...),
 		createdTime: mclock.Now(),
 		knownTxs:    knownTxs,
 		knownBlocks: knownBlocks,
+		txGate:      newTxGate(),
 	}
+	p.head.updated = p.createdTime
+	return p
+}
+
+// ProtoVersion returns the highest version of thor protocol shared with the peer.
+func (p *Peer) ProtoVersion() uint {
+	var ver uint
+	for _, c := range p.Caps() {
+		if c.Name == proto.Name && c.Version <= proto.Version {
+			ver = max(ver, c.Version)
+		}
+	}
+	return ver
 }
 
 // Head returns head block ID and total score.
@@ -75,6 +93,7 @@ func (p *Peer) UpdateHead(id thor.Bytes32, totalScore uint64) {
 	defer p.head.Unlock()
 	if totalScore > p.head.totalScore {
 		p.head.id, p.head.totalScore = id, totalScore
+		p.head.updated = mclock.Now()
 	}
 }
 
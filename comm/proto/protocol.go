@@ -12,9 +12,17 @@ import (
 // Constants
 const (
 	Name              = "thor"
-	Version    uint   = 1
-	Length     uint64 = 8
+	Version    uint   = 3
+	Length     uint64 = 17
 	MaxMsgSize        = 10 * 1024 * 1024
+
+	// Version2 is the legacy version without account proofs and compact blocks.
+	Version2 uint   = 2
+	Length2  uint64 = 14
+
+	// Version1 is the legacy version without messages for snap and header sync.
+	Version1 uint   = 1
+	Length1  uint64 = 8
 )
 
 // Protocol messages of thor
@@ -27,6 +35,15 @@ const (
 	MsgGetBlockIDByNumber
 	MsgGetBlocksFromNumber // fetch blocks from given number (including given number)
 	MsgGetTxs
+	MsgGetSnapPivot         // since version 2
+	MsgGetAccountRange      // since version 2
+	MsgGetStorageRange      // since version 2
+	MsgGetCodes             // since version 2
+	MsgGetReceipts          // since version 2
+	MsgGetHeadersFromNumber // fetch headers from given number (including given number), since version 2
+	MsgGetAccountProofs     // since version 3
+	MsgNewCompactBlock      // since version 3
+	MsgGetBlockTxs          // fetch txs of a block by indexes, since version 3
 )
 
 // MsgName convert msg code to string.
@@ -48,6 +65,24 @@ func MsgName(msgCode uint64) string {
 		return "MsgGetBlocksFromNumber"
 	case MsgGetTxs:
 		return "MsgGetTxs"
+	case MsgGetSnapPivot:
+		return "MsgGetSnapPivot"
+	case MsgGetAccountRange:
+		return "MsgGetAccountRange"
+	case MsgGetStorageRange:
+		return "MsgGetStorageRange"
+	case MsgGetCodes:
+		return "MsgGetCodes"
+	case MsgGetReceipts:
+		return "MsgGetReceipts"
+	case MsgGetHeadersFromNumber:
+		return "MsgGetHeadersFromNumber"
+	case MsgGetAccountProofs:
+		return "MsgGetAccountProofs"
+	case MsgNewCompactBlock:
+		return "MsgNewCompactBlock"
+	case MsgGetBlockTxs:
+		return "MsgGetBlockTxs"
 	default:
 		return fmt.Sprintf("unknown msg code(%v)", msgCode)
 	}
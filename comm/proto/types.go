@@ -24,6 +24,53 @@ type (
 		BestBlockID    thor.Bytes32
 		TotalScore     uint64
 	}
+
+	// SnapPivot result of MsgGetSnapPivot.
+	// The pivot is the last block of a round before the finalized checkpoint.
+	SnapPivot struct {
+		BlockID thor.Bytes32 // zero if no pivot available
+		Quality uint32
+	}
+
+	// AccountRangeRequest arg of MsgGetAccountRange.
+	AccountRangeRequest struct {
+		BlockID thor.Bytes32
+		Origin  thor.Bytes32
+	}
+
+	// StorageRangeRequest arg of MsgGetStorageRange.
+	StorageRangeRequest struct {
+		BlockID thor.Bytes32
+		Account thor.Bytes32 // the hashed address
+		Origin  thor.Bytes32
+	}
+
+	// AccountProofsRequest arg of MsgGetAccountProofs.
+	AccountProofsRequest struct {
+		BlockID   thor.Bytes32
+		Addresses []thor.Address
+	}
+
+	// CompactBlock arg of MsgNewCompactBlock.
+	// Txs are referred by IDs, to be reconstructed from the tx pool of the receiver.
+	CompactBlock struct {
+		Header *block.Header
+		TxIDs  []thor.Bytes32
+	}
+
+	// BlockTxsRequest arg of MsgGetBlockTxs.
+	BlockTxsRequest struct {
+		BlockID thor.Bytes32
+		Indexes []uint32
+	}
+
+	// TrieRange result of MsgGetAccountRange and MsgGetStorageRange.
+	TrieRange struct {
+		Keys   [][]byte
+		Values [][]byte
+		Metas  [][]byte
+		Proof  [][]byte
+	}
 )
 
 // RPC defines RPC interface.
@@ -51,6 +98,16 @@ func NotifyNewBlock(ctx context.Context, rpc RPC, block *block.Block) error {
 	return rpc.Notify(ctx, MsgNewBlock, block)
 }
 
+// NotifyNewCompactBlock notify new block in compact form to remote peer.
+func NotifyNewCompactBlock(ctx context.Context, rpc RPC, block *block.Block) error {
+	txs := block.Transactions()
+	ids := make([]thor.Bytes32, 0, len(txs))
+	for _, tx := range txs {
+		ids = append(ids, tx.ID())
+	}
+	return rpc.Notify(ctx, MsgNewCompactBlock, &CompactBlock{Header: block.Header(), TxIDs: ids})
+}
+
 // NotifyNewTx notify new tx to remote peer.
 func NotifyNewTx(ctx context.Context, rpc RPC, tx *tx.Transaction) error {
 	return rpc.Notify(ctx, MsgNewTx, tx)
@@ -87,6 +144,15 @@ func GetBlocksFromNumber(ctx context.Context, rpc RPC, num uint32) ([]rlp.RawVal
 	return blocks, nil
 }
 
+// GetHeadersFromNumber get a batch of block headers starts with num from remote peer.
+func GetHeadersFromNumber(ctx context.Context, rpc RPC, num uint32) ([]rlp.RawValue, error) {
+	var headers []rlp.RawValue
+	if err := rpc.Call(ctx, MsgGetHeadersFromNumber, num, &headers); err != nil {
+		return nil, err
+	}
+	return headers, nil
+}
+
 // GetTxs get txs from remote peer.
 func GetTxs(ctx context.Context, rpc RPC) (tx.Transactions, error) {
 	var txs tx.Transactions
@@ -95,3 +161,70 @@ func GetTxs(ctx context.Context, rpc RPC) (tx.Transactions, error) {
 	}
 	return txs, nil
 }
+
+// GetBlockTxs get txs of a block at the given indexes from remote peer.
+// It returns empty result if the block is unknown to the peer.
+func GetBlockTxs(ctx context.Context, rpc RPC, req *BlockTxsRequest) (tx.Transactions, error) {
+	var txs tx.Transactions
+	if err := rpc.Call(ctx, MsgGetBlockTxs, req, &txs); err != nil {
+		return nil, err
+	}
+	return txs, nil
+}
+
+// GetSnapPivot get the snap sync pivot from remote peer.
+func GetSnapPivot(ctx context.Context, rpc RPC) (*SnapPivot, error) {
+	var pivot SnapPivot
+	if err := rpc.Call(ctx, MsgGetSnapPivot, &struct{}{}, &pivot); err != nil {
+		return nil, err
+	}
+	return &pivot, nil
+}
+
+// GetAccountRange get a range of account trie leaves from remote peer.
+func GetAccountRange(ctx context.Context, rpc RPC, req *AccountRangeRequest) (*TrieRange, error) {
+	var r TrieRange
+	if err := rpc.Call(ctx, MsgGetAccountRange, req, &r); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// GetStorageRange get a range of storage trie leaves from remote peer.
+func GetStorageRange(ctx context.Context, rpc RPC, req *StorageRangeRequest) (*TrieRange, error) {
+	var r TrieRange
+	if err := rpc.Call(ctx, MsgGetStorageRange, req, &r); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// GetAccountProofs get merkle proofs of accounts in the state of the given block from remote peer.
+// Proofs returned may be fewer than requested, if the state is unavailable.
+func GetAccountProofs(ctx context.Context, rpc RPC, req *AccountProofsRequest) ([][][]byte, error) {
+	var proofs [][][]byte
+	if err := rpc.Call(ctx, MsgGetAccountProofs, req, &proofs); err != nil {
+		return nil, err
+	}
+	return proofs, nil
+}
+
+// GetCodes get contract codes by hashes from remote peer.
+// Codes returned may be fewer than requested, and the missing ones are empty.
+func GetCodes(ctx context.Context, rpc RPC, hashes []thor.Bytes32) ([][]byte, error) {
+	var codes [][]byte
+	if err := rpc.Call(ctx, MsgGetCodes, hashes, &codes); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// GetReceipts get receipts of blocks from remote peer.
+// Receipts returned may be fewer than requested.
+func GetReceipts(ctx context.Context, rpc RPC, blockIDs []thor.Bytes32) ([]tx.Receipts, error) {
+	var receipts []tx.Receipts
+	if err := rpc.Call(ctx, MsgGetReceipts, blockIDs, &receipts); err != nil {
+		return nil, err
+	}
+	return receipts, nil
+}
@@ -0,0 +1,88 @@
+// Copyright (c) 2025 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package comm
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/mclock"
+
+	"github.com/vechain/thor/v2/thor"
+)
+
+const (
+	scoreMax = 100
+
+	scoreLatencyUnit     = 200 * time.Millisecond // a point is taken for each unit of average RPC latency
+	scoreLatencyMax      = 20
+	scoreFailureMinCalls = 5 // minimum number of calls before the failure ratio is evaluated
+	scoreFailureMax      = 40
+	scoreAnnDelayGrace   = 2 * time.Second // announcements within the delay are deemed timely
+	scoreAnnDelayMax     = 20
+	scoreStaleHead       = 20
+	scoreStaleBlocks     = 3 // the peer head is stale if not advanced in the number of block intervals
+	scoreInvalidMsg      = 10
+	scoreInvalidMsgMax   = 60
+
+	scoreAnnDelayEMA = 0.2 // weight of the latest sample in the moving average of announcement delay
+)
+
+// peerScore tracks the behaviour of a peer, which is not covered by RPC stats.
+type peerScore struct {
+	lock        sync.Mutex
+	annDelay    time.Duration // moving average of delays of block announcements
+	invalidMsgs uint64
+}
+
+// RecordAnnouncement records the delay of a block announcement, which is the time elapsed since
+// the block timestamp when the announcement was received.
+func (p *Peer) RecordAnnouncement(blockTimestamp uint64, received time.Time) {
+	delay := max(received.Sub(time.Unix(int64(blockTimestamp), 0)), 0) //#nosec G115
+
+	p.score.lock.Lock()
+	defer p.score.lock.Unlock()
+	if p.score.annDelay == 0 {
+		p.score.annDelay = delay
+	} else {
+		p.score.annDelay = time.Duration(float64(p.score.annDelay)*(1-scoreAnnDelayEMA) + float64(delay)*scoreAnnDelayEMA)
+	}
+}
+
+// RecordInvalidMsg records a message which is decoded but invalid.
+func (p *Peer) RecordInvalidMsg() {
+	p.score.lock.Lock()
+	defer p.score.lock.Unlock()
+	p.score.invalidMsgs++
+}
+
+// Score returns the score of the peer in [0, 100], the higher the better.
+// The head of the peer is compared with the local best total score to judge the staleness.
+func (p *Peer) Score(bestTotalScore uint64) int {
+	var penalty int
+
+	stats := p.CallStats()
+	penalty += min(int(stats.Latency/scoreLatencyUnit), scoreLatencyMax)
+	if stats.Calls >= scoreFailureMinCalls {
+		penalty += int(scoreFailureMax * stats.Failures / stats.Calls)
+	}
+
+	p.head.Lock()
+	totalScore, headAge := p.head.totalScore, time.Duration(mclock.Now()-p.head.updated)
+	p.head.Unlock()
+
+	p.score.lock.Lock()
+	if delay := p.score.annDelay - scoreAnnDelayGrace; delay > 0 {
+		penalty += min(int(delay/time.Second), scoreAnnDelayMax)
+	}
+	penalty += min(int(p.score.invalidMsgs)*scoreInvalidMsg, scoreInvalidMsgMax)
+	p.score.lock.Unlock()
+
+	if totalScore < bestTotalScore && headAge > time.Duration(thor.BlockInterval()*scoreStaleBlocks)*time.Second {
+		penalty += scoreStaleHead
+	}
+	return max(scoreMax-penalty, 0)
+}
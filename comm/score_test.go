@@ -0,0 +1,129 @@
+// Copyright (c) 2025 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package comm
+
+import (
+	"context"
+	"crypto/rand"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/mclock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vechain/thor/v2/comm/proto"
+	"github.com/vechain/thor/v2/p2p"
+	"github.com/vechain/thor/v2/p2p/discover"
+	"github.com/vechain/thor/v2/thor"
+)
+
+func newScoreTestPeer() *Peer {
+	var id discover.NodeID
+	rand.Read(id[:])
+	return newPeer(p2p.NewPeer(id, "test", nil), stubMsgReadWriter{})
+}
+
+func TestPeerScore(t *testing.T) {
+	peer := newScoreTestPeer()
+	peer.UpdateHead(thor.Bytes32{1}, 10)
+	assert.Equal(t, scoreMax, peer.Score(10))
+
+	// timely announcements
+	now := time.Now()
+	peer.RecordAnnouncement(uint64(now.Unix()), now.Add(time.Second))
+	assert.Equal(t, scoreMax, peer.Score(10))
+
+	// late announcements
+	for range 20 {
+		peer.RecordAnnouncement(uint64(now.Unix()), now.Add(time.Minute))
+	}
+	assert.Equal(t, scoreMax-scoreAnnDelayMax, peer.Score(10))
+
+	// invalid messages
+	peer.RecordInvalidMsg()
+	assert.Equal(t, scoreMax-scoreAnnDelayMax-scoreInvalidMsg, peer.Score(10))
+	for range 10 {
+		peer.RecordInvalidMsg()
+	}
+	assert.Equal(t, scoreMax-scoreAnnDelayMax-scoreInvalidMsgMax, peer.Score(10))
+
+	// stale head
+	peer = newScoreTestPeer()
+	peer.UpdateHead(thor.Bytes32{1}, 10)
+	assert.Equal(t, scoreMax, peer.Score(20), "head recently updated")
+	peer.head.updated = mclock.Now() - mclock.AbsTime(time.Hour)
+	assert.Equal(t, scoreMax, peer.Score(10), "head not behind")
+	assert.Equal(t, scoreMax-scoreStaleHead, peer.Score(20))
+}
+
+func TestPeerScoreRPC(t *testing.T) {
+	remote, _ := newSyncTestChain(t, 1)
+	head := remote.Repo().BestBlockSummary().Header
+	server := New(remote.Repo(), nil, nil, nil)
+
+	delay := 500 * time.Millisecond
+	peer := newPipedPeer(t, func(peer *Peer, msg *p2p.Msg, write func(any)) error {
+		time.Sleep(delay)
+		return server.handleRPC(peer, msg, write, &txsToSync{})
+	}, head)
+
+	_, err := proto.GetStatus(context.Background(), peer)
+	require.NoError(t, err)
+	stats := peer.CallStats()
+	assert.Equal(t, uint64(1), stats.Calls)
+	assert.Zero(t, stats.Failures)
+	assert.GreaterOrEqual(t, stats.Latency, delay)
+	assert.Equal(t, scoreMax-int(stats.Latency/scoreLatencyUnit), peer.Score(head.TotalScore()))
+
+	// canceled by the caller, not counted
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = proto.GetStatus(ctx, peer)
+	assert.Error(t, err)
+	assert.Equal(t, uint64(1), peer.CallStats().Calls)
+}
+
+func TestPeersToEvict(t *testing.T) {
+	remote, _ := newSyncTestChain(t, 1)
+	c := New(remote.Repo(), nil, nil, nil)
+
+	var peers Peers
+	for range 20 {
+		peer := newScoreTestPeer()
+		peer.createdTime = mclock.Now() - mclock.AbsTime(evictGracePeriod)
+		c.peerSet.Add(peer)
+		peers = append(peers, peer)
+	}
+	// limit not reached
+	assert.Empty(t, c.peersToEvict())
+
+	c.SetMaxPeers(20)
+	assert.Empty(t, c.peersToEvict(), "all good")
+
+	for range 6 {
+		peers[0].RecordInvalidMsg()
+		peers[1].RecordInvalidMsg()
+		peers[2].RecordInvalidMsg()
+	}
+	now := time.Now()
+	peers[1].RecordAnnouncement(uint64(now.Unix()), now.Add(time.Minute))
+	// scored above the threshold
+	peers[3].RecordInvalidMsg()
+	// young peers are not evicted
+	peers[2].createdTime = mclock.Now()
+
+	evicted := c.peersToEvict()
+	assert.Equal(t, Peers{peers[1], peers[0]}, evicted)
+
+	// evicted at most 1/10 each round
+	for _, p := range peers[4:10] {
+		for range 6 {
+			p.RecordInvalidMsg()
+		}
+	}
+	assert.Len(t, c.peersToEvict(), 2)
+}
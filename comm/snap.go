@@ -0,0 +1,561 @@
+// Copyright (c) 2025 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package comm
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/vechain/thor/v2/block"
+	"github.com/vechain/thor/v2/comm/proto"
+	"github.com/vechain/thor/v2/state"
+	"github.com/vechain/thor/v2/thor"
+	"github.com/vechain/thor/v2/trie"
+	"github.com/vechain/thor/v2/tx"
+)
+
+const (
+	snapMinPeers       = 3  // min count of peers to select the pivot
+	snapAccountTasks   = 16 // the account trie is split into that many key ranges synced in parallel
+	snapStorageWorkers = 16 // count of storage tries synced in parallel
+)
+
+var (
+	snapPeersTimeout   = time.Minute      // proceed with fewer peers than snapMinPeers after the timeout
+	snapRequestTimeout = 20 * time.Second // timeout of a single snap request
+)
+
+// ProposerValidator validates the proposer of a header, whose parent is already in the repository.
+type ProposerValidator interface {
+	ValidateProposer(ctx context.Context, header *block.Header) error
+}
+
+// SnapSync syncs the chain to a pivot block, which is the last block of the round before the
+// finalized checkpoint agreed by most peers. The state of the pivot is fetched from peers in
+// ranges verified by merkle proofs, instead of being computed by replaying the history.
+// Blocks before the pivot are imported along with their receipts, without execution.
+//
+// The trusted checkpoint is required, and the pivot must be the last block of its round, since the
+// quality of the pivot is taken from peers rather than computed from votes.
+// Headers of the history are validated by the header validator, and must pass through the trusted
+// checkpoint. Signers of headers after the checkpoint are checked by the proposer validator.
+//
+// onSynced is called once all done, before the pivot becomes the best block.
+func (c *Communicator) SnapSync(
+	ctx context.Context,
+	trusted thor.Bytes32,
+	proposers ProposerValidator,
+	onSynced func(pivot thor.Bytes32, quality uint32) error,
+) error {
+	if c.stater == nil {
+		return errors.New("stater required")
+	}
+	if c.headerVal == nil {
+		return errors.New("header validator required")
+	}
+	if trusted.IsZero() {
+		return errors.New("trusted checkpoint required")
+	}
+	if block.Number(trusted)%thor.EpochLength() != 0 {
+		return errors.New("trusted checkpoint is not the first block of a round")
+	}
+
+	peers, err := c.waitSnapPeers(ctx)
+	if err != nil {
+		return err
+	}
+
+	// states before the pivot of an interrupted snap sync have been marked pruned
+	minNum := max(block.Number(trusted), c.stater.PrunedBefore())
+	pivot, peers, err := selectSnapPivot(ctx, peers, minNum, block.Number(trusted)+thor.EpochLength()-1)
+	if err != nil {
+		return errors.WithMessage(err, "select pivot")
+	}
+	// the history is imported without states, which are known absent by being marked pruned
+	if err := c.stater.MarkPrunedBefore(block.Number(pivot.BlockID)); err != nil {
+		return err
+	}
+	logger.Info("snap sync started", "pivot", fmt.Sprintf("%v #%v", pivot.BlockID, block.Number(pivot.BlockID)), "peers", len(peers))
+
+	ps := newSnapPeerSet(peers)
+	if err := importSnapHistory(ctx, c, ps, pivot.BlockID, trusted, proposers); err != nil {
+		return errors.WithMessage(err, "import history")
+	}
+
+	summary, err := c.repo.GetBlockSummary(pivot.BlockID)
+	if err != nil {
+		return err
+	}
+	if err := syncSnapState(ctx, c.stater, ps, pivot.BlockID, summary.Root()); err != nil {
+		return errors.WithMessage(err, "sync state")
+	}
+
+	if err := onSynced(pivot.BlockID, pivot.Quality); err != nil {
+		return err
+	}
+
+	// re-add the pivot to make it the best
+	blk, err := c.repo.GetBlock(pivot.BlockID)
+	if err != nil {
+		return err
+	}
+	receipts, err := c.repo.GetBlockReceipts(pivot.BlockID)
+	if err != nil {
+		return err
+	}
+	if err := c.repo.AddBlock(blk, receipts, 0, true); err != nil {
+		return err
+	}
+	logger.Info("snap sync done", "pivot", fmt.Sprintf("%v #%v", pivot.BlockID, block.Number(pivot.BlockID)))
+	return nil
+}
+
+// waitSnapPeers waits until enough peers supporting snap sync connected.
+func (c *Communicator) waitSnapPeers(ctx context.Context) (Peers, error) {
+	ticker := time.NewTicker(2 * time.Second)
+	defer ticker.Stop()
+
+	deadline := time.Now().Add(snapPeersTimeout)
+	for {
+		peers := c.peerSet.Slice().Filter(func(p *Peer) bool {
+			return p.ProtoVersion() > proto.Version1
+		})
+		if len(peers) >= snapMinPeers || (len(peers) > 0 && time.Now().After(deadline)) {
+			return peers, nil
+		}
+		logger.Debug("waiting for snap peers", "peers", len(peers))
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// selectSnapPivot selects the pivot agreed by most peers, and returns it along with these peers.
+// Pivots not the last block of a round, or out of the range [minNum, maxNum], are ignored.
+func selectSnapPivot(ctx context.Context, peers Peers, minNum, maxNum uint32) (*proto.SnapPivot, Peers, error) {
+	var (
+		mu    sync.Mutex
+		votes = make(map[proto.SnapPivot]Peers)
+		g     errgroup.Group
+	)
+	for _, peer := range peers {
+		g.Go(func() error {
+			ctx, cancel := context.WithTimeout(ctx, snapRequestTimeout)
+			defer cancel()
+
+			pivot, err := proto.GetSnapPivot(ctx, peer)
+			if err != nil {
+				peer.logger.Debug("failed to get snap pivot", "err", err)
+				return nil
+			}
+			if pivot.BlockID.IsZero() {
+				return nil
+			}
+			if num := block.Number(pivot.BlockID); num < minNum || num > maxNum || (num+1)%thor.EpochLength() != 0 {
+				peer.logger.Debug("invalid snap pivot", "pivot", pivot.BlockID)
+				return nil
+			}
+			mu.Lock()
+			votes[*pivot] = append(votes[*pivot], peer)
+			mu.Unlock()
+			return nil
+		})
+	}
+	_ = g.Wait()
+
+	var (
+		selected proto.SnapPivot
+		voters   Peers
+	)
+	for pivot, ps := range votes {
+		if len(ps) > len(voters) || (len(ps) == len(voters) && block.Number(pivot.BlockID) > block.Number(selected.BlockID)) {
+			selected, voters = pivot, ps
+		}
+	}
+	if len(voters) == 0 {
+		return nil, nil, errors.New("no pivot available")
+	}
+	return &selected, voters, nil
+}
+
+// FetchState fetches the accounts of the given addresses in the state of the given block from peers
+// supporting snap sync, see Light.FetchState.
+func (c *Communicator) FetchState(ctx context.Context, header *block.Header, addrs []thor.Address) (*state.State, error) {
+	peers := c.peerSet.Slice().Filter(func(peer *Peer) bool {
+		id, _ := peer.Head()
+		return peer.ProtoVersion() > proto.Version1 && block.Number(id) >= header.Number()
+	})
+	return fetchState(ctx, newSnapPeerSet(peers), header, addrs)
+}
+
+// snapPeerSet dispatches snap requests among peers, and excludes peers failed too many times.
+type snapPeerSet struct {
+	lock     sync.Mutex
+	peers    Peers
+	failures map[*Peer]int
+	next     int
+}
+
+func newSnapPeerSet(peers Peers) *snapPeerSet {
+	return &snapPeerSet{
+		peers:    peers,
+		failures: make(map[*Peer]int),
+	}
+}
+
+// pick picks a peer in turn, nil returned if all peers excluded.
+func (s *snapPeerSet) pick() *Peer {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for range s.peers {
+		p := s.peers[s.next%len(s.peers)]
+		s.next++
+		if s.failures[p] < syncMaxPeerFailure {
+			return p
+		}
+	}
+	return nil
+}
+
+func (s *snapPeerSet) fail(p *Peer) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.failures[p]++
+}
+
+// request does the request with peers in turn until succeeded.
+func (s *snapPeerSet) request(ctx context.Context, name string, fn func(ctx context.Context, peer *Peer) error) error {
+	for {
+		peer := s.pick()
+		if peer == nil {
+			return fmt.Errorf("no peer to %v", name)
+		}
+		err := func() error {
+			ctx, cancel := context.WithTimeout(ctx, snapRequestTimeout)
+			defer cancel()
+			return fn(ctx, peer)
+		}()
+		if err == nil {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		peer.logger.Debug("snap request failed", "req", name, "err", err)
+		s.fail(peer)
+	}
+}
+
+// importSnapHistory imports blocks and receipts up to the pivot, after their headers validated.
+// Proposers of blocks after the trusted checkpoint are validated once their parents imported.
+func importSnapHistory(ctx context.Context, c *Communicator, ps *snapPeerSet, pivot, trusted thor.Bytes32, proposers ProposerValidator) error {
+	pivotNum := block.Number(pivot)
+	sps := newSyncPeers(ps.peers)
+	for _, sp := range sps {
+		sp.headNum = min(sp.headNum, pivotNum)
+	}
+
+	var (
+		batches   = make(chan blockBatch, 10)
+		genesisID = c.repo.GenesisBlock().Header().ID()
+		last      = genesisID
+	)
+	g, ctx := errgroup.WithContext(ctx)
+	g.Go(func() error {
+		defer close(batches)
+		_, err := fetchBlockBatches(ctx, sps, 1, genesisID, batches, nil)
+		return err
+	})
+	g.Go(func() error {
+		parent := c.repo.GenesisBlock().Header()
+		for batch := range batches {
+			now := uint64(time.Now().Unix())
+			for _, blk := range batch.blocks {
+				header := blk.Header()
+				if err := c.headerVal.ValidateHeader(header, parent, now); err != nil {
+					return errors.WithMessage(err, "validate header")
+				}
+				if header.Number() == block.Number(trusted) && header.ID() != trusted {
+					return errors.New("conflicts with the trusted checkpoint")
+				}
+				if blk.Transactions().RootHash() != header.TxsRoot() {
+					return errors.New("txs root mismatch")
+				}
+				parent = header
+			}
+			receipts, err := fetchSnapReceipts(ctx, ps, batch.blocks)
+			if err != nil {
+				return err
+			}
+			for i, blk := range batch.blocks {
+				if blk.Header().Number() > block.Number(trusted) {
+					if err := proposers.ValidateProposer(ctx, blk.Header()); err != nil {
+						return errors.WithMessage(err, "validate proposer")
+					}
+				}
+				if err := c.repo.AddBlock(blk, receipts[i], 0, false); err != nil {
+					return err
+				}
+			}
+			last = batch.blocks[len(batch.blocks)-1].Header().ID()
+			logger.Debug("snap sync imported blocks", "to", block.Number(last))
+		}
+		return nil
+	})
+	if err := g.Wait(); err != nil {
+		return err
+	}
+	if last != pivot {
+		return fmt.Errorf("pivot not reached, last imported #%v", block.Number(last))
+	}
+	return nil
+}
+
+// fetchSnapReceipts fetches and verifies receipts of the blocks.
+func fetchSnapReceipts(ctx context.Context, ps *snapPeerSet, blocks []*block.Block) ([]tx.Receipts, error) {
+	receipts := make([]tx.Receipts, len(blocks))
+
+	// blocks without txs have no receipts
+	var pending []int
+	for i, blk := range blocks {
+		if len(blk.Transactions()) > 0 {
+			pending = append(pending, i)
+		}
+	}
+
+	for len(pending) > 0 {
+		n := min(len(pending), maxSnapReceipts)
+		ids := make([]thor.Bytes32, 0, n)
+		for _, i := range pending[:n] {
+			ids = append(ids, blocks[i].Header().ID())
+		}
+
+		if err := ps.request(ctx, "get receipts", func(ctx context.Context, peer *Peer) error {
+			result, err := proto.GetReceipts(ctx, peer, ids)
+			if err != nil {
+				return err
+			}
+			if len(result) == 0 || len(result) > len(ids) {
+				return errors.New("unexpected receipts count")
+			}
+			for j, rs := range result {
+				header := blocks[pending[j]].Header()
+				if rs.RootHash() != header.ReceiptsRoot() {
+					return errors.New("receipts root mismatch")
+				}
+			}
+			for j, rs := range result {
+				receipts[pending[j]] = rs
+			}
+			pending = pending[len(result):]
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+	}
+	return receipts, nil
+}
+
+// syncSnapState syncs the state of the given root.
+func syncSnapState(ctx context.Context, stater *state.Stater, ps *snapPeerSet, blockID thor.Bytes32, root trie.Root) error {
+	var (
+		builder    = stater.NewSnapBuilder(root)
+		builderMu  sync.Mutex
+		codeHashes = make(map[thor.Bytes32]struct{})
+		storages   = make(chan *state.StorageBuilder, snapStorageWorkers)
+		progress   struct{ accounts, storages atomic.Int64 }
+	)
+
+	g, gctx := errgroup.WithContext(ctx)
+
+	// accounts
+	g.Go(func() error {
+		defer close(storages)
+
+		ag, ctx := errgroup.WithContext(gctx)
+		for i := range snapAccountTasks {
+			var origin, end thor.Bytes32
+			origin[0] = byte(i * 256 / snapAccountTasks)
+			if i < snapAccountTasks-1 {
+				end[0] = byte((i + 1) * 256 / snapAccountTasks)
+			}
+			ag.Go(func() error {
+				return syncSnapRange(ctx, ps, root.Hash, origin, end, func(ctx context.Context, peer *Peer, origin thor.Bytes32) (*proto.TrieRange, error) {
+					return proto.GetAccountRange(ctx, peer, &proto.AccountRangeRequest{BlockID: blockID, Origin: origin})
+				}, func(r *proto.TrieRange) error {
+					for i, key := range r.Keys {
+						builderMu.Lock()
+						codeHash, storage, err := builder.AddAccount(key, r.Values[i])
+						if err == nil && len(codeHash) > 0 {
+							codeHashes[thor.BytesToBytes32(codeHash)] = struct{}{}
+						}
+						builderMu.Unlock()
+						if err != nil {
+							return err
+						}
+						progress.accounts.Add(1)
+
+						if storage != nil {
+							select {
+							case <-ctx.Done():
+								return ctx.Err()
+							case storages <- storage:
+							}
+						}
+					}
+					return nil
+				})
+			})
+		}
+		return ag.Wait()
+	})
+
+	// storages
+	for range snapStorageWorkers {
+		g.Go(func() error {
+			for sb := range storages {
+				if err := syncSnapRange(gctx, ps, sb.Root, thor.Bytes32{}, thor.Bytes32{}, func(ctx context.Context, peer *Peer, origin thor.Bytes32) (*proto.TrieRange, error) {
+					r, err := proto.GetStorageRange(ctx, peer, &proto.StorageRangeRequest{BlockID: blockID, Account: sb.Account, Origin: origin})
+					if err != nil {
+						return nil, err
+					}
+					if err := state.VerifyStorageKeys(r.Keys, r.Metas); err != nil {
+						return nil, err
+					}
+					return r, nil
+				}, func(r *proto.TrieRange) error {
+					return sb.Add(r.Keys, r.Values, r.Metas)
+				}); err != nil {
+					return err
+				}
+				if err := sb.Commit(); err != nil {
+					return err
+				}
+				progress.storages.Add(1)
+			}
+			return nil
+		})
+	}
+
+	// progress
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(8 * time.Second)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				logger.Info("snap sync state", "accounts", progress.accounts.Load(), "storages", progress.storages.Load())
+			}
+		}
+	}()
+	err := g.Wait()
+	close(done)
+	if err != nil {
+		return err
+	}
+
+	// codes
+	hashes := make([]thor.Bytes32, 0, len(codeHashes))
+	for h := range codeHashes {
+		hashes = append(hashes, h)
+	}
+	for len(hashes) > 0 {
+		n := min(len(hashes), maxSnapCodes)
+		if err := ps.request(ctx, "get codes", func(ctx context.Context, peer *Peer) error {
+			codes, err := proto.GetCodes(ctx, peer, hashes[:n])
+			if err != nil {
+				return err
+			}
+			if len(codes) == 0 || len(codes) > n {
+				return errors.New("unexpected codes count")
+			}
+			for i, code := range codes {
+				if err := builder.AddCode(hashes[i][:], code); err != nil {
+					return err
+				}
+			}
+			hashes = hashes[len(codes):]
+			return nil
+		}); err != nil {
+			return err
+		}
+	}
+
+	return builder.Commit()
+}
+
+// syncSnapRange fetches leaves in the key range [origin, end) of the trie with the given root,
+// and verifies them with range proofs. The range is unbounded if end is zero.
+func syncSnapRange(
+	ctx context.Context,
+	ps *snapPeerSet,
+	root thor.Bytes32,
+	origin, end thor.Bytes32,
+	fetch func(ctx context.Context, peer *Peer, origin thor.Bytes32) (*proto.TrieRange, error),
+	handle func(r *proto.TrieRange) error,
+) error {
+	for {
+		var (
+			r    *proto.TrieRange
+			more bool
+		)
+		if err := ps.request(ctx, "get trie range", func(ctx context.Context, peer *Peer) (err error) {
+			if r, err = fetch(ctx, peer, origin); err != nil {
+				return err
+			}
+			more, err = trie.VerifyRangeProof(root, origin[:], r.Keys, r.Values, r.Proof)
+			return err
+		}); err != nil {
+			return err
+		}
+
+		if !end.IsZero() {
+			for i, key := range r.Keys {
+				if bytes.Compare(key, end[:]) >= 0 {
+					r.Keys, r.Values = r.Keys[:i], r.Values[:i]
+					if len(r.Metas) > i {
+						r.Metas = r.Metas[:i]
+					}
+					more = false
+					break
+				}
+			}
+		}
+		if err := handle(r); err != nil {
+			return err
+		}
+		if !more || len(r.Keys) == 0 {
+			return nil
+		}
+
+		origin = thor.BytesToBytes32(r.Keys[len(r.Keys)-1])
+		// the next key
+		for i := len(origin) - 1; i >= 0; i-- {
+			origin[i]++
+			if origin[i] != 0 {
+				break
+			}
+		}
+		if origin.IsZero() {
+			// reached the max key
+			return nil
+		}
+	}
+}
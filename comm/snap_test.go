@@ -0,0 +1,241 @@
+// Copyright (c) 2025 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package comm
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vechain/thor/v2/block"
+	"github.com/vechain/thor/v2/chain"
+	"github.com/vechain/thor/v2/comm/proto"
+	"github.com/vechain/thor/v2/consensus"
+	"github.com/vechain/thor/v2/genesis"
+	"github.com/vechain/thor/v2/light"
+	"github.com/vechain/thor/v2/muxdb"
+	"github.com/vechain/thor/v2/p2p"
+	"github.com/vechain/thor/v2/state"
+	"github.com/vechain/thor/v2/test/testchain"
+	"github.com/vechain/thor/v2/thor"
+	"github.com/vechain/thor/v2/tx"
+)
+
+type fakeFinality struct {
+	finalized thor.Bytes32
+	quality   uint32
+}
+
+func (f *fakeFinality) Finalized() thor.Bytes32 { return f.finalized }
+
+func (f *fakeFinality) Quality(thor.Bytes32) (uint32, error) { return f.quality, nil }
+
+type fakeProposerValidator struct {
+	validated []uint32
+	err       error
+}
+
+func (f *fakeProposerValidator) ValidateProposer(_ context.Context, header *block.Header) error {
+	f.validated = append(f.validated, header.Number())
+	return f.err
+}
+
+func newSnapTestChain(t *testing.T, n int) *testchain.Chain {
+	// pivots are the last blocks of rounds
+	// launched in the past, so that headers are not in the future
+	chain, err := testchain.NewIntegrationTestChain(genesis.DevConfig{
+		ForkConfig: &testchain.DefaultForkConfig,
+		LaunchTime: uint64(time.Now().Unix()) - 1000,
+	}, 10)
+	require.NoError(t, err)
+
+	accounts := genesis.DevAccounts()
+	for i := range n {
+		if i%3 == 0 {
+			to := thor.BytesToAddress([]byte{byte(i)})
+			require.NoError(t, chain.MintClauses(accounts[i%len(accounts)], []*tx.Clause{
+				tx.NewClause(&to).WithValue(big.NewInt(int64(i + 1))),
+			}))
+		} else {
+			require.NoError(t, chain.MintBlock())
+		}
+	}
+	return chain
+}
+
+// dumpState returns all account leaves of the state.
+func dumpState(t *testing.T, stater *state.Stater, repo *chain.Repository, id thor.Bytes32) *state.Range {
+	summary, err := repo.GetBlockSummary(id)
+	require.NoError(t, err)
+	r, err := stater.AccountRange(summary.Root(), thor.Bytes32{}, 1<<30)
+	require.NoError(t, err)
+	return r
+}
+
+func TestSnapSync(t *testing.T) {
+	remote := newSnapTestChain(t, 30)
+	head := remote.Repo().BestBlockSummary().Header
+	finalized, err := remote.Repo().NewBestChain().GetBlockID(20)
+	require.NoError(t, err)
+	finality := &fakeFinality{finalized, 3}
+	server := New(remote.Repo(), remote.Stater(), finality, nil)
+
+	// responds tampered account ranges
+	bad := func(peer *Peer, msg *p2p.Msg, write func(any)) error {
+		if msg.Code == proto.MsgGetAccountRange {
+			var req proto.AccountRangeRequest
+			if err := msg.Decode(&req); err != nil {
+				return err
+			}
+			r, err := remote.Stater().AccountRange(remote.Repo().BestBlockSummary().Root(), req.Origin, 1024)
+			if err != nil {
+				return err
+			}
+			if len(r.Keys) > 0 {
+				r.Keys = r.Keys[1:]
+				r.Values = r.Values[1:]
+			}
+			write(&proto.TrieRange{Keys: r.Keys, Values: r.Values, Proof: r.Proof})
+			return nil
+		}
+		return server.handleRPC(peer, msg, write, &txsToSync{})
+	}
+	good := func(peer *Peer, msg *p2p.Msg, write func(any)) error {
+		return server.handleRPC(peer, msg, write, &txsToSync{})
+	}
+
+	db := muxdb.NewMem()
+	repo, err := chain.NewRepository(db, remote.GenesisBlock())
+	require.NoError(t, err)
+	stater := state.NewStater(db)
+	local := New(repo, stater, nil, nil)
+	local.SetHeaderValidator(consensus.New(repo, stater, remote.GetForkConfig()))
+	local.peerSet.Add(newPipedPeer(t, bad, head))
+	local.peerSet.Add(newPipedPeer(t, good, head))
+	local.peerSet.Add(newPipedPeer(t, good, head))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	trusted, err := remote.Repo().NewBestChain().GetBlockID(10)
+	require.NoError(t, err)
+
+	var (
+		pivot   thor.Bytes32
+		quality uint32
+	)
+	// signers are checked against leader sets fetched with proofs
+	proposers := light.NewProposerValidator(repo, local)
+	require.NoError(t, local.SnapSync(ctx, trusted, proposers, func(id thor.Bytes32, q uint32) error {
+		pivot, quality = id, q
+		return nil
+	}))
+
+	wantPivot, err := remote.Repo().NewBestChain().GetBlockID(19)
+	require.NoError(t, err)
+	assert.Equal(t, wantPivot, pivot)
+	assert.Equal(t, uint32(3), quality)
+	assert.Equal(t, pivot, repo.BestBlockSummary().Header.ID())
+	// states before the pivot are absent
+	assert.Equal(t, uint32(19), stater.PrunedBefore())
+
+	// blocks and receipts
+	for i := uint32(1); i <= 19; i++ {
+		id, err := repo.NewBestChain().GetBlockID(i)
+		require.NoError(t, err)
+		want, err := remote.Repo().GetBlockReceipts(id)
+		require.NoError(t, err)
+		got, err := repo.GetBlockReceipts(id)
+		require.NoError(t, err)
+		assert.Equal(t, want.RootHash(), got.RootHash())
+	}
+
+	// state
+	assert.Equal(t, dumpState(t, remote.Stater(), remote.Repo(), pivot), dumpState(t, stater, repo, pivot))
+	summary, err := repo.GetBlockSummary(pivot)
+	require.NoError(t, err)
+	st := stater.NewState(summary.Root())
+	code, err := st.GetCode(thor.BytesToAddress([]byte("Authority")))
+	require.NoError(t, err)
+	assert.NotEmpty(t, code)
+}
+
+func TestSnapSyncNoPivot(t *testing.T) {
+	remote := newSnapTestChain(t, 5)
+	head := remote.Repo().BestBlockSummary().Header
+	// genesis finalized, no pivot available
+	server := New(remote.Repo(), remote.Stater(), &fakeFinality{remote.GenesisBlock().Header().ID(), 0}, nil)
+
+	pivot, err := proto.GetSnapPivot(context.Background(), newPipedPeer(t, func(peer *Peer, msg *p2p.Msg, write func(any)) error {
+		return server.handleRPC(peer, msg, write, &txsToSync{})
+	}, head))
+	require.NoError(t, err)
+	assert.True(t, pivot.BlockID.IsZero())
+
+	_, _, err = selectSnapPivot(context.Background(), Peers{newPipedPeer(t, func(peer *Peer, msg *p2p.Msg, write func(any)) error {
+		return server.handleRPC(peer, msg, write, &txsToSync{})
+	}, head)}, 0, math.MaxUint32)
+	assert.Error(t, err)
+}
+
+func TestSnapSyncTrustedCheckpoint(t *testing.T) {
+	remote := newSnapTestChain(t, 30)
+	head := remote.Repo().BestBlockSummary().Header
+	finalized, err := remote.Repo().NewBestChain().GetBlockID(20)
+	require.NoError(t, err)
+	server := New(remote.Repo(), remote.Stater(), &fakeFinality{finalized, 3}, nil)
+	handler := func(peer *Peer, msg *p2p.Msg, write func(any)) error {
+		return server.handleRPC(peer, msg, write, &txsToSync{})
+	}
+
+	// the pivot #19 is before the trusted checkpoint
+	_, _, err = selectSnapPivot(context.Background(), Peers{newPipedPeer(t, handler, head)}, 20, 29)
+	assert.Error(t, err)
+	// or more than one round after it
+	_, _, err = selectSnapPivot(context.Background(), Peers{newPipedPeer(t, handler, head)}, 0, 9)
+	assert.Error(t, err)
+
+	snapSync := func(trusted thor.Bytes32, proposers ProposerValidator) error {
+		db := muxdb.NewMem()
+		repo, err := chain.NewRepository(db, remote.GenesisBlock())
+		require.NoError(t, err)
+		local := New(repo, state.NewStater(db), nil, nil)
+		local.SetHeaderValidator(consensus.New(repo, nil, remote.GetForkConfig()))
+		for range snapMinPeers {
+			local.peerSet.Add(newPipedPeer(t, handler, head))
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		return local.SnapSync(ctx, trusted, proposers, func(thor.Bytes32, uint32) error { return nil })
+	}
+
+	trusted, err := remote.Repo().NewBestChain().GetBlockID(10)
+	require.NoError(t, err)
+	proposers := &fakeProposerValidator{}
+	assert.NoError(t, snapSync(trusted, proposers))
+	// proposers after the trusted checkpoint validated
+	assert.Equal(t, []uint32{11, 12, 13, 14, 15, 16, 17, 18, 19}, proposers.validated)
+
+	assert.ErrorContains(t, snapSync(trusted, &fakeProposerValidator{err: errors.New("bad signer")}), "bad signer")
+
+	conflicting := trusted
+	conflicting[31]++
+	assert.ErrorContains(t, snapSync(conflicting, &fakeProposerValidator{}), "trusted checkpoint")
+
+	assert.ErrorContains(t, snapSync(thor.Bytes32{}, &fakeProposerValidator{}), "trusted checkpoint required")
+
+	// not the first block of a round
+	trusted, err = remote.Repo().NewBestChain().GetBlockID(11)
+	require.NoError(t, err)
+	assert.ErrorContains(t, snapSync(trusted, &fakeProposerValidator{}), "first block of a round")
+}
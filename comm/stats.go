@@ -6,6 +6,9 @@
 package comm
 
 import (
+	"sort"
+	"time"
+
 	"github.com/vechain/thor/v2/thor"
 )
 
@@ -24,4 +27,26 @@ type PeerStats struct {
 	NetAddr     string
 	Inbound     bool
 	Duration    uint64 // in seconds
+	Score       int    // in [0, 100], the higher the better
+}
+
+func peersStats(peers Peers, bestTotalScore uint64) []*PeerStats {
+	var stats []*PeerStats
+	for _, peer := range peers {
+		bestID, totalScore := peer.Head()
+		stats = append(stats, &PeerStats{
+			Name:        peer.Name(),
+			BestBlockID: bestID,
+			TotalScore:  totalScore,
+			PeerID:      peer.ID().String(),
+			NetAddr:     peer.RemoteAddr().String(),
+			Inbound:     peer.Inbound(),
+			Duration:    uint64(time.Duration(peer.Duration()) / time.Second),
+			Score:       peer.Score(bestTotalScore),
+		})
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		return stats[i].Duration < stats[j].Duration
+	})
+	return stats
 }
@@ -6,8 +6,11 @@
 package comm
 
 import (
+	"cmp"
 	"context"
 	"fmt"
+	"slices"
+	"time"
 
 	"golang.org/x/sync/errgroup"
 
@@ -18,22 +21,91 @@ import (
 	"github.com/vechain/thor/v2/chain"
 	"github.com/vechain/thor/v2/co"
 	"github.com/vechain/thor/v2/comm/proto"
+	"github.com/vechain/thor/v2/consensus"
+	"github.com/vechain/thor/v2/p2p"
+	"github.com/vechain/thor/v2/thor"
 )
 
-type rawBlockBatch struct {
-	rawBlocks []rlp.RawValue
-	startNum  uint32
+const (
+	syncWindowRanges   = 16  // max count of ranges fetched ahead of the block stream
+	syncMaxPeerFailure = 3   // peer is excluded from the download after that many failures
+	syncSlowPeerRatio  = 10  // peer is skipped if the best throughput is that many times faster
+	syncThroughputEMA  = 0.3 // weight of the latest sample in the throughput moving average
+)
+
+var (
+	syncRangeSize      = uint32(512)      // count of blocks in a range requested from one peer
+	syncRequestTimeout = 10 * time.Second // timeout of a single blocks request
+	syncHeaderSegment  = 8192             // count of headers validated ahead of downloading bodies
+)
+
+// HeaderValidator validates a block header against its parent header, which may not have been processed.
+type HeaderValidator interface {
+	ValidateHeader(header, parent *block.Header, nowTimestamp uint64) error
+}
+
+// headerChecker checks the chain of the peer by headers before downloading bodies.
+type headerChecker struct {
+	validator HeaderValidator
+	finalized thor.Bytes32 // the local finalized checkpoint, which the peer chain must include
+}
+
+type blockBatch struct {
+	blocks []*block.Block
 }
 
-func download(_ctx context.Context, repo *chain.Repository, peer *Peer, headNum uint32, handler HandleBlockStream) error {
-	ancestor, err := findCommonAncestor(_ctx, repo, peer, headNum)
+// blockRange is a range of block numbers, both ends inclusive.
+type blockRange struct {
+	from, to uint32
+}
+
+// syncPeer is a peer taking part in a download.
+type syncPeer struct {
+	*Peer
+	headNum    uint32
+	throughput float64 // blocks per second, zero if not measured yet
+	failures   int
+	busy       bool
+	failed     map[uint32]bool // starts of ranges the peer failed to serve
+}
+
+type rangeResult struct {
+	rng     blockRange
+	peer    *syncPeer
+	blocks  []*block.Block
+	elapsed time.Duration
+	err     error
+}
+
+// download downloads blocks from the peers and feeds them to the handler.
+// If checker is not nil, headers of the best peer's chain are fetched and validated ahead,
+// and only the bodies of validated headers are downloaded.
+func download(
+	_ctx context.Context,
+	repo *chain.Repository,
+	peers Peers,
+	headNum uint32,
+	checker *headerChecker,
+	handler HandleBlockStream,
+) error {
+	// peers are ordered by total score, take the best one to find the common ancestor
+	ancestor, err := findCommonAncestor(_ctx, repo, peers[0], headNum)
 	if err != nil {
 		return errors.WithMessage(err, "find common ancestor")
 	}
+	if checker != nil && ancestor < block.Number(checker.finalized) {
+		// the peer chain is conflicting with the finalized checkpoint, which will never be accepted
+		peers[0].logger.Debug("peer chain conflicts with finalized checkpoint", "ancestor", ancestor)
+		return errors.New("peer chain conflicts with finalized checkpoint")
+	}
+	ancestorID, err := repo.NewBestChain().GetBlockID(ancestor)
+	if err != nil {
+		return errors.WithMessage(err, "get ancestor id")
+	}
 
 	var (
 		ctx, cancel = context.WithCancel(_ctx)
-		rawBatches  = make(chan rawBlockBatch, 10)
+		batches     = make(chan blockBatch, 10)
 		warmedUp    = make(chan *block.Block, 2048)
 	)
 	defer cancel()
@@ -42,16 +114,16 @@ func download(_ctx context.Context, repo *chain.Repository, peer *Peer, headNum
 
 	// Three-stage pipeline for block synchronization:
 	//
-	// Stage 1: Raw Data Fetcher (Worker 1)
-	//   - Fetches raw block data from peer in batches
-	//   - Sends raw data to rawBatches channel
-	//   - Closes rawBatches when done
+	// Stage 1: Block Fetcher (Worker 1)
+	//   - Fetches disjoint ranges of blocks from peers concurrently
+	//   - Re-requests ranges from another peer on failure or invalid data
+	//   - Reorders ranges and sends them to batches channel in sequence
+	//   - Closes batches when done
 	//
-	// Stage 2: Decoder & Warm-up (Worker 2)
-	//   - Receives raw data from rawBatches channel
-	//   - Decodes raw data into block objects
+	// Stage 2: Warm-up (Worker 2)
+	//   - Receives blocks from batches channel
 	//   - Pre-warms block/transaction caches (ID, Beta, IntrinsicGas, etc.)
-	//   - Sends decoded blocks to warmedUp channel
+	//   - Sends blocks to warmedUp channel
 	//   - Closes warmedUp when done
 	//
 	// Stage 3: Block Handler (Worker 3)
@@ -60,15 +132,28 @@ func download(_ctx context.Context, repo *chain.Repository, peer *Peer, headNum
 	//   - Runs until warmedUp channel is closed
 	//
 	// Channel Flow:
-	//   rawBatches (chan []byte) -> warmedUp (chan *block.Block)
+	//   batches (chan blockBatch) -> warmedUp (chan *block.Block)
+	var headerErr error
 	g.Go(func() error {
-		defer close(rawBatches)
-		return fetchRawBlockBatches(ctx, peer, ancestor+1, rawBatches)
+		defer close(batches)
+		sps := newSyncPeers(peers)
+		if checker == nil || peers[0].ProtoVersion() <= proto.Version1 {
+			_, err := fetchBlockBatches(ctx, sps, ancestor+1, ancestorID, batches, nil)
+			return err
+		}
+		if err := fetchValidatedBatches(ctx, repo, sps, ancestorID, checker.validator, batches); err != nil {
+			if ctx.Err() != nil {
+				return err
+			}
+			// blocks of headers validated are still handled
+			headerErr = err
+		}
+		return nil
 	})
 
 	g.Go(func() error {
 		defer close(warmedUp)
-		return decodeAndWarmupBatches(ctx, rawBatches, warmedUp)
+		return warmupBatches(ctx, batches, warmedUp)
 	})
 
 	g.Go(func() error {
@@ -79,51 +164,355 @@ func download(_ctx context.Context, repo *chain.Repository, peer *Peer, headNum
 		return err
 	}
 
-	return nil
+	return headerErr
 }
 
-func fetchRawBlockBatches(ctx context.Context, peer *Peer, fromBlockNum uint32, rawBatches chan<- rawBlockBatch) error {
-	for {
-		result, err := proto.GetBlocksFromNumber(ctx, peer, fromBlockNum)
+func newSyncPeers(peers Peers) []*syncPeer {
+	sps := make([]*syncPeer, 0, len(peers))
+	for _, peer := range peers {
+		headID, _ := peer.Head()
+		sps = append(sps, &syncPeer{
+			Peer:    peer,
+			headNum: block.Number(headID),
+			failed:  make(map[uint32]bool),
+		})
+	}
+	return sps
+}
+
+// fetchValidatedBatches fetches and validates segments of headers from the best peer, which is
+// the first one, then fetches bodies of each segment from all peers.
+// A bad peer chain is detected by headers, before blocks are executed.
+func fetchValidatedBatches(
+	ctx context.Context,
+	repo *chain.Repository,
+	peers []*syncPeer,
+	parentID thor.Bytes32,
+	validator HeaderValidator,
+	batches chan<- blockBatch,
+) error {
+	parent, err := repo.GetBlockSummary(parentID)
+	if err != nil {
+		return errors.WithMessage(err, "get parent")
+	}
+
+	heads := make([]uint32, 0, len(peers))
+	for _, p := range peers {
+		heads = append(heads, p.headNum)
+	}
+
+	best := peers[0]
+	for header := parent.Header; ; {
+		headers, err := fetchHeaders(ctx, best.Peer, header, validator)
+		if err != nil {
+			if consensus.IsCritical(err) {
+				best.logger.Debug("peer chain invalid", "err", err)
+				metricSyncRangeCount().AddWithLabel(1, map[string]string{"result": "invalid"})
+				best.Disconnect(p2p.DiscUselessPeer)
+			}
+			return errors.WithMessage(err, "fetch headers")
+		}
+		if len(headers) == 0 {
+			return nil
+		}
+
+		ids := make([]thor.Bytes32, 0, len(headers))
+		for _, h := range headers {
+			ids = append(ids, h.ID())
+		}
+		// bodies beyond validated headers are not fetched
+		lastNum := headers[len(headers)-1].Number()
+		for i, p := range peers {
+			p.headNum = min(heads[i], lastNum)
+		}
+
+		last, err := fetchBlockBatches(ctx, peers, header.Number()+1, header.ID(), batches, ids)
 		if err != nil {
 			return err
 		}
-		if len(result) == 0 {
+		if last != ids[len(ids)-1] {
+			// bodies not fully fetched
 			return nil
 		}
+		header = headers[len(headers)-1]
+	}
+}
+
+// fetchHeaders fetches headers following the parent from the peer, and validates them in sequence.
+// It returns at most syncHeaderSegment headers, and stops before the first header in the future.
+func fetchHeaders(ctx context.Context, peer *Peer, parent *block.Header, validator HeaderValidator) ([]*block.Header, error) {
+	var (
+		headers []*block.Header
+		now     = uint64(time.Now().Unix())
+	)
+	for len(headers) < syncHeaderSegment {
+		result, err := func() ([]rlp.RawValue, error) {
+			ctx, cancel := context.WithTimeout(ctx, syncRequestTimeout)
+			defer cancel()
+			return proto.GetHeadersFromNumber(ctx, peer, parent.Number()+1)
+		}()
+		if err != nil {
+			return nil, err
+		}
+		if len(result) == 0 {
+			break
+		}
 
-		batch := rawBlockBatch{
-			rawBlocks: result,
-			startNum:  fromBlockNum,
+		for _, raw := range result {
+			var header block.Header
+			if err := rlp.DecodeBytes(raw, &header); err != nil {
+				return nil, errors.Wrap(err, "invalid header")
+			}
+			if header.Number() != parent.Number()+1 {
+				return nil, errors.New("broken sequence")
+			}
+			if err := validator.ValidateHeader(&header, parent, now); err != nil {
+				if consensus.IsFutureBlock(err) {
+					return headers, nil
+				}
+				return nil, err
+			}
+			headers = append(headers, &header)
+			parent = &header
+			if len(headers) >= syncHeaderSegment {
+				break
+			}
 		}
+	}
+	return headers, nil
+}
+
+// fetchBlockBatches fetches blocks since fromNum from the peers, and sends them in sequence to batches.
+// Each idle peer is assigned a range of blocks, peers with higher throughput are preferred.
+// If ids is not nil, blocks fetched must match the expected ids, which are validated in advance.
+// It returns the id of the last block sent when no more blocks can be fetched from any peer.
+func fetchBlockBatches(
+	ctx context.Context,
+	peers []*syncPeer,
+	fromNum uint32,
+	parentID thor.Bytes32,
+	batches chan<- blockBatch,
+	ids []thor.Bytes32,
+) (thor.Bytes32, error) {
+	var (
+		results  = make(chan *rangeResult, len(peers))
+		retries  []blockRange                    // ranges to be re-requested, ordered by start
+		pending  = make(map[uint32]*rangeResult) // fetched ranges waiting to be sent, by start
+		next     = fromNum                       // start of the next new range
+		sendNum  = fromNum                       // start of the next range to be sent
+		inflight int
+		endNum   uint32
+	)
+	for _, p := range peers {
+		endNum = max(endNum, p.headNum)
+	}
+
+	retry := func(rng blockRange) {
+		i, _ := slices.BinarySearchFunc(retries, rng.from, func(r blockRange, from uint32) int {
+			return cmp.Compare(r.from, from)
+		})
+		retries = slices.Insert(retries, i, rng)
+	}
 
+	fail := func(res *rangeResult, rng blockRange, penalty bool) {
+		res.peer.failed[rng.from] = true
+		if penalty {
+			res.peer.failures++
+		}
+		retry(rng)
+	}
+
+	// nextRange returns the range to be fetched next, retries come first.
+	nextRange := func() (blockRange, bool) {
+		if len(retries) > 0 {
+			return retries[0], true
+		}
+		if next > endNum || next >= sendNum+syncWindowRanges*syncRangeSize {
+			return blockRange{}, false
+		}
+		return blockRange{next, min(next+syncRangeSize-1, endNum)}, true
+	}
+
+	// pickPeer picks the idle peer with the highest throughput to fetch the range.
+	pickPeer := func(rng blockRange) *syncPeer {
+		var best float64
+		for _, p := range peers {
+			if p.failures < syncMaxPeerFailure {
+				best = max(best, p.throughput)
+			}
+		}
+
+		var picked *syncPeer
+		for _, p := range peers {
+			if p.busy || p.failures >= syncMaxPeerFailure || p.headNum < rng.from || p.failed[rng.from] {
+				continue
+			}
+			// unmeasured peers get a chance to be measured
+			if p.throughput > 0 && p.throughput*syncSlowPeerRatio < best {
+				continue
+			}
+			if picked == nil || p.throughput == 0 || (picked.throughput > 0 && p.throughput > picked.throughput) {
+				picked = p
+			}
+		}
+		return picked
+	}
+
+	for {
+		for {
+			rng, ok := nextRange()
+			if !ok {
+				break
+			}
+			p := pickPeer(rng)
+			if p == nil {
+				break
+			}
+			if len(retries) > 0 {
+				retries = retries[1:]
+			} else {
+				next = rng.to + 1
+			}
+
+			p.busy = true
+			inflight++
+			go func() {
+				start := time.Now()
+				blocks, err := fetchRange(ctx, p.Peer, rng)
+				results <- &rangeResult{rng, p, blocks, time.Since(start), err}
+			}()
+		}
+
+		if inflight == 0 {
+			// no more blocks can be fetched
+			if len(retries) > 0 {
+				logger.Debug("no peer to fetch blocks", "from", retries[0].from)
+			}
+			return parentID, nil
+		}
+
+		var res *rangeResult
 		select {
-		case rawBatches <- batch:
 		case <-ctx.Done():
-			return ctx.Err()
+			return thor.Bytes32{}, ctx.Err()
+		case res = <-results:
+		}
+		inflight--
+		res.peer.busy = false
+
+		switch {
+		case res.err != nil:
+			res.peer.logger.Debug("failed to fetch blocks", "from", res.rng.from, "err", res.err)
+			metricSyncRangeCount().AddWithLabel(1, map[string]string{"result": "failed"})
+			fail(res, res.rng, true)
+		case len(res.blocks) == 0:
+			// the peer does not have the range
+			fail(res, res.rng, false)
+		case ids != nil && !matchIDs(res.blocks, ids[res.rng.from-fromNum:]):
+			// not the blocks of validated headers
+			res.peer.logger.Debug("fetched blocks mismatch headers", "from", res.rng.from)
+			res.peer.RecordInvalidMsg()
+			metricSyncRangeCount().AddWithLabel(1, map[string]string{"result": "invalid"})
+			fail(res, res.rng, true)
+		default:
+			metricSyncRangeCount().AddWithLabel(1, map[string]string{"result": "fetched"})
+			tp := float64(len(res.blocks)) / max(res.elapsed.Seconds(), 0.001)
+			if res.peer.throughput == 0 {
+				res.peer.throughput = tp
+			} else {
+				res.peer.throughput = res.peer.throughput*(1-syncThroughputEMA) + tp*syncThroughputEMA
+			}
+			if n := uint32(len(res.blocks)); n < res.rng.to-res.rng.from+1 {
+				// the peer has fewer blocks than expected, the rest to be fetched from others
+				retry(blockRange{res.rng.from + n, res.rng.to})
+				res.rng.to = res.rng.from + n - 1
+			}
+			pending[res.rng.from] = res
 		}
 
-		fromBlockNum += uint32(len(result))
+		// send fetched ranges in sequence
+		for {
+			res, ok := pending[sendNum]
+			if !ok {
+				break
+			}
+			delete(pending, sendNum)
+
+			if res.blocks[0].Header().ParentID() != parentID {
+				// on another branch
+				res.peer.logger.Debug("fetched blocks not linked", "from", res.rng.from)
+				metricSyncRangeCount().AddWithLabel(1, map[string]string{"result": "invalid"})
+				fail(res, res.rng, true)
+				break
+			}
+
+			select {
+			case <-ctx.Done():
+				return thor.Bytes32{}, ctx.Err()
+			case batches <- blockBatch{res.blocks}:
+			}
+			parentID = res.blocks[len(res.blocks)-1].Header().ID()
+			sendNum = res.rng.to + 1
+		}
 	}
 }
 
-func decodeAndWarmupBatches(ctx context.Context, rawBatches <-chan rawBlockBatch, warmedUp chan<- *block.Block) error {
-	var err error
-	<-co.Parallel(func(queue chan<- func()) {
-		for batch := range rawBatches {
-			for i, raw := range batch.rawBlocks {
-				var blk block.Block
-				if err = rlp.DecodeBytes(raw, &blk); err != nil {
-					err = errors.Wrap(err, "invalid block")
-					return
-				}
+// matchIDs returns whether blocks match the leading ids.
+func matchIDs(blocks []*block.Block, ids []thor.Bytes32) bool {
+	if len(blocks) > len(ids) {
+		return false
+	}
+	for i, blk := range blocks {
+		if blk.Header().ID() != ids[i] {
+			return false
+		}
+	}
+	return true
+}
 
-				expectedNum := batch.startNum + uint32(i)
-				if blk.Header().Number() != expectedNum {
-					err = errors.New("broken sequence")
-					return
-				}
+// fetchRange fetches blocks in the range from the peer. Blocks returned are fewer than expected
+// if the peer does not have them all.
+func fetchRange(ctx context.Context, peer *Peer, rng blockRange) ([]*block.Block, error) {
+	blocks := make([]*block.Block, 0, rng.to-rng.from+1)
+	for num := rng.from; num <= rng.to; {
+		result, err := func() ([]rlp.RawValue, error) {
+			ctx, cancel := context.WithTimeout(ctx, syncRequestTimeout)
+			defer cancel()
+			return proto.GetBlocksFromNumber(ctx, peer, num)
+		}()
+		if err != nil {
+			return nil, err
+		}
+		if len(result) == 0 {
+			break
+		}
 
+		for _, raw := range result {
+			if num > rng.to {
+				break
+			}
+			var blk block.Block
+			if err := rlp.DecodeBytes(raw, &blk); err != nil {
+				return nil, errors.Wrap(err, "invalid block")
+			}
+			if blk.Header().Number() != num {
+				return nil, errors.New("broken sequence")
+			}
+			if n := len(blocks); n > 0 && blk.Header().ParentID() != blocks[n-1].Header().ID() {
+				return nil, errors.New("broken chain")
+			}
+			blocks = append(blocks, &blk)
+			num++
+		}
+	}
+	return blocks, nil
+}
+
+func warmupBatches(ctx context.Context, batches <-chan blockBatch, warmedUp chan<- *block.Block) error {
+	var err error
+	<-co.Parallel(func(queue chan<- func()) {
+		for batch := range batches {
+			for _, blk := range batch.blocks {
 				// warm up functions with cache, ignore error here
 				queue <- func() {
 					_ = blk.Header().ID()
@@ -142,7 +531,7 @@ func decodeAndWarmupBatches(ctx context.Context, rawBatches <-chan rawBlockBatch
 				case <-ctx.Done():
 					err = ctx.Err()
 					return
-				case warmedUp <- &blk:
+				case warmedUp <- blk:
 					// when queued blocks count > 10% warmed up channel cap,
 					// send nil block to throttle to reduce mem pressure.
 					if len(warmedUp)*10 > cap(warmedUp) {
@@ -0,0 +1,394 @@
+// Copyright (c) 2025 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package comm
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"errors"
+	"io"
+	"math/big"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vechain/thor/v2/block"
+	"github.com/vechain/thor/v2/chain"
+	"github.com/vechain/thor/v2/comm/proto"
+	"github.com/vechain/thor/v2/genesis"
+	"github.com/vechain/thor/v2/muxdb"
+	"github.com/vechain/thor/v2/p2p"
+	"github.com/vechain/thor/v2/p2p/discover"
+	"github.com/vechain/thor/v2/test/testchain"
+	"github.com/vechain/thor/v2/thor"
+	"github.com/vechain/thor/v2/tx"
+)
+
+// bufferedRW buffers the payload of read msgs, since rpc decodes a msg in more than one pass,
+// which requires the payload to be a byte reader as that from real connections.
+type bufferedRW struct {
+	p2p.MsgReadWriter
+}
+
+func (rw bufferedRW) ReadMsg() (p2p.Msg, error) {
+	msg, err := rw.MsgReadWriter.ReadMsg()
+	if err != nil {
+		return msg, err
+	}
+	data, err := io.ReadAll(msg.Payload)
+	msg.Payload = bytes.NewReader(data)
+	return msg, err
+}
+
+// newPipedPeer creates a peer connected through an in-memory pipe to a remote serving with handle.
+func newPipedPeer(t *testing.T, handle func(peer *Peer, msg *p2p.Msg, write func(any)) error, head *block.Header) *Peer {
+	var id discover.NodeID
+	rand.Read(id[:])
+
+	caps := []p2p.Cap{{Name: proto.Name, Version: proto.Version}}
+	rw1, rw2 := p2p.MsgPipe()
+	local := newPeer(p2p.NewPeer(id, "local", caps), bufferedRW{rw1})
+	remote := newPeer(p2p.NewPeer(id, "remote", caps), bufferedRW{rw2})
+	go local.Serve(func(*p2p.Msg, func(any)) error { return nil }, proto.MaxMsgSize)
+	go remote.Serve(func(msg *p2p.Msg, write func(any)) error {
+		return handle(remote, msg, write)
+	}, proto.MaxMsgSize)
+	t.Cleanup(func() { rw1.Close() })
+
+	local.UpdateHead(head.ID(), head.TotalScore())
+	return local
+}
+
+type syncTestServer struct {
+	comm     *Communicator
+	requests atomic.Int32
+}
+
+func (s *syncTestServer) handle(peer *Peer, msg *p2p.Msg, write func(any)) error {
+	if msg.Code == proto.MsgGetBlocksFromNumber {
+		s.requests.Add(1)
+	}
+	return s.comm.handleRPC(peer, msg, write, &txsToSync{})
+}
+
+func newSyncTestChain(t *testing.T, n int) (*testchain.Chain, []thor.Bytes32) {
+	chain, err := testchain.NewDefault()
+	require.NoError(t, err)
+	for range n {
+		require.NoError(t, chain.MintBlock())
+	}
+
+	ids := make([]thor.Bytes32, 0, n)
+	best := chain.Repo().NewBestChain()
+	for i := 1; i <= n; i++ {
+		id, err := best.GetBlockID(uint32(i))
+		require.NoError(t, err)
+		ids = append(ids, id)
+	}
+	return chain, ids
+}
+
+func collectBlocks(ids *[]thor.Bytes32) HandleBlockStream {
+	return func(ctx context.Context, stream <-chan *block.Block) error {
+		for blk := range stream {
+			if blk != nil {
+				*ids = append(*ids, blk.Header().ID())
+			}
+		}
+		return nil
+	}
+}
+
+func setSyncParams(t *testing.T, rangeSize uint32, timeout time.Duration) {
+	oldSize, oldTimeout := syncRangeSize, syncRequestTimeout
+	syncRangeSize, syncRequestTimeout = rangeSize, timeout
+	t.Cleanup(func() { syncRangeSize, syncRequestTimeout = oldSize, oldTimeout })
+}
+
+func newLocalRepo(t *testing.T, remote *testchain.Chain) *chain.Repository {
+	repo, err := chain.NewRepository(muxdb.NewMem(), remote.GenesisBlock())
+	require.NoError(t, err)
+	return repo
+}
+
+func TestDownloadFromMultiplePeers(t *testing.T) {
+	setSyncParams(t, 8, time.Second)
+
+	remote, ids := newSyncTestChain(t, 100)
+	head := remote.Repo().BestBlockSummary().Header
+
+	servers := make([]*syncTestServer, 3)
+	peers := make(Peers, 0, len(servers))
+	for i := range servers {
+		servers[i] = &syncTestServer{comm: New(remote.Repo(), nil, nil, nil)}
+		peers = append(peers, newPipedPeer(t, servers[i].handle, head))
+	}
+
+	var got []thor.Bytes32
+	require.NoError(t, download(context.Background(), newLocalRepo(t, remote), peers, 0, nil, collectBlocks(&got)))
+	assert.Equal(t, ids, got)
+
+	for _, s := range servers {
+		assert.NotZero(t, s.requests.Load(), "all peers should take part in")
+	}
+}
+
+func TestDownloadWithFaultyPeers(t *testing.T) {
+	setSyncParams(t, 8, 100*time.Millisecond)
+
+	remote, ids := newSyncTestChain(t, 60)
+	head := remote.Repo().BestBlockSummary().Header
+
+	good := &syncTestServer{comm: New(remote.Repo(), nil, nil, nil)}
+
+	// never responds in time
+	var slowRequests atomic.Int32
+	slow := func(peer *Peer, msg *p2p.Msg, write func(any)) error {
+		if msg.Code == proto.MsgGetBlocksFromNumber {
+			slowRequests.Add(1)
+			time.Sleep(200 * time.Millisecond)
+		}
+		return good.comm.handleRPC(peer, msg, write, &txsToSync{})
+	}
+
+	// responds blocks out of sequence
+	var badRequests atomic.Int32
+	bad := func(peer *Peer, msg *p2p.Msg, write func(any)) error {
+		if msg.Code == proto.MsgGetBlocksFromNumber {
+			badRequests.Add(1)
+			var num uint32
+			if err := msg.Decode(&num); err != nil {
+				return err
+			}
+			blk, err := remote.Repo().NewBestChain().GetBlock(num + 1)
+			if err != nil {
+				write([]rlp.RawValue{})
+				return nil
+			}
+			raw, _ := rlp.EncodeToBytes(blk)
+			write([]rlp.RawValue{raw})
+			return nil
+		}
+		return good.comm.handleRPC(peer, msg, write, &txsToSync{})
+	}
+
+	peers := Peers{
+		newPipedPeer(t, slow, head),
+		newPipedPeer(t, bad, head),
+		newPipedPeer(t, good.handle, head),
+	}
+
+	var got []thor.Bytes32
+	require.NoError(t, download(context.Background(), newLocalRepo(t, remote), peers, 0, nil, collectBlocks(&got)))
+	assert.Equal(t, ids, got)
+
+	// faulty peers are excluded after max failures
+	assert.LessOrEqual(t, slowRequests.Load(), int32(syncMaxPeerFailure))
+	assert.LessOrEqual(t, badRequests.Load(), int32(syncMaxPeerFailure))
+	assert.NotZero(t, good.requests.Load())
+}
+
+func TestDownloadPeersWithShorterChain(t *testing.T) {
+	setSyncParams(t, 8, time.Second)
+
+	remote, ids := newSyncTestChain(t, 40)
+	head := remote.Repo().BestBlockSummary().Header
+
+	// claims the same head, but only has the first 20 blocks
+	short := func(peer *Peer, msg *p2p.Msg, write func(any)) error {
+		if msg.Code == proto.MsgGetBlocksFromNumber {
+			var num uint32
+			if err := msg.Decode(&num); err != nil {
+				return err
+			}
+			var result []rlp.RawValue
+			for ; num <= 20; num++ {
+				blk, _ := remote.Repo().NewBestChain().GetBlock(num)
+				raw, _ := rlp.EncodeToBytes(blk)
+				result = append(result, raw)
+			}
+			write(result)
+			return nil
+		}
+		return New(remote.Repo(), nil, nil, nil).handleRPC(peer, msg, write, &txsToSync{})
+	}
+
+	// only the short peer
+	var got []thor.Bytes32
+	peers := Peers{newPipedPeer(t, short, head)}
+	require.NoError(t, download(context.Background(), newLocalRepo(t, remote), peers, 0, nil, collectBlocks(&got)))
+	assert.Equal(t, ids[:20], got)
+
+	// the rest fetched from another peer
+	got = nil
+	good := &syncTestServer{comm: New(remote.Repo(), nil, nil, nil)}
+	peers = Peers{newPipedPeer(t, short, head), newPipedPeer(t, good.handle, head)}
+	require.NoError(t, download(context.Background(), newLocalRepo(t, remote), peers, 0, nil, collectBlocks(&got)))
+	assert.Equal(t, ids, got)
+}
+
+// linkValidator checks only the linkage of headers, and rejects the header of badNum.
+type linkValidator struct {
+	badNum uint32
+	count  atomic.Int32
+}
+
+func (v *linkValidator) ValidateHeader(header, parent *block.Header, _ uint64) error {
+	v.count.Add(1)
+	if header.ParentID() != parent.ID() || header.Number() == v.badNum {
+		return errors.New("invalid header")
+	}
+	return nil
+}
+
+func TestDownloadHeadersFirst(t *testing.T) {
+	setSyncParams(t, 8, time.Second)
+	oldSegment := syncHeaderSegment
+	syncHeaderSegment = 30
+	t.Cleanup(func() { syncHeaderSegment = oldSegment })
+
+	remote, ids := newSyncTestChain(t, 100)
+	head := remote.Repo().BestBlockSummary().Header
+
+	servers := make([]*syncTestServer, 2)
+	peers := make(Peers, 0, len(servers))
+	for i := range servers {
+		servers[i] = &syncTestServer{comm: New(remote.Repo(), nil, nil, nil)}
+		peers = append(peers, newPipedPeer(t, servers[i].handle, head))
+	}
+
+	validator := &linkValidator{}
+	var got []thor.Bytes32
+	require.NoError(t, download(context.Background(), newLocalRepo(t, remote), peers, 0, &headerChecker{validator: validator}, collectBlocks(&got)))
+	assert.Equal(t, ids, got)
+	assert.Equal(t, int32(len(ids)), validator.count.Load())
+
+	// the bad chain is detected before any body fetched
+	for _, s := range servers {
+		s.requests.Store(0)
+	}
+	got = nil
+	err := download(context.Background(), newLocalRepo(t, remote), peers, 0, &headerChecker{validator: &linkValidator{badNum: 20}}, collectBlocks(&got))
+	assert.Error(t, err)
+	assert.Empty(t, got)
+	for _, s := range servers {
+		assert.Zero(t, s.requests.Load())
+	}
+
+	// bodies of the valid segment are downloaded
+	err = download(context.Background(), newLocalRepo(t, remote), peers, 0, &headerChecker{validator: &linkValidator{badNum: 40}}, collectBlocks(&got))
+	assert.Error(t, err)
+	assert.Equal(t, ids[:30], got)
+}
+
+func TestDownloadBodiesMismatchHeaders(t *testing.T) {
+	setSyncParams(t, 8, 100*time.Millisecond)
+
+	remote, ids := newSyncTestChain(t, 40)
+	// another chain forked since block 1
+	other, err := testchain.NewDefault()
+	require.NoError(t, err)
+	to := thor.BytesToAddress([]byte("to"))
+	require.NoError(t, other.MintClauses(genesis.DevAccounts()[0], []*tx.Clause{tx.NewClause(&to).WithValue(big.NewInt(1))}))
+	for range 39 {
+		require.NoError(t, other.MintBlock())
+	}
+	head := remote.Repo().BestBlockSummary().Header
+
+	good := &syncTestServer{comm: New(remote.Repo(), nil, nil, nil)}
+	// serves headers of the remote chain, but blocks of another chain
+	var badRequests atomic.Int32
+	bad := func(peer *Peer, msg *p2p.Msg, write func(any)) error {
+		if msg.Code == proto.MsgGetBlocksFromNumber {
+			badRequests.Add(1)
+			return New(other.Repo(), nil, nil, nil).handleRPC(peer, msg, write, &txsToSync{})
+		}
+		return good.comm.handleRPC(peer, msg, write, &txsToSync{})
+	}
+
+	peers := Peers{newPipedPeer(t, good.handle, head), newPipedPeer(t, bad, head)}
+	var got []thor.Bytes32
+	require.NoError(t, download(context.Background(), newLocalRepo(t, remote), peers, 0, &headerChecker{validator: &linkValidator{}}, collectBlocks(&got)))
+	assert.Equal(t, ids, got)
+	assert.LessOrEqual(t, badRequests.Load(), int32(syncMaxPeerFailure))
+}
+
+func TestDownloadConflictsWithFinalized(t *testing.T) {
+	remote, ids := newSyncTestChain(t, 40)
+	head := remote.Repo().BestBlockSummary().Header
+
+	// the local chain shares the first 20 blocks
+	local := newLocalRepo(t, remote)
+	for _, id := range ids[:20] {
+		blk, err := remote.Repo().GetBlock(id)
+		require.NoError(t, err)
+		receipts, err := remote.Repo().GetBlockReceipts(id)
+		require.NoError(t, err)
+		require.NoError(t, local.AddBlock(blk, receipts, 0, true))
+	}
+
+	// the peer forks since block 11
+	good := &syncTestServer{comm: New(remote.Repo(), nil, nil, nil)}
+	fork := func(peer *Peer, msg *p2p.Msg, write func(any)) error {
+		if msg.Code == proto.MsgGetBlockIDByNumber {
+			var num uint32
+			if err := msg.Decode(&num); err != nil {
+				return err
+			}
+			if num > 10 {
+				write(thor.Blake2b([]byte("fork"), []byte{byte(num)}))
+				return nil
+			}
+			id, _ := remote.Repo().NewBestChain().GetBlockID(num)
+			write(id)
+			return nil
+		}
+		return good.handle(peer, msg, write)
+	}
+	peers := Peers{newPipedPeer(t, fork, head)}
+
+	var got []thor.Bytes32
+	checker := &headerChecker{validator: &linkValidator{}, finalized: ids[14]}
+	assert.Error(t, download(context.Background(), local, peers, 20, checker, collectBlocks(&got)))
+	assert.Empty(t, got)
+	assert.Zero(t, good.requests.Load())
+
+	// not conflicting if finalized before the fork
+	checker.finalized = ids[9]
+	require.NoError(t, download(context.Background(), local, peers, 20, checker, collectBlocks(&got)))
+	assert.Equal(t, ids[10:], got)
+}
+
+func TestGetBlocksFromNumberExpired(t *testing.T) {
+	remote, ids := newSyncTestChain(t, 10)
+	head := remote.Repo().BestBlockSummary().Header
+	require.NoError(t, remote.Repo().ExpireHistory(context.Background(), 5))
+
+	server := New(remote.Repo(), nil, nil, nil)
+	peer := newPipedPeer(t, func(peer *Peer, msg *p2p.Msg, write func(any)) error {
+		return server.handleRPC(peer, msg, write, &txsToSync{})
+	}, head)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// refused
+	blocks, err := proto.GetBlocksFromNumber(ctx, peer, 4)
+	require.NoError(t, err)
+	assert.Empty(t, blocks)
+
+	blocks, err = proto.GetBlocksFromNumber(ctx, peer, 5)
+	require.NoError(t, err)
+	require.NotEmpty(t, blocks)
+	var blk block.Block
+	require.NoError(t, rlp.DecodeBytes(blocks[0], &blk))
+	assert.Equal(t, ids[4], blk.Header().ID())
+}
@@ -0,0 +1,103 @@
+// Copyright (c) 2025 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package comm
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/mclock"
+)
+
+const (
+	txGateRate  = 50  // Sustained number of txs per second accepted from a single peer
+	txGateBurst = 500 // Maximum number of txs a peer can push in a burst
+
+	txGateMinSamples     = 100 // Minimum number of judged txs before a peer's reputation is evaluated
+	txGateMaxRejectRatio = 0.8 // Peers with a higher ratio of rejected txs are considered harmful
+	txGateDecayInterval  = time.Minute
+)
+
+// txGate rate-limits incoming txs of a peer and tracks how many of them
+// were accepted or rejected by the tx pool.
+type txGate struct {
+	lock       sync.Mutex
+	tokens     float64
+	lastRefill mclock.AbsTime
+	lastDecay  mclock.AbsTime
+	accepted   uint64
+	rejected   uint64
+	dropped    uint64
+}
+
+func newTxGate() *txGate {
+	now := mclock.Now()
+	return &txGate{
+		tokens:     txGateBurst,
+		lastRefill: now,
+		lastDecay:  now,
+	}
+}
+
+// Allow consumes a token and returns whether the tx should be processed.
+func (g *txGate) Allow() bool {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	now := mclock.Now()
+	elapsed := time.Duration(now - g.lastRefill).Seconds()
+	g.lastRefill = now
+
+	g.tokens += elapsed * txGateRate
+	if g.tokens > txGateBurst {
+		g.tokens = txGateBurst
+	}
+	if g.tokens < 1 {
+		g.dropped++
+		return false
+	}
+	g.tokens--
+	return true
+}
+
+// Record records the judgement of a tx.
+func (g *txGate) Record(accepted bool) {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	// halve the counters periodically, so that the reputation reflects recent behaviour
+	now := mclock.Now()
+	if time.Duration(now-g.lastDecay) >= txGateDecayInterval {
+		g.accepted /= 2
+		g.rejected /= 2
+		g.lastDecay = now
+	}
+
+	if accepted {
+		g.accepted++
+	} else {
+		g.rejected++
+	}
+}
+
+// Stats returns counts of accepted, rejected and rate-limited txs.
+func (g *txGate) Stats() (accepted, rejected, dropped uint64) {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	return g.accepted, g.rejected, g.dropped
+}
+
+// IsHarmful returns whether the txs from the peer are consistently rejected.
+func (g *txGate) IsHarmful() bool {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	total := g.accepted + g.rejected
+	if total < txGateMinSamples {
+		return false
+	}
+	return float64(g.rejected)/float64(total) > txGateMaxRejectRatio
+}
@@ -0,0 +1,130 @@
+// Copyright (c) 2025 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package comm
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/mclock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vechain/thor/v2/comm/proto"
+	"github.com/vechain/thor/v2/genesis"
+	"github.com/vechain/thor/v2/p2p"
+	"github.com/vechain/thor/v2/test/testchain"
+	"github.com/vechain/thor/v2/thor"
+	"github.com/vechain/thor/v2/tx"
+	"github.com/vechain/thor/v2/txpool"
+)
+
+func TestTxGateAllow(t *testing.T) {
+	g := newTxGate()
+
+	for range txGateBurst {
+		assert.True(t, g.Allow())
+	}
+	assert.False(t, g.Allow())
+
+	_, _, dropped := g.Stats()
+	assert.Equal(t, uint64(1), dropped)
+
+	// simulate one second passed
+	g.lastRefill -= mclock.AbsTime(time.Second)
+	for range txGateRate {
+		assert.True(t, g.Allow())
+	}
+	assert.False(t, g.Allow())
+}
+
+func TestTxGateReputation(t *testing.T) {
+	g := newTxGate()
+
+	for range txGateMinSamples - 1 {
+		g.Record(false)
+	}
+	// not enough samples
+	assert.False(t, g.IsHarmful())
+
+	g.Record(false)
+	assert.True(t, g.IsHarmful())
+
+	for range txGateMinSamples {
+		g.Record(true)
+	}
+	assert.False(t, g.IsHarmful())
+
+	accepted, rejected, _ := g.Stats()
+	assert.Equal(t, uint64(txGateMinSamples), accepted)
+	assert.Equal(t, uint64(txGateMinSamples), rejected)
+}
+
+func TestTxGateDecay(t *testing.T) {
+	g := newTxGate()
+	for range 10 {
+		g.Record(false)
+	}
+
+	g.lastDecay -= mclock.AbsTime(txGateDecayInterval)
+	g.Record(true)
+
+	accepted, rejected, _ := g.Stats()
+	assert.Equal(t, uint64(1), accepted)
+	assert.Equal(t, uint64(5), rejected)
+}
+
+func TestTxGateOnlyRecordsBadTxs(t *testing.T) {
+	chain, err := testchain.NewDefault()
+	require.NoError(t, err)
+	pool := txpool.New(chain.Repo(), chain.Stater(), txpool.Options{
+		Limit:           100,
+		LimitPerAccount: 1,
+		MaxLifetime:     time.Hour,
+	}, chain.GetForkConfig())
+	defer pool.Close()
+
+	server := New(chain.Repo(), nil, nil, pool)
+	var remote atomic.Pointer[Peer]
+	peer := newPipedPeer(t, func(peer *Peer, msg *p2p.Msg, write func(any)) error {
+		remote.Store(peer)
+		return server.handleRPC(peer, msg, write, &txsToSync{})
+	}, chain.GenesisBlock().Header())
+
+	newTx := func(chainTag byte, nonce uint64, acc genesis.DevAccount) *tx.Transaction {
+		to := thor.BytesToAddress([]byte("to"))
+		trx := tx.NewBuilder(tx.TypeLegacy).
+			ChainTag(chainTag).
+			Expiration(1000).
+			Gas(21000).
+			Nonce(nonce).
+			Clause(tx.NewClause(&to)).
+			Build()
+		return tx.MustSign(trx, acc.PrivateKey)
+	}
+	var (
+		ctx      = context.Background()
+		chainTag = chain.Repo().ChainTag()
+		accs     = genesis.DevAccounts()
+	)
+	require.NoError(t, proto.NotifyNewTx(ctx, peer, newTx(chainTag, 1, accs[0])))
+	// beyond the quota of the account
+	require.NoError(t, proto.NotifyNewTx(ctx, peer, newTx(chainTag, 2, accs[0])))
+	// invalid on any chain state
+	require.NoError(t, proto.NotifyNewTx(ctx, peer, newTx(chainTag+1, 3, accs[1])))
+	require.NoError(t, proto.NotifyNewTx(ctx, peer, newTx(chainTag, 4, accs[2])))
+
+	assert.Eventually(t, func() bool {
+		p := remote.Load()
+		if p == nil {
+			return false
+		}
+		accepted, rejected, _ := p.txGate.Stats()
+		return accepted == 2 && rejected == 1
+	}, time.Second, 10*time.Millisecond)
+}
@@ -31,6 +31,7 @@ type Consensus struct {
 	forkConfig           *thor.ForkConfig
 	correctReceiptsRoots map[string]string
 	validatorsCache      *simplelru.LRU
+	parallelExec         bool
 }
 
 // New create a Consensus instance.
@@ -46,6 +47,13 @@ func New(repo *chain.Repository, stater *state.Stater, forkConfig *thor.ForkConf
 	}
 }
 
+// EnableParallelExecution enables speculative parallel execution of block txs.
+// Txs are executed in parallel upon isolated states of the parent block, and those conflicting
+// with preceding txs of the block are re-executed serially.
+func (c *Consensus) EnableParallelExecution() {
+	c.parallelExec = true
+}
+
 // Process process a block.
 func (c *Consensus) Process(
 	parentSummary *chain.BlockSummary,
@@ -55,14 +63,13 @@ func (c *Consensus) Process(
 ) (*state.Stage, tx.Receipts, error) {
 	header := blk.Header()
 	state := c.stater.NewState(parentSummary.Root())
+	// warm up state for txs while validating the header
+	prefetcher := c.stater.NewPrefetcher(parentSummary.Root())
+	prefetcher.PrefetchTxs(blk.Transactions())
+	state.UsePrefetcher(prefetcher)
 
-	var features tx.Features
-	if header.Number() >= c.forkConfig.VIP191 {
-		features |= tx.DelegationFeature
-	}
-
-	if header.TxsFeatures() != features {
-		return nil, nil, consensusError(fmt.Sprintf("block txs features invalid: want %v, have %v", features, header.TxsFeatures()))
+	if err := c.validateTxsFeatures(header); err != nil {
+		return nil, nil, err
 	}
 
 	stage, receipts, err := c.validate(state, blk, parentSummary.Header, nowTimestamp, blockConflicts)
@@ -73,6 +80,67 @@ func (c *Consensus) Process(
 	return stage, receipts, nil
 }
 
+// ValidateHeader validates the block header against its parent header.
+// It checks the signature, the VRF proof, the timestamp and fields derived from the parent, which
+// allows a chain of headers to be verified before the bodies downloaded.
+// The signer and the timestamp are validated against the schedule only if the parent is a processed
+// block with its state not pruned, e.g. the first one of a chain of headers to be verified. Otherwise,
+// scheduling is not checked here but left to Process.
+//
+// Caches are shared with Process, so it should not be called concurrently with Process.
+func (c *Consensus) ValidateHeader(header, parent *block.Header, nowTimestamp uint64) error {
+	if header.ParentID() != parent.ID() {
+		return consensusError(fmt.Sprintf("block parent mismatch: want %v, have %v", parent.ID(), header.ParentID()))
+	}
+	if _, err := header.Signer(); err != nil {
+		return consensusError(fmt.Sprintf("block signer unavailable: %v", err))
+	}
+	if err := c.validateTxsFeatures(header); err != nil {
+		return err
+	}
+	if err := c.validateBlockHeader(header, parent, nowTimestamp); err != nil {
+		return err
+	}
+
+	st, err := c.parentState(parent)
+	if err != nil || st == nil {
+		return err
+	}
+	_, _, err = c.validateProposer(header, parent, st)
+	return err
+}
+
+// parentState returns the state of the parent, nil if not processed or pruned. States of blocks imported
+// without execution, e.g. by snap sync, are marked pruned.
+func (c *Consensus) parentState(parent *block.Header) (*state.State, error) {
+	if c.stater == nil {
+		return nil, nil
+	}
+	summary, err := c.repo.GetBlockSummary(parent.ID())
+	if err != nil {
+		if c.repo.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if c.stater.IsPruned(summary.Root()) {
+		return nil, nil
+	}
+	return c.stater.NewState(summary.Root()), nil
+}
+
+func (c *Consensus) validateTxsFeatures(header *block.Header) error {
+	var features tx.Features
+	if header.Number() >= c.forkConfig.VIP191 {
+		features |= tx.DelegationFeature
+	}
+
+	if header.TxsFeatures() != features {
+		return consensusError(fmt.Sprintf("block txs features invalid: want %v, have %v", features, header.TxsFeatures()))
+	}
+	return nil
+}
+
 func (c *Consensus) NewRuntimeForReplay(header *block.Header, skipValidation bool) (*runtime.Runtime, error) {
 	signer, err := header.Signer()
 	if err != nil {
@@ -475,6 +475,66 @@ func TestValidateBlockHeader(t *testing.T) {
 	}
 }
 
+func TestValidateHeader(t *testing.T) {
+	tc, err := newTestConsensus()
+	if err != nil {
+		t.Fatal(err)
+	}
+	parent := tc.parent.Header()
+
+	assert.Nil(t, tc.con.ValidateHeader(tc.original.Header(), parent, tc.time))
+
+	// not linked
+	assert.True(t, IsCritical(tc.con.ValidateHeader(parent, parent, tc.time)))
+
+	// in the future
+	assert.True(t, IsFutureBlock(tc.con.ValidateHeader(tc.original.Header(), parent, parent.Timestamp())))
+
+	// unsigned
+	blk := tc.builder(tc.original.Header()).Build()
+	assert.True(t, IsCritical(tc.con.ValidateHeader(blk.Header(), parent, tc.time)))
+
+	// bad txs features
+	blk, err = tc.sign(tc.builder(tc.original.Header()).TransactionFeatures(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.True(t, IsCritical(tc.con.ValidateHeader(blk.Header(), parent, tc.time)))
+
+	// bad VRF proof
+	blk, err = tc.sign(tc.builder(tc.original.Header()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig := blk.Header().Signature()
+	sig[len(sig)-1] ^= 1
+	blk = blk.WithSignature(sig)
+	assert.True(t, IsCritical(tc.con.ValidateHeader(blk.Header(), parent, tc.time)))
+
+	// unscheduled signer
+	pk, _ := crypto.GenerateKey()
+	blk, err = tc.signWithKey(tc.builder(tc.original.Header()), pk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = tc.con.ValidateHeader(blk.Header(), parent, tc.time)
+	assert.True(t, IsCritical(err))
+	assert.Contains(t, err.Error(), "block signer invalid")
+
+	// scheduling not checked without the parent state
+	assert.Nil(t, New(tc.con.repo, nil, tc.forkConfig).ValidateHeader(blk.Header(), parent, tc.time))
+
+	// the parent state unexpectedly missing
+	stater := state.NewStater(muxdb.NewMem())
+	err = New(tc.con.repo, stater, tc.forkConfig).ValidateHeader(blk.Header(), parent, tc.time)
+	assert.Error(t, err)
+	assert.False(t, IsCritical(err))
+
+	// the parent state absent but known, e.g. imported by snap sync
+	assert.Nil(t, stater.MarkPrunedBefore(parent.Number()+1))
+	assert.Nil(t, New(tc.con.repo, stater, tc.forkConfig).ValidateHeader(blk.Header(), parent, tc.time))
+}
+
 func TestVerifyBlock(t *testing.T) {
 	tc, err := newTestConsensus()
 	if err != nil {
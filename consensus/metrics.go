@@ -0,0 +1,10 @@
+// Copyright (c) 2025 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package consensus
+
+import "github.com/vechain/thor/v2/metrics"
+
+var metricSpeculativeTxCount = metrics.LazyLoadCounterVec("consensus_speculative_tx_count", []string{"applied"})
@@ -0,0 +1,38 @@
+// Copyright (c) 2025 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package consensus
+
+import (
+	"github.com/vechain/thor/v2/block"
+	"github.com/vechain/thor/v2/co"
+	"github.com/vechain/thor/v2/runtime"
+	"github.com/vechain/thor/v2/tx"
+	"github.com/vechain/thor/v2/xenv"
+)
+
+// speculate executes txs of the block in parallel, each upon its own state of the parent block.
+// The speculations are then validated and applied in order by the block executing runtime.
+func (c *Consensus) speculate(header *block.Header, ctx *xenv.BlockContext, txs tx.Transactions) ([]*runtime.Speculation, error) {
+	parent, err := c.repo.GetBlockSummary(header.ParentID())
+	if err != nil {
+		return nil, err
+	}
+
+	specs := make([]*runtime.Speculation, len(txs))
+	<-co.Parallel(func(queue chan<- func()) {
+		for i, trx := range txs {
+			queue <- func() {
+				rt := runtime.New(
+					c.repo.NewChain(header.ParentID()),
+					c.stater.NewState(parent.Root()),
+					ctx,
+					c.forkConfig)
+				specs[i] = rt.Speculate(trx)
+			}
+		}
+	})
+	return specs, nil
+}
@@ -0,0 +1,175 @@
+// Copyright (c) 2025 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package consensus_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vechain/thor/v2/consensus"
+	"github.com/vechain/thor/v2/genesis"
+	"github.com/vechain/thor/v2/test/datagen"
+	"github.com/vechain/thor/v2/test/testchain"
+	"github.com/vechain/thor/v2/thor"
+	"github.com/vechain/thor/v2/tx"
+)
+
+func TestParallelExecution(t *testing.T) {
+	chain, err := testchain.NewDefault()
+	require.NoError(t, err)
+
+	accs := genesis.DevAccounts()[1:9]
+	recipients := datagen.RandAddresses(4)
+
+	newTx := func(from genesis.DevAccount, to thor.Address, value *big.Int, dependsOn *thor.Bytes32) *tx.Transaction {
+		return tx.MustSign(tx.NewBuilder(tx.TypeDynamicFee).
+			ChainTag(chain.ChainTag()).
+			MaxFeePerGas(big.NewInt(thor.InitialBaseFee*10)).
+			MaxPriorityFeePerGas(big.NewInt(int64(datagen.RandIntN(1000)))).
+			Expiration(100).
+			Gas(50000).
+			Nonce(datagen.RandUint64()).
+			DependsOn(dependsOn).
+			Clause(tx.NewClause(&to).WithValue(value)).
+			Build(), from.PrivateKey)
+	}
+
+	for range 5 {
+		var txs tx.Transactions
+		for i := range 40 {
+			from := accs[datagen.RandIntN(len(accs))]
+			var to thor.Address
+			if i%3 == 0 {
+				// shared recipients conflict with each other
+				to = recipients[datagen.RandIntN(len(recipients))]
+			} else {
+				to = datagen.RandAddress()
+			}
+			value := big.NewInt(int64(datagen.RandIntN(1e6)))
+			if i%7 == 0 {
+				// reverted for insufficient balance
+				value = new(big.Int).Lsh(big.NewInt(1), 128)
+			}
+			var dependsOn *thor.Bytes32
+			if i%11 == 10 {
+				id := txs[i-1].ID()
+				dependsOn = &id
+			}
+			txs = append(txs, newTx(from, to, value, dependsOn))
+		}
+
+		assertParallelExecution(t, chain, txs)
+	}
+}
+
+func TestParallelExecutionStorage(t *testing.T) {
+	chain, err := testchain.NewDefault()
+	require.NoError(t, err)
+
+	accs := genesis.DevAccounts()[1:9]
+	newTx := func(from genesis.DevAccount, clause *tx.Clause) *tx.Transaction {
+		return tx.MustSign(tx.NewBuilder(tx.TypeLegacy).
+			ChainTag(chain.ChainTag()).
+			Expiration(100).
+			Gas(100000).
+			Nonce(datagen.RandUint64()).
+			Clause(clause).
+			Build(), from.PrivateKey)
+	}
+	// returns the code which deploys the given runtime code
+	deployCode := func(code []byte) []byte {
+		n := byte(len(code))
+		// CODECOPY(0, 12, n) RETURN(0, n)
+		return append([]byte{0x60, n, 0x60, 0x0c, 0x60, 0x00, 0x39, 0x60, n, 0x60, 0x00, 0xf3}, code...)
+	}
+
+	// increments the counter in slot 0, read and written by all callers
+	sharedCode := []byte{0x60, 0x00, 0x54, 0x60, 0x01, 0x01, 0x60, 0x00, 0x55, 0x00}
+	// increments the counter in the slot of the caller
+	callerCode := []byte{0x33, 0x54, 0x60, 0x01, 0x01, 0x33, 0x55, 0x00}
+	deployTx := newTx(genesis.DevAccounts()[0], tx.NewClause(nil).WithData(deployCode(sharedCode)))
+	require.NoError(t, chain.MintBlock(deployTx))
+	shared := thor.CreateContractAddress(deployTx.ID(), 0, 0)
+	deployTx = newTx(genesis.DevAccounts()[0], tx.NewClause(nil).WithData(deployCode(callerCode)))
+	require.NoError(t, chain.MintBlock(deployTx))
+	perCaller := thor.CreateContractAddress(deployTx.ID(), 0, 0)
+
+	getStorage := func(addr thor.Address, key thor.Bytes32) *big.Int {
+		st := chain.Stater().NewState(chain.Repo().BestBlockSummary().Root())
+		v, err := st.GetStorage(addr, key)
+		require.NoError(t, err)
+		return new(big.Int).SetBytes(v[:])
+	}
+
+	t.Run("conflicting", func(t *testing.T) {
+		var txs tx.Transactions
+		for _, acc := range accs {
+			txs = append(txs, newTx(acc, tx.NewClause(&shared)))
+		}
+		assertParallelExecution(t, chain, txs)
+		// each call sees the increment of the previous one
+		assert.Equal(t, big.NewInt(int64(len(accs))), getStorage(shared, thor.Bytes32{}))
+	})
+
+	t.Run("non-conflicting", func(t *testing.T) {
+		var txs tx.Transactions
+		for _, acc := range accs {
+			txs = append(txs, newTx(acc, tx.NewClause(&perCaller)))
+		}
+		assertParallelExecution(t, chain, txs)
+		for _, acc := range accs {
+			assert.Equal(t, big.NewInt(1), getStorage(perCaller, thor.BytesToBytes32(acc.Address.Bytes())))
+		}
+	})
+
+	t.Run("mixed", func(t *testing.T) {
+		var txs tx.Transactions
+		for i, acc := range accs {
+			if i%2 == 0 {
+				txs = append(txs, newTx(acc, tx.NewClause(&shared)))
+			} else {
+				txs = append(txs, newTx(acc, tx.NewClause(&perCaller)))
+			}
+		}
+		assertParallelExecution(t, chain, txs)
+		assert.Equal(t, big.NewInt(int64(len(accs)+len(accs)/2)), getStorage(shared, thor.Bytes32{}))
+		assert.Equal(t, big.NewInt(2), getStorage(perCaller, thor.BytesToBytes32(accs[1].Address.Bytes())))
+	})
+}
+
+// assertParallelExecution mints a block of the txs, and asserts that processing it in parallel
+// results in the same state and receipts as processing it sequentially.
+func assertParallelExecution(t *testing.T, chain *testchain.Chain, txs tx.Transactions) {
+	parent := chain.Repo().BestBlockSummary()
+	require.NoError(t, chain.MintBlock(txs...))
+
+	blk, err := chain.BestBlock()
+	require.NoError(t, err)
+	require.Len(t, blk.Transactions(), len(txs))
+
+	sequential := consensus.New(chain.Repo(), chain.Stater(), chain.GetForkConfig())
+	seqStage, seqReceipts, err := sequential.Process(parent, blk, blk.Header().Timestamp(), 0)
+	require.NoError(t, err)
+
+	parallel := consensus.New(chain.Repo(), chain.Stater(), chain.GetForkConfig())
+	parallel.EnableParallelExecution()
+	stage, receipts, err := parallel.Process(parent, blk, blk.Header().Timestamp(), 0)
+	require.NoError(t, err)
+
+	assert.Equal(t, seqStage.Hash(), stage.Hash())
+	assert.Equal(t, blk.Header().StateRoot(), stage.Hash())
+	assert.Equal(t, seqReceipts.RootHash(), receipts.RootHash())
+	require.Len(t, receipts, len(seqReceipts))
+	for i := range receipts {
+		assert.Equal(t, seqReceipts[i].GasUsed, receipts[i].GasUsed)
+		assert.Equal(t, seqReceipts[i].Reverted, receipts[i].Reverted)
+		assert.Equal(t, seqReceipts[i].Paid, receipts[i].Paid)
+		assert.Equal(t, seqReceipts[i].Reward, receipts[i].Reward)
+	}
+}
@@ -8,6 +8,7 @@ package consensus
 import (
 	"bytes"
 	"fmt"
+	"strconv"
 
 	"github.com/ethereum/go-ethereum/common/hexutil"
 
@@ -40,24 +41,7 @@ func (c *Consensus) validate(
 		return nil, nil, err
 	}
 
-	checkpoint := state.NewCheckpoint()
-	staker := builtin.Staker.Native(state)
-	dPosStatus, err := staker.SyncPOS(c.forkConfig, header.Number())
-	if err != nil {
-		log.Error("staker sync pos failed - reverting state", "err", err, "height", header.Number(), "parent", parent, "checkpoint", checkpoint)
-		dPosStatus.Updates = false // reset since no changes actually occurred
-		state.RevertTo(checkpoint)
-	}
-	if dPosStatus.Updates {
-		c.validatorsCache.Remove(parent.ID())
-	}
-
-	var cacher cacher
-	if dPosStatus.Active {
-		cacher, err = c.validateStakingProposer(header, parent, staker)
-	} else {
-		cacher, err = c.validateAuthorityProposer(header, parent, state)
-	}
+	cacher, posActive, err := c.validateProposer(header, parent, state)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -66,7 +50,7 @@ func (c *Consensus) validate(
 		return nil, nil, err
 	}
 
-	stage, receipts, err := c.verifyBlock(block, state, blockConflicts, dPosStatus.Active)
+	stage, receipts, err := c.verifyBlock(block, state, blockConflicts, posActive)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -82,6 +66,29 @@ func (c *Consensus) validate(
 	return stage, receipts, nil
 }
 
+// validateProposer validates the signer and the timestamp against the schedule built upon the parent state.
+// It returns the cacher to be called after the block verified, and whether PoS is active.
+func (c *Consensus) validateProposer(header *block.Header, parent *block.Header, state *state.State) (cacher, bool, error) {
+	checkpoint := state.NewCheckpoint()
+	staker := builtin.Staker.Native(state)
+	dPosStatus, err := staker.SyncPOS(c.forkConfig, header.Number())
+	if err != nil {
+		log.Error("staker sync pos failed - reverting state", "err", err, "height", header.Number(), "parent", parent, "checkpoint", checkpoint)
+		dPosStatus.Updates = false // reset since no changes actually occurred
+		state.RevertTo(checkpoint)
+	}
+	if dPosStatus.Updates {
+		c.validatorsCache.Remove(parent.ID())
+	}
+
+	if dPosStatus.Active {
+		cacher, err := c.validateStakingProposer(header, parent, staker)
+		return cacher, true, err
+	}
+	cacher, err := c.validateAuthorityProposer(header, parent, state)
+	return cacher, false, err
+}
+
 func (c *Consensus) validateBlockHeader(header *block.Header, parent *block.Header, nowTimestamp uint64) error {
 	if header.Timestamp() <= parent.Timestamp() {
 		return consensusError(fmt.Sprintf("block timestamp behind parents: parent %v, current %v", parent.Timestamp(), header.Timestamp()))
@@ -257,7 +264,27 @@ func (c *Consensus) verifyBlock(blk *block.Block, state *state.State, blockConfl
 		return chain.HasTransaction(txid, txBlockRef)
 	}
 
-	for _, tx := range txs {
+	var specs []*runtime.Speculation
+	if c.parallelExec && len(txs) > 1 {
+		var err error
+		if specs, err = c.speculate(header, rt.Context(), txs); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	execute := func(i int) (*tx.Receipt, error) {
+		if specs == nil {
+			return rt.ExecuteTransaction(txs[i])
+		}
+		receipt, applied, err := rt.ExecuteSpeculation(specs[i])
+		if err != nil {
+			return nil, err
+		}
+		metricSpeculativeTxCount().AddWithLabel(1, map[string]string{"applied": strconv.FormatBool(applied)})
+		return receipt, nil
+	}
+
+	for i, tx := range txs {
 		// check if tx existed
 		if found, err := hasTx(tx.ID(), tx.BlockRef().Number()); err != nil {
 			return nil, nil, err
@@ -280,7 +307,7 @@ func (c *Consensus) verifyBlock(blk *block.Block, state *state.State, blockConfl
 			}
 		}
 
-		receipt, err := rt.ExecuteTransaction(tx)
+		receipt, err := execute(i)
 		if err != nil {
 			return nil, nil, err
 		}
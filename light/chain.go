@@ -0,0 +1,450 @@
+// Copyright (c) 2025 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+// Package light implements the header chain of a light node.
+//
+// Headers are validated without the parent state, and the signer of each header is checked against
+// the leader set, which is computed from the storage of builtin contracts fetched with merkle proofs.
+// The bft finality is tracked by counting COM votes in headers, as full nodes do.
+package light
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+
+	lru "github.com/hashicorp/golang-lru"
+	"github.com/pkg/errors"
+
+	"github.com/vechain/thor/v2/block"
+	"github.com/vechain/thor/v2/builtin"
+	"github.com/vechain/thor/v2/chain"
+	"github.com/vechain/thor/v2/consensus"
+	"github.com/vechain/thor/v2/kv"
+	"github.com/vechain/thor/v2/log"
+	"github.com/vechain/thor/v2/state"
+	"github.com/vechain/thor/v2/thor"
+)
+
+var (
+	logger       = log.WithContext("pkg", "light")
+	finalizedKey = []byte("finalized")
+)
+
+// StateFetcher fetches accounts with their storage from peers, verified against the state root of the header.
+type StateFetcher interface {
+	FetchState(ctx context.Context, header *block.Header, addrs []thor.Address) (*state.State, error)
+}
+
+// Chain maintains the header chain of a light node.
+type Chain struct {
+	repo       *chain.Repository
+	data       kv.Store
+	cons       *consensus.Consensus
+	forkConfig *thor.ForkConfig
+	fetcher    StateFetcher
+	proposers  *ProposerValidator
+	trusted    thor.Bytes32
+	origin     uint32 // the checkpoint since which votes are counted
+	finalized  atomic.Value
+	lock       sync.Mutex
+	caches     struct {
+		staker    *lru.Cache // staker states of blocks, to get weights of their child blocks' signers
+		state     *lru.Cache
+		justifier *lru.Cache
+		quality   *lru.Cache
+	}
+}
+
+// New creates a light chain.
+// The trusted checkpoint is optional, which should be the first block of a round. Headers up to it
+// are validated without checking signers, and votes are counted since it.
+func New(repo *chain.Repository, data kv.Store, forkConfig *thor.ForkConfig, fetcher StateFetcher, trusted thor.Bytes32) (*Chain, error) {
+	if !trusted.IsZero() && !isCheckPoint(block.Number(trusted)) {
+		return nil, errors.New("trusted checkpoint is not the first block of a round")
+	}
+
+	c := &Chain{
+		repo:       repo,
+		data:       data,
+		cons:       consensus.New(repo, nil, forkConfig),
+		forkConfig: forkConfig,
+		fetcher:    fetcher,
+		proposers:  NewProposerValidator(repo, fetcher),
+		trusted:    trusted,
+		origin:     max(getCheckPoint(forkConfig.FINALITY), block.Number(trusted)),
+	}
+	c.caches.staker, _ = lru.New(16)
+	c.caches.state, _ = lru.New(256)
+	c.caches.justifier, _ = lru.New(16)
+	c.caches.quality, _ = lru.New(16)
+
+	if val, err := data.Get(finalizedKey); err != nil {
+		if !data.IsNotFound(err) {
+			return nil, err
+		}
+		if trusted.IsZero() {
+			c.finalized.Store(repo.GenesisBlock().Header().ID())
+		} else {
+			c.finalized.Store(trusted)
+		}
+	} else {
+		c.finalized.Store(thor.BytesToBytes32(val))
+	}
+	return c, nil
+}
+
+// Finalized returns the finalized checkpoint.
+func (c *Chain) Finalized() thor.Bytes32 {
+	return c.finalized.Load().(thor.Bytes32)
+}
+
+// Justified returns the justified checkpoint.
+func (c *Chain) Justified() (thor.Bytes32, error) {
+	head := c.repo.BestBlockSummary().Header
+	finalized := c.Finalized()
+
+	// if head is in the first round and not concluded yet
+	if head.Number() < c.origin+thor.EpochLength()-1 {
+		return finalized, nil
+	}
+
+	// find the recent concluded checkpoint
+	concluded := getCheckPoint(head.Number())
+	if head.Number() < getStorePoint(head.Number()) {
+		concluded -= thor.EpochLength()
+	}
+
+	storeID, err := c.repo.NewChain(head.ID()).GetBlockID(getStorePoint(concluded))
+	if err != nil {
+		return thor.Bytes32{}, err
+	}
+	quality, err := c.getQuality(storeID)
+	if err != nil {
+		return thor.Bytes32{}, err
+	}
+	if quality == 0 {
+		return finalized, nil
+	}
+	return c.findCheckpointByQuality(quality, finalized, storeID)
+}
+
+// ValidateHeader validates the header against its parent without the parent state.
+// The proposer is checked later when the header handled, since the leader set depends on the chain.
+func (c *Chain) ValidateHeader(header, parent *block.Header, nowTimestamp uint64) error {
+	if err := c.cons.ValidateHeader(header, parent, nowTimestamp); err != nil {
+		return err
+	}
+	if !c.trusted.IsZero() && header.Number() == block.Number(c.trusted) && header.ID() != c.trusted {
+		return fmt.Errorf("block mismatches the trusted checkpoint: want %v, have %v", c.trusted, header.ID())
+	}
+	return nil
+}
+
+// HandleHeaders checks proposers of the validated headers, and adds them to the chain.
+// The best header is selected by the bft quality and then the total score, as full nodes do.
+func (c *Chain) HandleHeaders(ctx context.Context, headers []*block.Header) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	for _, header := range headers {
+		if _, err := c.repo.GetBlockSummary(header.ID()); err == nil {
+			continue
+		} else if !c.repo.IsNotFound(err) {
+			return err
+		}
+		if err := c.handleHeader(ctx, header); err != nil {
+			return errors.WithMessage(err, fmt.Sprintf("block %v", header.ID()))
+		}
+	}
+	return nil
+}
+
+func (c *Chain) handleHeader(ctx context.Context, header *block.Header) error {
+	if ok, err := c.accepts(header); err != nil {
+		return err
+	} else if !ok {
+		return errors.New("conflicts with the finalized checkpoint")
+	}
+
+	if header.Number() > block.Number(c.trusted) {
+		if err := c.proposers.ValidateProposer(ctx, header); err != nil {
+			return err
+		}
+	}
+
+	st, err := c.computeState(ctx, header)
+	if err != nil {
+		return errors.WithMessage(err, "compute bft state")
+	}
+	best := c.repo.BestBlockSummary().Header
+	bestSt, err := c.computeState(ctx, best)
+	if err != nil {
+		return errors.WithMessage(err, "compute bft state of best")
+	}
+	isTrunk := st.Quality > bestSt.Quality || (st.Quality == bestSt.Quality && header.BetterThan(best))
+
+	conflicts, err := c.repo.ScanConflicts(header.Number())
+	if err != nil {
+		return err
+	}
+	if err := c.repo.AddBlock(block.Compose(header, nil), nil, conflicts, isTrunk); err != nil {
+		return err
+	}
+
+	// save quality and finalized at the end of each round
+	if getStorePoint(header.Number()) == header.Number() && header.Number() >= c.origin {
+		if err := saveQuality(c.data, header.ID(), st.Quality); err != nil {
+			return err
+		}
+		c.caches.quality.Add(header.ID(), st.Quality)
+
+		if st.Committed && st.Quality > 1 {
+			id, err := c.findCheckpointByQuality(st.Quality-1, c.Finalized(), header.ID())
+			if err != nil {
+				return err
+			}
+			if err := c.data.Put(finalizedKey, id[:]); err != nil {
+				return err
+			}
+			c.finalized.Store(id)
+			logger.Debug("finalized", "id", id)
+		}
+	}
+	return nil
+}
+
+// accepts checks if the header is on the same branch of the finalized checkpoint.
+func (c *Chain) accepts(header *block.Header) (bool, error) {
+	finalized := c.Finalized()
+	num := block.Number(finalized)
+	if num == 0 {
+		return true, nil
+	}
+
+	if header.Number() > num {
+		return c.repo.NewChain(header.ParentID()).HasBlock(finalized)
+	}
+
+	if _, err := c.repo.GetBlockSummary(finalized); err != nil {
+		if !c.repo.IsNotFound(err) {
+			return false, err
+		}
+		// the trusted checkpoint not reached yet
+		return header.Number() < num || header.ID() == finalized, nil
+	}
+	id, err := c.repo.NewChain(finalized).GetBlockID(header.Number())
+	if err != nil {
+		return false, err
+	}
+	return id == header.ID(), nil
+}
+
+// computeState computes the bft state regarding the given header to the closest checkpoint, see bft.Engine.
+func (c *Chain) computeState(ctx context.Context, header *block.Header) (*bftState, error) {
+	if cached, ok := c.caches.state.Get(header.ID()); ok {
+		return cached.(*bftState), nil
+	}
+	if header.Number() == 0 || header.Number() < c.forkConfig.FINALITY || header.Number() < c.origin {
+		return &bftState{}, nil
+	}
+
+	var (
+		js  *justifier
+		end uint32
+	)
+	if cached, ok := c.caches.justifier.Get(header.ParentID()); ok && !isCheckPoint(header.Number()) {
+		js = cached.(*justifier).copy()
+		end = header.Number()
+	} else {
+		var err error
+		if js, err = c.newJustifier(ctx, header.ParentID()); err != nil {
+			return nil, errors.WithMessage(err, "create vote set")
+		}
+		end = js.checkpoint
+	}
+
+	h := header
+	for h.Number() >= c.forkConfig.FINALITY {
+		signer, err := h.Signer()
+		if err != nil {
+			return nil, err
+		}
+		var weight uint64
+		if h.Number() > c.forkConfig.HAYABUSA+thor.HayabusaTP() {
+			if weight, err = c.validatorWeight(ctx, h.ParentID(), signer); err != nil {
+				return nil, err
+			}
+		}
+		js.AddBlock(signer, h.COM(), weight)
+
+		if h.Number() <= end {
+			break
+		}
+		parent, err := c.repo.GetBlockSummary(h.ParentID())
+		if err != nil {
+			return nil, err
+		}
+		h = parent.Header
+	}
+
+	st := js.Summarize()
+	c.caches.state.Add(header.ID(), st)
+	c.caches.justifier.Add(header.ID(), js)
+	return st, nil
+}
+
+// validatorWeight returns the weight of the validator taken from the state of the parent block, which is
+// zero if PoS not active, see bft.Engine.
+func (c *Chain) validatorWeight(ctx context.Context, parentID thor.Bytes32, signer thor.Address) (uint64, error) {
+	var st *state.State
+	if cached, ok := c.caches.staker.Get(parentID); ok {
+		st = cached.(*state.State)
+	} else {
+		parent, err := c.repo.GetBlockSummary(parentID)
+		if err != nil {
+			return 0, err
+		}
+		if st, err = c.fetcher.FetchState(ctx, parent.Header, []thor.Address{builtin.Staker.Address}); err != nil {
+			return 0, errors.WithMessage(err, "fetch state")
+		}
+		c.caches.staker.Add(parentID, st)
+	}
+
+	staker := builtin.Staker.Native(st)
+	if posActive, err := staker.IsPoSActive(); err != nil {
+		return 0, errors.WithMessage(err, "pos status")
+	} else if !posActive {
+		return 0, nil
+	}
+	validator, err := staker.GetValidation(signer)
+	if err != nil {
+		return 0, err
+	}
+	if validator == nil {
+		return 0, errors.New("validator not found")
+	}
+	return validator.Weight, nil
+}
+
+func (c *Chain) newJustifier(ctx context.Context, parentID thor.Bytes32) (*justifier, error) {
+	checkpoint := getCheckPoint(block.Number(parentID) + 1)
+	sum, err := c.repo.NewChain(parentID).GetBlockSummary(lastOfParentRound(checkpoint))
+	if err != nil {
+		return nil, err
+	}
+
+	var parentQuality uint32
+	if checkpoint > c.origin {
+		if parentQuality, err = c.getQuality(sum.Header.ID()); err != nil {
+			return nil, err
+		}
+	}
+
+	ls, err := c.proposers.leaderSet(ctx, sum.Header)
+	if err != nil {
+		return nil, err
+	}
+	return newJustifier(parentQuality, checkpoint, ls), nil
+}
+
+// findCheckpointByQuality finds the first checkpoint reaches the given quality, see bft.Engine.
+func (c *Chain) findCheckpointByQuality(target uint32, finalized, headID thor.Bytes32) (blockID thor.Bytes32, err error) {
+	searchStart := block.Number(finalized)
+	if searchStart == 0 {
+		searchStart = c.origin
+	}
+
+	ch := c.repo.NewChain(headID)
+	get := func(i int) (uint32, error) {
+		id, err := ch.GetBlockID(getStorePoint(searchStart + uint32(i)*thor.EpochLength()))
+		if err != nil {
+			return 0, err
+		}
+		return c.getQuality(id)
+	}
+
+	var searchErr error
+	n := int((block.Number(headID)-searchStart)/thor.EpochLength()) + 1
+	num := sort.Search(n, func(i int) bool {
+		if searchErr != nil {
+			return true
+		}
+		quality, err := get(i)
+		if err != nil {
+			searchErr = err
+			return true
+		}
+		return quality >= target
+	})
+	if searchErr != nil {
+		return thor.Bytes32{}, searchErr
+	}
+	if num == n {
+		return thor.Bytes32{}, errors.New("failed to find the block by quality")
+	}
+
+	quality, err := get(num)
+	if err != nil {
+		return thor.Bytes32{}, err
+	}
+	if quality != target {
+		return thor.Bytes32{}, errors.New("failed to find the block by quality")
+	}
+	return ch.GetBlockID(searchStart + uint32(num)*thor.EpochLength())
+}
+
+func (c *Chain) getQuality(id thor.Bytes32) (uint32, error) {
+	if cached, ok := c.caches.quality.Get(id); ok {
+		return cached.(uint32), nil
+	}
+	quality, err := loadQuality(c.data, id)
+	if err != nil {
+		// no quality saved yet
+		if c.data.IsNotFound(err) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	c.caches.quality.Add(id, quality)
+	return quality, nil
+}
+
+func saveQuality(putter kv.Putter, id thor.Bytes32, quality uint32) error {
+	var b [4]byte
+	binary.BigEndian.PutUint32(b[:], quality)
+	return putter.Put(id.Bytes(), b[:])
+}
+
+func loadQuality(getter kv.Getter, id thor.Bytes32) (uint32, error) {
+	b, err := getter.Get(id.Bytes())
+	if err != nil {
+		return 0, err
+	}
+	return binary.BigEndian.Uint32(b), nil
+}
+
+func getCheckPoint(blockNum uint32) uint32 {
+	return blockNum / thor.EpochLength() * thor.EpochLength()
+}
+
+func isCheckPoint(blockNum uint32) bool {
+	return getCheckPoint(blockNum) == blockNum
+}
+
+func getStorePoint(blockNum uint32) uint32 {
+	return getCheckPoint(blockNum) + thor.EpochLength() - 1
+}
+
+// lastOfParentRound returns the number of the block where the previous round of the given block concluded.
+func lastOfParentRound(blockNum uint32) uint32 {
+	if checkpoint := getCheckPoint(blockNum); checkpoint > 0 {
+		return checkpoint - 1
+	}
+	return 0
+}
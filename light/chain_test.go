@@ -0,0 +1,205 @@
+// Copyright (c) 2025 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package light
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/binary"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vechain/thor/v2/bft"
+	"github.com/vechain/thor/v2/block"
+	"github.com/vechain/thor/v2/chain"
+	"github.com/vechain/thor/v2/genesis"
+	"github.com/vechain/thor/v2/muxdb"
+	"github.com/vechain/thor/v2/packer"
+	"github.com/vechain/thor/v2/state"
+	"github.com/vechain/thor/v2/thor"
+)
+
+var devAccounts = genesis.DevAccounts()
+
+// fullNode builds blocks with all dev accounts voting, and tracks finality with the bft engine.
+type fullNode struct {
+	repo   *chain.Repository
+	stater *state.Stater
+	engine *bft.Engine
+	fc     *thor.ForkConfig
+}
+
+func newFullNode(t *testing.T) *fullNode {
+	fc := thor.NoFork
+	fc.FINALITY = 0
+
+	auth := make([]genesis.Authority, 0, len(devAccounts))
+	accounts := make([]genesis.Account, 0, len(devAccounts))
+	bal, _ := new(big.Int).SetString("1000000000000000000000000000", 10)
+	for _, acc := range devAccounts {
+		auth = append(auth, genesis.Authority{
+			MasterAddress:   acc.Address,
+			EndorsorAddress: acc.Address,
+			Identity:        thor.BytesToBytes32([]byte("master")),
+		})
+		accounts = append(accounts, genesis.Account{
+			Address: acc.Address,
+			Balance: (*genesis.HexOrDecimal256)(bal),
+			Energy:  (*genesis.HexOrDecimal256)(bal),
+		})
+	}
+	mbp := uint64(len(devAccounts))
+	builder, err := genesis.NewCustomNet(&genesis.CustomGenesis{
+		LaunchTime: 1526400000,
+		GasLimit:   thor.InitialGasLimit,
+		ForkConfig: &fc,
+		Authority:  auth,
+		Accounts:   accounts,
+		Params:     genesis.Params{MaxBlockProposers: &mbp},
+	})
+	require.NoError(t, err)
+
+	db := muxdb.NewMem()
+	stater := state.NewStater(db)
+	gene, _, _, err := builder.Build(stater)
+	require.NoError(t, err)
+	repo, err := chain.NewRepository(db, gene)
+	require.NoError(t, err)
+	engine, err := bft.NewEngine(repo, db, &fc, thor.Address{})
+	require.NoError(t, err)
+
+	return &fullNode{repo, stater, engine, &fc}
+}
+
+func (n *fullNode) mint(t *testing.T, count int) {
+	for range count {
+		parent := n.repo.BestBlockSummary()
+		master := devAccounts[int(parent.Header.Number()+1)%len(devAccounts)]
+		n.pack(t, parent, master.PrivateKey, true)
+	}
+}
+
+func (n *fullNode) pack(t *testing.T, parent *chain.BlockSummary, privateKey *ecdsa.PrivateKey, asBest bool) *block.Header {
+	flow, _, err := packer.New(n.repo, n.stater, thor.Address(crypto.PubkeyToAddress(privateKey.PublicKey)), &thor.Address{}, n.fc, 0).
+		Mock(parent, parent.Header.Timestamp()+thor.BlockInterval(), parent.Header.GasLimit())
+	require.NoError(t, err)
+	blk, stage, _, err := flow.Pack(privateKey, 0, true)
+	require.NoError(t, err)
+	_, err = stage.Commit()
+	require.NoError(t, err)
+	if asBest {
+		require.NoError(t, n.repo.AddBlock(blk, nil, 0, true))
+		require.NoError(t, n.engine.CommitBlock(blk.Header(), false))
+	}
+	return blk.Header()
+}
+
+func (n *fullNode) headers(t *testing.T, from uint32) []*block.Header {
+	var headers []*block.Header
+	best := n.repo.NewBestChain()
+	for i := from; i <= block.Number(best.HeadID()); i++ {
+		h, err := best.GetBlockHeader(i)
+		require.NoError(t, err)
+		headers = append(headers, h)
+	}
+	return headers
+}
+
+// FetchState implements StateFetcher.
+func (n *fullNode) FetchState(_ context.Context, header *block.Header, _ []thor.Address) (*state.State, error) {
+	summary, err := n.repo.GetBlockSummary(header.ID())
+	if err != nil {
+		return nil, err
+	}
+	return n.stater.NewState(summary.Root()), nil
+}
+
+func newLightChain(t *testing.T, full *fullNode, trusted thor.Bytes32) *Chain {
+	db := muxdb.NewMem()
+	repo, err := chain.NewRepository(db, full.repo.GenesisBlock())
+	require.NoError(t, err)
+	c, err := New(repo, db.NewStore("light"), full.fc, full, trusted)
+	require.NoError(t, err)
+	return c
+}
+
+func handle(c *Chain, headers []*block.Header) error {
+	parent := c.repo.BestBlockSummary().Header
+	for _, h := range headers {
+		if err := c.ValidateHeader(h, parent, h.Timestamp()); err != nil {
+			return err
+		}
+		parent = h
+	}
+	return c.HandleHeaders(context.Background(), headers)
+}
+
+func TestChainFinality(t *testing.T) {
+	full := newFullNode(t)
+	full.mint(t, int(thor.EpochLength())*4+10)
+	require.NotEqual(t, full.repo.GenesisBlock().Header().ID(), full.engine.Finalized())
+
+	c := newLightChain(t, full, thor.Bytes32{})
+	headers := full.headers(t, 1)
+	// in segments
+	require.NoError(t, handle(c, headers[:300]))
+	require.NoError(t, handle(c, headers[300:]))
+
+	assert.Equal(t, full.repo.BestBlockSummary().Header.ID(), c.repo.BestBlockSummary().Header.ID())
+	assert.Equal(t, full.engine.Finalized(), c.Finalized())
+	wantJustified, err := full.engine.Justified()
+	require.NoError(t, err)
+	gotJustified, err := c.Justified()
+	require.NoError(t, err)
+	assert.Equal(t, wantJustified, gotJustified)
+
+	// finalized restored
+	restored, err := New(c.repo, c.data, full.fc, full, thor.Bytes32{})
+	require.NoError(t, err)
+	assert.Equal(t, c.Finalized(), restored.Finalized())
+}
+
+func TestChainRejectsUnknownSigner(t *testing.T) {
+	full := newFullNode(t)
+	full.mint(t, 10)
+
+	c := newLightChain(t, full, thor.Bytes32{})
+	require.NoError(t, handle(c, full.headers(t, 1)))
+
+	outsider, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	header := full.pack(t, full.repo.BestBlockSummary(), outsider, false)
+
+	err = handle(c, []*block.Header{header})
+	assert.ErrorContains(t, err, "not in the leader set")
+}
+
+func TestChainTrustedCheckpoint(t *testing.T) {
+	full := newFullNode(t)
+	full.mint(t, int(thor.EpochLength())+10)
+	headers := full.headers(t, 1)
+	trusted := headers[thor.EpochLength()-1].ID()
+
+	_, err := New(full.repo, muxdb.NewMem().NewStore("light"), full.fc, full, headers[0].ID())
+	assert.Error(t, err, "not a checkpoint")
+
+	c := newLightChain(t, full, trusted)
+	assert.Equal(t, trusted, c.Finalized())
+	require.NoError(t, handle(c, headers))
+	assert.Equal(t, full.repo.BestBlockSummary().Header.ID(), c.repo.BestBlockSummary().Header.ID())
+
+	// the chain conflicts with the trusted checkpoint
+	var fake thor.Bytes32
+	binary.BigEndian.PutUint32(fake[:], thor.EpochLength())
+	fake[31] = 1
+	c = newLightChain(t, full, fake)
+	err = handle(c, headers)
+	assert.ErrorContains(t, err, "trusted checkpoint")
+}
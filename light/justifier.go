@@ -0,0 +1,93 @@
+// Copyright (c) 2025 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package light
+
+import (
+	"github.com/vechain/thor/v2/thor"
+)
+
+// bftState is the summary of a bft round for a given head, the same as computed by full nodes.
+type bftState struct {
+	Quality   uint32 // accumulated justified round count
+	Justified bool
+	Committed bool
+}
+
+type vote struct {
+	isCOM  bool
+	weight uint64
+}
+
+// justifier tracks votes of blocks in one round, see bft.justifier.
+type justifier struct {
+	parentQuality uint32
+	checkpoint    uint32
+	leaders       *leaderSet
+
+	votes     map[thor.Address]vote
+	comVotes  uint64
+	comWeight uint64
+	weight    uint64
+}
+
+func newJustifier(parentQuality, checkpoint uint32, ls *leaderSet) *justifier {
+	return &justifier{
+		parentQuality: parentQuality,
+		checkpoint:    checkpoint,
+		leaders:       ls,
+		votes:         make(map[thor.Address]vote),
+	}
+}
+
+func (js *justifier) copy() *justifier {
+	cpy := *js
+	cpy.votes = make(map[thor.Address]vote, len(js.votes))
+	for k, v := range js.votes {
+		cpy.votes[k] = v
+	}
+	return &cpy
+}
+
+// AddBlock adds the vote of a block.
+func (js *justifier) AddBlock(signer thor.Address, isCOM bool, weight uint64) {
+	if prev, ok := js.votes[signer]; !ok {
+		js.votes[signer] = vote{isCOM: isCOM, weight: weight}
+		js.weight += weight
+		if isCOM {
+			js.comVotes++
+			js.comWeight += weight
+		}
+	} else if prev.isCOM != isCOM {
+		// if one votes both COM and non-COM in one round, count as non-COM
+		js.votes[signer] = vote{isCOM: false, weight: prev.weight}
+		if prev.isCOM {
+			js.comVotes--
+			js.comWeight -= prev.weight
+		}
+	}
+}
+
+// Summarize summarizes the state of the round.
+func (js *justifier) Summarize() *bftState {
+	var justified, committed bool
+	if threshold := js.leaders.threshold; js.leaders.posActive {
+		justified = js.weight > threshold
+		committed = js.comWeight > threshold
+	} else {
+		justified = uint64(len(js.votes)) > threshold
+		committed = js.comVotes > threshold
+	}
+
+	quality := js.parentQuality
+	if justified {
+		quality++
+	}
+	return &bftState{
+		Quality:   quality,
+		Justified: justified,
+		Committed: committed,
+	}
+}
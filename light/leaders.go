@@ -0,0 +1,156 @@
+// Copyright (c) 2025 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package light
+
+import (
+	"context"
+	"fmt"
+
+	lru "github.com/hashicorp/golang-lru"
+	"github.com/pkg/errors"
+
+	"github.com/vechain/thor/v2/block"
+	"github.com/vechain/thor/v2/builtin"
+	"github.com/vechain/thor/v2/chain"
+	"github.com/vechain/thor/v2/state"
+	"github.com/vechain/thor/v2/thor"
+)
+
+// accounts of builtin contracts fetched to compute the leader set.
+var leaderAccounts = []thor.Address{
+	builtin.Authority.Address,
+	builtin.Params.Address,
+	builtin.Staker.Address,
+}
+
+// leaderSet is the set of block proposers and the bft threshold derived from a state.
+type leaderSet struct {
+	posActive bool
+	proposers map[thor.Address]bool
+	threshold uint64 // count of votes before PoS activated, otherwise the weight
+}
+
+// newLeaderSet computes the leader set from the state, which contains storage of leaderAccounts.
+// Authority candidates are always included, to cover the transition to PoS.
+func newLeaderSet(st *state.State) (*leaderSet, error) {
+	candidates, err := builtin.Authority.Native(st).AllCandidates()
+	if err != nil {
+		return nil, errors.WithMessage(err, "authority candidates")
+	}
+	ls := &leaderSet{
+		proposers: make(map[thor.Address]bool, len(candidates)),
+	}
+	for _, c := range candidates {
+		ls.proposers[c.NodeMaster] = true
+	}
+
+	staker := builtin.Staker.Native(st)
+	if ls.posActive, err = staker.IsPoSActive(); err != nil {
+		return nil, errors.WithMessage(err, "pos status")
+	}
+	if ls.posActive {
+		leaders, err := staker.LeaderGroup()
+		if err != nil {
+			return nil, errors.WithMessage(err, "leader group")
+		}
+		for _, leader := range leaders {
+			ls.proposers[leader.Address] = true
+		}
+		_, totalWeight, err := staker.LockedStake()
+		if err != nil {
+			return nil, errors.WithMessage(err, "locked stake")
+		}
+		if totalWeight == 0 {
+			return nil, errors.New("total weight is zero")
+		}
+		ls.threshold = totalWeight * 2 / 3
+	} else {
+		mbp, err := thor.GetMaxBlockProposers(builtin.Params.Native(st), true)
+		if err != nil {
+			return nil, errors.WithMessage(err, "max block proposers")
+		}
+		ls.threshold = mbp * 2 / 3
+	}
+	return ls, nil
+}
+
+// Has returns whether the signer is a proposer in the set.
+func (ls *leaderSet) Has(signer thor.Address) bool {
+	return ls.proposers[signer]
+}
+
+// ProposerValidator checks signers of headers against leader sets, which are computed from states
+// fetched with merkle proofs. It works without local states, e.g. for light nodes and snap sync.
+type ProposerValidator struct {
+	repo    *chain.Repository
+	fetcher StateFetcher
+	cache   *lru.Cache
+}
+
+// NewProposerValidator creates a proposer validator.
+func NewProposerValidator(repo *chain.Repository, fetcher StateFetcher) *ProposerValidator {
+	cache, _ := lru.New(16)
+	return &ProposerValidator{
+		repo:    repo,
+		fetcher: fetcher,
+		cache:   cache,
+	}
+}
+
+// ValidateProposer checks the signer against the leader set of the round, which is taken from the state
+// where the previous round concluded. The leader set of the parent state is also checked, since it
+// may change during the round. The parent header is required to be in the repository.
+func (v *ProposerValidator) ValidateProposer(ctx context.Context, header *block.Header) error {
+	signer, err := header.Signer()
+	if err != nil {
+		return err
+	}
+	ls, err := v.roundLeaderSet(ctx, header.ParentID())
+	if err != nil {
+		return err
+	}
+	if ls.Has(signer) {
+		return nil
+	}
+
+	parent, err := v.repo.GetBlockSummary(header.ParentID())
+	if err != nil {
+		return err
+	}
+	if ls, err = v.leaderSet(ctx, parent.Header); err != nil {
+		return err
+	}
+	if !ls.Has(signer) {
+		return fmt.Errorf("block signer %v not in the leader set", signer)
+	}
+	return nil
+}
+
+// roundLeaderSet returns the leader set of the round the child block of parentID belongs to.
+func (v *ProposerValidator) roundLeaderSet(ctx context.Context, parentID thor.Bytes32) (*leaderSet, error) {
+	header, err := v.repo.NewChain(parentID).GetBlockHeader(lastOfParentRound(block.Number(parentID) + 1))
+	if err != nil {
+		return nil, err
+	}
+	return v.leaderSet(ctx, header)
+}
+
+// leaderSet returns the leader set computed from the state of the given block.
+func (v *ProposerValidator) leaderSet(ctx context.Context, header *block.Header) (*leaderSet, error) {
+	if cached, ok := v.cache.Get(header.ID()); ok {
+		return cached.(*leaderSet), nil
+	}
+	st, err := v.fetcher.FetchState(ctx, header, leaderAccounts)
+	if err != nil {
+		return nil, errors.WithMessage(err, "fetch state")
+	}
+	ls, err := newLeaderSet(st)
+	if err != nil {
+		return nil, err
+	}
+	v.cache.Add(header.ID(), ls)
+	return ls, nil
+}
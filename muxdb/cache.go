@@ -10,20 +10,60 @@ import (
 	"encoding/binary"
 	"fmt"
 	"slices"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
 
 	"github.com/qianbin/directcache"
 
+	cache2 "github.com/vechain/thor/v2/cache"
 	"github.com/vechain/thor/v2/trie"
 )
 
+// Kinds of tries, by which cache statistics are grouped.
+const (
+	TrieKindAccount = "account"
+	TrieKindStorage = "storage"
+	TrieKindIndex   = "index"
+)
+
+var trieKinds = [...]string{TrieKindAccount, TrieKindStorage, TrieKindIndex}
+
+// trieKindOf returns the index of the kind in trieKinds by the trie name.
+func trieKindOf(name string) int {
+	switch {
+	case name == AccountTrieName:
+		return 0
+	case strings.HasPrefix(name, StorageTrieNamePrefix):
+		return 1
+	default:
+		return 2
+	}
+}
+
 type Cache interface {
 	AddNodeBlob(keyBuf *[]byte, name string, path []byte, ver trie.Version, blob []byte, isCommitting bool)
 	GetNodeBlob(keyBuf *[]byte, name string, path []byte, ver trie.Version, peek bool) []byte
 	AddRootNode(name string, n trie.Node)
 	GetRootNode(name string, ver trie.Version) trie.Node
+	Stats() CacheStats
+	Resize(sizeMB int)
+}
+
+// CacheHitMiss is the number of hits and misses of cache lookups.
+type CacheHitMiss struct {
+	Hit  int64
+	Miss int64
+}
+
+// CacheStats is the statistics of the trie cache.
+type CacheStats struct {
+	SizeMB    int                     // size of node blob caches, which is preallocated
+	Nodes     map[string]CacheHitMiss // lookups of node blobs by trie kind
+	Roots     CacheHitMiss            // lookups of root nodes
+	RootCount int                     // number of cached root nodes
+	Evictions int64                   // number of node blobs evicted
 }
 
 // cache is the cache layer for trie.
@@ -37,8 +77,11 @@ type cache struct {
 		ttl      uint32
 	}
 
-	nodeStats   cacheStats
-	rootStats   cacheStats
+	sizeMB      atomic.Int64
+	nodeStats   cache2.Stats
+	kindStats   [len(trieKinds)]cache2.Stats // node stats by trie kind
+	rootStats   cache2.Stats
+	evictions   atomic.Int64
 	lastLogTime atomic.Int64
 }
 
@@ -49,12 +92,51 @@ func newCache(sizeMB int, rootTTL uint32) Cache {
 		queriedNodes:   directcache.New(sizeBytes / 4),
 		committedNodes: directcache.New(sizeBytes - sizeBytes/4),
 	}
+	// the default LRU policy, with evictions counted
+	shouldEvict := func(ent directcache.Entry) bool {
+		if ent.RecentlyUsed() {
+			return false
+		}
+		cache.evictions.Add(1)
+		return true
+	}
+	cache.queriedNodes.SetEvictionPolicy(shouldEvict)
+	cache.committedNodes.SetEvictionPolicy(shouldEvict)
+	cache.sizeMB.Store(int64(sizeMB))
 	cache.lastLogTime.Store(time.Now().UnixNano())
 	cache.roots.m = make(map[string]trie.Node)
 	cache.roots.ttl = rootTTL
 	return cache
 }
 
+// Stats returns the statistics of the cache.
+func (c *cache) Stats() CacheStats {
+	stats := CacheStats{
+		SizeMB:    int(c.sizeMB.Load()),
+		Nodes:     make(map[string]CacheHitMiss, len(trieKinds)),
+		Evictions: c.evictions.Load(),
+	}
+	for i, kind := range trieKinds {
+		hit, miss := c.kindStats[i].Counts()
+		stats.Nodes[kind] = CacheHitMiss{hit, miss}
+	}
+	// counts only, not to consume the hit rate change that logging relies on
+	stats.Roots.Hit, stats.Roots.Miss = c.rootStats.Counts()
+
+	c.roots.lock.RLock()
+	stats.RootCount = len(c.roots.m)
+	c.roots.lock.RUnlock()
+	return stats
+}
+
+// Resize resizes node blob caches, and cached node blobs are dropped. Root nodes are kept.
+func (c *cache) Resize(sizeMB int) {
+	sizeBytes := sizeMB * 1024 * 1024
+	c.queriedNodes.Reset(sizeBytes / 4)
+	c.committedNodes.Reset(sizeBytes - sizeBytes/4)
+	c.sizeMB.Store(int64(sizeMB))
+}
+
 func (c *cache) log() {
 	now := time.Now().UnixNano()
 	last := c.lastLogTime.Swap(now)
@@ -75,6 +157,12 @@ func (c *cache) log() {
 		metricCacheHitMiss().SetWithLabel(missRoot, map[string]string{"type": "root", "event": "miss"})
 		metricCacheHitMiss().SetWithLabel(hitNode, map[string]string{"type": "node", "event": "hit"})
 		metricCacheHitMiss().SetWithLabel(missNode, map[string]string{"type": "node", "event": "miss"})
+		metricCacheHitMiss().SetWithLabel(c.evictions.Load(), map[string]string{"type": "node", "event": "evict"})
+		for i, kind := range trieKinds {
+			_, hit, miss := c.kindStats[i].Stats()
+			metricCacheHitMiss().SetWithLabel(hit, map[string]string{"type": "node-" + kind, "event": "hit"})
+			metricCacheHitMiss().SetWithLabel(miss, map[string]string{"type": "node-" + kind, "event": "miss"})
+		}
 	} else {
 		c.lastLogTime.CompareAndSwap(now, last)
 	}
@@ -127,6 +215,7 @@ func (c *cache) GetNodeBlob(keyBuf *[]byte, name string, path []byte, ver trie.V
 	}, peek) && len(blob) > 0 {
 		if !peek {
 			c.nodeStats.Hit()
+			c.kindStats[trieKindOf(name)].Hit()
 		}
 		return blob
 	}
@@ -137,11 +226,13 @@ func (c *cache) GetNodeBlob(keyBuf *[]byte, name string, path []byte, ver trie.V
 	}, peek) && len(blob) > 0 {
 		if !peek {
 			c.nodeStats.Hit()
+			c.kindStats[trieKindOf(name)].Hit()
 		}
 		return blob
 	}
 	if !peek {
 		c.nodeStats.Miss()
+		c.kindStats[trieKindOf(name)].Miss()
 	}
 	return nil
 }
@@ -184,28 +275,6 @@ func (c *cache) GetRootNode(name string, ver trie.Version) trie.Node {
 	return nil
 }
 
-type cacheStats struct {
-	hit, miss atomic.Int64
-	flag      atomic.Int32
-}
-
-func (cs *cacheStats) Hit() int64  { return cs.hit.Add(1) }
-func (cs *cacheStats) Miss() int64 { return cs.miss.Add(1) }
-
-func (cs *cacheStats) Stats() (bool, int64, int64) {
-	hit := cs.hit.Load()
-	miss := cs.miss.Load()
-	lookups := hit + miss
-
-	hitRate := float64(0)
-	if lookups > 0 {
-		hitRate = float64(hit) / float64(lookups)
-	}
-	flag := int32(hitRate * 1000)
-
-	return cs.flag.Swap(flag) != flag, hit, miss
-}
-
 func logStats(msg string, hit, miss int64) {
 	lookups := hit + miss
 	var str string
@@ -238,3 +307,11 @@ func (*dummyCache) AddRootNode(_ string, _ trie.Node) {}
 func (*dummyCache) GetRootNode(_ string, _ trie.Version) trie.Node {
 	return nil
 }
+
+// Stats returns empty stats.
+func (*dummyCache) Stats() CacheStats {
+	return CacheStats{}
+}
+
+// Resize is a no-op.
+func (*dummyCache) Resize(_ int) {}
@@ -236,35 +236,47 @@ func TestCacheLogTrigger(t *testing.T) {
 	assert.Nil(t, result)
 }
 
-func TestCacheStatsFunction(t *testing.T) {
-	cs := cacheStats{}
-
-	shouldLog, hits, misses := cs.Stats()
-	assert.Equal(t, int64(0), hits)
-	assert.Equal(t, int64(0), misses)
-	assert.False(t, shouldLog)
-
-	cs.hit.Store(100)
-	shouldLog, hits, misses = cs.Stats()
-	assert.Equal(t, int64(100), hits)
-	assert.Equal(t, int64(0), misses)
-	assert.True(t, shouldLog)
-
-	shouldLog, hits, misses = cs.Stats()
-	assert.Equal(t, int64(100), hits)
-	assert.Equal(t, int64(0), misses)
-	assert.False(t, shouldLog)
-
-	cs.miss.Store(100)
-	shouldLog, hits, misses = cs.Stats()
-	assert.Equal(t, int64(100), hits)
-	assert.Equal(t, int64(100), misses)
-	assert.True(t, shouldLog)
-
-	cs = cacheStats{}
-	cs.miss.Store(100)
-	shouldLog, hits, misses = cs.Stats()
-	assert.Equal(t, int64(0), hits)
-	assert.Equal(t, int64(100), misses)
-	assert.False(t, shouldLog)
+func TestCacheStats(t *testing.T) {
+	var (
+		cache  = newCache(1, 100)
+		keyBuf []byte
+		blob   = []byte{1, 1, 1}
+		ver    = trie.Version{Major: 1}
+	)
+
+	cache.AddNodeBlob(&keyBuf, AccountTrieName, []byte{0x0a}, ver, blob, true)
+	cache.AddNodeBlob(&keyBuf, StorageTrieNamePrefix+"x", []byte{0x0a}, ver, blob, false)
+	cache.AddRootNode(AccountTrieName, &mockedRootNode{ver: ver})
+
+	assert.NotNil(t, cache.GetNodeBlob(&keyBuf, AccountTrieName, []byte{0x0a}, ver, false))
+	assert.Nil(t, cache.GetNodeBlob(&keyBuf, AccountTrieName, []byte{0x0b}, ver, false))
+	assert.NotNil(t, cache.GetNodeBlob(&keyBuf, StorageTrieNamePrefix+"x", []byte{0x0a}, ver, false))
+	assert.Nil(t, cache.GetNodeBlob(&keyBuf, IndexTrieName, []byte{0x0a}, ver, false))
+	// peeks are not counted
+	assert.NotNil(t, cache.GetNodeBlob(&keyBuf, AccountTrieName, []byte{0x0a}, ver, true))
+	assert.NotNil(t, cache.GetRootNode(AccountTrieName, ver))
+
+	stats := cache.Stats()
+	assert.Equal(t, 1, stats.SizeMB)
+	assert.Equal(t, map[string]CacheHitMiss{
+		TrieKindAccount: {Hit: 1, Miss: 1},
+		TrieKindStorage: {Hit: 1},
+		TrieKindIndex:   {Miss: 1},
+	}, stats.Nodes)
+	assert.Equal(t, CacheHitMiss{Hit: 1}, stats.Roots)
+	assert.Equal(t, 1, stats.RootCount)
+
+	// evicted when full
+	for i := range 100000 {
+		cache.AddNodeBlob(&keyBuf, AccountTrieName, []byte{byte(i), byte(i >> 8), byte(i >> 16)}, ver, blob, false)
+	}
+	assert.NotZero(t, cache.Stats().Evictions)
+
+	// resized, with node blobs dropped and root nodes kept
+	cache.Resize(2)
+	stats = cache.Stats()
+	assert.Equal(t, 2, stats.SizeMB)
+	assert.Equal(t, 1, stats.RootCount)
+	assert.Nil(t, cache.GetNodeBlob(&keyBuf, AccountTrieName, []byte{0x0a}, ver, false))
+	assert.NotNil(t, cache.GetRootNode(AccountTrieName, ver))
 }
@@ -15,4 +15,7 @@ import (
 type Engine interface {
 	kv.Store
 	io.Closer
+
+	// Compact compacts the underlying storage within the key range, to reclaim space of deleted keys.
+	Compact(r kv.Range) error
 }
@@ -185,6 +185,10 @@ func (ldb *LevelEngine) DeleteRange(ctx context.Context, r kv.Range) error {
 	return bulk.Write()
 }
 
+func (ldb *LevelEngine) Compact(r kv.Range) error {
+	return ldb.db.CompactRange(util.Range(r))
+}
+
 func (ldb *LevelEngine) Stats(s *leveldb.DBStats) error {
 	return ldb.db.Stats(s)
 }
@@ -0,0 +1,292 @@
+// Copyright (c) 2025 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package engine
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/cockroachdb/pebble"
+
+	"github.com/vechain/thor/v2/kv"
+)
+
+var pebbleWriteOpt = pebble.NoSync
+
+type PebbleEngine struct {
+	db *pebble.DB
+}
+
+// NewPebbleEngine creates pebble instance which implements the Engine interface.
+func NewPebbleEngine(db *pebble.DB) Engine {
+	return &PebbleEngine{db}
+}
+
+func (pdb *PebbleEngine) Close() error {
+	return pdb.db.Close()
+}
+
+func (pdb *PebbleEngine) IsNotFound(err error) bool {
+	// compared by identity as leveldb engine does, wrapped errors are not treated as not found
+	return err == pebble.ErrNotFound
+}
+
+func (pdb *PebbleEngine) Get(key []byte) ([]byte, error) {
+	return pebbleGet(pdb.db.Get(key))
+}
+
+func (pdb *PebbleEngine) Has(key []byte) (bool, error) {
+	return pebbleHas(pdb.db.Get(key))
+}
+
+func (pdb *PebbleEngine) Put(key, val []byte) error {
+	return pdb.db.Set(key, val, pebbleWriteOpt)
+}
+
+func (pdb *PebbleEngine) Delete(key []byte) error {
+	return pdb.db.Delete(key, pebbleWriteOpt)
+}
+
+func (pdb *PebbleEngine) Snapshot() kv.Snapshot {
+	s := pdb.db.NewSnapshot()
+	return &struct {
+		kv.GetFunc
+		kv.HasFunc
+		kv.IsNotFoundFunc
+		kv.ReleaseFunc
+	}{
+		func(key []byte) ([]byte, error) {
+			return pebbleGet(s.Get(key))
+		},
+		func(key []byte) (bool, error) {
+			return pebbleHas(s.Get(key))
+		},
+		pdb.IsNotFound,
+		func() { s.Close() },
+	}
+}
+
+func (pdb *PebbleEngine) Bulk() kv.Bulk {
+	const idealBatchSize = 128 * 1024
+	var batch *pebble.Batch
+
+	getBatch := func() *pebble.Batch {
+		if batch == nil {
+			batch = pdb.db.NewBatch()
+		}
+		return batch
+	}
+	flush := func(minSize int) error {
+		if batch != nil && batch.Len() >= minSize {
+			if batch.Count() > 0 {
+				if err := batch.Commit(pebbleWriteOpt); err != nil {
+					return err
+				}
+			}
+			batch.Close()
+			batch = nil
+		}
+		return nil
+	}
+	var autoFlush bool
+
+	return &struct {
+		kv.PutFunc
+		kv.DeleteFunc
+		kv.EnableAutoFlushFunc
+		kv.WriteFunc
+	}{
+		func(key, val []byte) error {
+			if err := getBatch().Set(key, val, nil); err != nil {
+				return err
+			}
+			if autoFlush {
+				return flush(idealBatchSize)
+			}
+			return nil
+		},
+		func(key []byte) error {
+			if err := getBatch().Delete(key, nil); err != nil {
+				return err
+			}
+			if autoFlush {
+				return flush(idealBatchSize)
+			}
+			return nil
+		},
+		func() { autoFlush = true },
+		func() error { return flush(0) },
+	}
+}
+
+func (pdb *PebbleEngine) Iterate(r kv.Range) kv.Iterator {
+	it, err := pdb.db.NewIter(&pebble.IterOptions{
+		LowerBound: r.Start,
+		UpperBound: r.Limit,
+	})
+	return &pebbleIterator{it: it, err: err}
+}
+
+func (pdb *PebbleEngine) Compact(r kv.Range) error {
+	limit := r.Limit
+	if len(limit) == 0 {
+		// pebble requires the end key, take the last key which is inclusive
+		iter := pdb.Iterate(r)
+		defer iter.Release()
+		if !iter.Last() {
+			return iter.Error()
+		}
+		limit = append([]byte(nil), iter.Key()...)
+	}
+	if bytes.Compare(r.Start, limit) >= 0 {
+		return nil
+	}
+	return pdb.db.Compact(r.Start, limit, true)
+}
+
+func (pdb *PebbleEngine) DeleteRange(ctx context.Context, r kv.Range) error {
+	if len(r.Limit) > 0 {
+		// range tombstone, which is cheap and reclaims space on compaction
+		if bytes.Compare(r.Start, r.Limit) >= 0 {
+			return nil
+		}
+		return pdb.db.DeleteRange(r.Start, r.Limit, pebbleWriteOpt)
+	}
+
+	// unbounded range
+	iter := pdb.Iterate(r)
+	defer iter.Release()
+
+	cnt := 0
+
+	bulk := pdb.Bulk()
+	bulk.EnableAutoFlush()
+
+	for iter.Next() {
+		cnt++
+		// check context every 1000 times.
+		if cnt%1000 == 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			default:
+			}
+		}
+		if err := bulk.Delete(iter.Key()); err != nil {
+			return err
+		}
+	}
+
+	if err := iter.Error(); err != nil {
+		return err
+	}
+	return bulk.Write()
+}
+
+func (pdb *PebbleEngine) Stats() *pebble.Metrics {
+	return pdb.db.Metrics()
+}
+
+func pebbleGet(val []byte, closer interface{ Close() error }, err error) ([]byte, error) {
+	if err != nil {
+		return nil, err
+	}
+	defer closer.Close()
+	if len(val) == 0 {
+		return nil, nil
+	}
+	// val is only valid until closer closed
+	return bytes.Clone(val), nil
+}
+
+func pebbleHas(_ []byte, closer interface{ Close() error }, err error) (bool, error) {
+	if err != nil {
+		if err == pebble.ErrNotFound {
+			return false, nil
+		}
+		return false, err
+	}
+	closer.Close()
+	return true, nil
+}
+
+// pebbleIterator adapts pebble iterator to kv.Iterator, which is initially positioned before the first
+// pair, the same as leveldb iterator.
+type pebbleIterator struct {
+	it         *pebble.Iterator
+	err        error
+	positioned bool
+}
+
+func (i *pebbleIterator) First() bool {
+	if i.it == nil {
+		return false
+	}
+	i.positioned = true
+	return i.it.First()
+}
+
+func (i *pebbleIterator) Last() bool {
+	if i.it == nil {
+		return false
+	}
+	i.positioned = true
+	return i.it.Last()
+}
+
+func (i *pebbleIterator) Next() bool {
+	if i.it == nil {
+		return false
+	}
+	if !i.positioned {
+		return i.First()
+	}
+	return i.it.Next()
+}
+
+func (i *pebbleIterator) Prev() bool {
+	if i.it == nil {
+		return false
+	}
+	if !i.positioned {
+		// nothing before the start
+		return false
+	}
+	return i.it.Prev()
+}
+
+func (i *pebbleIterator) Key() []byte {
+	if i.it == nil || !i.it.Valid() {
+		return nil
+	}
+	return i.it.Key()
+}
+
+func (i *pebbleIterator) Value() []byte {
+	if i.it == nil || !i.it.Valid() {
+		return nil
+	}
+	return i.it.Value()
+}
+
+func (i *pebbleIterator) Release() {
+	if i.it != nil {
+		if err := i.it.Close(); err != nil && i.err == nil {
+			i.err = err
+		}
+		i.it = nil
+	}
+}
+
+func (i *pebbleIterator) Error() error {
+	if i.err != nil {
+		return i.err
+	}
+	if i.it != nil {
+		return i.it.Error()
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+// Copyright (c) 2025 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package muxdb
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"time"
+
+	"github.com/vechain/thor/v2/kv"
+)
+
+const (
+	migrationKey            = "migration"      // the key of migration progress marker in the props store.
+	migrationCheckpointSize = 64 * 1024 * 1024 // bytes copied between two progress markers.
+)
+
+// MigrateTo copies all key spaces of this DB, including trie nodes and named stores, into dst, which is
+// usually backed by another engine. The progress is saved in dst, so that an interrupted migration
+// resumes from where it stopped. The config of dst is kept.
+func (db *MuxDB) MigrateTo(ctx context.Context, dst *MuxDB) error {
+	if db.trieBackend.HistPtnFactor != dst.trieBackend.HistPtnFactor ||
+		db.trieBackend.DedupedPtnFactor != dst.trieBackend.DedupedPtnFactor {
+		return errors.New("trie partition factors mismatch")
+	}
+
+	var (
+		propsPrefix = string(namedStoreSpace) + propStoreName
+		cfgKey      = []byte(propsPrefix + configKey)
+		markerKey   = []byte(propsPrefix + migrationKey)
+		start       []byte
+	)
+	// resume from the marker
+	marker, err := dst.engine.Get(markerKey)
+	if err != nil {
+		if !dst.engine.IsNotFound(err) {
+			return err
+		}
+	} else {
+		start = append(marker, 0) // the successor of the last copied key
+		logger.Info("resume migration", "from", kvSpaceName(marker[0]))
+	}
+
+	iter := db.engine.Iterate(kv.Range{Start: start})
+	defer iter.Release()
+
+	var (
+		bulk   = dst.engine.Bulk()
+		size   int
+		copied int
+		last   []byte
+		ticker = time.NewTicker(metricsSampleInterval)
+	)
+	defer ticker.Stop()
+	bulk.EnableAutoFlush()
+
+	checkpoint := func() error {
+		if err := bulk.Write(); err != nil {
+			return err
+		}
+		if len(last) > 0 {
+			// the marker is written after the copied pairs flushed
+			return dst.engine.Put(markerKey, last)
+		}
+		return nil
+	}
+
+	for iter.Next() {
+		key, val := iter.Key(), iter.Value()
+		// engine recorded in config differs, and the marker of the source is meaningless
+		if bytes.Equal(key, cfgKey) || bytes.Equal(key, markerKey) {
+			continue
+		}
+		if err := bulk.Put(key, val); err != nil {
+			return err
+		}
+		copied++
+		size += len(key) + len(val)
+		last = append(last[:0], key...)
+
+		if size >= migrationCheckpointSize {
+			if err := checkpoint(); err != nil {
+				return err
+			}
+			size = 0
+
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-ticker.C:
+				logger.Info("migrating", "space", kvSpaceName(last[0]), "copied", copied)
+			default:
+			}
+		}
+	}
+	if err := iter.Error(); err != nil {
+		return err
+	}
+	if err := checkpoint(); err != nil {
+		return err
+	}
+	logger.Info("migration completed", "copied", copied)
+	return nil
+}
+
+// FinishMigration removes the migration progress marker.
+func (db *MuxDB) FinishMigration() error {
+	return db.NewStore(propStoreName).Delete([]byte(migrationKey))
+}
+
+// kvSpaceByName returns the key space of the readable name.
+func kvSpaceByName(name string) (byte, bool) {
+	for _, space := range []byte{trieHistSpace, trieDedupedSpace, namedStoreSpace} {
+		if kvSpaceName(space) == name {
+			return space, true
+		}
+	}
+	return 0, false
+}
+
+// kvSpaceName returns the readable name of the key space.
+func kvSpaceName(space byte) string {
+	switch space {
+	case trieHistSpace:
+		return "trie-hist"
+	case trieDedupedSpace:
+		return "trie-deduped"
+	case namedStoreSpace:
+		return "named-store"
+	default:
+		return "unknown"
+	}
+}
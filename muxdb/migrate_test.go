@@ -0,0 +1,105 @@
+// Copyright (c) 2025 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package muxdb
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vechain/thor/v2/trie"
+)
+
+func openMigrationTestDB(t *testing.T, path, engine string) *MuxDB {
+	db, err := Open(path, &Options{
+		TrieHistPartitionFactor:    1000,
+		TrieDedupedPartitionFactor: 2000,
+		OpenFilesCacheCapacity:     16,
+		ReadCacheMB:                16,
+		WriteBufferMB:              16,
+		Engine:                     engine,
+	})
+	require.NoError(t, err)
+	return db
+}
+
+func TestMigrate(t *testing.T) {
+	dir := t.TempDir()
+	src := openMigrationTestDB(t, filepath.Join(dir, "src.db"), EngineLevelDB)
+	defer src.Close()
+
+	tr := src.NewTrie("test", trie.Root{})
+	for i := range 100 {
+		require.NoError(t, tr.Update([]byte{byte(i)}, []byte{byte(i), 1}, nil))
+	}
+	require.NoError(t, tr.Commit(trie.Version{Major: 1}, false))
+	root := trie.Root{Hash: tr.Hash(), Ver: trie.Version{Major: 1}}
+	require.NoError(t, src.NewStore("store").Put([]byte("key"), []byte("value")))
+
+	dstPath := filepath.Join(dir, "dst.db")
+	dst := openMigrationTestDB(t, dstPath, EnginePebble)
+	require.NoError(t, src.MigrateTo(context.Background(), dst))
+	require.NoError(t, dst.FinishMigration())
+	require.NoError(t, dst.Close())
+
+	dst = openMigrationTestDB(t, dstPath, EnginePebble)
+	defer dst.Close()
+
+	tr = dst.NewTrie("test", root)
+	for i := range 100 {
+		val, _, err := tr.Get([]byte{byte(i)})
+		assert.NoError(t, err)
+		assert.Equal(t, []byte{byte(i), 1}, val)
+	}
+	val, err := dst.NewStore("store").Get([]byte("key"))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("value"), val)
+
+	// config of dst kept
+	cfg := config{}
+	assert.NoError(t, cfg.LoadOrSave(dst.NewStore(propStoreName)))
+	assert.Equal(t, config{1000, 2000, EnginePebble}, cfg)
+
+	has, err := dst.NewStore(propStoreName).Has([]byte(migrationKey))
+	assert.NoError(t, err)
+	assert.False(t, has)
+}
+
+func TestMigrateResume(t *testing.T) {
+	dir := t.TempDir()
+	src := openMigrationTestDB(t, filepath.Join(dir, "src.db"), EnginePebble)
+	defer src.Close()
+	dst := openMigrationTestDB(t, filepath.Join(dir, "dst.db"), EngineLevelDB)
+	defer dst.Close()
+
+	store := src.NewStore("store")
+	for i := range 10 {
+		require.NoError(t, store.Put([]byte{byte(i)}, []byte{byte(i)}))
+	}
+
+	// stopped after the 5th pair copied
+	marker := append([]byte(string(namedStoreSpace)+"store"), 4)
+	require.NoError(t, dst.NewStore(propStoreName).Put([]byte(migrationKey), marker))
+	require.NoError(t, src.MigrateTo(context.Background(), dst))
+
+	for i := range 10 {
+		has, err := dst.NewStore("store").Has([]byte{byte(i)})
+		assert.NoError(t, err)
+		assert.Equal(t, i > 4, has)
+	}
+}
+
+func TestMigrateFactorsMismatch(t *testing.T) {
+	src := openMigrationTestDB(t, filepath.Join(t.TempDir(), "src.db"), EngineLevelDB)
+	defer src.Close()
+
+	dst := NewMem()
+	defer dst.Close()
+	assert.ErrorContains(t, src.MigrateTo(context.Background(), dst), "partition factors mismatch")
+}
@@ -9,9 +9,17 @@ package muxdb
 
 import (
 	"context"
+	"encoding/binary"
 	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync/atomic"
 	"time"
 
+	"github.com/cockroachdb/pebble"
+	"github.com/cockroachdb/pebble/bloom"
+	"github.com/cockroachdb/pebble/vfs"
 	"github.com/syndtr/goleveldb/leveldb"
 	dberrors "github.com/syndtr/goleveldb/leveldb/errors"
 	"github.com/syndtr/goleveldb/leveldb/filter"
@@ -33,8 +41,15 @@ const (
 )
 
 const (
-	propStoreName = "muxdb.props"
-	configKey     = "config"
+	propStoreName     = "muxdb.props"
+	configKey         = "config"
+	trieHistPrunedKey = "trieHistPruned"
+)
+
+// Names of storage engines.
+const (
+	EngineLevelDB = "leveldb"
+	EnginePebble  = "pebble"
 )
 
 var logger = log.WithContext("pkg", "muxdb")
@@ -58,18 +73,91 @@ type Options struct {
 	ReadCacheMB int
 	// WriteBufferMB is the size of write buffer for underlying database.
 	WriteBufferMB int
+	// Engine is the name of storage engine, EngineLevelDB if empty.
+	// It's persisted on creation, and a DB can't be opened with another engine.
+	Engine string
 }
 
 // MuxDB is the database to efficiently store state trie and block-chain data.
 type MuxDB struct {
 	engine      engine.Engine
 	trieBackend *backend
+	histPruned  atomic.Uint32 // the major version before which trie history nodes deleted
 
 	done chan struct{}
 }
 
 // Open opens or creates DB at the given path.
 func Open(path string, options *Options) (*MuxDB, error) {
+	name := options.Engine
+	if name == "" {
+		name = EngineLevelDB
+	}
+	// detect by files before opening, since opening with the wrong engine may damage the data
+	detected, err := detectEngine(path)
+	if err != nil {
+		return nil, err
+	}
+	if detected != "" && detected != name {
+		return nil, fmt.Errorf("database created by engine %v, can't be opened by %v", detected, name)
+	}
+
+	var eng engine.Engine
+	switch name {
+	case EngineLevelDB:
+		eng, err = openLevelEngine(path, options)
+	case EnginePebble:
+		eng, err = openPebbleEngine(path, options)
+	default:
+		return nil, fmt.Errorf("unsupported engine %v", name)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	propStore := kv.Bucket(string(namedStoreSpace) + propStoreName).NewStore(eng)
+	// persists critical options to avoid corruption when tweaked.
+	cfg := config{
+		HistPtnFactor:    options.TrieHistPartitionFactor,
+		DedupedPtnFactor: options.TrieDedupedPartitionFactor,
+		Engine:           name,
+	}
+	if err := cfg.LoadOrSave(propStore); err != nil {
+		eng.Close()
+		return nil, err
+	}
+	// DBs created before engines recorded are all of leveldb
+	if cfg.Engine != name {
+		eng.Close()
+		return nil, fmt.Errorf("database created by engine %v, can't be opened by %v", cfg.Engine, name)
+	}
+
+	histPruned, err := propStore.Get([]byte(trieHistPrunedKey))
+	if err != nil && !propStore.IsNotFound(err) {
+		eng.Close()
+		return nil, err
+	}
+
+	db := &MuxDB{
+		engine: eng,
+		trieBackend: &backend{
+			Store: eng,
+			Cache: newCache(
+				options.TrieNodeCacheSizeMB,
+				uint32(options.TrieCachedNodeTTL)),
+			HistPtnFactor:    cfg.HistPtnFactor,
+			DedupedPtnFactor: cfg.DedupedPtnFactor,
+			CachedNodeTTL:    options.TrieCachedNodeTTL,
+		},
+		done: make(chan struct{}),
+	}
+	if len(histPruned) == 4 {
+		db.histPruned.Store(binary.BigEndian.Uint32(histPruned))
+	}
+	return db, nil
+}
+
+func openLevelEngine(path string, options *Options) (engine.Engine, error) {
 	// prepare leveldb options
 	ldbOpts := opt.Options{
 		OpenFilesCacheCapacity: options.OpenFilesCacheCapacity,
@@ -94,46 +182,84 @@ func Open(path string, options *Options) (*MuxDB, error) {
 	if err != nil {
 		return nil, err
 	}
+	return engine.NewLevelEngine(ldb), nil
+}
 
-	// as engine
-	engine := engine.NewLevelEngine(ldb)
+func openPebbleEngine(path string, options *Options) (engine.Engine, error) {
+	cache := pebble.NewCache(int64(options.ReadCacheMB) * 1024 * 1024)
+	defer cache.Unref()
 
-	propStore := kv.Bucket(string(namedStoreSpace) + propStoreName).NewStore(engine)
-	// persists critical options to avoid corruption when tweaked.
-	cfg := config{
-		HistPtnFactor:    options.TrieHistPartitionFactor,
-		DedupedPtnFactor: options.TrieDedupedPartitionFactor,
+	pdbOpts := pebble.Options{
+		Cache:        cache,
+		MaxOpenFiles: options.OpenFilesCacheCapacity,
+		MemTableSize: uint64(options.WriteBufferMB) * 1024 * 1024, //#nosec G115
+		Logger:       pebbleLogger{},
 	}
-	if err := cfg.LoadOrSave(propStore); err != nil {
-		ldb.Close()
+	// range deletions of pebble reclaim disk space efficiently, no need to segregate historical trie nodes
+	pdbOpts.Levels = make([]pebble.LevelOptions, 7)
+	for i := range pdbOpts.Levels {
+		pdbOpts.Levels[i] = pebble.LevelOptions{
+			BlockSize:      1024 * 32, // balance performance of point reads and compression ratio.
+			FilterPolicy:   bloom.FilterPolicy(10),
+			TargetFileSize: 4 * 1024 * 1024 << i,
+		}
+	}
+	return newPebbleEngine(path, &pdbOpts)
+}
+
+func newPebbleEngine(path string, opts *pebble.Options) (engine.Engine, error) {
+	pdb, err := pebble.Open(path, opts)
+	if err != nil {
 		return nil, err
 	}
+	return engine.NewPebbleEngine(pdb), nil
+}
 
-	return &MuxDB{
-		engine: engine,
-		trieBackend: &backend{
-			Store: engine,
-			Cache: newCache(
-				options.TrieNodeCacheSizeMB,
-				uint32(options.TrieCachedNodeTTL)),
-			HistPtnFactor:    cfg.HistPtnFactor,
-			DedupedPtnFactor: cfg.DedupedPtnFactor,
-			CachedNodeTTL:    options.TrieCachedNodeTTL,
-		},
-		done: make(chan struct{}),
-	}, nil
+// detectEngine detects the engine of the DB at the given path by its files.
+// Empty name returned if no DB found.
+func detectEngine(path string) (string, error) {
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", nil
+		}
+		return "", err
+	}
+	var hasCurrent bool
+	for _, e := range entries {
+		name := e.Name()
+		if strings.HasPrefix(name, "OPTIONS-") || strings.HasPrefix(name, "marker.") {
+			return EnginePebble, nil
+		}
+		if name == "CURRENT" {
+			hasCurrent = true
+		}
+	}
+	if hasCurrent {
+		return EngineLevelDB, nil
+	}
+	return "", nil
 }
 
-// NewMem creates a memory-backed DB.
+// NewMem creates a memory-backed DB with the default engine.
 func NewMem() *MuxDB {
-	storage := storage.NewMemStorage()
-	ldb, _ := leveldb.Open(storage, nil)
+	return NewMemWithEngine(EngineLevelDB)
+}
 
-	engine := engine.NewLevelEngine(ldb)
+// NewMemWithEngine creates a memory-backed DB with the given engine.
+func NewMemWithEngine(name string) *MuxDB {
+	var eng engine.Engine
+	if name == EnginePebble {
+		eng, _ = newPebbleEngine("", &pebble.Options{FS: vfs.NewMem(), Logger: pebbleLogger{}})
+	} else {
+		storage := storage.NewMemStorage()
+		ldb, _ := leveldb.Open(storage, nil)
+		eng = engine.NewLevelEngine(ldb)
+	}
 	return &MuxDB{
-		engine: engine,
+		engine: eng,
 		trieBackend: &backend{
-			Store:            engine,
+			Store:            eng,
 			Cache:            &dummyCache{},
 			HistPtnFactor:    1,
 			DedupedPtnFactor: 1,
@@ -161,7 +287,59 @@ func (db *MuxDB) NewTrie(name string, root trie.Root) *Trie {
 
 // DeleteTrieHistoryNodes deletes trie history nodes within partitions of [startMajorVer, limitMajorVer).
 func (db *MuxDB) DeleteTrieHistoryNodes(ctx context.Context, startMajorVer, limitMajorVer uint32) error {
-	return db.trieBackend.DeleteHistoryNodes(ctx, startMajorVer, limitMajorVer)
+	if err := db.trieBackend.DeleteHistoryNodes(ctx, startMajorVer, limitMajorVer); err != nil {
+		return err
+	}
+
+	// the start of the limit partition
+	return db.MarkTrieHistoryPruned(limitMajorVer / db.trieBackend.HistPtnFactor * db.trieBackend.HistPtnFactor)
+}
+
+// MarkTrieHistoryPruned marks trie history nodes before the major version as pruned without deleting them,
+// e.g. the history is incomplete since the trie is synced at the major version. The mark never goes backwards.
+func (db *MuxDB) MarkTrieHistoryPruned(majorVer uint32) error {
+	if majorVer <= db.histPruned.Load() {
+		return nil
+	}
+	if err := db.NewStore(propStoreName).Put([]byte(trieHistPrunedKey), binary.BigEndian.AppendUint32(nil, majorVer)); err != nil {
+		return err
+	}
+	db.histPruned.Store(majorVer)
+	return nil
+}
+
+// TrieHistoryPrunedBefore returns the major version, before which trie history nodes have been deleted.
+// Tries of versions before it are inaccessible by roots.
+func (db *MuxDB) TrieHistoryPrunedBefore() uint32 {
+	return db.histPruned.Load()
+}
+
+// Compact compacts the underlying engine within the given key spaces, or the whole DB if none given.
+// Key spaces are named as trie-hist, trie-deduped and named-store.
+func (db *MuxDB) Compact(spaces ...string) error {
+	if len(spaces) == 0 {
+		return db.engine.Compact(kv.Range{})
+	}
+	for _, name := range spaces {
+		space, ok := kvSpaceByName(name)
+		if !ok {
+			return fmt.Errorf("unknown key space %v", name)
+		}
+		if err := db.engine.Compact(kv.Range{Start: []byte{space}, Limit: []byte{space + 1}}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// TrieCacheStats returns the statistics of the trie cache.
+func (db *MuxDB) TrieCacheStats() CacheStats {
+	return db.trieBackend.Cache.Stats()
+}
+
+// ResizeTrieCache resizes the trie node cache at runtime. Cached node blobs are dropped.
+func (db *MuxDB) ResizeTrieCache(sizeMB int) {
+	db.trieBackend.Cache.Resize(sizeMB)
 }
 
 // NewStore creates named kv-store.
@@ -186,14 +364,15 @@ func (db *MuxDB) EnableMetrics() {
 		for {
 			select {
 			case <-ticker.C:
-				// we only have one engine implementation for now, put the type assertion just for safety
-				lvl, ok := db.engine.(*engine.LevelEngine)
-				if ok {
-					err = lvl.Stats(&stats)
+				switch eng := db.engine.(type) {
+				case *engine.LevelEngine:
+					err = eng.Stats(&stats)
 					if err != nil {
 						logger.Warn("Failed to get LevelDB stats: %v", err)
 					}
 					registerCompactionMetrics(&stats)
+				case *engine.PebbleEngine:
+					registerPebbleCompactionMetrics(eng.Stats())
 				}
 			case <-db.done:
 				return
@@ -205,6 +384,7 @@ func (db *MuxDB) EnableMetrics() {
 type config struct {
 	HistPtnFactor    uint32
 	DedupedPtnFactor uint32
+	Engine           string `json:",omitempty"`
 }
 
 func (c *config) LoadOrSave(store kv.Store) error {
@@ -226,3 +406,19 @@ func (c *config) LoadOrSave(store kv.Store) error {
 	}
 	return store.Put([]byte(configKey), data)
 }
+
+// pebbleLogger redirects logs of pebble.
+type pebbleLogger struct{}
+
+func (pebbleLogger) Infof(format string, args ...any) {
+	logger.Debug(fmt.Sprintf(format, args...))
+}
+
+func (pebbleLogger) Errorf(format string, args ...any) {
+	logger.Warn(fmt.Sprintf(format, args...))
+}
+
+func (pebbleLogger) Fatalf(format string, args ...any) {
+	logger.Crit(fmt.Sprintf(format, args...))
+	os.Exit(1)
+}
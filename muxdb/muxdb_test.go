@@ -21,6 +21,20 @@ import (
 	"github.com/vechain/thor/v2/trie"
 )
 
+// engines are the storage engines which tests run against.
+var engines = []string{EngineLevelDB, EnginePebble}
+
+// forEachMem runs the test against a memory-backed DB of each engine.
+func forEachMem(t *testing.T, f func(t *testing.T, db *MuxDB)) {
+	for _, name := range engines {
+		t.Run(name, func(t *testing.T) {
+			db := NewMemWithEngine(name)
+			defer db.Close()
+			f(t, db)
+		})
+	}
+}
+
 func TestNewMuxDB(t *testing.T) {
 	opts := Options{
 		TrieNodeCacheSizeMB:        128,
@@ -46,47 +60,45 @@ func TestNewMuxDB(t *testing.T) {
 }
 
 func TestNewMemDB(t *testing.T) {
-	db := NewMem()
-	defer db.Close()
-
-	assert.NotNil(t, db.engine)
-	assert.NotNil(t, db.trieBackend)
-	assert.Equal(t, uint32(1), db.trieBackend.HistPtnFactor)
-	assert.Equal(t, uint32(1), db.trieBackend.DedupedPtnFactor)
-	assert.Equal(t, uint16(32), db.trieBackend.CachedNodeTTL)
+	forEachMem(t, func(t *testing.T, db *MuxDB) {
+		assert.NotNil(t, db.engine)
+		assert.NotNil(t, db.trieBackend)
+		assert.Equal(t, uint32(1), db.trieBackend.HistPtnFactor)
+		assert.Equal(t, uint32(1), db.trieBackend.DedupedPtnFactor)
+		assert.Equal(t, uint16(32), db.trieBackend.CachedNodeTTL)
+	})
 }
 
 func TestDBStore(t *testing.T) {
-	db := NewMem()
-	defer db.Close()
-
-	store := db.NewStore("test")
-
-	tests := []struct {
-		key   []byte
-		value []byte
-	}{
-		{[]byte("key1"), []byte("value1")},
-		{[]byte{0x00}, []byte{0x01}},
-		{[]byte("large-key"), make([]byte, 1024)},
-		{nil, []byte("value")},
-		{[]byte("key"), nil},
-	}
-
-	for _, tt := range tests {
-		err := store.Put(tt.key, tt.value)
-		assert.Nil(t, err)
-
-		got, err := store.Get(tt.key)
-		assert.Nil(t, err)
-		assert.Equal(t, tt.value, got)
-
-		err = store.Delete(tt.key)
-		assert.Nil(t, err)
-
-		_, err = store.Get(tt.key)
-		assert.True(t, db.IsNotFound(err))
-	}
+	forEachMem(t, func(t *testing.T, db *MuxDB) {
+		store := db.NewStore("test")
+
+		tests := []struct {
+			key   []byte
+			value []byte
+		}{
+			{[]byte("key1"), []byte("value1")},
+			{[]byte{0x00}, []byte{0x01}},
+			{[]byte("large-key"), make([]byte, 1024)},
+			{nil, []byte("value")},
+			{[]byte("key"), nil},
+		}
+
+		for _, tt := range tests {
+			err := store.Put(tt.key, tt.value)
+			assert.Nil(t, err)
+
+			got, err := store.Get(tt.key)
+			assert.Nil(t, err)
+			assert.Equal(t, tt.value, got)
+
+			err = store.Delete(tt.key)
+			assert.Nil(t, err)
+
+			_, err = store.Get(tt.key)
+			assert.True(t, db.IsNotFound(err))
+		}
+	})
 }
 
 type mockStore struct {
@@ -111,43 +123,42 @@ func (m *mockStore) IsNotFound(err error) bool {
 }
 
 func TestDBConfig(t *testing.T) {
-	db := NewMem()
-	defer db.Close()
-
-	store := db.NewStore(propStoreName)
+	forEachMem(t, func(t *testing.T, db *MuxDB) {
+		store := db.NewStore(propStoreName)
 
-	cfg := config{
-		HistPtnFactor:    2000,
-		DedupedPtnFactor: 3000,
-	}
+		cfg := config{
+			HistPtnFactor:    2000,
+			DedupedPtnFactor: 3000,
+		}
 
-	err := cfg.LoadOrSave(store)
-	assert.Nil(t, err)
+		err := cfg.LoadOrSave(store)
+		assert.Nil(t, err)
 
-	loaded := config{}
-	err = loaded.LoadOrSave(store)
-	assert.Nil(t, err)
+		loaded := config{}
+		err = loaded.LoadOrSave(store)
+		assert.Nil(t, err)
 
-	assert.Equal(t, cfg.HistPtnFactor, loaded.HistPtnFactor)
-	assert.Equal(t, cfg.DedupedPtnFactor, loaded.DedupedPtnFactor)
+		assert.Equal(t, cfg.HistPtnFactor, loaded.HistPtnFactor)
+		assert.Equal(t, cfg.DedupedPtnFactor, loaded.DedupedPtnFactor)
 
-	// Test Get error that is not NotFound
-	_mockStore := &mockStore{getErr: errors.New("db error")}
-	err = loaded.LoadOrSave(_mockStore)
-	assert.Equal(t, errors.New("db error"), err)
+		// Test Get error that is not NotFound
+		_mockStore := &mockStore{getErr: errors.New("db error")}
+		err = loaded.LoadOrSave(_mockStore)
+		assert.Equal(t, errors.New("db error"), err)
 
-	// Test Put error
-	_mockStore = &mockStore{putErr: errors.New("put error")}
-	err = loaded.LoadOrSave(_mockStore)
-	assert.Equal(t, errors.New("put error"), err)
+		// Test Put error
+		_mockStore = &mockStore{putErr: errors.New("put error")}
+		err = loaded.LoadOrSave(_mockStore)
+		assert.Equal(t, errors.New("put error"), err)
 
-	// Test invalid JSON unmarshal
-	invalidData := []byte("invalid-json")
-	err = store.Put([]byte(configKey), invalidData)
-	assert.Nil(t, err)
+		// Test invalid JSON unmarshal
+		invalidData := []byte("invalid-json")
+		err = store.Put([]byte(configKey), invalidData)
+		assert.Nil(t, err)
 
-	err = loaded.LoadOrSave(store)
-	assert.NotNil(t, err)
+		err = loaded.LoadOrSave(store)
+		assert.NotNil(t, err)
+	})
 }
 
 func TestCorruptDBRecovery(t *testing.T) {
@@ -180,27 +191,26 @@ func TestCorruptDBRecovery(t *testing.T) {
 }
 
 func TestTrieOperations(t *testing.T) {
-	db := NewMem()
-	defer db.Close()
-
-	tr := db.NewTrie("test", trie.Root{})
+	forEachMem(t, func(t *testing.T, db *MuxDB) {
+		tr := db.NewTrie("test", trie.Root{})
 
-	key := []byte("test-key")
-	value := []byte("test-value")
+		key := []byte("test-key")
+		value := []byte("test-value")
 
-	err := tr.Update(key, value, nil)
-	assert.Nil(t, err)
+		err := tr.Update(key, value, nil)
+		assert.Nil(t, err)
 
-	ver := trie.Version{Major: 1}
-	err = tr.Commit(ver, false)
-	assert.Nil(t, err)
+		ver := trie.Version{Major: 1}
+		err = tr.Commit(ver, false)
+		assert.Nil(t, err)
 
-	root := tr.Hash()
-	tr2 := db.NewTrie("test", trie.Root{Hash: root, Ver: ver})
+		root := tr.Hash()
+		tr2 := db.NewTrie("test", trie.Root{Hash: root, Ver: ver})
 
-	val, _, err := tr2.Get(key)
-	assert.Nil(t, err)
-	assert.Equal(t, value, val)
+		val, _, err := tr2.Get(key)
+		assert.Nil(t, err)
+		assert.Equal(t, value, val)
+	})
 }
 
 func TestEnableMetrics(t *testing.T) {
@@ -212,28 +222,55 @@ func TestEnableMetrics(t *testing.T) {
 }
 
 func TestMultipleStores(t *testing.T) {
-	db := NewMem()
-	defer db.Close()
+	forEachMem(t, func(t *testing.T, db *MuxDB) {
+		store1 := db.NewStore("store1")
+		store2 := db.NewStore("store2")
 
-	store1 := db.NewStore("store1")
-	store2 := db.NewStore("store2")
+		key := []byte("key")
+		val1 := []byte("val1")
+		val2 := []byte("val2")
 
-	key := []byte("key")
-	val1 := []byte("val1")
-	val2 := []byte("val2")
+		err := store1.Put(key, val1)
+		assert.Nil(t, err)
+		err = store2.Put(key, val2)
+		assert.Nil(t, err)
 
-	err := store1.Put(key, val1)
-	assert.Nil(t, err)
-	err = store2.Put(key, val2)
-	assert.Nil(t, err)
+		got1, err := store1.Get(key)
+		assert.Nil(t, err)
+		assert.Equal(t, val1, got1)
 
-	got1, err := store1.Get(key)
-	assert.Nil(t, err)
-	assert.Equal(t, val1, got1)
+		got2, err := store2.Get(key)
+		assert.Nil(t, err)
+		assert.Equal(t, val2, got2)
+	})
+}
 
-	got2, err := store2.Get(key)
-	assert.Nil(t, err)
-	assert.Equal(t, val2, got2)
+func TestCompact(t *testing.T) {
+	for _, engine := range engines {
+		db, err := Open(filepath.Join(t.TempDir(), engine), &Options{Engine: engine})
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		store := db.NewStore("store")
+		for i := range 100 {
+			assert.NoError(t, store.Put([]byte{byte(i)}, []byte{byte(i)}))
+		}
+		for i := range 50 {
+			assert.NoError(t, store.Delete([]byte{byte(i)}))
+		}
+
+		assert.NoError(t, db.Compact(), engine)
+		assert.NoError(t, db.Compact("named-store", "trie-hist"), engine)
+		assert.ErrorContains(t, db.Compact("unknown"), "unknown key space", engine)
+
+		for i := range 100 {
+			has, err := store.Has([]byte{byte(i)})
+			assert.NoError(t, err)
+			assert.Equal(t, i >= 50, has, engine)
+		}
+		assert.NoError(t, db.Close())
+	}
 }
 
 func TestDeleteTrieHistory(t *testing.T) {
@@ -253,4 +290,153 @@ func TestDeleteTrieHistory(t *testing.T) {
 
 	err = db.DeleteTrieHistoryNodes(context.Background(), 0, 2)
 	assert.Nil(t, err)
+	assert.Equal(t, uint32(2), db.TrieHistoryPrunedBefore())
+
+	// persisted, and aligned to partitions
+	path := filepath.Join(t.TempDir(), "test.db")
+	opts := Options{TrieHistPartitionFactor: 10, TrieDedupedPartitionFactor: 10}
+	db, err = Open(path, &opts)
+	assert.Nil(t, err)
+	assert.Nil(t, db.DeleteTrieHistoryNodes(context.Background(), 0, 25))
+	assert.Equal(t, uint32(20), db.TrieHistoryPrunedBefore())
+	assert.Nil(t, db.Close())
+
+	db, err = Open(path, &opts)
+	assert.Nil(t, err)
+	defer db.Close()
+	assert.Equal(t, uint32(20), db.TrieHistoryPrunedBefore())
+
+	// marked without alignment, and never backwards
+	assert.Nil(t, db.MarkTrieHistoryPruned(25))
+	assert.Equal(t, uint32(25), db.TrieHistoryPrunedBefore())
+	assert.Nil(t, db.MarkTrieHistoryPruned(21))
+	assert.Nil(t, db.DeleteTrieHistoryNodes(context.Background(), 0, 22))
+	assert.Equal(t, uint32(25), db.TrieHistoryPrunedBefore())
+}
+
+func TestOpenWithEngine(t *testing.T) {
+	opts := Options{
+		TrieHistPartitionFactor:    1000,
+		TrieDedupedPartitionFactor: 2000,
+		OpenFilesCacheCapacity:     16,
+		ReadCacheMB:                16,
+		WriteBufferMB:              16,
+	}
+
+	for _, tt := range []struct{ engine, other string }{
+		{EngineLevelDB, EnginePebble},
+		{EnginePebble, EngineLevelDB},
+	} {
+		t.Run(tt.engine, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "test.db")
+
+			opts := opts
+			opts.Engine = tt.engine
+			db, err := Open(path, &opts)
+			assert.Nil(t, err)
+			assert.Nil(t, db.NewStore("test").Put([]byte("key"), []byte("value")))
+			assert.Nil(t, db.Close())
+
+			// refused to open by another engine
+			opts.Engine = tt.other
+			_, err = Open(path, &opts)
+			assert.ErrorContains(t, err, "database created by engine "+tt.engine)
+
+			opts.Engine = tt.engine
+			db, err = Open(path, &opts)
+			assert.Nil(t, err)
+			defer db.Close()
+			val, err := db.NewStore("test").Get([]byte("key"))
+			assert.Nil(t, err)
+			assert.Equal(t, []byte("value"), val)
+
+			cfg := config{}
+			assert.Nil(t, cfg.LoadOrSave(db.NewStore(propStoreName)))
+			assert.Equal(t, config{1000, 2000, tt.engine}, cfg)
+		})
+	}
+
+	opts.Engine = "unknown"
+	_, err := Open(filepath.Join(t.TempDir(), "test.db"), &opts)
+	assert.ErrorContains(t, err, "unsupported engine")
+}
+
+func TestOpenLegacyConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.db")
+	opts := Options{TrieHistPartitionFactor: 1000, TrieDedupedPartitionFactor: 2000}
+
+	db, err := Open(path, &opts)
+	assert.Nil(t, err)
+	// config saved without engine
+	assert.Nil(t, db.NewStore(propStoreName).Put([]byte(configKey), []byte(`{"HistPtnFactor":1000,"DedupedPtnFactor":2000}`)))
+	assert.Nil(t, db.Close())
+
+	opts.Engine = EngineLevelDB
+	db, err = Open(path, &opts)
+	assert.Nil(t, err)
+	assert.Nil(t, db.Close())
+}
+
+func TestStoreIterate(t *testing.T) {
+	forEachMem(t, func(t *testing.T, db *MuxDB) {
+		store := db.NewStore("test")
+		for i := range 10 {
+			assert.Nil(t, store.Put([]byte{byte(i)}, []byte{byte(i)}))
+		}
+
+		iter := store.Iterate(kv.Range{Start: []byte{2}, Limit: []byte{5}})
+		var keys []byte
+		for iter.Next() {
+			keys = append(keys, iter.Key()...)
+			assert.Equal(t, iter.Key(), iter.Value())
+		}
+		assert.Nil(t, iter.Error())
+		assert.Equal(t, []byte{2, 3, 4}, keys)
+
+		assert.True(t, iter.Last())
+		assert.Equal(t, []byte{4}, iter.Key())
+		assert.True(t, iter.Prev())
+		assert.Equal(t, []byte{3}, iter.Key())
+		iter.Release()
+		assert.False(t, iter.Next())
+
+		// reversed
+		iter = store.Iterate(kv.Range{})
+		assert.False(t, iter.Prev())
+		keys = keys[:0]
+		for ok := iter.Last(); ok; ok = iter.Prev() {
+			keys = append(keys, iter.Key()...)
+		}
+		iter.Release()
+		assert.Equal(t, []byte{9, 8, 7, 6, 5, 4, 3, 2, 1, 0}, keys)
+
+		// bulk and delete range
+		bulk := store.Bulk()
+		bulk.EnableAutoFlush()
+		for i := 10; i < 20; i++ {
+			assert.Nil(t, bulk.Put([]byte{byte(i)}, []byte{byte(i)}))
+		}
+		assert.Nil(t, bulk.Delete([]byte{0}))
+		assert.Nil(t, bulk.Write())
+
+		assert.Nil(t, store.DeleteRange(context.Background(), kv.Range{Start: []byte{5}, Limit: []byte{15}}))
+		iter = store.Iterate(kv.Range{})
+		keys = keys[:0]
+		for iter.Next() {
+			keys = append(keys, iter.Key()...)
+		}
+		iter.Release()
+		assert.Equal(t, []byte{1, 2, 3, 4, 15, 16, 17, 18, 19}, keys)
+
+		// snapshot
+		snapshot := store.Snapshot()
+		defer snapshot.Release()
+		assert.Nil(t, store.Delete([]byte{1}))
+		has, err := snapshot.Has([]byte{1})
+		assert.Nil(t, err)
+		assert.True(t, has)
+		has, err = store.Has([]byte{1})
+		assert.Nil(t, err)
+		assert.False(t, has)
+	})
 }
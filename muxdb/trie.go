@@ -110,6 +110,11 @@ func (t *Trie) Get(key []byte) ([]byte, []byte, error) {
 	return t.trie.Get(key)
 }
 
+// Prove constructs a merkle proof for key, see trie.Trie.Prove for details.
+func (t *Trie) Prove(key []byte) ([][]byte, error) {
+	return t.trie.Prove(key)
+}
+
 // Update associates key with value in the trie. Subsequent calls to
 // Get will return value. If value has length zero, any existing value
 // is deleted from the trie and calls to Get will return nil.
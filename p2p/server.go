@@ -143,6 +143,10 @@ type Config struct {
 
 	// Logger is a custom logger to use with the p2p.Server.
 	Logger log.Logger `toml:",omitempty"`
+
+	// If MeterConn is set, connections are wrapped by it before the RLPx handshakes,
+	// so that the traffic on the wire can be metered.
+	MeterConn func(net.Conn) net.Conn `toml:"-"`
 }
 
 // Server manages all peer connections.
@@ -851,6 +855,9 @@ func (srv *Server) SetupConn(fd net.Conn, flags connFlag, dialDest *discover.Nod
 	if self == nil {
 		return errors.New("shutdown")
 	}
+	if srv.MeterConn != nil {
+		fd = srv.MeterConn(fd)
+	}
 	c := &conn{fd: fd, transport: srv.newTransport(fd), flags: flags, cont: make(chan error)}
 	err := srv.setupConn(c, flags, dialDest)
 	if err != nil {
@@ -0,0 +1,189 @@
+// Copyright (c) 2025 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package p2psrv
+
+import (
+	"cmp"
+	"net"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/pkg/errors"
+
+	"github.com/vechain/thor/v2/p2p/discover"
+)
+
+// Ban bans a node, or nodes in an IP range, until the expiry.
+type Ban struct {
+	Target string // node ID in hex, IP address or IP range in CIDR notation
+	Expiry uint64 // unix timestamp
+}
+
+// banTarget is the parsed form of Ban.Target.
+type banTarget struct {
+	id  *discover.NodeID
+	net *net.IPNet
+}
+
+func parseBanTarget(target string) (*banTarget, error) {
+	if strings.Contains(target, "/") {
+		_, n, err := net.ParseCIDR(target)
+		if err != nil {
+			return nil, err
+		}
+		return &banTarget{net: n}, nil
+	}
+	if ip := net.ParseIP(target); ip != nil {
+		bits := 8 * net.IPv6len
+		if ip4 := ip.To4(); ip4 != nil {
+			ip, bits = ip4, 8*net.IPv4len
+		}
+		return &banTarget{net: &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)}}, nil
+	}
+	id, err := discover.HexID(target)
+	if err != nil {
+		return nil, errors.New("neither node ID nor IP range")
+	}
+	return &banTarget{id: &id}, nil
+}
+
+func (t *banTarget) match(id discover.NodeID, ip net.IP) bool {
+	if t.id != nil {
+		return *t.id == id
+	}
+	return ip != nil && t.net.Contains(ip)
+}
+
+// banList keeps bans, and persists them into the file if path is set.
+type banList struct {
+	path    string
+	lock    sync.Mutex
+	bans    map[string]*Ban // by target
+	targets map[string]*banTarget
+}
+
+func newBanList(path string) *banList {
+	l := &banList{
+		path:    path,
+		bans:    make(map[string]*Ban),
+		targets: make(map[string]*banTarget),
+	}
+	if path == "" {
+		return l
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			logger.Warn("failed to load ban list", "err", err)
+		}
+		return l
+	}
+	var bans []*Ban
+	if err := rlp.DecodeBytes(data, &bans); err != nil {
+		logger.Warn("failed to decode ban list", "err", err)
+		return l
+	}
+	for _, ban := range bans {
+		if target, err := parseBanTarget(ban.Target); err == nil {
+			l.bans[ban.Target] = ban
+			l.targets[ban.Target] = target
+		}
+	}
+	return l
+}
+
+// add adds or renews the ban.
+func (l *banList) add(target string, duration time.Duration) (*banTarget, error) {
+	t, err := parseBanTarget(target)
+	if err != nil {
+		return nil, err
+	}
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	l.bans[target] = &Ban{Target: target, Expiry: uint64(time.Now().Add(duration).Unix())}
+	l.targets[target] = t
+	return t, l.save()
+}
+
+// remove removes the ban, and returns whether it was present.
+func (l *banList) remove(target string) (bool, error) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if _, ok := l.bans[target]; !ok {
+		return false, nil
+	}
+	delete(l.bans, target)
+	delete(l.targets, target)
+	return true, l.save()
+}
+
+// list returns bans not expired, ordered by expiry.
+func (l *banList) list() []*Ban {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	l.purge()
+	bans := make([]*Ban, 0, len(l.bans))
+	for _, ban := range l.bans {
+		cpy := *ban
+		bans = append(bans, &cpy)
+	}
+	slices.SortFunc(bans, func(a, b *Ban) int {
+		if c := cmp.Compare(a.Expiry, b.Expiry); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Target, b.Target)
+	})
+	return bans
+}
+
+// banned returns whether the node is banned. ip can be nil if unknown.
+func (l *banList) banned(id discover.NodeID, ip net.IP) bool {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	l.purge()
+	for _, t := range l.targets {
+		if t.match(id, ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// purge removes expired bans. The file is left as is, expired bans loaded are purged on use.
+func (l *banList) purge() {
+	now := uint64(time.Now().Unix())
+	for target, ban := range l.bans {
+		if ban.Expiry <= now {
+			delete(l.bans, target)
+			delete(l.targets, target)
+		}
+	}
+}
+
+func (l *banList) save() error {
+	if l.path == "" {
+		return nil
+	}
+	bans := make([]*Ban, 0, len(l.bans))
+	for _, ban := range l.bans {
+		bans = append(bans, ban)
+	}
+	data, err := rlp.EncodeToBytes(bans)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(l.path, data, 0o600)
+}
@@ -0,0 +1,67 @@
+// Copyright (c) 2025 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package p2psrv
+
+import (
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vechain/thor/v2/p2p/discover"
+)
+
+func TestBans(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	id := discover.PubkeyID(&key.PublicKey)
+	other := discover.NodeID{1}
+
+	path := filepath.Join(t.TempDir(), "peers.bans")
+	srv := New(&Options{BanListPath: path})
+
+	assert.Error(t, srv.Ban("invalid", time.Hour))
+	assert.Error(t, srv.Ban("10.0.0.0/33", time.Hour))
+
+	require.NoError(t, srv.Ban(id.String(), time.Hour))
+	require.NoError(t, srv.Ban("10.0.0.0/8", 2*time.Hour))
+	require.NoError(t, srv.Ban("192.168.1.1", 3*time.Hour))
+	require.NoError(t, srv.Ban("::1", 4*time.Hour))
+
+	assert.True(t, srv.bans.banned(id, nil))
+	assert.True(t, srv.bans.banned(other, net.ParseIP("10.1.2.3")))
+	assert.True(t, srv.bans.banned(other, net.ParseIP("192.168.1.1")))
+	assert.True(t, srv.bans.banned(other, net.ParseIP("::1")))
+	assert.False(t, srv.bans.banned(other, net.ParseIP("192.168.1.2")))
+	assert.False(t, srv.bans.banned(other, nil))
+	assert.Equal(t, errBannedPeer, srv.TryDial(discover.NewNode(id, net.ParseIP("1.1.1.1"), 0, 0)))
+
+	bans := srv.Bans()
+	require.Len(t, bans, 4)
+	assert.Equal(t, id.String(), bans[0].Target)
+
+	// persisted
+	srv = New(&Options{BanListPath: path})
+	assert.Equal(t, bans, srv.Bans())
+
+	ok, err := srv.Unban(id.String())
+	require.NoError(t, err)
+	assert.True(t, ok)
+	ok, err = srv.Unban(id.String())
+	require.NoError(t, err)
+	assert.False(t, ok)
+	assert.False(t, srv.bans.banned(id, nil))
+	assert.Len(t, New(&Options{BanListPath: path}).Bans(), 3)
+
+	// expired
+	require.NoError(t, srv.Ban(other.String(), -time.Second))
+	assert.False(t, srv.bans.banned(other, nil))
+	assert.Len(t, srv.Bans(), 3)
+}
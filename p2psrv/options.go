@@ -7,6 +7,7 @@ package p2psrv
 
 import (
 	"crypto/ecdsa"
+	"net"
 
 	"github.com/vechain/thor/v2/p2p/nat"
 	"github.com/vechain/thor/v2/p2p/netutil"
@@ -60,4 +61,10 @@ type Options struct {
 
 	// If NoDial is true, the server will not dial any peers.
 	NoDial bool
+
+	// BanListPath is the file to persist banned nodes. Bans are kept in memory only if empty.
+	BanListPath string
+
+	// MeterConn wraps connections to meter the traffic on the wire, if not nil.
+	MeterConn func(net.Conn) net.Conn
 }
@@ -20,6 +20,7 @@ import (
 
 const (
 	rpcDefaultTimeout = time.Second * 10
+	rpcLatencyEMA     = 0.2 // weight of the latest sample in the moving average of call latency
 )
 
 var (
@@ -39,6 +40,10 @@ type RPC struct {
 	pendings map[uint32]*resultListener
 	lock     sync.Mutex
 	logger   log.Logger
+	stats    struct {
+		sync.Mutex
+		CallStats
+	}
 }
 
 // New create a new RPC instance.
@@ -180,8 +185,32 @@ func (r *RPC) Notify(_ context.Context, msgCode uint64, arg any) error {
 	return p2p.Send(r.rw, msgCode, &msgData{0, false, arg})
 }
 
+// CallStats returns the stats of calls made to the peer.
+func (r *RPC) CallStats() CallStats {
+	r.stats.Lock()
+	defer r.stats.Unlock()
+	return r.stats.CallStats
+}
+
+func (r *RPC) recordCall(latency time.Duration, failed bool) {
+	r.stats.Lock()
+	defer r.stats.Unlock()
+
+	r.stats.Calls++
+	if failed {
+		r.stats.Failures++
+		return
+	}
+	if r.stats.Latency == 0 {
+		r.stats.Latency = latency
+	} else {
+		r.stats.Latency = time.Duration(float64(r.stats.Latency)*(1-rpcLatencyEMA) + float64(latency)*rpcLatencyEMA)
+	}
+}
+
 // Call send a call to the peer and wait for result.
 func (r *RPC) Call(ctx context.Context, msgCode uint64, arg any, result any) error {
+	parent := ctx
 	ctx, cancel := context.WithTimeout(ctx, rpcDefaultTimeout)
 	defer cancel()
 
@@ -197,6 +226,7 @@ func (r *RPC) Call(ctx context.Context, msgCode uint64, arg any, result any) err
 	})
 	defer r.finalizeCall(id)
 
+	start := time.Now()
 	if err := p2p.Send(r.rw, msgCode, &msgData{id, false, arg}); err != nil {
 		return err
 	}
@@ -205,8 +235,13 @@ func (r *RPC) Call(ctx context.Context, msgCode uint64, arg any, result any) err
 	case <-r.doneCh:
 		return errPeerDisconnected
 	case <-ctx.Done():
+		// calls canceled by the caller are not counted
+		if parent.Err() == nil {
+			r.recordCall(0, true)
+		}
 		return ctx.Err()
 	case err := <-errCh:
+		r.recordCall(time.Since(start), err != nil)
 		return err
 	}
 }
@@ -5,7 +5,11 @@
 
 package rpc
 
-import "github.com/vechain/thor/v2/p2p"
+import (
+	"time"
+
+	"github.com/vechain/thor/v2/p2p"
+)
 
 type msgData struct {
 	ID       uint32
@@ -17,3 +21,10 @@ type resultListener struct {
 	msgCode  uint64
 	onResult func(*p2p.Msg) error
 }
+
+// CallStats summarizes calls made to the peer.
+type CallStats struct {
+	Calls    uint64        // count of calls with the result or timed out
+	Failures uint64        // count of calls timed out or with undecodable result
+	Latency  time.Duration // moving average of latency of succeeded calls
+}
@@ -12,6 +12,7 @@ import (
 	"net"
 	"slices"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common/mclock"
@@ -24,7 +25,10 @@ import (
 	"github.com/vechain/thor/v2/log"
 )
 
-var logger = log.WithContext("pkg", "p2psrv")
+var (
+	logger        = log.WithContext("pkg", "p2psrv")
+	errBannedPeer = errors.New("banned peer")
+)
 
 // Server p2p server wraps ethereum's p2p.Server, and handles discovery v5 stuff.
 type Server struct {
@@ -37,6 +41,11 @@ type Server struct {
 	knownNodes      *cache.PrioCache
 	discoveredNodes *cache.RandCache
 	dialingNodes    *nodeMap
+	bans            *banList
+	started         atomic.Bool
+	pendingLock     sync.Mutex // guards pending nodes and the start of the server
+	pendingStatic   map[discover.NodeID]*discover.Node
+	pendingTrusted  map[discover.NodeID]*discover.Node
 }
 
 // New create a p2p server.
@@ -62,12 +71,16 @@ func New(opts *Options) *Server {
 				NAT:         opts.NAT,
 				NoDial:      opts.NoDial,
 				DialRatio:   int(math.Sqrt(float64(opts.MaxPeers))),
+				MeterConn:   opts.MeterConn,
 			},
 		},
 		done:            make(chan struct{}),
 		knownNodes:      knownNodes,
 		discoveredNodes: discoveredNodes,
 		dialingNodes:    newNodeMap(),
+		bans:            newBanList(opts.BanListPath),
+		pendingStatic:   make(map[discover.NodeID]*discover.Node),
+		pendingTrusted:  make(map[discover.NodeID]*discover.Node),
 	}
 }
 
@@ -89,6 +102,10 @@ func (s *Server) Start(protocols []*p2p.Protocol, topic discv5.Topic) error {
 			}
 			log := logger.New("peer", peer, "dir", dir)
 
+			if s.bans.banned(peer.ID(), remoteIP(peer)) {
+				log.Debug("banned peer rejected")
+				return errBannedPeer
+			}
 			log.Trace("peer connected")
 			metricConnectedPeers().Add(1)
 
@@ -106,7 +123,7 @@ func (s *Server) Start(protocols []*p2p.Protocol, topic discv5.Topic) error {
 		s.srv.Protocols = append(s.srv.Protocols, cpy)
 	}
 
-	if err := s.srv.Start(); err != nil {
+	if err := s.startServer(); err != nil {
 		return err
 	}
 	if !s.opts.NoDiscovery {
@@ -132,6 +149,24 @@ func (s *Server) Start(protocols []*p2p.Protocol, topic discv5.Topic) error {
 	return nil
 }
 
+// startServer starts the inner server with the nodes added before.
+func (s *Server) startServer() error {
+	s.pendingLock.Lock()
+	defer s.pendingLock.Unlock()
+
+	for _, node := range s.pendingStatic {
+		s.srv.StaticNodes = append(s.srv.StaticNodes, node)
+	}
+	for _, node := range s.pendingTrusted {
+		s.srv.TrustedNodes = append(s.srv.TrustedNodes, node)
+	}
+	if err := s.srv.Start(); err != nil {
+		return err
+	}
+	s.started.Store(true)
+	return nil
+}
+
 // Stop stop the server.
 func (s *Server) Stop() {
 	if s.discv5 != nil {
@@ -154,14 +189,90 @@ func (s *Server) KnownNodes() Nodes {
 
 // AddStatic connects to the given node and maintains the connection until the
 // server is shut down. If the connection fails for any reason, the server will
-// attempt to reconnect the peer.
+// attempt to reconnect the peer. Nodes added before the server started are connected once started.
 func (s *Server) AddStatic(node *discover.Node) {
-	s.srv.AddPeer(node)
+	s.pendingLock.Lock()
+	defer s.pendingLock.Unlock()
+
+	if s.started.Load() {
+		s.srv.AddPeer(node)
+	} else {
+		s.pendingStatic[node.ID] = node
+	}
 }
 
 // RemoveStatic disconnects from the given node
 func (s *Server) RemoveStatic(node *discover.Node) {
-	s.srv.RemovePeer(node)
+	s.pendingLock.Lock()
+	defer s.pendingLock.Unlock()
+
+	if s.started.Load() {
+		s.srv.RemovePeer(node)
+	} else {
+		delete(s.pendingStatic, node.ID)
+	}
+}
+
+// AddTrusted adds the given node to the trusted set, which is allowed to connect even above the peer limit.
+// Nodes added before the server started are trusted once started.
+func (s *Server) AddTrusted(node *discover.Node) {
+	s.pendingLock.Lock()
+	defer s.pendingLock.Unlock()
+
+	if s.started.Load() {
+		s.srv.AddTrustedPeer(node)
+	} else {
+		s.pendingTrusted[node.ID] = node
+	}
+}
+
+// RemoveTrusted removes the given node from the trusted set.
+func (s *Server) RemoveTrusted(node *discover.Node) {
+	s.pendingLock.Lock()
+	defer s.pendingLock.Unlock()
+
+	if s.started.Load() {
+		s.srv.RemoveTrustedPeer(node)
+	} else {
+		delete(s.pendingTrusted, node.ID)
+	}
+}
+
+// PeersInfo returns metadata of connected peers.
+// Only available when server is running.
+func (s *Server) PeersInfo() []*p2p.PeerInfo {
+	if !s.started.Load() {
+		return nil
+	}
+	return s.srv.PeersInfo()
+}
+
+// Ban bans the target for the duration, and disconnects peers banned.
+// The target is a node ID in hex, an IP address or an IP range in CIDR notation.
+func (s *Server) Ban(target string, duration time.Duration) error {
+	t, err := s.bans.add(target, duration)
+	if err != nil {
+		return err
+	}
+	if !s.started.Load() {
+		return nil
+	}
+	for _, peer := range s.srv.Peers() {
+		if t.match(peer.ID(), remoteIP(peer)) {
+			peer.Disconnect(p2p.DiscUselessPeer)
+		}
+	}
+	return nil
+}
+
+// Unban lifts the ban of the target. It returns false if the target is not banned.
+func (s *Server) Unban(target string) (bool, error) {
+	return s.bans.remove(target)
+}
+
+// Bans returns bans in effect.
+func (s *Server) Bans() []*Ban {
+	return s.bans.list()
 }
 
 // NodeInfo gathers and returns a collection of metadata known about the host.
@@ -179,6 +290,9 @@ func (s *Server) TryDial(node *discover.Node) error {
 	if s.dialingNodes.Contains(node.ID) {
 		return nil
 	}
+	if s.bans.banned(node.ID, node.IP) {
+		return errBannedPeer
+	}
 
 	// Record the manual dialing node for future dial ratio calculation.
 	// But the dial ratio limit is not applied to manual dialing.
@@ -322,6 +436,10 @@ func (s *Server) dialLoop() {
 			if s.dialingNodes.Contains(node.ID) {
 				continue
 			}
+			if s.bans.banned(node.ID, node.IP) {
+				s.discoveredNodes.Remove(node.ID)
+				continue
+			}
 
 			log := logger.New("node", node)
 			log.Debug("try to dial node")
@@ -393,3 +511,10 @@ func (s *Server) fetchBootstrap() {
 		}
 	}
 }
+
+func remoteIP(peer *p2p.Peer) net.IP {
+	if addr, ok := peer.RemoteAddr().(*net.TCPAddr); ok {
+		return addr.IP
+	}
+	return nil
+}
@@ -79,3 +79,41 @@ func TestNewServerConnectOnly(t *testing.T) {
 	assert.True(t, server.discoveredNodes.Contains(knownNode.ID))
 	assert.True(t, server.knownNodes.Contains(knownNode.ID))
 }
+
+func TestServerNodesAddedBeforeStart(t *testing.T) {
+	privateKey, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("Unable to generate private key: %v", err)
+	}
+	server := New(&Options{
+		Name:        "testNode",
+		PrivateKey:  privateKey,
+		MaxPeers:    10,
+		ListenAddr:  "127.0.0.1:0",
+		NoDial:      true,
+		NoDiscovery: true,
+	})
+
+	static := discover.MustParseNode(
+		"enode://1234cf28ab5f0255a3923ac094d0168ce884a9fa5f3998b1844986b4a2b1eac52fcccd8f2916be9b8b0f7798147ee5592ec3c83518925fac50f812577515d6ad@10.3.58.6:30303",
+	)
+	removed := discover.MustParseNode(
+		"enode://5678cf28ab5f0255a3923ac094d0168ce884a9fa5f3998b1844986b4a2b1eac52fcccd8f2916be9b8b0f7798147ee5592ec3c83518925fac50f812577515d6ad@10.3.58.7:30303",
+	)
+	server.AddStatic(static)
+	server.AddStatic(removed)
+	server.RemoveStatic(removed)
+	server.AddTrusted(removed)
+
+	if err := server.Start(nil, "test"); err != nil {
+		t.Fatalf("Unable to start server: %v", err)
+	}
+	defer server.Stop()
+
+	assert.Equal(t, []*discover.Node{static}, server.srv.StaticNodes)
+	assert.Equal(t, []*discover.Node{removed}, server.srv.TrustedNodes)
+
+	// applied to the running server without blocking
+	server.AddStatic(removed)
+	server.RemoveTrusted(removed)
+}
@@ -24,6 +24,16 @@ func IsTxNotAdoptableNow(err error) bool {
 	return errors.Is(err, errTxNotAdoptableNow)
 }
 
+// IsTxNotAdoptableForever tx can never be adopted, e.g. its dependency reverted.
+func IsTxNotAdoptableForever(err error) bool {
+	return errors.Is(err, errTxNotAdoptableForever)
+}
+
+// IsKnownTx tx is already in the chain or the flow.
+func IsKnownTx(err error) bool {
+	return errors.Is(err, errKnownTx)
+}
+
 // IsBadTx not a valid tx.
 func IsBadTx(err error) bool {
 	return errors.As(err, &badTxError{})
@@ -9,6 +9,7 @@ import (
 	"crypto/ecdsa"
 	"errors"
 	"fmt"
+	"math/big"
 
 	"github.com/ethereum/go-ethereum/crypto"
 
@@ -27,6 +28,7 @@ type Flow struct {
 	packer       *Packer
 	parentHeader *block.Header
 	runtime      *runtime.Runtime
+	prefetcher   *state.Prefetcher
 	processedTxs map[thor.Bytes32]bool // txID -> reverted
 	gasUsed      uint64
 	txs          tx.Transactions
@@ -39,6 +41,7 @@ func newFlow(
 	packer *Packer,
 	parentHeader *block.Header,
 	runtime *runtime.Runtime,
+	prefetcher *state.Prefetcher,
 	features tx.Features,
 	posActive bool,
 ) *Flow {
@@ -46,6 +49,7 @@ func newFlow(
 		packer:       packer,
 		parentHeader: parentHeader,
 		runtime:      runtime,
+		prefetcher:   prefetcher,
 		processedTxs: make(map[thor.Bytes32]bool),
 		features:     features,
 		posActive:    posActive,
@@ -72,6 +76,12 @@ func (f *Flow) TotalScore() uint64 {
 	return f.runtime.Context().TotalScore
 }
 
+// Prefetch loads accounts to be accessed by the txs in background,
+// to speed up adopting them later.
+func (f *Flow) Prefetch(txs tx.Transactions) {
+	f.prefetcher.PrefetchTxs(txs)
+}
+
 func (f *Flow) findDep(txID thor.Bytes32) (found bool, reverted bool, err error) {
 	if reverted, ok := f.processedTxs[txID]; ok {
 		return true, reverted, nil
@@ -207,22 +217,52 @@ func (f *Flow) Adopt(t *tx.Transaction) error {
 	return nil
 }
 
-// Pack build and sign the new block.
-func (f *Flow) Pack(privateKey *ecdsa.PrivateKey, newBlockConflicts uint32, shouldVote bool) (*block.Block, *state.Stage, tx.Receipts, error) {
-	if f.packer.nodeMaster != thor.Address(crypto.PubkeyToAddress(privateKey.PublicKey)) {
-		return nil, nil, nil, errors.New("private key mismatch")
-	}
+// Beneficiary returns beneficiary of new block.
+func (f *Flow) Beneficiary() thor.Address {
+	return f.runtime.Context().Beneficiary
+}
+
+// BaseFee returns base fee of new block, nil before galactica fork.
+func (f *Flow) BaseFee() *big.Int {
+	return f.runtime.Context().BaseFee
+}
 
+// GasLimit returns gas limit of new block.
+func (f *Flow) GasLimit() uint64 {
+	return f.runtime.Context().GasLimit
+}
+
+// GasUsed returns gas used by adopted txs.
+func (f *Flow) GasUsed() uint64 {
+	return f.gasUsed
+}
+
+// Receipts returns receipts of adopted txs.
+func (f *Flow) Receipts() tx.Receipts {
+	return f.receipts
+}
+
+// Stage distributes block rewards and stages the state changes of the adopted txs,
+// without building and signing the block. The flow should not be used afterwards.
+func (f *Flow) Stage(newBlockConflicts uint32) (*state.Stage, error) {
 	if f.posActive {
-		signer := crypto.PubkeyToAddress(privateKey.PublicKey)
 		staker := builtin.Staker.Native(f.runtime.State())
 		energy := builtin.Energy.Native(f.runtime.State(), f.runtime.Context().Time)
-		if err := energy.DistributeRewards(f.runtime.Context().Beneficiary, thor.Address(signer), staker, f.Number()); err != nil {
-			return nil, nil, nil, err
+		if err := energy.DistributeRewards(f.runtime.Context().Beneficiary, f.packer.nodeMaster, staker, f.Number()); err != nil {
+			return nil, err
 		}
 	}
 
-	stage, err := f.runtime.State().Stage(trie.Version{Major: f.Number(), Minor: newBlockConflicts})
+	return f.runtime.State().Stage(trie.Version{Major: f.Number(), Minor: newBlockConflicts})
+}
+
+// Pack build and sign the new block.
+func (f *Flow) Pack(privateKey *ecdsa.PrivateKey, newBlockConflicts uint32, shouldVote bool) (*block.Block, *state.Stage, tx.Receipts, error) {
+	if f.packer.nodeMaster != thor.Address(crypto.PubkeyToAddress(privateKey.PublicKey)) {
+		return nil, nil, nil, errors.New("private key mismatch")
+	}
+
+	stage, err := f.Stage(newBlockConflicts)
 	if err != nil {
 		return nil, nil, nil, err
 	}
@@ -99,7 +99,9 @@ func (p *Packer) Schedule(parent *chain.BlockSummary, nowTimestamp uint64) (*Flo
 		},
 		p.forkConfig)
 
-	return newFlow(p, parent.Header, rt, features, dPosStatus.Active), nil
+	prefetcher := p.stater.NewPrefetcher(parent.Root())
+	st.UsePrefetcher(prefetcher)
+	return newFlow(p, parent.Header, rt, prefetcher, features, dPosStatus.Active), nil
 }
 
 // Mock create a packing flow upon given parent, but with a designated timestamp.
@@ -193,7 +195,9 @@ func (p *Packer) Mock(parent *chain.BlockSummary, targetTime uint64, gasLimit ui
 		},
 		p.forkConfig)
 
-	return newFlow(p, parent.Header, rt, features, dPosStatus.Active), dPosStatus.Active, nil
+	prefetcher := p.stater.NewPrefetcher(parent.Root())
+	state.UsePrefetcher(prefetcher)
+	return newFlow(p, parent.Header, rt, prefetcher, features, dPosStatus.Active), dPosStatus.Active, nil
 }
 
 func (p *Packer) gasLimit(parentGasLimit uint64) uint64 {
@@ -0,0 +1,132 @@
+// Copyright (c) 2025 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package packer
+
+import (
+	"fmt"
+	"math/big"
+	"slices"
+
+	"github.com/vechain/thor/v2/builtin"
+	"github.com/vechain/thor/v2/thor"
+	"github.com/vechain/thor/v2/tx"
+)
+
+// Names of the built-in tx selectors.
+const (
+	TxSelectorDefault     = "default"
+	TxSelectorPriorityFee = "priority-fee"
+	TxSelectorFairShare   = "fair-share"
+)
+
+// TxSelector decides the order in which candidate txs are offered to Flow.Adopt.
+// Txs not returned are not packed into the block.
+type TxSelector interface {
+	Select(flow *Flow, txs tx.Transactions) tx.Transactions
+}
+
+// NewTxSelector creates the built-in tx selector for the given name.
+func NewTxSelector(name string) (TxSelector, error) {
+	switch name {
+	case "", TxSelectorDefault:
+		return DefaultTxSelector{}, nil
+	case TxSelectorPriorityFee:
+		return PriorityFeeTxSelector{}, nil
+	case TxSelectorFairShare:
+		return FairShareTxSelector{}, nil
+	default:
+		return nil, fmt.Errorf("unknown tx selector %q", name)
+	}
+}
+
+// DefaultTxSelector keeps the order of the candidates, which is the order of the tx pool.
+type DefaultTxSelector struct{}
+
+// Select implements TxSelector.
+func (DefaultTxSelector) Select(_ *Flow, txs tx.Transactions) tx.Transactions {
+	return txs
+}
+
+// PriorityFeeTxSelector orders the candidates by effective priority fee per gas in descending order,
+// keeping the pool order among equal fees. A tx ordered before the one it depends on is not
+// adoptable at its turn, and is skipped.
+type PriorityFeeTxSelector struct{}
+
+// Select implements TxSelector.
+func (PriorityFeeTxSelector) Select(flow *Flow, txs tx.Transactions) tx.Transactions {
+	fees := flow.priorityFees(txs)
+
+	sorted := slices.Clone(txs)
+	slices.SortStableFunc(sorted, func(a, b *tx.Transaction) int {
+		return fees[b.ID()].Cmp(fees[a.ID()])
+	})
+	return sorted
+}
+
+// FairShareTxSelector interleaves the candidates of different origins in a round-robin
+// manner, so that a single busy origin cannot take up the whole block.
+// The relative order of txs from the same origin is kept.
+type FairShareTxSelector struct{}
+
+// Select implements TxSelector.
+func (FairShareTxSelector) Select(_ *Flow, txs tx.Transactions) tx.Transactions {
+	var (
+		origins []thor.Address
+		queues  = make(map[thor.Address]tx.Transactions)
+	)
+	for _, t := range txs {
+		origin, err := t.Origin()
+		if err != nil {
+			// left to Flow.Adopt to reject
+			origin = thor.Address{}
+		}
+		if _, ok := queues[origin]; !ok {
+			origins = append(origins, origin)
+		}
+		queues[origin] = append(queues[origin], t)
+	}
+
+	selected := make(tx.Transactions, 0, len(txs))
+	for len(selected) < len(txs) {
+		for _, origin := range origins {
+			if q := queues[origin]; len(q) > 0 {
+				selected = append(selected, q[0])
+				queues[origin] = q[1:]
+			}
+		}
+	}
+	return selected
+}
+
+// priorityFees returns the effective priority fee per gas of each tx if adopted by the flow.
+// Zero is assigned if it can't be determined.
+func (f *Flow) priorityFees(txs tx.Transactions) map[thor.Bytes32]*big.Int {
+	fees := make(map[thor.Bytes32]*big.Int, len(txs))
+
+	legacyTxBaseGasPrice, err := builtin.Params.Native(f.runtime.State()).Get(thor.KeyLegacyTxBaseGasPrice)
+	if err != nil {
+		for _, t := range txs {
+			fees[t.ID()] = &big.Int{}
+		}
+		return fees
+	}
+
+	baseFee := f.runtime.Context().BaseFee
+	if baseFee == nil {
+		// before galactica fork
+		baseFee = &big.Int{}
+	}
+
+	for _, t := range txs {
+		provedWork, err := t.ProvedWork(f.Number(), f.runtime.Chain().GetBlockID)
+		if err != nil {
+			fees[t.ID()] = &big.Int{}
+			continue
+		}
+		fees[t.ID()] = t.EffectivePriorityFeePerGas(baseFee, legacyTxBaseGasPrice, provedWork)
+	}
+	return fees
+}
@@ -0,0 +1,131 @@
+// Copyright (c) 2025 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package packer_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vechain/thor/v2/genesis"
+	"github.com/vechain/thor/v2/packer"
+	"github.com/vechain/thor/v2/test/testchain"
+	"github.com/vechain/thor/v2/thor"
+	"github.com/vechain/thor/v2/tx"
+)
+
+func newSelectorTestFlow(t *testing.T) (*testchain.Chain, *packer.Flow) {
+	chain, err := testchain.NewDefault()
+	require.NoError(t, err)
+
+	validator, ok := chain.NextValidator()
+	require.True(t, ok)
+
+	best := chain.Repo().BestBlockSummary()
+	p := packer.New(chain.Repo(), chain.Stater(), validator.Address, nil, chain.GetForkConfig(), 0)
+	flow, err := p.Schedule(best, best.Header.Timestamp()+thor.BlockInterval())
+	require.NoError(t, err)
+	return chain, flow
+}
+
+func newSelectorTestTx(chainTag byte, acc genesis.DevAccount, nonce uint64, priorityFee int64) *tx.Transaction {
+	to := thor.BytesToAddress([]byte("to"))
+	trx := tx.NewBuilder(tx.TypeDynamicFee).
+		ChainTag(chainTag).
+		MaxFeePerGas(big.NewInt(thor.InitialBaseFee * 10)).
+		MaxPriorityFeePerGas(big.NewInt(priorityFee)).
+		Expiration(100).
+		Gas(21000).
+		Nonce(nonce).
+		Clause(tx.NewClause(&to).WithValue(big.NewInt(1))).
+		Build()
+	return tx.MustSign(trx, acc.PrivateKey)
+}
+
+func TestNewTxSelector(t *testing.T) {
+	for name, expected := range map[string]packer.TxSelector{
+		"":                           packer.DefaultTxSelector{},
+		packer.TxSelectorDefault:     packer.DefaultTxSelector{},
+		packer.TxSelectorPriorityFee: packer.PriorityFeeTxSelector{},
+		packer.TxSelectorFairShare:   packer.FairShareTxSelector{},
+	} {
+		selector, err := packer.NewTxSelector(name)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, selector)
+	}
+
+	_, err := packer.NewTxSelector("unknown")
+	assert.Error(t, err)
+}
+
+func TestDefaultTxSelector(t *testing.T) {
+	chain, flow := newSelectorTestFlow(t)
+	accs := genesis.DevAccounts()
+
+	txs := tx.Transactions{
+		newSelectorTestTx(chain.ChainTag(), accs[1], 1, 10),
+		newSelectorTestTx(chain.ChainTag(), accs[2], 1, 1000),
+		newSelectorTestTx(chain.ChainTag(), accs[3], 1, 100),
+	}
+	assert.Equal(t, txs, packer.DefaultTxSelector{}.Select(flow, txs))
+}
+
+func TestPriorityFeeTxSelector(t *testing.T) {
+	chain, flow := newSelectorTestFlow(t)
+	accs := genesis.DevAccounts()
+
+	low := newSelectorTestTx(chain.ChainTag(), accs[1], 1, 10)
+	high := newSelectorTestTx(chain.ChainTag(), accs[2], 1, 1000)
+	mid := newSelectorTestTx(chain.ChainTag(), accs[3], 1, 100)
+	// capped by max fee per gas
+	capped := tx.MustSign(
+		tx.NewBuilder(tx.TypeDynamicFee).
+			ChainTag(chain.ChainTag()).
+			MaxFeePerGas(big.NewInt(thor.InitialBaseFee+50)).
+			MaxPriorityFeePerGas(big.NewInt(10000)).
+			Expiration(100).
+			Gas(21000).
+			Nonce(1).
+			Build(),
+		accs[4].PrivateKey,
+	)
+
+	txs := tx.Transactions{low, capped, high, mid}
+	selected := packer.PriorityFeeTxSelector{}.Select(flow, txs)
+	assert.Equal(t, tx.Transactions{high, mid, capped, low}, selected)
+	// input untouched
+	assert.Equal(t, tx.Transactions{low, capped, high, mid}, txs)
+
+	for _, trx := range selected {
+		assert.NoError(t, flow.Adopt(trx))
+	}
+	validator, _ := chain.NextValidator()
+	blk, stage, receipts, err := flow.Pack(validator.PrivateKey, 0, false)
+	assert.NoError(t, err)
+	assert.NoError(t, chain.CommitBlock(blk, stage, receipts))
+	assert.Equal(t, high.ID(), blk.Transactions()[0].ID())
+}
+
+func TestFairShareTxSelector(t *testing.T) {
+	chain, flow := newSelectorTestFlow(t)
+	accs := genesis.DevAccounts()
+
+	a1 := newSelectorTestTx(chain.ChainTag(), accs[1], 1, 10)
+	a2 := newSelectorTestTx(chain.ChainTag(), accs[1], 2, 10)
+	a3 := newSelectorTestTx(chain.ChainTag(), accs[1], 3, 10)
+	b1 := newSelectorTestTx(chain.ChainTag(), accs[2], 1, 10)
+	c1 := newSelectorTestTx(chain.ChainTag(), accs[3], 1, 10)
+	c2 := newSelectorTestTx(chain.ChainTag(), accs[3], 2, 10)
+
+	selected := packer.FairShareTxSelector{}.Select(flow, tx.Transactions{a1, a2, a3, b1, c1, c2})
+	assert.Equal(t, tx.Transactions{a1, b1, c1, a2, c2, a3}, selected)
+
+	for _, trx := range selected {
+		assert.NoError(t, flow.Adopt(trx))
+	}
+}
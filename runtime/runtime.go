@@ -415,9 +415,23 @@ func (rt *Runtime) ExecuteTransaction(tx *tx.Transaction) (receipt *tx.Receipt,
 
 // PrepareTransaction prepare to execute tx.
 func (rt *Runtime) PrepareTransaction(trx *tx.Transaction) (*TransactionExecutor, error) {
+	executor, _, err := rt.prepareTransaction(trx)
+	return executor, err
+}
+
+// txProgress is the progress of the clauses execution of a tx.
+type txProgress struct {
+	payer             thor.Address
+	effectiveGasPrice *big.Int
+	leftOverGas       uint64
+	outputs           []*tx.Output
+	reverted          bool
+}
+
+func (rt *Runtime) prepareTransaction(trx *tx.Transaction) (*TransactionExecutor, *txProgress, error) {
 	resolvedTx, err := ResolveTransaction(trx)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	legacyTxBaseGasPrice, effectiveGasPrice, payer, _, returnGas, err := resolvedTx.BuyGas(
@@ -426,38 +440,41 @@ func (rt *Runtime) PrepareTransaction(trx *tx.Transaction) (*TransactionExecutor
 		rt.ctx.BaseFee,
 	)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	txCtx, err := resolvedTx.ToContext(effectiveGasPrice, payer, rt.ctx.Number, rt.chain.GetBlockID)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	// ResolveTransaction has checked that tx.Gas() >= IntrinsicGas
-	leftOverGas := trx.Gas() - resolvedTx.IntrinsicGas
+	progress := &txProgress{
+		payer:             payer,
+		effectiveGasPrice: effectiveGasPrice,
+		// ResolveTransaction has checked that tx.Gas() >= IntrinsicGas
+		leftOverGas: trx.Gas() - resolvedTx.IntrinsicGas,
+		outputs:     make([]*tx.Output, 0, len(resolvedTx.Clauses)),
+	}
 	// checkpoint to be reverted when clause failure.
 	checkpoint := rt.state.NewCheckpoint()
 
-	txOutputs := make([]*tx.Output, 0, len(resolvedTx.Clauses))
-	reverted := false
 	finalized := false
 
 	hasNext := func() bool {
-		return !reverted && len(txOutputs) < len(resolvedTx.Clauses)
+		return !progress.reverted && len(progress.outputs) < len(resolvedTx.Clauses)
 	}
 
 	return &TransactionExecutor{
 		HasNextClause: hasNext,
 		PrepareNext: func() (exec func() (uint64, *Output, error), interrupt func()) {
-			nextClauseIndex := uint32(len(txOutputs))
-			execFunc, interrupt := rt.PrepareClause(resolvedTx.Clauses[nextClauseIndex], nextClauseIndex, leftOverGas, txCtx)
+			nextClauseIndex := uint32(len(progress.outputs))
+			execFunc, interrupt := rt.PrepareClause(resolvedTx.Clauses[nextClauseIndex], nextClauseIndex, progress.leftOverGas, txCtx)
 
 			exec = func() (gasUsed uint64, output *Output, err error) {
 				if rt.vmConfig.Tracer != nil {
-					rt.vmConfig.Tracer.CaptureClauseStart(leftOverGas)
+					rt.vmConfig.Tracer.CaptureClauseStart(progress.leftOverGas)
 					defer func() {
-						rt.vmConfig.Tracer.CaptureClauseEnd(leftOverGas)
+						rt.vmConfig.Tracer.CaptureClauseEnd(progress.leftOverGas)
 					}()
 				}
 
@@ -465,24 +482,24 @@ func (rt *Runtime) PrepareTransaction(trx *tx.Transaction) (*TransactionExecutor
 				if err != nil {
 					return 0, nil, err
 				}
-				gasUsed = leftOverGas - output.LeftOverGas
-				leftOverGas = output.LeftOverGas
+				gasUsed = progress.leftOverGas - output.LeftOverGas
+				progress.leftOverGas = output.LeftOverGas
 
 				// Apply refund counter, capped to half of the used gas.
 				refund := min(gasUsed/2, output.RefundGas)
 
 				// won't overflow
-				leftOverGas += refund
+				progress.leftOverGas += refund
 
 				if output.VMErr != nil {
 					// vm exception here
 					// revert all executed clauses
 					rt.state.RevertTo(checkpoint)
-					reverted = true
-					txOutputs = nil
+					progress.reverted = true
+					progress.outputs = nil
 					return
 				}
-				txOutputs = append(txOutputs, &tx.Output{Events: output.Events, Transfers: output.Transfers})
+				progress.outputs = append(progress.outputs, &tx.Output{Events: output.Events, Transfers: output.Transfers})
 				return
 			}
 
@@ -499,14 +516,14 @@ func (rt *Runtime) PrepareTransaction(trx *tx.Transaction) (*TransactionExecutor
 
 			receipt := &tx.Receipt{
 				Type:     trx.Type(),
-				Reverted: reverted,
-				Outputs:  txOutputs,
-				GasUsed:  trx.Gas() - leftOverGas,
+				Reverted: progress.reverted,
+				Outputs:  progress.outputs,
+				GasUsed:  trx.Gas() - progress.leftOverGas,
 				GasPayer: payer,
 			}
 			receipt.Paid = new(big.Int).Mul(new(big.Int).SetUint64(receipt.GasUsed), effectiveGasPrice)
 
-			if err := returnGas(leftOverGas); err != nil {
+			if err := returnGas(progress.leftOverGas); err != nil {
 				return nil, err
 			}
 
@@ -540,5 +557,5 @@ func (rt *Runtime) PrepareTransaction(trx *tx.Transaction) (*TransactionExecutor
 			}
 			return receipt, nil
 		},
-	}, nil
+	}, progress, nil
 }
@@ -0,0 +1,89 @@
+// Copyright (c) 2025 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package runtime
+
+import (
+	"github.com/vechain/thor/v2/state"
+	"github.com/vechain/thor/v2/tx"
+)
+
+// Speculation is the result of executing clauses of a tx upon an isolated state.
+type Speculation struct {
+	trx      *tx.Transaction
+	progress *txProgress
+	access   *state.Access
+	err      error
+}
+
+// Speculate executes clauses of the tx upon the state of the runtime, which is expected to be
+// an isolated one, e.g. the state of the parent block. State accesses of the clauses are tracked,
+// to be validated and applied later by ExecuteSpeculation.
+func (rt *Runtime) Speculate(trx *tx.Transaction) *Speculation {
+	executor, progress, err := rt.prepareTransaction(trx)
+	if err != nil {
+		return &Speculation{trx: trx, err: err}
+	}
+
+	rt.state.StartTracking()
+	for executor.HasNextClause() {
+		exec, _ := executor.PrepareNext()
+		if _, _, err := exec(); err != nil {
+			rt.state.StopTracking()
+			return &Speculation{trx: trx, err: err}
+		}
+	}
+	return &Speculation{
+		trx:      trx,
+		progress: progress,
+		access:   rt.state.StopTracking(),
+	}
+}
+
+// ExecuteSpeculation executes the tx of the speculation.
+// Gas is bought and returned upon the state of the runtime as usual, while the clauses execution is
+// skipped in favor of applying the speculative writes, if no value read by the clauses has changed.
+// Otherwise the clauses are re-executed. The second return value indicates whether the speculation is applied.
+func (rt *Runtime) ExecuteSpeculation(spec *Speculation) (*tx.Receipt, bool, error) {
+	if spec.err != nil {
+		receipt, err := rt.ExecuteTransaction(spec.trx)
+		return receipt, false, err
+	}
+
+	executor, progress, err := rt.prepareTransaction(spec.trx)
+	if err != nil {
+		return nil, false, err
+	}
+
+	// the tx context of clauses depends on the gas payer and price
+	applicable := rt.vmConfig.Tracer == nil &&
+		progress.payer == spec.progress.payer &&
+		progress.effectiveGasPrice.Cmp(spec.progress.effectiveGasPrice) == 0
+	if applicable {
+		conflicts, err := rt.state.Conflicts(spec.access)
+		if err != nil {
+			return nil, false, err
+		}
+		applicable = !conflicts
+	}
+
+	if applicable {
+		rt.state.Apply(spec.access)
+		*progress = *spec.progress
+	} else {
+		for executor.HasNextClause() {
+			exec, _ := executor.PrepareNext()
+			if _, _, err := exec(); err != nil {
+				return nil, false, err
+			}
+		}
+	}
+
+	receipt, err := executor.Finalize()
+	if err != nil {
+		return nil, false, err
+	}
+	return receipt, applicable, nil
+}
@@ -0,0 +1,100 @@
+// Copyright (c) 2025 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package runtime_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vechain/thor/v2/consensus/upgrade/galactica"
+	"github.com/vechain/thor/v2/genesis"
+	"github.com/vechain/thor/v2/runtime"
+	"github.com/vechain/thor/v2/test/datagen"
+	"github.com/vechain/thor/v2/test/testchain"
+	"github.com/vechain/thor/v2/thor"
+	"github.com/vechain/thor/v2/trie"
+	"github.com/vechain/thor/v2/tx"
+	"github.com/vechain/thor/v2/xenv"
+)
+
+func TestSpeculation(t *testing.T) {
+	chain, err := testchain.NewDefault()
+	require.NoError(t, err)
+
+	best := chain.Repo().BestBlockSummary()
+	ctx := &xenv.BlockContext{
+		Beneficiary: datagen.RandAddress(),
+		Number:      best.Header.Number() + 1,
+		Time:        best.Header.Timestamp() + thor.BlockInterval(),
+		GasLimit:    best.Header.GasLimit(),
+		BaseFee:     galactica.CalcBaseFee(best.Header, chain.GetForkConfig()),
+	}
+	newRuntime := func() *runtime.Runtime {
+		return runtime.New(chain.Repo().NewChain(best.Header.ID()), chain.Stater().NewState(best.Root()), ctx, chain.GetForkConfig())
+	}
+
+	accs := genesis.DevAccounts()
+	recipient := datagen.RandAddress()
+	transfer := func(from genesis.DevAccount, to thor.Address) *tx.Transaction {
+		return tx.MustSign(tx.NewBuilder(tx.TypeDynamicFee).
+			ChainTag(chain.ChainTag()).
+			MaxFeePerGas(big.NewInt(thor.InitialBaseFee*10)).
+			Expiration(100).
+			Gas(21000).
+			Nonce(datagen.RandUint64()).
+			Clause(tx.NewClause(&to).WithValue(big.NewInt(1))).
+			Build(), from.PrivateKey)
+	}
+
+	txs := tx.Transactions{
+		transfer(accs[1], recipient),
+		transfer(accs[2], datagen.RandAddress()),
+		// conflicts with the first one
+		transfer(accs[3], recipient),
+		// reverted for insufficient balance
+		tx.MustSign(tx.NewBuilder(tx.TypeDynamicFee).
+			ChainTag(chain.ChainTag()).
+			MaxFeePerGas(big.NewInt(thor.InitialBaseFee*10)).
+			Expiration(100).
+			Gas(21000).
+			Clause(tx.NewClause(&recipient).WithValue(new(big.Int).Lsh(big.NewInt(1), 128))).
+			Build(), accs[4].PrivateKey),
+	}
+
+	sequential := newRuntime()
+	var seqReceipts tx.Receipts
+	for _, trx := range txs {
+		receipt, err := sequential.ExecuteTransaction(trx)
+		require.NoError(t, err)
+		seqReceipts = append(seqReceipts, receipt)
+	}
+
+	specs := make([]*runtime.Speculation, 0, len(txs))
+	for _, trx := range txs {
+		specs = append(specs, newRuntime().Speculate(trx))
+	}
+
+	rt := newRuntime()
+	var receipts tx.Receipts
+	for i, spec := range specs {
+		receipt, applied, err := rt.ExecuteSpeculation(spec)
+		require.NoError(t, err)
+		assert.Equal(t, i != 2, applied, "tx %d", i)
+		receipts = append(receipts, receipt)
+	}
+	assert.True(t, receipts[3].Reverted)
+	assert.Equal(t, seqReceipts.RootHash(), receipts.RootHash())
+
+	ver := trie.Version{Major: ctx.Number}
+	seqStage, err := sequential.State().Stage(ver)
+	require.NoError(t, err)
+	stage, err := rt.State().Stage(ver)
+	require.NoError(t, err)
+	assert.Equal(t, seqStage.Hash(), stage.Hash())
+}
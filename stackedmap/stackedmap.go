@@ -88,6 +88,20 @@ func (sm *StackedMap) Get(key any) (any, bool, error) {
 	return sm.src(key)
 }
 
+// Locate gets value for given key, along with the stack depth of the map where the value is found.
+// The returned depth is -1 if the value comes from src.
+func (sm *StackedMap) Locate(key any) (any, int, error) {
+	if revs, ok := sm.keyRevisionMap[key]; ok {
+		rev := revs.top().(int)
+		lvl := sm.mapStack[rev].(*level)
+		if v, ok := lvl.kvs[key]; ok {
+			return v, rev, nil
+		}
+	}
+	v, _, err := sm.src(key)
+	return v, -1, err
+}
+
 // Put puts key value into map at stack top.
 // It will panic if stack is empty.
 func (sm *StackedMap) Put(key, value any) {
@@ -118,6 +132,18 @@ func (sm *StackedMap) Journal(cb func(key, value any) bool) {
 	}
 }
 
+// JournalSince traverse journal entries of Put operations made in maps at or above the given depth.
+// The traverse will abort if the callback func returns false.
+func (sm *StackedMap) JournalSince(depth int, cb func(key, value any) bool) {
+	for i := max(depth, 0); i < len(sm.mapStack); i++ {
+		for _, entry := range sm.mapStack[i].(*level).journal {
+			if !cb(entry.key, entry.value) {
+				return
+			}
+		}
+	}
+}
+
 // stack ops
 type stack []any
 
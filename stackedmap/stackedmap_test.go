@@ -94,3 +94,55 @@ func TestStackedMapPuts(t *testing.T) {
 
 	assert.Equal(1, i, "Journal traverse should abort")
 }
+
+func TestStackedMapLocate(t *testing.T) {
+	sm := New(func(key any) (any, bool, error) {
+		return "src", true, nil
+	})
+
+	v, depth, err := sm.Locate("foo")
+	assert.Nil(t, err)
+	assert.Equal(t, "src", v)
+	assert.Equal(t, -1, depth)
+
+	sm.Put("foo", "bar")
+	sm.Push()
+	sm.Push()
+	sm.Put("foo", "baz")
+
+	v, depth, _ = sm.Locate("foo")
+	assert.Equal(t, "baz", v)
+	assert.Equal(t, 2, depth)
+
+	sm.PopTo(1)
+	v, depth, _ = sm.Locate("foo")
+	assert.Equal(t, "bar", v)
+	assert.Equal(t, 0, depth)
+}
+
+func TestStackedMapJournalSince(t *testing.T) {
+	sm := New(func(key any) (any, bool, error) {
+		return nil, false, nil
+	})
+
+	sm.Put("a", 1)
+	sm.Push()
+	sm.Put("b", 2)
+	sm.Push()
+	sm.Put("c", 3)
+	sm.Put("b", 4)
+
+	var keys []any
+	sm.JournalSince(1, func(k, _ any) bool {
+		keys = append(keys, k)
+		return true
+	})
+	assert.Equal(t, M("b", "c", "b"), keys)
+
+	keys = nil
+	sm.JournalSince(3, func(k, _ any) bool {
+		keys = append(keys, k)
+		return true
+	})
+	assert.Nil(t, keys)
+}
@@ -0,0 +1,134 @@
+// Copyright (c) 2025 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package state
+
+import (
+	"bytes"
+
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// Access records the reads and writes made upon a state while tracking.
+// Reads keep the values seen before the tracking started, writes are kept in order.
+type Access struct {
+	reads  map[any]any
+	writes []accessEntry
+}
+
+type accessEntry struct {
+	key   any
+	value any
+}
+
+// tracker tracks reads of values which are put below the depth.
+type tracker struct {
+	depth int
+	reads map[any]any
+}
+
+// Reads returns the count of values read.
+func (a *Access) Reads() int {
+	return len(a.reads)
+}
+
+// Writes returns the count of values written.
+func (a *Access) Writes() int {
+	return len(a.writes)
+}
+
+// StartTracking starts to track accesses made from now on.
+func (s *State) StartTracking() {
+	s.tracker = &tracker{
+		depth: s.sm.Push(),
+		reads: make(map[any]any),
+	}
+}
+
+// StopTracking stops tracking and returns the accesses tracked.
+// Writes reverted during tracking are not included.
+func (s *State) StopTracking() *Access {
+	if s.tracker == nil {
+		return nil
+	}
+	a := &Access{reads: s.tracker.reads}
+	s.sm.JournalSince(s.tracker.depth, func(k, v any) bool {
+		a.writes = append(a.writes, accessEntry{k, v})
+		return true
+	})
+	s.tracker = nil
+	return a
+}
+
+// Conflicts returns whether any value read by the given access differs from the current state.
+func (s *State) Conflicts(a *Access) (bool, error) {
+	for k, v := range a.reads {
+		cur, _, err := s.sm.Get(k)
+		if err != nil {
+			return false, &Error{err}
+		}
+		if !valueEqual(v, cur) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// Apply puts writes of the given access into the state.
+func (s *State) Apply(a *Access) {
+	for _, w := range a.writes {
+		s.sm.Put(w.key, w.value)
+	}
+}
+
+// get gets value from the stacked map, and records the read if tracking.
+func (s *State) get(key any) (any, error) {
+	if s.tracker == nil {
+		v, _, err := s.sm.Get(key)
+		return v, err
+	}
+
+	v, depth, err := s.sm.Locate(key)
+	if err != nil {
+		return nil, err
+	}
+	// values put since tracking started are results of the tracked execution itself
+	if depth < s.tracker.depth {
+		if _, ok := s.tracker.reads[key]; !ok {
+			s.tracker.reads[key] = v
+		}
+	}
+	return v, nil
+}
+
+// valueEqual returns whether the two values are equal. Values of different types, or nil, are unequal
+// unless both are nil.
+func valueEqual(a, b any) bool {
+	switch va := a.(type) {
+	case nil:
+		return b == nil
+	case *Account:
+		vb, ok := b.(*Account)
+		if !ok || va == nil || vb == nil {
+			return ok && va == vb
+		}
+		return va.Balance.Cmp(vb.Balance) == 0 &&
+			va.Energy.Cmp(vb.Energy) == 0 &&
+			va.BlockTime == vb.BlockTime &&
+			bytes.Equal(va.Master, vb.Master) &&
+			bytes.Equal(va.CodeHash, vb.CodeHash) &&
+			bytes.Equal(va.StorageRoot, vb.StorageRoot)
+	case []byte:
+		vb, ok := b.([]byte)
+		return ok && bytes.Equal(va, vb)
+	case rlp.RawValue:
+		vb, ok := b.(rlp.RawValue)
+		return ok && bytes.Equal(va, vb)
+	case int:
+		vb, ok := b.(int)
+		return ok && va == vb
+	}
+	return false
+}
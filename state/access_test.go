@@ -0,0 +1,83 @@
+// Copyright (c) 2025 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package state
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/vechain/thor/v2/muxdb"
+	"github.com/vechain/thor/v2/thor"
+	"github.com/vechain/thor/v2/trie"
+)
+
+func TestAccess(t *testing.T) {
+	db := muxdb.NewMem()
+	addr1 := thor.BytesToAddress([]byte("addr1"))
+	addr2 := thor.BytesToAddress([]byte("addr2"))
+	key := thor.BytesToBytes32([]byte("key"))
+
+	spec := New(db, trie.Root{})
+	spec.StartTracking()
+	bal, _ := spec.GetBalance(addr1)
+	spec.SetBalance(addr2, new(big.Int).Add(bal, big.NewInt(1)))
+	spec.SetStorage(addr2, key, thor.BytesToBytes32([]byte("value")))
+	// reads of own writes are not tracked
+	spec.GetBalance(addr2)
+
+	access := spec.StopTracking()
+	// accounts addr1, addr2 and the storage barrier of addr2
+	assert.Equal(t, 3, access.Reads())
+	assert.Equal(t, 2, access.Writes())
+
+	// no conflict
+	st := New(db, trie.Root{})
+	assert.Equal(t, M(false, nil), M(st.Conflicts(access)))
+	st.Apply(access)
+	assert.Equal(t, M(big.NewInt(1), nil), M(st.GetBalance(addr2)))
+	assert.Equal(t, M(thor.BytesToBytes32([]byte("value")), nil), M(st.GetStorage(addr2, key)))
+
+	// addr1 changed
+	st = New(db, trie.Root{})
+	st.SetBalance(addr1, big.NewInt(1))
+	assert.Equal(t, M(true, nil), M(st.Conflicts(access)))
+
+	// reverted writes are excluded
+	spec = New(db, trie.Root{})
+	spec.StartTracking()
+	cp := spec.NewCheckpoint()
+	spec.SetBalance(addr1, big.NewInt(1))
+	spec.RevertTo(cp)
+	assert.Equal(t, 0, spec.StopTracking().Writes())
+}
+
+func TestValueEqual(t *testing.T) {
+	acc := &Account{Balance: big.NewInt(1), Energy: big.NewInt(0)}
+
+	assert.True(t, valueEqual(nil, nil))
+	assert.True(t, valueEqual(acc, &Account{Balance: big.NewInt(1), Energy: big.NewInt(0)}))
+	assert.True(t, valueEqual((*Account)(nil), (*Account)(nil)))
+	assert.True(t, valueEqual([]byte{1}, []byte{1}))
+	assert.True(t, valueEqual(rlp.RawValue{1}, rlp.RawValue{1}))
+	assert.True(t, valueEqual(1, 1))
+
+	// nil on either side
+	assert.False(t, valueEqual(acc, nil))
+	assert.False(t, valueEqual(nil, acc))
+	assert.False(t, valueEqual(acc, (*Account)(nil)))
+	assert.False(t, valueEqual((*Account)(nil), acc))
+	assert.False(t, valueEqual([]byte{1}, nil))
+	assert.False(t, valueEqual(nil, []byte{1}))
+	assert.False(t, valueEqual(rlp.RawValue{1}, nil))
+	assert.False(t, valueEqual(1, nil))
+
+	// mismatched types
+	assert.False(t, valueEqual([]byte{1}, rlp.RawValue{1}))
+	assert.False(t, valueEqual(acc, 1))
+}
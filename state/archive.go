@@ -0,0 +1,346 @@
+// Copyright (c) 2025 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package state
+
+import (
+	"context"
+	"encoding/binary"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/rlp"
+
+	"github.com/vechain/thor/v2/kv"
+	"github.com/vechain/thor/v2/muxdb"
+	"github.com/vechain/thor/v2/thor"
+	"github.com/vechain/thor/v2/trie"
+)
+
+const (
+	archiveStoreName = "state.archive"
+
+	archiveAccountSpace = byte(0) // hashed address + version => account
+	archiveStorageSpace = byte(1) // storage id + hashed key + version => storage value
+	archiveForkSpace    = byte(2) // major version => nil, marks heights having more than one block
+
+	archiveStartKey = "start" // the version since which states are archived
+	archiveHeadKey  = "head"  // the version of the highest block archived
+)
+
+// Archive is the flat index of account and storage changes of each block. Historical state read from the
+// archive costs one lookup per account or storage slot, other than traversing tries.
+//
+// Entries are keyed by the hashed address (or storage key) and the version of the block, so that the value
+// at a given version is the one with the nearest version not greater than it, on the same chain.
+//
+// The head, which is the highest block archived, is advanced along with the entries. Blocks beyond the head
+// are not archived, e.g. committed by a node not running in archive mode, and their states are read from tries.
+type Archive struct {
+	db    *muxdb.MuxDB
+	store kv.Store
+	lock  sync.Mutex // serializes indexing of changes, which advances the head
+}
+
+// NewArchive creates the archive in the given DB.
+func NewArchive(db *muxdb.MuxDB) *Archive {
+	return &Archive{db: db, store: db.NewStore(archiveStoreName)}
+}
+
+// Start returns the version since which states are archived. False returned if the archive not initialized.
+func (a *Archive) Start() (trie.Version, bool, error) {
+	return a.getVersion(archiveStartKey)
+}
+
+// Head returns the version of the highest block archived. False returned if the archive not initialized.
+func (a *Archive) Head() (trie.Version, bool, error) {
+	return a.getVersion(archiveHeadKey)
+}
+
+func (a *Archive) getVersion(key string) (trie.Version, bool, error) {
+	data, err := a.store.Get([]byte(key))
+	if err != nil {
+		if a.store.IsNotFound(err) {
+			return trie.Version{}, false, nil
+		}
+		return trie.Version{}, false, err
+	}
+	return trie.Version{
+		Major: binary.BigEndian.Uint32(data),
+		Minor: binary.BigEndian.Uint32(data[4:]),
+	}, true, nil
+}
+
+// Init initializes the archive with the full state of the given root, as the base of later changes.
+// Entries of the previous initialization are cleared.
+func (a *Archive) Init(ctx context.Context, root trie.Root) error {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	// entries left may shadow accounts absent in the new base
+	if err := a.store.DeleteRange(ctx, kv.Range{}); err != nil {
+		return err
+	}
+
+	var (
+		bulk    = a.store.Bulk()
+		keyBuf  []byte
+		count   int
+		accTrie = a.db.NewTrie(muxdb.AccountTrieName, root)
+	)
+	bulk.EnableAutoFlush()
+	accTrie.SetNoFillCache(true)
+
+	accIter := trie.NewIterator(accTrie.NodeIterator(nil, 0))
+	for accIter.Next() {
+		// check context every 1000 accounts
+		if count++; count%1000 == 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			default:
+			}
+		}
+
+		var (
+			acc Account
+			am  AccountMetadata
+		)
+		if err := rlp.DecodeBytes(accIter.Value, &acc); err != nil {
+			return err
+		}
+		if len(accIter.Meta) > 0 {
+			if err := rlp.DecodeBytes(accIter.Meta, &am); err != nil {
+				return err
+			}
+		}
+		keyBuf = appendArchiveAccountKey(keyBuf[:0], accIter.Key, root.Ver)
+		if err := putArchivedAccount(bulk, keyBuf, &acc, &am); err != nil {
+			return err
+		}
+
+		if len(acc.StorageRoot) == 0 {
+			continue
+		}
+		sTrie := a.db.NewTrie(
+			StorageTrieName(am.StorageID),
+			trie.Root{
+				Hash: thor.BytesToBytes32(acc.StorageRoot),
+				Ver: trie.Version{
+					Major: am.StorageMajorVer,
+					Minor: am.StorageMinorVer,
+				},
+			})
+		sTrie.SetNoFillCache(true)
+		sIter := trie.NewIterator(sTrie.NodeIterator(nil, 0))
+		for sIter.Next() {
+			keyBuf = appendArchiveStorageKey(keyBuf[:0], am.StorageID, sIter.Key, root.Ver)
+			if err := bulk.Put(keyBuf, sIter.Value); err != nil {
+				return err
+			}
+		}
+		if sIter.Err != nil {
+			return sIter.Err
+		}
+	}
+	if accIter.Err != nil {
+		return accIter.Err
+	}
+
+	ver := appendVersion(nil, root.Ver)
+	if err := bulk.Put([]byte(archiveStartKey), ver); err != nil {
+		return err
+	}
+	if err := bulk.Put([]byte(archiveHeadKey), ver); err != nil {
+		return err
+	}
+	return bulk.Write()
+}
+
+// index writes the entries put by fn for the block of the given version, and advances the head
+// in the same write.
+func (a *Archive) index(ver trie.Version, fn func(putter kv.Putter) error) error {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	head, ok, err := a.Head()
+	if err != nil {
+		return err
+	}
+	bulk := a.store.Bulk()
+	bulk.EnableAutoFlush()
+	if err := fn(bulk); err != nil {
+		return err
+	}
+	if ver.Minor > 0 {
+		if err := bulk.Put(appendArchiveForkKey(nil, ver.Major), nil); err != nil {
+			return err
+		}
+	}
+	// put last, so that it's written after entries even if auto flushed
+	if !ok || ver.Major > head.Major {
+		if err := bulk.Put([]byte(archiveHeadKey), appendVersion(nil, ver)); err != nil {
+			return err
+		}
+	}
+	return bulk.Write()
+}
+
+// newReader creates the reader to read the state of the given version, which belongs to the chain
+// checked by onChain. Nil returned if the state is not archived.
+func (a *Archive) newReader(ver trie.Version, onChain func(trie.Version) (bool, error)) (*archiveReader, error) {
+	start, ok, err := a.Start()
+	if err != nil {
+		return nil, err
+	}
+	if !ok || ver.Major < start.Major || (ver.Major == start.Major && ver.Minor != start.Minor) {
+		return nil, nil
+	}
+	head, ok, err := a.Head()
+	if err != nil {
+		return nil, err
+	}
+	if !ok || ver.Major > head.Major {
+		return nil, nil
+	}
+	return &archiveReader{a.store, ver, onChain}, nil
+}
+
+// archiveReader reads archived state of a version.
+type archiveReader struct {
+	store   kv.Store
+	ver     trie.Version
+	onChain func(trie.Version) (bool, error)
+}
+
+// get returns the value with the nearest version not greater than the reader's on the chain.
+func (r *archiveReader) get(prefix []byte) ([]byte, bool, error) {
+	limit := appendVersion(append([]byte(nil), prefix...), r.ver)
+	iter := r.store.Iterate(kv.Range{
+		Start: prefix,
+		Limit: append(limit, 0), // included the reader's version
+	})
+	defer iter.Release()
+
+	for ok := iter.Last(); ok; ok = iter.Prev() {
+		key := iter.Key()
+		ver := trie.Version{
+			Major: binary.BigEndian.Uint32(key[len(key)-8:]),
+			Minor: binary.BigEndian.Uint32(key[len(key)-4:]),
+		}
+		if ver.Major == r.ver.Major {
+			if ver.Minor != r.ver.Minor {
+				// another block of the same height
+				continue
+			}
+		} else {
+			forked, err := r.store.Has(appendArchiveForkKey(nil, ver.Major))
+			if err != nil {
+				return nil, false, err
+			}
+			if forked {
+				on, err := r.onChain(ver)
+				if err != nil {
+					return nil, false, err
+				}
+				if !on {
+					continue
+				}
+			}
+		}
+		return append([]byte(nil), iter.Value()...), true, nil
+	}
+	return nil, false, iter.Error()
+}
+
+// loadAccount loads the account and its metadata by address.
+func (r *archiveReader) loadAccount(addr thor.Address) (*Account, *AccountMetadata, error) {
+	data, found, err := r.get(append([]byte{archiveAccountSpace}, secureKey(addr[:])...))
+	if err != nil {
+		return nil, nil, err
+	}
+	if !found {
+		return emptyAccount(), &AccountMetadata{}, nil
+	}
+	return decodeArchivedAccount(data)
+}
+
+// loadStorage loads the storage value of the storage identified by sid.
+func (r *archiveReader) loadStorage(sid []byte, key thor.Bytes32) (rlp.RawValue, error) {
+	prefix := appendArchiveStoragePrefix(nil, sid, secureKey(key[:]))
+	v, _, err := r.get(prefix)
+	return v, err
+}
+
+// archivedAccount is the encoding form of an account in the archive.
+type archivedAccount struct {
+	Account Account
+	Meta    AccountMetadata
+}
+
+// putArchivedAccount puts the account at the given key. Empty value put for empty account.
+func putArchivedAccount(putter kv.Putter, key []byte, acc *Account, am *AccountMetadata) error {
+	data, err := encodeArchivedAccount(acc, am)
+	if err != nil {
+		return err
+	}
+	return putter.Put(key, data)
+}
+
+// encodeArchivedAccount encodes the account with its metadata. Nil returned for empty account.
+func encodeArchivedAccount(acc *Account, am *AccountMetadata) ([]byte, error) {
+	if acc.IsEmpty() {
+		return nil, nil
+	}
+	aa := archivedAccount{Account: *acc}
+	if len(acc.StorageRoot) > 0 {
+		aa.Meta = *am
+	}
+	return rlp.EncodeToBytes(&aa)
+}
+
+// decodeArchivedAccount decodes the account encoded by encodeArchivedAccount.
+func decodeArchivedAccount(data []byte) (*Account, *AccountMetadata, error) {
+	if len(data) == 0 {
+		return emptyAccount(), &AccountMetadata{}, nil
+	}
+	var aa archivedAccount
+	if err := rlp.DecodeBytes(data, &aa); err != nil {
+		return nil, nil, err
+	}
+	return &aa.Account, &aa.Meta, nil
+}
+
+// archivedStorageID returns the storage id of the encoded account.
+func archivedStorageID(data []byte) (string, error) {
+	_, am, err := decodeArchivedAccount(data)
+	if err != nil {
+		return "", err
+	}
+	return string(am.StorageID), nil
+}
+
+func appendVersion(buf []byte, ver trie.Version) []byte {
+	buf = binary.BigEndian.AppendUint32(buf, ver.Major)
+	return binary.BigEndian.AppendUint32(buf, ver.Minor)
+}
+
+func appendArchiveAccountKey(buf []byte, hashedAddr []byte, ver trie.Version) []byte {
+	buf = append(append(buf, archiveAccountSpace), hashedAddr...)
+	return appendVersion(buf, ver)
+}
+
+func appendArchiveStoragePrefix(buf []byte, sid []byte, hashedKey []byte) []byte {
+	// sid is length prefixed, since it's variable-length
+	buf = append(buf, archiveStorageSpace, byte(len(sid)))
+	return append(append(buf, sid...), hashedKey...)
+}
+
+func appendArchiveStorageKey(buf []byte, sid []byte, hashedKey []byte, ver trie.Version) []byte {
+	return appendVersion(appendArchiveStoragePrefix(buf, sid, hashedKey), ver)
+}
+
+func appendArchiveForkKey(buf []byte, major uint32) []byte {
+	return binary.BigEndian.AppendUint32(append(buf, archiveForkSpace), major)
+}
@@ -0,0 +1,209 @@
+// Copyright (c) 2025 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package state
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vechain/thor/v2/muxdb"
+	"github.com/vechain/thor/v2/thor"
+	"github.com/vechain/thor/v2/trie"
+)
+
+func commitState(t *testing.T, st *State, ver trie.Version) trie.Root {
+	stage, err := st.Stage(ver)
+	require.NoError(t, err)
+	root, err := stage.Commit()
+	require.NoError(t, err)
+	return trie.Root{Hash: root, Ver: ver}
+}
+
+func onMainChain(ver trie.Version) (bool, error) {
+	return ver.Minor == 0, nil
+}
+
+func TestArchive(t *testing.T) {
+	db := muxdb.NewMem()
+	archive := NewArchive(db)
+	require.NoError(t, archive.Init(context.Background(), trie.Root{}))
+	stater := NewArchiveStater(db, archive)
+
+	addr := thor.BytesToAddress([]byte("addr"))
+	key := thor.BytesToBytes32([]byte("key"))
+	forkedAddr := thor.BytesToAddress([]byte("forked"))
+
+	// #1 balance and storage set
+	st := stater.NewState(trie.Root{})
+	st.SetBalance(addr, big.NewInt(1))
+	st.SetStorage(addr, key, thor.BytesToBytes32([]byte("v1")))
+	root1 := commitState(t, st, trie.Version{Major: 1})
+
+	// #2 only balance changed
+	st = stater.NewState(root1)
+	st.SetBalance(addr, big.NewInt(2))
+	root2 := commitState(t, st, trie.Version{Major: 2})
+
+	// #2' forked block, storage changed
+	st = stater.NewState(root1)
+	st.SetStorage(addr, key, thor.BytesToBytes32([]byte("v2'")))
+	st.SetBalance(forkedAddr, big.NewInt(1))
+	root2x := commitState(t, st, trie.Version{Major: 2, Minor: 1})
+
+	// #3 account deleted, and storage set again
+	st = stater.NewState(root2)
+	st.Delete(addr)
+	st.SetBalance(addr, big.NewInt(3))
+	st.SetStorage(thor.BytesToAddress([]byte("other")), key, thor.BytesToBytes32([]byte("other")))
+	root3 := commitState(t, st, trie.Version{Major: 3})
+
+	for _, tt := range []struct {
+		root    trie.Root
+		onChain func(trie.Version) (bool, error)
+		balance int64
+		storage thor.Bytes32
+	}{
+		{trie.Root{}, onMainChain, 0, thor.Bytes32{}},
+		{root1, onMainChain, 1, thor.BytesToBytes32([]byte("v1"))},
+		{root2, onMainChain, 2, thor.BytesToBytes32([]byte("v1"))},
+		{root2x, onMainChain, 1, thor.BytesToBytes32([]byte("v2'"))},
+		{root3, onMainChain, 3, thor.Bytes32{}},
+	} {
+		st, err := stater.NewHistoricalState(tt.root, tt.onChain)
+		require.NoError(t, err)
+		assert.NotNil(t, st.archiveReader)
+
+		balance, err := st.GetBalance(addr)
+		assert.NoError(t, err)
+		assert.Equal(t, big.NewInt(tt.balance), balance, "balance at %v", tt.root.Ver)
+		storage, err := st.GetStorage(addr, key)
+		assert.NoError(t, err)
+		assert.Equal(t, tt.storage, storage, "storage at %v", tt.root.Ver)
+
+		// the same as read from tries
+		trieSt := stater.NewState(tt.root)
+		trieBalance, _ := trieSt.GetBalance(addr)
+		trieStorage, _ := trieSt.GetStorage(addr, key)
+		assert.Equal(t, trieBalance, balance)
+		assert.Equal(t, trieStorage, storage)
+	}
+
+	// changes of the forked block are invisible to the main chain
+	st, err := stater.NewHistoricalState(root3, onMainChain)
+	require.NoError(t, err)
+	balance, err := st.GetBalance(forkedAddr)
+	assert.NoError(t, err)
+	assert.Zero(t, balance.Sign())
+
+	st, err = stater.NewHistoricalState(root2x, onMainChain)
+	require.NoError(t, err)
+	balance, err = st.GetBalance(forkedAddr)
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(1), balance)
+}
+
+func TestArchiveInit(t *testing.T) {
+	db := muxdb.NewMem()
+	addr := thor.BytesToAddress([]byte("addr"))
+	key := thor.BytesToBytes32([]byte("key"))
+
+	// committed before archived
+	st := NewStater(db).NewState(trie.Root{})
+	st.SetBalance(addr, big.NewInt(1))
+	st.SetStorage(addr, key, thor.BytesToBytes32([]byte("v1")))
+	st.SetCode(addr, []byte("code"))
+	root1 := commitState(t, st, trie.Version{Major: 1})
+
+	archive := NewArchive(db)
+	_, ok, err := archive.Start()
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	require.NoError(t, archive.Init(context.Background(), root1))
+	start, ok, err := archive.Start()
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, root1.Ver, start)
+
+	stater := NewArchiveStater(db, archive)
+	st = stater.NewState(root1)
+	st.SetEnergy(addr, big.NewInt(2), 100)
+	root2 := commitState(t, st, trie.Version{Major: 2})
+
+	st, err = stater.NewHistoricalState(root2, onMainChain)
+	require.NoError(t, err)
+	require.NotNil(t, st.archiveReader)
+	balance, _ := st.GetBalance(addr)
+	assert.Equal(t, big.NewInt(1), balance)
+	energy, _ := st.GetEnergy(addr, 100, 0)
+	assert.Equal(t, big.NewInt(2), energy)
+	storage, _ := st.GetStorage(addr, key)
+	assert.Equal(t, thor.BytesToBytes32([]byte("v1")), storage)
+	code, _ := st.GetCode(addr)
+	assert.Equal(t, []byte("code"), code)
+
+	// states before archived are read from tries
+	st, err = stater.NewHistoricalState(trie.Root{}, onMainChain)
+	require.NoError(t, err)
+	assert.Nil(t, st.archiveReader)
+}
+
+func TestArchiveHead(t *testing.T) {
+	db := muxdb.NewMem()
+	addr := thor.BytesToAddress([]byte("addr"))
+
+	archive := NewArchive(db)
+	require.NoError(t, archive.Init(context.Background(), trie.Root{}))
+	head, ok, err := archive.Head()
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, trie.Version{}, head)
+
+	stater := NewArchiveStater(db, archive)
+	st := stater.NewState(trie.Root{})
+	st.SetBalance(addr, big.NewInt(1))
+	root1 := commitState(t, st, trie.Version{Major: 1})
+
+	// forked block doesn't move the head back
+	st = stater.NewState(trie.Root{})
+	st.SetBalance(addr, big.NewInt(2))
+	commitState(t, st, trie.Version{Major: 1, Minor: 1})
+	head, _, _ = archive.Head()
+	assert.Equal(t, root1.Ver, head)
+
+	// committed without archiving
+	st = NewStater(db).NewState(root1)
+	st.Delete(addr)
+	root2 := commitState(t, st, trie.Version{Major: 2})
+	head, _, _ = archive.Head()
+	assert.Equal(t, root1.Ver, head)
+
+	// states beyond the head are read from tries
+	st, err = stater.NewHistoricalState(root2, onMainChain)
+	require.NoError(t, err)
+	assert.Nil(t, st.archiveReader)
+	balance, err := st.GetBalance(addr)
+	assert.NoError(t, err)
+	assert.Zero(t, balance.Sign())
+
+	// reinitialized, entries before cleared
+	require.NoError(t, archive.Init(context.Background(), root2))
+	head, _, _ = archive.Head()
+	assert.Equal(t, root2.Ver, head)
+	st, err = stater.NewHistoricalState(root2, onMainChain)
+	require.NoError(t, err)
+	require.NotNil(t, st.archiveReader)
+	balance, err = st.GetBalance(addr)
+	assert.NoError(t, err)
+	assert.Zero(t, balance.Sign())
+	st, err = stater.NewHistoricalState(root1, onMainChain)
+	require.NoError(t, err)
+	assert.Nil(t, st.archiveReader)
+}
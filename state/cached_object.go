@@ -23,6 +23,9 @@ type cachedObject struct {
 	data Account
 	meta AccountMetadata
 
+	archiveReader  *archiveReader  // reads storage from the archive if not nil
+	snapshotReader *snapshotReader // reads storage from the snapshot if not nil
+
 	cache struct {
 		code        []byte
 		storageTrie *muxdb.Trie
@@ -71,6 +74,29 @@ func (co *cachedObject) GetStorage(key thor.Bytes32) (rlp.RawValue, error) {
 	}
 	// not found in cache
 
+	if co.archiveReader != nil {
+		if len(co.data.StorageRoot) == 0 {
+			return nil, nil
+		}
+		v, err := co.archiveReader.loadStorage(co.meta.StorageID, key)
+		if err != nil {
+			return nil, err
+		}
+		cache.storage[key] = v
+		return v, nil
+	}
+
+	if co.snapshotReader != nil && len(co.data.StorageRoot) > 0 {
+		v, found, err := co.snapshotReader.loadStorage(co.meta.StorageID, key)
+		if err != nil {
+			return nil, err
+		}
+		if found {
+			cache.storage[key] = v
+			return v, nil
+		}
+	}
+
 	trie := co.getOrCreateStorageTrie()
 	if trie == nil {
 		return nil, nil
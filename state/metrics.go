@@ -7,4 +7,8 @@ package state
 
 import "github.com/vechain/thor/v2/metrics"
 
-var metricAccountChanges = metrics.LazyLoadCounter("account_state_changes_count")
+var (
+	metricAccountChanges   = metrics.LazyLoadCounter("account_state_changes_count")
+	metricPrefetchCount    = metrics.LazyLoadCounterVec("state_prefetch_count", []string{"type"})
+	metricPrefetchUseCount = metrics.LazyLoadCounterVec("state_prefetch_use_count", []string{"result"})
+)
@@ -0,0 +1,128 @@
+// Copyright (c) 2025 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package state
+
+import (
+	"sync"
+
+	"github.com/vechain/thor/v2/co"
+	"github.com/vechain/thor/v2/muxdb"
+	"github.com/vechain/thor/v2/thor"
+	"github.com/vechain/thor/v2/trie"
+	"github.com/vechain/thor/v2/tx"
+)
+
+// Prefetcher loads accounts to be accessed by upcoming txs ahead of execution.
+// It warms the trie node cache of muxdb and the code cache, which are shared
+// with the state instances upon the same root. Prefetched accounts are also
+// taken by the state using the prefetcher.
+type Prefetcher struct {
+	db      *muxdb.MuxDB
+	root    trie.Root
+	mu      sync.Mutex
+	fetched map[thor.Address]*cachedObject // nil value if being fetched, failed or taken
+}
+
+// NewPrefetcher creates a prefetcher for the state of the given root.
+func (s *Stater) NewPrefetcher(root trie.Root) *Prefetcher {
+	return &Prefetcher{
+		db:      s.db,
+		root:    root,
+		fetched: make(map[thor.Address]*cachedObject),
+	}
+}
+
+// PrefetchTxs prefetches origins, delegators and clause targets of the txs in background.
+func (p *Prefetcher) PrefetchTxs(txs tx.Transactions) <-chan struct{} {
+	addrs := make([]thor.Address, 0, len(txs)*2)
+	for _, t := range txs {
+		if origin, err := t.Origin(); err == nil {
+			addrs = append(addrs, origin)
+		}
+		if delegator, err := t.Delegator(); err == nil && delegator != nil {
+			addrs = append(addrs, *delegator)
+		}
+		for _, c := range t.Clauses() {
+			if to := c.To(); to != nil {
+				addrs = append(addrs, *to)
+			}
+		}
+	}
+	return p.Prefetch(addrs...)
+}
+
+// Prefetch prefetches the accounts in background, skipping already prefetched ones.
+// The returned channel is closed when done.
+func (p *Prefetcher) Prefetch(addrs ...thor.Address) <-chan struct{} {
+	p.mu.Lock()
+	pending := make([]thor.Address, 0, len(addrs))
+	for _, addr := range addrs {
+		if _, ok := p.fetched[addr]; !ok {
+			p.fetched[addr] = nil
+			pending = append(pending, addr)
+		}
+	}
+	p.mu.Unlock()
+
+	return co.Parallel(func(queue chan<- func()) {
+		for _, addr := range pending {
+			queue <- func() {
+				// best effort, errors will be met again on execution
+				_ = p.fetch(addr)
+			}
+		}
+	})
+}
+
+// fetch loads the account, its code and the top nodes of its storage trie.
+func (p *Prefetcher) fetch(addr thor.Address) error {
+	a, am, err := loadAccount(p.db.NewTrie(muxdb.AccountTrieName, p.root), addr)
+	if err != nil {
+		return err
+	}
+	metricPrefetchCount().AddWithLabel(1, map[string]string{"type": "account"})
+
+	obj := newCachedObject(p.db, addr, a, am)
+	if len(a.CodeHash) > 0 {
+		if _, err := obj.GetCode(); err != nil {
+			return err
+		}
+		metricPrefetchCount().AddWithLabel(1, map[string]string{"type": "code"})
+	}
+
+	if len(a.StorageRoot) > 0 {
+		// nodes near the root are shared by all storage lookups
+		if _, err := obj.GetStorage(thor.Bytes32{}); err != nil {
+			return err
+		}
+		metricPrefetchCount().AddWithLabel(1, map[string]string{"type": "storage"})
+	}
+
+	p.mu.Lock()
+	p.fetched[addr] = obj
+	p.mu.Unlock()
+	return nil
+}
+
+// take takes the prefetched object of the account, which is then owned by the caller.
+// It counts a hit if the account was prefetched in time, and a miss if requested
+// but not ready. Accounts never requested are not counted.
+func (p *Prefetcher) take(addr thor.Address) (*cachedObject, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	obj, ok := p.fetched[addr]
+	if !ok {
+		return nil, false
+	}
+	if obj == nil {
+		metricPrefetchUseCount().AddWithLabel(1, map[string]string{"result": "miss"})
+		return nil, false
+	}
+	p.fetched[addr] = nil
+	metricPrefetchUseCount().AddWithLabel(1, map[string]string{"result": "hit"})
+	return obj, true
+}
@@ -0,0 +1,64 @@
+// Copyright (c) 2025 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package state
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/vechain/thor/v2/muxdb"
+	"github.com/vechain/thor/v2/thor"
+	"github.com/vechain/thor/v2/trie"
+	"github.com/vechain/thor/v2/tx"
+)
+
+func TestPrefetcher(t *testing.T) {
+	db := muxdb.NewMem()
+	st := New(db, trie.Root{})
+
+	contract := thor.BytesToAddress([]byte("contract"))
+	code := []byte("prefetcher test code")
+	st.SetCode(contract, code)
+	st.SetStorage(contract, thor.BytesToBytes32([]byte("key")), thor.BytesToBytes32([]byte("value")))
+
+	stage, err := st.Stage(trie.Version{Major: 1})
+	assert.Nil(t, err)
+	root, err := stage.Commit()
+	assert.Nil(t, err)
+
+	codeCache.Remove(string(thor.Keccak256(code).Bytes()))
+
+	p := NewStater(db).NewPrefetcher(trie.Root{Hash: root, Ver: trie.Version{Major: 1}})
+	<-p.Prefetch(contract, contract)
+	assert.True(t, codeCache.Contains(string(thor.Keccak256(code).Bytes())))
+	assert.Len(t, p.fetched, 1)
+
+	key, _ := crypto.GenerateKey()
+	trx := tx.MustSign(
+		tx.NewBuilder(tx.TypeLegacy).Clause(tx.NewClause(&contract)).Build(),
+		key,
+	)
+	<-p.PrefetchTxs(tx.Transactions{trx})
+	origin, _ := trx.Origin()
+	assert.Len(t, p.fetched, 2)
+	assert.Contains(t, p.fetched, origin)
+
+	// prefetched accounts are taken by the state once
+	st = New(db, trie.Root{Hash: root, Ver: trie.Version{Major: 1}})
+	st.UsePrefetcher(p)
+	got, err := st.GetCode(contract)
+	assert.Nil(t, err)
+	assert.Equal(t, code, got)
+	assert.Nil(t, p.fetched[contract])
+	assert.Contains(t, st.cache, contract)
+
+	_, ok := p.take(contract)
+	assert.False(t, ok, "already taken")
+	_, ok = p.take(thor.BytesToAddress([]byte("not requested")))
+	assert.False(t, ok)
+}
@@ -0,0 +1,79 @@
+// Copyright (c) 2025 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package state
+
+import (
+	"github.com/ethereum/go-ethereum/rlp"
+
+	"github.com/vechain/thor/v2/muxdb"
+	"github.com/vechain/thor/v2/thor"
+	"github.com/vechain/thor/v2/trie"
+)
+
+// AccountProof is the merkle proof of an account and some of its storage slots.
+type AccountProof struct {
+	Account      *Account   // nil if the account is absent
+	Proof        [][]byte   // nodes on the path of the account key in the account trie
+	Storage      [][]byte   // the rlp raw values of the storage slots, nil if absent
+	StorageProof [][][]byte // nodes on the path of each storage key in the storage trie
+}
+
+// Prove builds the merkle proof of the account at the given address, along with storage
+// slots of the given keys. The proof can be verified against the state root by trie.VerifyProof,
+// with the blake2b hash of address or storage key as the leaf key.
+func (s *Stater) Prove(root trie.Root, addr thor.Address, keys []thor.Bytes32) (*AccountProof, error) {
+	accTrie := s.db.NewTrie(muxdb.AccountTrieName, root)
+	accTrie.SetNoFillCache(true)
+
+	var p AccountProof
+	proof, err := accTrie.Prove(secureKey(addr[:]))
+	if err != nil {
+		return nil, &Error{err}
+	}
+	p.Proof = proof
+	p.Storage = make([][]byte, len(keys))
+	p.StorageProof = make([][][]byte, len(keys))
+
+	data, meta, err := accTrie.Get(secureKey(addr[:]))
+	if err != nil {
+		return nil, &Error{err}
+	}
+	if len(data) == 0 {
+		return &p, nil
+	}
+	var acc Account
+	if err := rlp.DecodeBytes(data, &acc); err != nil {
+		return nil, &Error{err}
+	}
+	p.Account = &acc
+	if len(acc.StorageRoot) == 0 || len(meta) == 0 {
+		return &p, nil
+	}
+	var am AccountMetadata
+	if err := rlp.DecodeBytes(meta, &am); err != nil {
+		return nil, &Error{err}
+	}
+
+	sTrie := s.db.NewTrie(
+		StorageTrieName(am.StorageID),
+		trie.Root{
+			Hash: thor.BytesToBytes32(acc.StorageRoot),
+			Ver: trie.Version{
+				Major: am.StorageMajorVer,
+				Minor: am.StorageMinorVer,
+			},
+		})
+	sTrie.SetNoFillCache(true)
+	for i, key := range keys {
+		if p.Storage[i], err = loadStorage(sTrie, key); err != nil {
+			return nil, &Error{err}
+		}
+		if p.StorageProof[i], err = sTrie.Prove(secureKey(key[:])); err != nil {
+			return nil, &Error{err}
+		}
+	}
+	return &p, nil
+}
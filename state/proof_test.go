@@ -0,0 +1,79 @@
+// Copyright (c) 2025 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package state
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/vechain/thor/v2/muxdb"
+	"github.com/vechain/thor/v2/thor"
+	"github.com/vechain/thor/v2/trie"
+)
+
+func TestProve(t *testing.T) {
+	db := muxdb.NewMem()
+	state := New(db, trie.Root{})
+
+	var (
+		addr     = thor.BytesToAddress([]byte("acc"))
+		plain    = thor.BytesToAddress([]byte("plain"))
+		absent   = thor.BytesToAddress([]byte("absent"))
+		key      = thor.BytesToBytes32([]byte("k"))
+		emptyKey = thor.BytesToBytes32([]byte("empty"))
+	)
+	for i := range 100 {
+		state.SetBalance(thor.BytesToAddress([]byte{byte(i)}), big.NewInt(int64(i+1)))
+	}
+	state.SetBalance(addr, big.NewInt(1))
+	state.SetBalance(plain, big.NewInt(2))
+	state.SetStorage(addr, key, thor.BytesToBytes32([]byte("v")))
+	stage, err := state.Stage(trie.Version{Major: 1})
+	assert.Nil(t, err)
+	hash, err := stage.Commit()
+	assert.Nil(t, err)
+	root := trie.Root{Hash: hash, Ver: trie.Version{Major: 1}}
+
+	stater := NewStater(db)
+
+	p, err := stater.Prove(root, addr, []thor.Bytes32{key, emptyKey})
+	assert.Nil(t, err)
+	data, err := trie.VerifyProof(hash, thor.Blake2b(addr[:]).Bytes(), p.Proof)
+	assert.Nil(t, err)
+	enc, _ := rlp.EncodeToBytes(p.Account)
+	assert.Equal(t, enc, data)
+	assert.Equal(t, big.NewInt(1), p.Account.Balance)
+
+	storageRoot := thor.BytesToBytes32(p.Account.StorageRoot)
+	v, err := trie.VerifyProof(storageRoot, thor.Blake2b(key[:]).Bytes(), p.StorageProof[0])
+	assert.Nil(t, err)
+	assert.Equal(t, p.Storage[0], v)
+	raw, _ := rlp.EncodeToBytes([]byte("v"))
+	assert.Equal(t, raw, v)
+
+	v, err = trie.VerifyProof(storageRoot, thor.Blake2b(emptyKey[:]).Bytes(), p.StorageProof[1])
+	assert.Nil(t, err)
+	assert.Nil(t, v)
+	assert.Empty(t, p.Storage[1])
+
+	// account without storage
+	p, err = stater.Prove(root, plain, []thor.Bytes32{key})
+	assert.Nil(t, err)
+	assert.Equal(t, big.NewInt(2), p.Account.Balance)
+	assert.Empty(t, p.Storage[0])
+	assert.Empty(t, p.StorageProof[0])
+
+	// absent account
+	p, err = stater.Prove(root, absent, []thor.Bytes32{key})
+	assert.Nil(t, err)
+	assert.Nil(t, p.Account)
+	data, err = trie.VerifyProof(hash, thor.Blake2b(absent[:]).Bytes(), p.Proof)
+	assert.Nil(t, err)
+	assert.Nil(t, data)
+}
@@ -0,0 +1,306 @@
+// Copyright (c) 2025 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package state
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/rlp"
+
+	"github.com/vechain/thor/v2/muxdb"
+	"github.com/vechain/thor/v2/thor"
+	"github.com/vechain/thor/v2/trie"
+)
+
+// the count of leaves to be inserted before flushing a trie being built.
+const snapFlushInterval = 50000
+
+var emptyStorageRoot = thor.Blake2b(rlp.EmptyString)
+
+// Range is a range of consecutive trie leaves in key order, along with the merkle proof
+// to verify them against the trie root.
+type Range struct {
+	Keys   [][]byte
+	Values [][]byte
+	Metas  [][]byte // only for storage tries, the preimages of keys
+	Proof  [][]byte // nil if the range covers the whole trie
+}
+
+// AccountRange returns a range of leaves of the account trie, starting from origin.
+// The total size of leaves returned is roughly limited by maxSize.
+func (s *Stater) AccountRange(root trie.Root, origin thor.Bytes32, maxSize int) (*Range, error) {
+	t := s.db.NewTrie(muxdb.AccountTrieName, root)
+	t.SetNoFillCache(true)
+	return trieRange(t, origin, maxSize, false)
+}
+
+// StorageRange returns a range of leaves of the storage trie, which belongs to the account
+// with the given account key (the hashed address), starting from origin.
+func (s *Stater) StorageRange(root trie.Root, accountKey thor.Bytes32, origin thor.Bytes32, maxSize int) (*Range, error) {
+	accTrie := s.db.NewTrie(muxdb.AccountTrieName, root)
+	accTrie.SetNoFillCache(true)
+
+	data, meta, err := accTrie.Get(accountKey[:])
+	if err != nil {
+		return nil, &Error{err}
+	}
+	if len(data) == 0 {
+		return &Range{}, nil
+	}
+	var (
+		acc Account
+		am  AccountMetadata
+	)
+	if err := rlp.DecodeBytes(data, &acc); err != nil {
+		return nil, &Error{err}
+	}
+	if len(acc.StorageRoot) == 0 || len(meta) == 0 {
+		return &Range{}, nil
+	}
+	if err := rlp.DecodeBytes(meta, &am); err != nil {
+		return nil, &Error{err}
+	}
+
+	sTrie := s.db.NewTrie(
+		StorageTrieName(am.StorageID),
+		trie.Root{
+			Hash: thor.BytesToBytes32(acc.StorageRoot),
+			Ver: trie.Version{
+				Major: am.StorageMajorVer,
+				Minor: am.StorageMinorVer,
+			},
+		})
+	sTrie.SetNoFillCache(true)
+	return trieRange(sTrie, origin, maxSize, true)
+}
+
+// Code returns the contract code by its hash. Nil returned if not found.
+func (s *Stater) Code(hash thor.Bytes32) ([]byte, error) {
+	code, err := s.db.NewStore(codeStoreName).Get(hash[:])
+	if err != nil {
+		if s.db.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, &Error{err}
+	}
+	return code, nil
+}
+
+func trieRange(t *muxdb.Trie, origin thor.Bytes32, maxSize int, withMeta bool) (*Range, error) {
+	var (
+		r    Range
+		size int
+		full = true
+		it   = trie.NewIterator(t.NodeIterator(origin[:], 0))
+	)
+
+	for it.Next() {
+		r.Keys = append(r.Keys, append([]byte(nil), it.Key...))
+		r.Values = append(r.Values, it.Value)
+		size += len(it.Key) + len(it.Value)
+		if withMeta {
+			r.Metas = append(r.Metas, it.Meta)
+			size += len(it.Meta)
+		}
+		if size >= maxSize {
+			full = false
+			break
+		}
+	}
+	if it.Err != nil {
+		return nil, &Error{it.Err}
+	}
+
+	// the proof can be omitted if all leaves included
+	if full && origin.IsZero() {
+		return &r, nil
+	}
+
+	proof, err := t.Prove(origin[:])
+	if err != nil {
+		return nil, &Error{err}
+	}
+	if len(r.Keys) > 0 {
+		last, err := t.Prove(r.Keys[len(r.Keys)-1])
+		if err != nil {
+			return nil, &Error{err}
+		}
+		// dedup nodes shared by both proofs
+		known := make(map[string]struct{}, len(proof))
+		for _, node := range proof {
+			known[string(node)] = struct{}{}
+		}
+		for _, node := range last {
+			if _, ok := known[string(node)]; !ok {
+				proof = append(proof, node)
+			}
+		}
+	}
+	r.Proof = proof
+	return &r, nil
+}
+
+// SnapBuilder rebuilds the state of the given root from trie leaves, which are usually
+// fetched from remote peers and verified by range proofs.
+// The tries being built are flushed to the database periodically, and the roots are checked
+// against the expected ones on commit.
+//
+// It's not safe for concurrent use.
+type SnapBuilder struct {
+	db       *muxdb.MuxDB
+	root     trie.Root
+	accounts *muxdb.Trie
+	pending  int
+	sidCount uint64
+}
+
+// NewSnapBuilder creates a snap builder to rebuild the state of the given root.
+func (s *Stater) NewSnapBuilder(root trie.Root) *SnapBuilder {
+	accounts := s.db.NewTrie(muxdb.AccountTrieName, trie.Root{})
+	accounts.SetNoFillCache(true)
+	return &SnapBuilder{
+		db:       s.db,
+		root:     root,
+		accounts: accounts,
+	}
+}
+
+// AddAccount adds an account leaf. It returns the code hash of the account if it's a contract,
+// and the builder of the storage trie if the account has non-empty storage.
+func (b *SnapBuilder) AddAccount(key, value []byte) (codeHash []byte, storage *StorageBuilder, err error) {
+	var acc Account
+	if err := rlp.DecodeBytes(value, &acc); err != nil {
+		return nil, nil, &Error{err}
+	}
+
+	var meta []byte
+	if len(acc.StorageRoot) > 0 {
+		// storage ids are allocated in the same manner as Stage does
+		id := binary.BigEndian.AppendUint32(nil, b.root.Ver.Major)
+		id = binary.AppendUvarint(id, uint64(b.root.Ver.Minor))
+		id = binary.AppendUvarint(id, b.sidCount)
+		b.sidCount++
+
+		if meta, err = rlp.EncodeToBytes(&AccountMetadata{
+			StorageID:       id,
+			StorageMajorVer: b.root.Ver.Major,
+			StorageMinorVer: b.root.Ver.Minor,
+		}); err != nil {
+			return nil, nil, &Error{err}
+		}
+
+		if root := thor.BytesToBytes32(acc.StorageRoot); root != emptyStorageRoot {
+			sTrie := b.db.NewTrie(StorageTrieName(id), trie.Root{})
+			sTrie.SetNoFillCache(true)
+			storage = &StorageBuilder{
+				Account: thor.BytesToBytes32(key),
+				Root:    root,
+				trie:    sTrie,
+				ver:     b.root.Ver,
+			}
+		}
+	}
+
+	if err := b.accounts.Update(key, value, meta); err != nil {
+		return nil, nil, &Error{err}
+	}
+	if err := flush(b.accounts, &b.pending, b.root.Ver); err != nil {
+		return nil, nil, err
+	}
+	return acc.CodeHash, storage, nil
+}
+
+// AddCode adds the contract code. The code is verified against its hash.
+func (b *SnapBuilder) AddCode(hash []byte, code []byte) error {
+	if thor.Keccak256(code) != thor.BytesToBytes32(hash) {
+		return &Error{errors.New("code hash mismatch")}
+	}
+	if err := b.db.NewStore(codeStoreName).Put(hash, code); err != nil {
+		return &Error{err}
+	}
+	return nil
+}
+
+// Commit writes the account trie into the database, and checks the root.
+func (b *SnapBuilder) Commit() error {
+	if h := b.accounts.Hash(); h != b.root.Hash {
+		return &Error{fmt.Errorf("state root mismatch: want %v, got %v", b.root.Hash, h)}
+	}
+	if err := b.accounts.Commit(b.root.Ver, false); err != nil {
+		return &Error{err}
+	}
+	return nil
+}
+
+// StorageBuilder rebuilds a storage trie of the state being built by SnapBuilder.
+type StorageBuilder struct {
+	Account thor.Bytes32 // the key of the account leaf
+	Root    thor.Bytes32 // the expected storage root
+
+	trie    *muxdb.Trie
+	ver     trie.Version
+	pending int
+}
+
+// VerifyStorageKeys checks that metas of storage leaves are the preimages of keys,
+// which are the hashed storage keys.
+func VerifyStorageKeys(keys, metas [][]byte) error {
+	if len(keys) != len(metas) {
+		return errors.New("metas count mismatch")
+	}
+	for i, key := range keys {
+		if len(metas[i]) > 32 || !bytes.Equal(thor.Blake2b(thor.BytesToBytes32(metas[i]).Bytes()).Bytes(), key) {
+			return errors.New("storage key preimage mismatch")
+		}
+	}
+	return nil
+}
+
+// Add adds storage leaves. Metas, which are the preimages of keys, are verified.
+func (b *StorageBuilder) Add(keys, values, metas [][]byte) error {
+	if len(keys) != len(values) {
+		return &Error{errors.New("leaves count mismatch")}
+	}
+	if err := VerifyStorageKeys(keys, metas); err != nil {
+		return &Error{err}
+	}
+	for i, key := range keys {
+		if err := b.trie.Update(key, values[i], metas[i]); err != nil {
+			return &Error{err}
+		}
+		if err := flush(b.trie, &b.pending, b.ver); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Commit writes the storage trie into the database, and checks the root.
+func (b *StorageBuilder) Commit() error {
+	if h := b.trie.Hash(); h != b.Root {
+		return &Error{fmt.Errorf("storage root mismatch: want %v, got %v", b.Root, h)}
+	}
+	if err := b.trie.Commit(b.ver, false); err != nil {
+		return &Error{err}
+	}
+	return nil
+}
+
+// flush commits the trie being built to release memory, when enough leaves inserted.
+func flush(t *muxdb.Trie, pending *int, ver trie.Version) error {
+	*pending++
+	if *pending < snapFlushInterval {
+		return nil
+	}
+	*pending = 0
+	if err := t.Commit(ver, false); err != nil {
+		return &Error{err}
+	}
+	return nil
+}
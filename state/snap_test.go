@@ -0,0 +1,160 @@
+// Copyright (c) 2025 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package state
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/vechain/thor/v2/muxdb"
+	"github.com/vechain/thor/v2/thor"
+	"github.com/vechain/thor/v2/trie"
+)
+
+func nextKey(key []byte) thor.Bytes32 {
+	next := thor.BytesToBytes32(key)
+	for i := len(next) - 1; i >= 0; i-- {
+		next[i]++
+		if next[i] != 0 {
+			break
+		}
+	}
+	return next
+}
+
+func TestSnap(t *testing.T) {
+	db := muxdb.NewMem()
+	state := New(db, trie.Root{})
+
+	var addrs []thor.Address
+	for i := range 200 {
+		addr := thor.BytesToAddress([]byte{byte(i), 1})
+		addrs = append(addrs, addr)
+		state.SetBalance(addr, big.NewInt(int64(i+1)))
+		if i%10 == 0 {
+			state.SetCode(addr, []byte{byte(i), 2, 3})
+			for j := range 100 {
+				state.SetStorage(addr, thor.BytesToBytes32([]byte{byte(j)}), thor.BytesToBytes32([]byte{byte(i), byte(j)}))
+			}
+		}
+	}
+	stage, err := state.Stage(trie.Version{Major: 1})
+	assert.Nil(t, err)
+	hash, err := stage.Commit()
+	assert.Nil(t, err)
+	root := trie.Root{Hash: hash, Ver: trie.Version{Major: 1}}
+
+	src := NewStater(db)
+	dstDB := muxdb.NewMem()
+	builder := NewStater(dstDB).NewSnapBuilder(root)
+
+	const maxSize = 1000
+	var (
+		origin   thor.Bytes32
+		storages []*StorageBuilder
+		codes    [][]byte
+	)
+	for {
+		r, err := src.AccountRange(root, origin, maxSize)
+		assert.Nil(t, err)
+		more, err := trie.VerifyRangeProof(hash, origin[:], r.Keys, r.Values, r.Proof)
+		assert.Nil(t, err)
+
+		for i, key := range r.Keys {
+			codeHash, storage, err := builder.AddAccount(key, r.Values[i])
+			assert.Nil(t, err)
+			if len(codeHash) > 0 {
+				codes = append(codes, codeHash)
+			}
+			if storage != nil {
+				storages = append(storages, storage)
+			}
+		}
+		if !more {
+			break
+		}
+		origin = nextKey(r.Keys[len(r.Keys)-1])
+	}
+	assert.Len(t, storages, 20)
+	assert.Len(t, codes, 20)
+
+	for _, sb := range storages {
+		var origin thor.Bytes32
+		for {
+			r, err := src.StorageRange(root, sb.Account, origin, maxSize)
+			assert.Nil(t, err)
+			more, err := trie.VerifyRangeProof(sb.Root, origin[:], r.Keys, r.Values, r.Proof)
+			assert.Nil(t, err)
+			assert.Nil(t, sb.Add(r.Keys, r.Values, r.Metas))
+			if !more {
+				break
+			}
+			origin = nextKey(r.Keys[len(r.Keys)-1])
+		}
+		assert.Nil(t, sb.Commit())
+	}
+
+	for _, h := range codes {
+		code, err := src.Code(thor.BytesToBytes32(h))
+		assert.Nil(t, err)
+		assert.NotNil(t, builder.AddCode(h, append(code, 0)), "bad code should be rejected")
+		assert.Nil(t, builder.AddCode(h, code))
+	}
+	assert.Nil(t, builder.Commit())
+
+	// the rebuilt state should be identical
+	dst := New(dstDB, root)
+	for i, addr := range addrs {
+		assert.Equal(t, M(big.NewInt(int64(i+1)), nil), M(dst.GetBalance(addr)))
+		if i%10 == 0 {
+			assert.Equal(t, M([]byte{byte(i), 2, 3}, nil), M(dst.GetCode(addr)))
+			for j := range 100 {
+				assert.Equal(t, M(thor.BytesToBytes32([]byte{byte(i), byte(j)}), nil), M(dst.GetStorage(addr, thor.BytesToBytes32([]byte{byte(j)}))))
+			}
+		}
+	}
+
+	// and can be updated further
+	dst.SetStorage(addrs[0], thor.BytesToBytes32([]byte("new")), thor.BytesToBytes32([]byte("v")))
+	stage, err = dst.Stage(trie.Version{Major: 2})
+	assert.Nil(t, err)
+	_, err = stage.Commit()
+	assert.Nil(t, err)
+}
+
+func TestSnapBadStorage(t *testing.T) {
+	db := muxdb.NewMem()
+	state := New(db, trie.Root{})
+	addr := thor.BytesToAddress([]byte("acc"))
+	state.SetBalance(addr, big.NewInt(1))
+	state.SetStorage(addr, thor.BytesToBytes32([]byte("k")), thor.BytesToBytes32([]byte("v")))
+	stage, err := state.Stage(trie.Version{Major: 1})
+	assert.Nil(t, err)
+	hash, err := stage.Commit()
+	assert.Nil(t, err)
+	root := trie.Root{Hash: hash, Ver: trie.Version{Major: 1}}
+
+	src := NewStater(db)
+	r, err := src.AccountRange(root, thor.Bytes32{}, 1000)
+	assert.Nil(t, err)
+	assert.Nil(t, r.Proof, "no proof for the whole trie")
+
+	builder := NewStater(muxdb.NewMem()).NewSnapBuilder(root)
+	_, sb, err := builder.AddAccount(r.Keys[0], r.Values[0])
+	assert.Nil(t, err)
+
+	sr, err := src.StorageRange(root, sb.Account, thor.Bytes32{}, 1000)
+	assert.Nil(t, err)
+	assert.Nil(t, VerifyStorageKeys(sr.Keys, sr.Metas))
+	assert.NotNil(t, VerifyStorageKeys(sr.Keys, nil), "metas count mismatch")
+	assert.NotNil(t, sb.Add(sr.Keys, sr.Values, [][]byte{[]byte("x")}), "bad preimage")
+	assert.NotNil(t, sb.Commit(), "incomplete storage")
+	assert.Nil(t, sb.Add(sr.Keys, sr.Values, sr.Metas))
+	assert.Nil(t, sb.Commit())
+	assert.Nil(t, builder.Commit())
+}
@@ -0,0 +1,435 @@
+// Copyright (c) 2025 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package state
+
+import (
+	"context"
+	"encoding/binary"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/syndtr/goleveldb/leveldb/util"
+
+	"github.com/vechain/thor/v2/kv"
+	"github.com/vechain/thor/v2/muxdb"
+	"github.com/vechain/thor/v2/thor"
+	"github.com/vechain/thor/v2/trie"
+)
+
+const (
+	snapshotStoreName = "state.snapshot"
+
+	snapshotAccountSpace = byte(0) // hashed address => account
+	snapshotStorageSpace = byte(1) // storage id + hashed key => storage value
+
+	snapshotRootKey = "root" // the root of the state held by the disk layer
+)
+
+// Snapshot is the flat key-value view of recent states, which reads an account or a storage slot in one
+// lookup other than traversing tries.
+//
+// It consists of the disk layer, which persists the full state of a block, and in-memory diff layers on top
+// of it, each of which holds changes of a block. Diff layers form a tree since blocks may fork, and are
+// flattened into the disk layer by Cap as blocks go final. States not in the snapshot are read from tries.
+type Snapshot struct {
+	db     *muxdb.MuxDB
+	store  kv.Store
+	lock   sync.RWMutex
+	root   trie.Root // root of the disk layer
+	ready  bool      // whether the disk layer is generated
+	layers map[trie.Root]*diffLayer
+}
+
+// diffLayer holds changes of a block.
+type diffLayer struct {
+	parent   *diffLayer        // nil if on the disk layer
+	root     trie.Root         // root of the state after changes
+	accounts map[string][]byte // account key => encoded account, nil if empty
+	storage  map[string][]byte // storage key => value, empty if zero
+}
+
+// NewSnapshot opens the snapshot in the given DB.
+func NewSnapshot(db *muxdb.MuxDB) (*Snapshot, error) {
+	s := &Snapshot{
+		db:     db,
+		store:  db.NewStore(snapshotStoreName),
+		layers: make(map[trie.Root]*diffLayer),
+	}
+	data, err := s.store.Get([]byte(snapshotRootKey))
+	if err != nil {
+		if s.store.IsNotFound(err) {
+			return s, nil
+		}
+		return nil, err
+	}
+	copy(s.root.Hash[:], data)
+	s.root.Ver = trie.Version{
+		Major: binary.BigEndian.Uint32(data[32:]),
+		Minor: binary.BigEndian.Uint32(data[36:]),
+	}
+	s.ready = true
+	return s, nil
+}
+
+// Root returns the root of the state held by the disk layer. False returned if the disk layer is not generated,
+// or its generation was interrupted.
+func (s *Snapshot) Root() (trie.Root, bool) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.root, s.ready
+}
+
+// Generate regenerates the disk layer from tries with the full state of the given root. All diff layers are dropped.
+func (s *Snapshot) Generate(ctx context.Context, root trie.Root) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	// the root key is removed first, so that an interrupted generation is detectable
+	s.ready = false
+	s.layers = make(map[trie.Root]*diffLayer)
+	if err := s.store.DeleteRange(ctx, kv.Range{}); err != nil {
+		return err
+	}
+
+	var (
+		bulk    = s.store.Bulk()
+		keyBuf  []byte
+		count   int
+		accTrie = s.db.NewTrie(muxdb.AccountTrieName, root)
+	)
+	bulk.EnableAutoFlush()
+	accTrie.SetNoFillCache(true)
+
+	accIter := trie.NewIterator(accTrie.NodeIterator(nil, 0))
+	for accIter.Next() {
+		// check context every 1000 accounts
+		if count++; count%1000 == 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			default:
+			}
+		}
+
+		var (
+			acc Account
+			am  AccountMetadata
+		)
+		if err := rlp.DecodeBytes(accIter.Value, &acc); err != nil {
+			return err
+		}
+		if len(accIter.Meta) > 0 {
+			if err := rlp.DecodeBytes(accIter.Meta, &am); err != nil {
+				return err
+			}
+		}
+		data, err := encodeArchivedAccount(&acc, &am)
+		if err != nil {
+			return err
+		}
+		if err := bulk.Put(appendSnapshotAccountKey(keyBuf[:0], accIter.Key), data); err != nil {
+			return err
+		}
+
+		if len(acc.StorageRoot) == 0 {
+			continue
+		}
+		sTrie := s.db.NewTrie(
+			StorageTrieName(am.StorageID),
+			trie.Root{
+				Hash: thor.BytesToBytes32(acc.StorageRoot),
+				Ver: trie.Version{
+					Major: am.StorageMajorVer,
+					Minor: am.StorageMinorVer,
+				},
+			})
+		sTrie.SetNoFillCache(true)
+		sIter := trie.NewIterator(sTrie.NodeIterator(nil, 0))
+		for sIter.Next() {
+			if err := bulk.Put(appendSnapshotStorageKey(keyBuf[:0], am.StorageID, sIter.Key), sIter.Value); err != nil {
+				return err
+			}
+		}
+		if sIter.Err != nil {
+			return sIter.Err
+		}
+	}
+	if accIter.Err != nil {
+		return accIter.Err
+	}
+
+	if err := putSnapshotRoot(bulk, root); err != nil {
+		return err
+	}
+	if err := bulk.Write(); err != nil {
+		return err
+	}
+	s.root, s.ready = root, true
+	return nil
+}
+
+// Cap keeps at most the given number of diff layers from the head down, and flattens the rest into the disk
+// layer. Layers not descending from the new disk layer, which belong to abandoned forks, are dropped.
+// Nothing done if the head is not in the snapshot.
+func (s *Snapshot) Cap(head trie.Root, layers int) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	top := s.layers[head]
+	for ; top != nil && layers > 0; layers-- {
+		top = top.parent
+	}
+	if top == nil {
+		return nil
+	}
+	if err := s.flatten(top); err != nil {
+		return err
+	}
+
+	for root, l := range s.layers {
+		if !l.descends(top) {
+			delete(s.layers, root)
+		}
+	}
+	for _, l := range s.layers {
+		if l.parent == top {
+			l.parent = nil
+		}
+	}
+	s.root = top.root
+	return nil
+}
+
+// flatten writes changes of the given layer and all its ancestors into the disk layer.
+func (s *Snapshot) flatten(top *diffLayer) error {
+	var chain []*diffLayer
+	for l := top; l != nil; l = l.parent {
+		chain = append(chain, l)
+	}
+
+	var (
+		accounts  = make(map[string][]byte)
+		storage   = make(map[string][]byte)
+		abandoned = make(map[string]bool) // storage ids no longer referenced
+	)
+	// merge from the oldest
+	for i := len(chain) - 1; i >= 0; i-- {
+		for key, data := range chain[i].accounts {
+			prev, ok := accounts[key]
+			if !ok {
+				var err error
+				if prev, err = s.store.Get([]byte(key)); err != nil && !s.store.IsNotFound(err) {
+					return err
+				}
+			}
+			prevSID, err := archivedStorageID(prev)
+			if err != nil {
+				return err
+			}
+			if len(prevSID) > 0 {
+				if sid, err := archivedStorageID(data); err != nil {
+					return err
+				} else if sid != prevSID {
+					abandoned[prevSID] = true
+				}
+			}
+			accounts[key] = data
+		}
+		for key, v := range chain[i].storage {
+			storage[key] = v
+		}
+	}
+
+	bulk := s.store.Bulk()
+	for sid := range abandoned {
+		if err := s.deleteStorage(bulk, sid); err != nil {
+			return err
+		}
+	}
+	for key, v := range storage {
+		if abandoned[snapshotStorageID(key)] {
+			continue
+		}
+		if err := putOrDelete(bulk, []byte(key), v); err != nil {
+			return err
+		}
+	}
+	for key, data := range accounts {
+		if err := putOrDelete(bulk, []byte(key), data); err != nil {
+			return err
+		}
+	}
+	if err := putSnapshotRoot(bulk, top.root); err != nil {
+		return err
+	}
+	return bulk.Write()
+}
+
+// deleteStorage deletes all storage values of the given storage id from the disk layer.
+func (s *Snapshot) deleteStorage(bulk kv.Bulk, sid string) error {
+	iter := s.store.Iterate(kv.Range(*util.BytesPrefix(appendSnapshotStoragePrefix(nil, []byte(sid)))))
+	defer iter.Release()
+	for iter.Next() {
+		if err := bulk.Delete(append([]byte(nil), iter.Key()...)); err != nil {
+			return err
+		}
+	}
+	return iter.Error()
+}
+
+// add adds the diff layer on top of the layer of the parent root. The diff layer is discarded if the parent
+// is not in the snapshot.
+func (s *Snapshot) add(parent trie.Root, diff *diffLayer) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if _, ok := s.layers[diff.root]; ok {
+		return
+	}
+	if p, ok := s.layers[parent]; ok {
+		diff.parent = p
+	} else if !s.ready || parent != s.root {
+		return
+	}
+	s.layers[diff.root] = diff
+}
+
+// has returns whether the state of the given root is in the snapshot.
+func (s *Snapshot) has(root trie.Root) bool {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	_, ok := s.layers[root]
+	return ok || (s.ready && root == s.root)
+}
+
+// get gets the value of the key from the state of the given root. False returned if the state is not in
+// the snapshot. Nil value returned if the key is absent in the state.
+func (s *Snapshot) get(root trie.Root, key []byte, fromDiff func(l *diffLayer) ([]byte, bool)) ([]byte, bool, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	l, ok := s.layers[root]
+	if !ok && (!s.ready || root != s.root) {
+		return nil, false, nil
+	}
+	for ; l != nil; l = l.parent {
+		if v, ok := fromDiff(l); ok {
+			return v, true, nil
+		}
+	}
+	v, err := s.store.Get(key)
+	if err != nil {
+		if s.store.IsNotFound(err) {
+			return nil, true, nil
+		}
+		return nil, false, err
+	}
+	return v, true, nil
+}
+
+// newReader creates the reader to read the state of the given root. Nil returned if the state is not in the snapshot.
+func (s *Snapshot) newReader(root trie.Root) *snapshotReader {
+	if !s.has(root) {
+		return nil
+	}
+	return &snapshotReader{s, root}
+}
+
+// descends returns whether the layer is a descendant of the given ancestor.
+func (l *diffLayer) descends(ancestor *diffLayer) bool {
+	for p := l.parent; p != nil; p = p.parent {
+		if p == ancestor {
+			return true
+		}
+	}
+	return false
+}
+
+func newDiffLayer(root trie.Root) *diffLayer {
+	return &diffLayer{
+		root:     root,
+		accounts: make(map[string][]byte),
+		storage:  make(map[string][]byte),
+	}
+}
+
+func (l *diffLayer) putAccount(addr thor.Address, acc *Account, am *AccountMetadata) error {
+	data, err := encodeArchivedAccount(acc, am)
+	if err != nil {
+		return err
+	}
+	l.accounts[string(appendSnapshotAccountKey(nil, secureKey(addr[:])))] = data
+	return nil
+}
+
+func (l *diffLayer) putStorage(sid []byte, key thor.Bytes32, value rlp.RawValue) {
+	l.storage[string(appendSnapshotStorageKey(nil, sid, secureKey(key[:])))] = value
+}
+
+// snapshotReader reads the state of a root from the snapshot. Reads of a state that has been flattened away
+// report not found, and should fall back to tries.
+type snapshotReader struct {
+	snapshot *Snapshot
+	root     trie.Root
+}
+
+// loadAccount loads the account and its metadata by address. False returned if the state is not in the snapshot.
+func (r *snapshotReader) loadAccount(addr thor.Address) (*Account, *AccountMetadata, bool, error) {
+	key := appendSnapshotAccountKey(nil, secureKey(addr[:]))
+	data, ok, err := r.snapshot.get(r.root, key, func(l *diffLayer) ([]byte, bool) {
+		v, ok := l.accounts[string(key)]
+		return v, ok
+	})
+	if err != nil || !ok {
+		return nil, nil, ok, err
+	}
+	acc, am, err := decodeArchivedAccount(data)
+	if err != nil {
+		return nil, nil, false, err
+	}
+	return acc, am, true, nil
+}
+
+// loadStorage loads the storage value of the storage identified by sid. False returned if the state is not
+// in the snapshot.
+func (r *snapshotReader) loadStorage(sid []byte, key thor.Bytes32) (rlp.RawValue, bool, error) {
+	skey := appendSnapshotStorageKey(nil, sid, secureKey(key[:]))
+	return r.snapshot.get(r.root, skey, func(l *diffLayer) ([]byte, bool) {
+		v, ok := l.storage[string(skey)]
+		return v, ok
+	})
+}
+
+func putOrDelete(putter kv.Putter, key, val []byte) error {
+	if len(val) == 0 {
+		return putter.Delete(key)
+	}
+	return putter.Put(key, val)
+}
+
+func putSnapshotRoot(putter kv.Putter, root trie.Root) error {
+	return putter.Put([]byte(snapshotRootKey), appendVersion(root.Hash.Bytes(), root.Ver))
+}
+
+func appendSnapshotAccountKey(buf []byte, hashedAddr []byte) []byte {
+	return append(append(buf, snapshotAccountSpace), hashedAddr...)
+}
+
+func appendSnapshotStoragePrefix(buf []byte, sid []byte) []byte {
+	// sid is length prefixed, since it's variable-length
+	buf = append(buf, snapshotStorageSpace, byte(len(sid)))
+	return append(buf, sid...)
+}
+
+func appendSnapshotStorageKey(buf []byte, sid []byte, hashedKey []byte) []byte {
+	return append(appendSnapshotStoragePrefix(buf, sid), hashedKey...)
+}
+
+// snapshotStorageID extracts the storage id from the storage key.
+func snapshotStorageID(key string) string {
+	return key[2 : 2+int(key[1])]
+}
@@ -0,0 +1,146 @@
+// Copyright (c) 2025 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package state
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vechain/thor/v2/kv"
+	"github.com/vechain/thor/v2/muxdb"
+	"github.com/vechain/thor/v2/thor"
+	"github.com/vechain/thor/v2/trie"
+)
+
+func dumpSnapshot(t *testing.T, snapshot *Snapshot) map[string]string {
+	entries := make(map[string]string)
+	iter := snapshot.store.Iterate(kv.Range{})
+	defer iter.Release()
+	for iter.Next() {
+		entries[string(iter.Key())] = string(iter.Value())
+	}
+	require.NoError(t, iter.Error())
+	return entries
+}
+
+func TestSnapshot(t *testing.T) {
+	db := muxdb.NewMem()
+	snapshot, err := NewSnapshot(db)
+	require.NoError(t, err)
+	_, ok := snapshot.Root()
+	assert.False(t, ok)
+	require.NoError(t, snapshot.Generate(context.Background(), trie.Root{}))
+
+	stater := NewStater(db)
+	stater.EnableSnapshot(snapshot)
+
+	addr := thor.BytesToAddress([]byte("addr"))
+	key := thor.BytesToBytes32([]byte("key"))
+	forkedAddr := thor.BytesToAddress([]byte("forked"))
+
+	// #1 balance, storage and code set
+	st := stater.NewState(trie.Root{})
+	st.SetBalance(addr, big.NewInt(1))
+	st.SetStorage(addr, key, thor.BytesToBytes32([]byte("v1")))
+	st.SetCode(addr, []byte("code"))
+	root1 := commitState(t, st, trie.Version{Major: 1})
+
+	// #2 only balance changed
+	st = stater.NewState(root1)
+	st.SetBalance(addr, big.NewInt(2))
+	root2 := commitState(t, st, trie.Version{Major: 2})
+
+	// #2' forked block, storage changed
+	st = stater.NewState(root1)
+	st.SetStorage(addr, key, thor.BytesToBytes32([]byte("v2'")))
+	st.SetBalance(forkedAddr, big.NewInt(1))
+	root2x := commitState(t, st, trie.Version{Major: 2, Minor: 1})
+
+	// #3 account deleted, and storage set again
+	st = stater.NewState(root2)
+	st.Delete(addr)
+	st.SetBalance(addr, big.NewInt(3))
+	other := thor.BytesToAddress([]byte("other"))
+	st.SetBalance(other, big.NewInt(1))
+	st.SetStorage(other, key, thor.BytesToBytes32([]byte("other")))
+	root3 := commitState(t, st, trie.Version{Major: 3})
+
+	check := func(root trie.Root, inSnapshot bool) {
+		st := stater.NewState(root)
+		assert.Equal(t, inSnapshot, st.snapshotReader != nil, "in snapshot at %v", root.Ver)
+
+		// the same as read from tries
+		trieSt := New(db, root)
+		for _, a := range []thor.Address{addr, forkedAddr} {
+			balance, err := st.GetBalance(a)
+			assert.NoError(t, err)
+			trieBalance, _ := trieSt.GetBalance(a)
+			assert.Equal(t, trieBalance, balance, "balance at %v", root.Ver)
+
+			storage, err := st.GetStorage(a, key)
+			assert.NoError(t, err)
+			trieStorage, _ := trieSt.GetStorage(a, key)
+			assert.Equal(t, trieStorage, storage, "storage at %v", root.Ver)
+
+			code, err := st.GetCode(a)
+			assert.NoError(t, err)
+			trieCode, _ := trieSt.GetCode(a)
+			assert.Equal(t, trieCode, code, "code at %v", root.Ver)
+		}
+	}
+	for _, root := range []trie.Root{{}, root1, root2, root2x, root3} {
+		check(root, true)
+	}
+
+	// flattened to #2, the fork dropped
+	require.NoError(t, snapshot.Cap(root3, 1))
+	diskRoot, ok := snapshot.Root()
+	assert.True(t, ok)
+	assert.Equal(t, root2, diskRoot)
+	check(root2x, false)
+	check(root1, false)
+	check(root2, true)
+	check(root3, true)
+
+	// readers of flattened states fall back to tries
+	st2 := stater.NewState(root2)
+	require.NoError(t, snapshot.Cap(root3, 0))
+	balance, err := st2.GetBalance(addr)
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(2), balance)
+	storage, err := st2.GetStorage(addr, key)
+	assert.NoError(t, err)
+	assert.Equal(t, thor.BytesToBytes32([]byte("v1")), storage)
+	check(root3, true)
+
+	// unknown head
+	require.NoError(t, snapshot.Cap(root2x, 0))
+	diskRoot, _ = snapshot.Root()
+	assert.Equal(t, root3, diskRoot)
+
+	// reopened
+	snapshot, err = NewSnapshot(db)
+	require.NoError(t, err)
+	diskRoot, ok = snapshot.Root()
+	assert.True(t, ok)
+	assert.Equal(t, root3, diskRoot)
+
+	// storage of the deleted account is removed, and the flattened equals to the generated
+	flattened := dumpSnapshot(t, snapshot)
+	storageCount := 0
+	for k := range flattened {
+		if k[0] == snapshotStorageSpace {
+			storageCount++
+		}
+	}
+	assert.Equal(t, 1, storageCount)
+	require.NoError(t, snapshot.Generate(context.Background(), root3))
+	assert.Equal(t, flattened, dumpSnapshot(t, snapshot))
+}
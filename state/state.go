@@ -13,6 +13,7 @@ import (
 
 	"github.com/ethereum/go-ethereum/rlp"
 
+	"github.com/vechain/thor/v2/kv"
 	"github.com/vechain/thor/v2/muxdb"
 	"github.com/vechain/thor/v2/stackedmap"
 	"github.com/vechain/thor/v2/thor"
@@ -41,12 +42,26 @@ func (e *Error) Error() string {
 	return fmt.Sprintf("state: %v", e.cause)
 }
 
+func (e *Error) Unwrap() error {
+	return e.cause
+}
+
 // State manages the world state.
 type State struct {
 	db    *muxdb.MuxDB
 	trie  *muxdb.Trie                    // the accounts trie reader
 	cache map[thor.Address]*cachedObject // cache of accounts trie
 	sm    *stackedmap.StackedMap         // keeps revisions of accounts state
+
+	tracker *tracker // tracks reads, nil if not tracking
+
+	archive       *Archive       // indexes changes on commit, nil if not archiving
+	archiveReader *archiveReader // reads accounts and storage from the archive other than tries, if not nil
+
+	snapshot       *Snapshot       // maintained on commit, nil if not enabled
+	snapshotReader *snapshotReader // reads accounts and storage from the snapshot, if not nil
+
+	prefetcher *Prefetcher // serves prefetched accounts, nil if not used
 }
 
 // New create state object.
@@ -63,9 +78,26 @@ func New(db *muxdb.MuxDB, root trie.Root) *State {
 	return &state
 }
 
+// UsePrefetcher makes the state take accounts loaded by the prefetcher, which should be upon the same root.
+func (s *State) UsePrefetcher(p *Prefetcher) {
+	if s.archiveReader == nil {
+		s.prefetcher = p
+	}
+}
+
 // Checkout checkouts to another state.
 func (s *State) Checkout(root trie.Root) *State {
-	return New(s.db, root)
+	st := New(s.db, root)
+	st.archive = s.archive
+	st.setSnapshot(s.snapshot, root)
+	return st
+}
+
+func (s *State) setSnapshot(snapshot *Snapshot, root trie.Root) {
+	if snapshot != nil {
+		s.snapshot = snapshot
+		s.snapshotReader = snapshot.newReader(root)
+	}
 }
 
 // cacheGetter implements stackedmap.MapGetter.
@@ -113,18 +145,42 @@ func (s *State) getCachedObject(addr thor.Address) (*cachedObject, error) {
 	if co, ok := s.cache[addr]; ok {
 		return co, nil
 	}
-	a, am, err := loadAccount(s.trie, addr)
+	var (
+		a   *Account
+		am  *AccountMetadata
+		err error
+	)
+	if s.prefetcher != nil {
+		if co, ok := s.prefetcher.take(addr); ok {
+			co.snapshotReader = s.snapshotReader
+			s.cache[addr] = co
+			return co, nil
+		}
+	}
+	if s.archiveReader != nil {
+		a, am, err = s.archiveReader.loadAccount(addr)
+	} else {
+		found := false
+		if s.snapshotReader != nil {
+			a, am, found, err = s.snapshotReader.loadAccount(addr)
+		}
+		if !found && err == nil {
+			a, am, err = loadAccount(s.trie, addr)
+		}
+	}
 	if err != nil {
 		return nil, err
 	}
 	co := newCachedObject(s.db, addr, a, am)
+	co.archiveReader = s.archiveReader
+	co.snapshotReader = s.snapshotReader
 	s.cache[addr] = co
 	return co, nil
 }
 
 // getAccount gets account by address. the returned account should not be modified.
 func (s *State) getAccount(addr thor.Address) (*Account, error) {
-	v, _, err := s.sm.Get(addr)
+	v, err := s.get(addr)
 	if err != nil {
 		return nil, err
 	}
@@ -145,7 +201,7 @@ func (s *State) updateAccount(addr thor.Address, acc *Account) {
 }
 
 func (s *State) getStorageBarrier(addr thor.Address) int {
-	b, _, _ := s.sm.Get(storageBarrierKey(addr))
+	b, _ := s.get(storageBarrierKey(addr))
 	return b.(int)
 }
 
@@ -252,7 +308,7 @@ func (s *State) SetStorage(addr thor.Address, key, value thor.Bytes32) {
 
 // GetRawStorage returns storage value in rlp raw for given address and key.
 func (s *State) GetRawStorage(addr thor.Address, key thor.Bytes32) (rlp.RawValue, error) {
-	data, _, err := s.sm.Get(storageKey{addr, s.getStorageBarrier(addr), key})
+	data, err := s.get(storageKey{addr, s.getStorageBarrier(addr), key})
 	if err != nil {
 		return nil, &Error{err}
 	}
@@ -290,7 +346,7 @@ func (s *State) DecodeStorage(addr thor.Address, key thor.Bytes32, dec func([]by
 
 // GetCode returns code for the given address.
 func (s *State) GetCode(addr thor.Address) ([]byte, error) {
-	v, _, err := s.sm.Get(codeKey(addr))
+	v, err := s.get(codeKey(addr))
 	if err != nil {
 		return nil, &Error{err}
 	}
@@ -516,6 +572,8 @@ func (s *State) Stage(newVer trie.Version) (*Stage, error) {
 	}
 	root := trieCpy.Hash()
 	tries = append(tries, trieCpy)
+	archive := s.archive
+	snapshotReader := s.snapshotReader
 
 	return &Stage{
 		root: root,
@@ -536,6 +594,45 @@ func (s *State) Stage(newVer trie.Version) (*Stage, error) {
 					return err
 				}
 			}
+			if archive != nil {
+				// index changes after tries committed
+				if err := archive.index(newVer, func(putter kv.Putter) error {
+					var keyBuf []byte
+					for addr, c := range changes {
+						keyBuf = appendArchiveAccountKey(keyBuf[:0], secureKey(addr[:]), newVer)
+						if err := putArchivedAccount(putter, keyBuf, &c.data, &c.meta); err != nil {
+							return err
+						}
+						if c.data.IsEmpty() {
+							continue
+						}
+						for k, v := range c.storage {
+							keyBuf = appendArchiveStorageKey(keyBuf[:0], c.meta.StorageID, secureKey(k[:]), newVer)
+							if err := putter.Put(keyBuf, v); err != nil {
+								return err
+							}
+						}
+					}
+					return nil
+				}); err != nil {
+					return err
+				}
+			}
+			if snapshotReader != nil {
+				diff := newDiffLayer(trie.Root{Hash: root, Ver: newVer})
+				for addr, c := range changes {
+					if err := diff.putAccount(addr, &c.data, &c.meta); err != nil {
+						return err
+					}
+					if c.data.IsEmpty() {
+						continue
+					}
+					for k, v := range c.storage {
+						diff.putStorage(c.meta.StorageID, k, v)
+					}
+				}
+				snapshotReader.snapshot.add(snapshotReader.root, diff)
+			}
 			// Just once for the account trie.
 			metricAccountChanges().Add(int64(len(changes)))
 			return nil
@@ -12,15 +12,67 @@ import (
 
 // Stater is the state creator.
 type Stater struct {
-	db *muxdb.MuxDB
+	db       *muxdb.MuxDB
+	archive  *Archive
+	snapshot *Snapshot
 }
 
 // NewStater create a new stater.
 func NewStater(db *muxdb.MuxDB) *Stater {
-	return &Stater{db}
+	return &Stater{db: db}
+}
+
+// NewArchiveStater create a new stater, whose states index their changes into the archive when committed,
+// and historical states are read from the archive.
+func NewArchiveStater(db *muxdb.MuxDB, archive *Archive) *Stater {
+	return &Stater{db: db, archive: archive}
+}
+
+// EnableSnapshot makes states maintain the snapshot when committed, and states in the snapshot read from it.
+// It should be called before any state created.
+func (s *Stater) EnableSnapshot(snapshot *Snapshot) {
+	s.snapshot = snapshot
+}
+
+// Snapshot returns the snapshot, nil if not enabled.
+func (s *Stater) Snapshot() *Snapshot {
+	return s.snapshot
 }
 
 // NewState create a new state object.
 func (s *Stater) NewState(root trie.Root) *State {
-	return New(s.db, root)
+	st := New(s.db, root)
+	st.archive = s.archive
+	st.setSnapshot(s.snapshot, root)
+	return st
+}
+
+// IsPruned returns whether the state of the given root has been pruned.
+func (s *Stater) IsPruned(root trie.Root) bool {
+	return root.Ver.Major < s.PrunedBefore()
+}
+
+// PrunedBefore returns the block number, before which states have been pruned.
+func (s *Stater) PrunedBefore() uint32 {
+	return s.db.TrieHistoryPrunedBefore()
+}
+
+// MarkPrunedBefore marks states before the block number as pruned, e.g. the state is synced at
+// the block without its history.
+func (s *Stater) MarkPrunedBefore(num uint32) error {
+	return s.db.MarkTrieHistoryPruned(num)
+}
+
+// NewHistoricalState create a state object for reading historical state, which is read from the archive
+// if archived. onChain reports whether the block of the given version is on the chain the root belongs to.
+func (s *Stater) NewHistoricalState(root trie.Root, onChain func(ver trie.Version) (bool, error)) (*State, error) {
+	st := s.NewState(root)
+	if s.archive != nil {
+		reader, err := s.archive.newReader(root.Ver, onChain)
+		if err != nil {
+			return nil, &Error{err}
+		}
+		st.archiveReader = reader
+	}
+	return st, nil
 }
@@ -152,6 +152,30 @@ func (c *Client) GetRawAccountStorage(addr *thor.Address, key *thor.Bytes32, rev
 	return &res, nil
 }
 
+// GetAccountProof retrieves the merkle proof of the account and the given storage keys at the specified revision.
+func (c *Client) GetAccountProof(addr *thor.Address, keys []thor.Bytes32, revision string) (*api.AccountProof, error) {
+	strs := make([]string, 0, len(keys))
+	for _, key := range keys {
+		strs = append(strs, key.String())
+	}
+	url := c.url + "/accounts/" + addr.String() + "/proof?keys=" + strings.Join(strs, ",")
+	if revision != "" {
+		url += "&revision=" + revision
+	}
+
+	body, err := c.httpGET(url)
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve account proof - %w", err)
+	}
+
+	var res api.AccountProof
+	if err = json.Unmarshal(body, &res); err != nil {
+		return nil, fmt.Errorf("unable to unmarshal account proof - %w", err)
+	}
+
+	return &res, nil
+}
+
 // GetTransaction retrieves the transaction details by the transaction ID, along with options for head and pending status.
 func (c *Client) GetTransaction(txID *thor.Bytes32, head string, isPending bool) (*transactions.Transaction, error) {
 	url := c.url + "/transactions/" + txID.String() + "?"
@@ -222,6 +246,38 @@ func (c *Client) GetTransactionReceipt(txID *thor.Bytes32, head string) (*api.Re
 	return &receipt, nil
 }
 
+// GetTransactionProof retrieves the merkle proof of the transaction included in the block.
+func (c *Client) GetTransactionProof(txID *thor.Bytes32, head string) (*api.InclusionProof, error) {
+	return c.getInclusionProof(c.url+"/transactions/"+txID.String()+"/proof", head)
+}
+
+// GetReceiptProof retrieves the merkle proof of the transaction receipt included in the block.
+func (c *Client) GetReceiptProof(txID *thor.Bytes32, head string) (*api.InclusionProof, error) {
+	return c.getInclusionProof(c.url+"/transactions/"+txID.String()+"/receipt/proof", head)
+}
+
+func (c *Client) getInclusionProof(url string, head string) (*api.InclusionProof, error) {
+	if head != "" {
+		url += "?head=" + head
+	}
+
+	body, err := c.httpGET(url)
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch inclusion proof - %w", err)
+	}
+
+	if len(body) == 0 || bytes.Equal(bytes.TrimSpace(body), []byte("null")) {
+		return nil, ErrNotFound
+	}
+
+	var proof api.InclusionProof
+	if err = json.Unmarshal(body, &proof); err != nil {
+		return nil, fmt.Errorf("unable to unmarshal inclusion proof - %w", err)
+	}
+
+	return &proof, nil
+}
+
 // SendTransaction sends a raw transaction to the blockchain.
 func (c *Client) SendTransaction(obj *api.RawTx) (*api.SendTxResult, error) {
 	body, err := c.httpPOST(c.url+"/transactions", obj)
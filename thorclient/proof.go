@@ -0,0 +1,156 @@
+// Copyright (c) 2025 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package thorclient
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rlp"
+
+	"github.com/vechain/thor/v2/api"
+	"github.com/vechain/thor/v2/block"
+	"github.com/vechain/thor/v2/state"
+	"github.com/vechain/thor/v2/thor"
+	"github.com/vechain/thor/v2/trie"
+	"github.com/vechain/thor/v2/tx"
+)
+
+// VerifyAccountProof verifies the account proof against the trusted state root, which is
+// usually taken from a block header already verified by the caller.
+// It ensures the account fields and storage values in the proof are exactly what stored in the state,
+// and that the proof has the storage proof of each requested key, no more and no less.
+// The state root carried by the proof itself is not trusted.
+func VerifyAccountProof(stateRoot thor.Bytes32, addr thor.Address, keys []thor.Bytes32, proof *api.AccountProof) error {
+	if proof == nil {
+		return errors.New("nil proof")
+	}
+	if proof.Address != addr {
+		return fmt.Errorf("address mismatch: want %v, got %v", addr, proof.Address)
+	}
+
+	data, err := trie.VerifyProof(stateRoot, thor.Blake2b(addr[:]).Bytes(), toBytesSlice(proof.AccountProof))
+	if err != nil {
+		return fmt.Errorf("account proof: %w", err)
+	}
+	acc := state.Account{Balance: new(big.Int), Energy: new(big.Int)}
+	if len(data) > 0 {
+		if err := rlp.DecodeBytes(data, &acc); err != nil {
+			return fmt.Errorf("account proof: %w", err)
+		}
+	}
+	if proof.Balance == nil || proof.Energy == nil {
+		return errors.New("missing balance or energy")
+	}
+	if (*big.Int)(proof.Balance).Cmp(acc.Balance) != 0 ||
+		(*big.Int)(proof.Energy).Cmp(acc.Energy) != 0 ||
+		proof.BlockTime != acc.BlockTime ||
+		!bytes.Equal(proof.Master, acc.Master) ||
+		!bytes.Equal(proof.CodeHash, acc.CodeHash) ||
+		!bytes.Equal(proof.StorageRoot, acc.StorageRoot) {
+		return errors.New("account fields mismatch")
+	}
+
+	// a key requested more than once is expected to be proved as many times
+	pending := make(map[thor.Bytes32]int, len(keys))
+	for _, key := range keys {
+		pending[key]++
+	}
+
+	storageRoot := thor.BytesToBytes32(acc.StorageRoot)
+	for _, sp := range proof.StorageProof {
+		if sp == nil {
+			return errors.New("nil storage proof")
+		}
+		if pending[sp.Key] == 0 {
+			return fmt.Errorf("unexpected storage proof: %v", sp.Key)
+		}
+		pending[sp.Key]--
+		var value []byte
+		// absent account or empty storage proves nothing stored
+		if len(acc.StorageRoot) > 0 {
+			if value, err = trie.VerifyProof(storageRoot, thor.Blake2b(sp.Key[:]).Bytes(), toBytesSlice(sp.Proof)); err != nil {
+				return fmt.Errorf("storage proof %v: %w", sp.Key, err)
+			}
+		}
+		if !bytes.Equal(value, sp.Value) {
+			return fmt.Errorf("storage value mismatch: %v", sp.Key)
+		}
+	}
+	for _, key := range keys {
+		if pending[key] > 0 {
+			return fmt.Errorf("missing storage proof: %v", key)
+		}
+	}
+	return nil
+}
+
+// VerifyTxProof verifies the tx inclusion proof against the trusted block header,
+// and returns the proved tx.
+func VerifyTxProof(header *block.Header, txID thor.Bytes32, proof *api.InclusionProof) (*tx.Transaction, error) {
+	raw, err := verifyInclusionProof(header, header.TxsRoot(), proof)
+	if err != nil {
+		return nil, err
+	}
+	var trx tx.Transaction
+	if err := trx.UnmarshalBinary(raw); err != nil {
+		return nil, fmt.Errorf("decode tx: %w", err)
+	}
+	if trx.ID() != txID {
+		return nil, fmt.Errorf("tx id mismatch: want %v, got %v", txID, trx.ID())
+	}
+	return &trx, nil
+}
+
+// VerifyReceiptProof verifies the receipt inclusion proof against the trusted block header,
+// and returns the proved receipt. Since a receipt doesn't carry the tx id, the caller should
+// check that the index of the proof matches the one of the verified tx proof.
+func VerifyReceiptProof(header *block.Header, proof *api.InclusionProof) (*tx.Receipt, error) {
+	raw, err := verifyInclusionProof(header, header.ReceiptsRoot(), proof)
+	if err != nil {
+		return nil, err
+	}
+	var receipt tx.Receipt
+	if err := receipt.UnmarshalBinary(raw); err != nil {
+		return nil, fmt.Errorf("decode receipt: %w", err)
+	}
+	return &receipt, nil
+}
+
+func verifyInclusionProof(header *block.Header, root thor.Bytes32, proof *api.InclusionProof) ([]byte, error) {
+	if proof == nil {
+		return nil, errors.New("nil proof")
+	}
+	if proof.Meta.BlockID != header.ID() {
+		return nil, fmt.Errorf("block id mismatch: want %v, got %v", header.ID(), proof.Meta.BlockID)
+	}
+	if proof.Index > math.MaxInt32 {
+		return nil, fmt.Errorf("index too large: %d", proof.Index)
+	}
+	raw, err := trie.VerifyDerivedProof(root, int(proof.Index), toBytesSlice(proof.Proof))
+	if err != nil {
+		return nil, fmt.Errorf("inclusion proof: %w", err)
+	}
+	if len(raw) == 0 {
+		return nil, errors.New("inclusion proof: item absent")
+	}
+	if !bytes.Equal(raw, proof.Raw) {
+		return nil, errors.New("raw data mismatch")
+	}
+	return raw, nil
+}
+
+func toBytesSlice(s []hexutil.Bytes) [][]byte {
+	out := make([][]byte, 0, len(s))
+	for _, b := range s {
+		out = append(out, b)
+	}
+	return out
+}
@@ -460,6 +460,36 @@ func (c *Client) RawAccountStorage(addr *thor.Address, key *thor.Bytes32, opts .
 	return c.httpConn.GetRawAccountStorage(addr, key, options.revision)
 }
 
+// AccountProof retrieves the merkle proof of an account and the given storage slots.
+//
+// This method corresponds to the GET /accounts/{address}/proof API endpoint. The returned
+// proof should be checked by VerifyAccountProof against the state root of a trusted block header,
+// instead of trusting the node serving the request.
+//
+// Parameters:
+//   - addr: The VeChain address of the account/contract to prove
+//   - keys: The storage positions to prove, can be empty
+//   - opts: Optional parameters (Revision)
+//
+// Returns:
+//   - *api.AccountProof: The account fields, storage values and their merkle proofs
+//   - error: Error if the request fails or parameters are invalid
+//
+// Example:
+//
+//	keys := []thor.Bytes32{key}
+//	proof, err := client.AccountProof(addr, keys, thorclient.Revision(header.ID().String()))
+//	if err != nil {
+//		return err
+//	}
+//	if err := thorclient.VerifyAccountProof(header.StateRoot(), *addr, keys, proof); err != nil {
+//		return err
+//	}
+func (c *Client) AccountProof(addr *thor.Address, keys []thor.Bytes32, opts ...Option) (*api.AccountProof, error) {
+	options := applyOptions(opts)
+	return c.httpConn.GetAccountProof(addr, keys, options.revision)
+}
+
 // Transaction retrieves a transaction by its ID from the VeChainThor blockchain.
 //
 // This method corresponds to the GET /transactions/{id} API endpoint and returns
@@ -584,6 +614,57 @@ func (c *Client) TransactionReceipt(id *thor.Bytes32, opts ...Option) (*api.Rece
 	return c.httpConn.GetTransactionReceipt(id, options.revision)
 }
 
+// TransactionProof retrieves the merkle proof of a transaction included in a block.
+//
+// This method corresponds to the GET /transactions/{id}/proof API endpoint. The returned
+// proof should be checked by VerifyTxProof against a trusted block header.
+//
+// Parameters:
+//   - id: The 32-byte transaction ID
+//   - opts: Optional parameters (revision via head parameter)
+//
+// Returns:
+//   - *api.InclusionProof: The encoded transaction, its position in the block and the merkle proof
+//   - error: Error if the request fails, or httpclient.ErrNotFound if the transaction not found
+//
+// Example:
+//
+//	proof, err := client.TransactionProof(txID)
+//	if err != nil {
+//		return err
+//	}
+//	// the header is obtained from a trusted source, e.g. a verified header chain
+//	trx, err := thorclient.VerifyTxProof(header, *txID, proof)
+func (c *Client) TransactionProof(id *thor.Bytes32, opts ...Option) (*api.InclusionProof, error) {
+	options := applyHeadOptions(opts)
+	return c.httpConn.GetTransactionProof(id, options.revision)
+}
+
+// ReceiptProof retrieves the merkle proof of a transaction receipt included in a block.
+//
+// This method corresponds to the GET /transactions/{id}/receipt/proof API endpoint. The returned
+// proof should be checked by VerifyReceiptProof against a trusted block header.
+//
+// Parameters:
+//   - id: The 32-byte transaction ID
+//   - opts: Optional parameters (revision via head parameter)
+//
+// Returns:
+//   - *api.InclusionProof: The encoded receipt, its position in the block and the merkle proof
+//   - error: Error if the request fails, or httpclient.ErrNotFound if the transaction not found
+//
+// Example:
+//
+//	proof, err := client.ReceiptProof(txID)
+//	if err != nil {
+//		return err
+//	}
+//	receipt, err := thorclient.VerifyReceiptProof(header, proof)
+func (c *Client) ReceiptProof(id *thor.Bytes32, opts ...Option) (*api.InclusionProof, error) {
+	options := applyHeadOptions(opts)
+	return c.httpConn.GetReceiptProof(id, options.revision)
+}
+
 // DebugRevertedTransaction retrieves the revert reason for a reverted transaction.
 //
 // This method corresponds to the POST /debug/tracers API endpoint and provides
@@ -5,6 +5,8 @@
 package trie
 
 import (
+	"fmt"
+
 	"github.com/qianbin/drlp"
 
 	"github.com/vechain/thor/v2/thor"
@@ -18,15 +20,42 @@ type DerivableList interface {
 }
 
 func DeriveRoot(list DerivableList) thor.Bytes32 {
+	trie := deriveTrie(list)
+	return trie.Hash()
+}
+
+// DeriveProof returns the merkle proof of the item at index i of the list,
+// which can be verified against the root computed by DeriveRoot.
+func DeriveProof(list DerivableList, i int) ([][]byte, error) {
+	if i < 0 || i >= list.Len() {
+		return nil, fmt.Errorf("index out of range: %d", i)
+	}
+	trie := deriveTrie(list)
+	return trie.Prove(deriveKey(nil, i))
+}
+
+// VerifyDerivedProof verifies the proof generated by DeriveProof, and returns the
+// encoded item at index i. A nil value is returned if the proof proves the absence of the item.
+func VerifyDerivedProof(root thor.Bytes32, i int, proof [][]byte) ([]byte, error) {
+	if i < 0 {
+		return nil, fmt.Errorf("negative index: %d", i)
+	}
+	return VerifyProof(root, deriveKey(nil, i), proof)
+}
+
+func deriveTrie(list DerivableList) *Trie {
 	var (
 		trie Trie
 		key  []byte
 	)
 
 	for i := range list.Len() {
-		key = drlp.AppendUint(key[:0], uint64(i))
+		key = deriveKey(key[:0], i)
 		trie.Update(key, list.EncodeIndex(i), nil)
 	}
+	return &trie
+}
 
-	return trie.Hash()
+func deriveKey(buf []byte, i int) []byte {
+	return drlp.AppendUint(buf, uint64(i))
 }
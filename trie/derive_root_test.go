@@ -6,7 +6,10 @@
 package trie
 
 import (
+	"fmt"
 	"testing"
+
+	"github.com/stretchr/testify/assert"
 )
 
 type mockedDerivableList struct {
@@ -27,3 +30,41 @@ func BenchmarkDeriveRoot(b *testing.B) {
 		DeriveRoot(&list)
 	}
 }
+
+type indexedList int
+
+func (l indexedList) Len() int { return int(l) }
+
+func (l indexedList) EncodeIndex(i int) []byte { return fmt.Appendf(nil, "item-%d", i) }
+
+func TestDeriveProof(t *testing.T) {
+	for _, n := range []int{1, 2, 17, 128, 1000} {
+		list := indexedList(n)
+		root := DeriveRoot(list)
+		for i := range n {
+			proof, err := DeriveProof(list, i)
+			assert.Nil(t, err)
+			val, err := VerifyDerivedProof(root, i, proof)
+			assert.Nil(t, err)
+			assert.Equal(t, list.EncodeIndex(i), val, "n=%d, i=%d", n, i)
+
+			if i > 0 {
+				// the proof for another index should not prove the item
+				val, err := VerifyDerivedProof(root, i-1, proof)
+				if err == nil {
+					assert.NotEqual(t, list.EncodeIndex(i), val)
+				}
+			}
+		}
+		// absence of the item out of range
+		proof, err := DeriveProof(indexedList(n+1), n)
+		assert.Nil(t, err)
+		_, err = VerifyDerivedProof(root, n, proof)
+		assert.NotNil(t, err, "proof from another list")
+
+		_, err = DeriveProof(list, n)
+		assert.NotNil(t, err)
+		_, err = DeriveProof(list, -1)
+		assert.NotNil(t, err)
+	}
+}
@@ -85,6 +85,22 @@ func (h *hasher) hash(n node, force bool) []byte {
 	}
 }
 
+// HashBlob computes the hash of the node encoded in the blob, which is returned by NodeIterator.Blob.
+// Hashes of the child nodes are read from the blob other than recomputed, so it checks the node itself only.
+func HashBlob(blob []byte) (thor.Bytes32, error) {
+	n, _, err := decodeNode(&refNode{}, blob, 0)
+	if err != nil {
+		return thor.Bytes32{}, err
+	}
+	if n == nil {
+		return emptyRoot, nil
+	}
+
+	h := hasherPool.Get().(*hasher)
+	defer hasherPool.Put(h)
+	return thor.BytesToBytes32(h.hash(n, true)), nil
+}
+
 // store stores node n and all its dirty sub nodes.
 // Root node is always stored regardless of its dirty flag.
 func (h *hasher) store(n node, db DatabaseWriter, path []byte) (node, error) {
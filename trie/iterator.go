@@ -19,6 +19,8 @@ package trie
 import (
 	"bytes"
 	"errors"
+
+	"github.com/vechain/thor/v2/thor"
 )
 
 // Iterator is a key-value trie iterator that traverses a Trie.
@@ -76,6 +78,10 @@ type NodeIterator interface {
 	// If the current node is not stored as standalone node, the returned blob has zero length.
 	Blob() ([]byte, Version, error)
 
+	// Hash returns the hash of the current node referenced by its parent, or the root hash for the root node.
+	// If the current node is embedded in its parent or stored without hash, the returned hash is zero.
+	Hash() thor.Bytes32
+
 	// Path returns the hex-encoded path to the current node.
 	// Callers must not retain references to the return value after calling Next.
 	// For leaf nodes, the last element of the path is the 'terminator symbol' 0x10.
@@ -153,6 +159,17 @@ func (it *nodeIterator) Blob() (blob []byte, ver Version, err error) {
 	return
 }
 
+func (it *nodeIterator) Hash() thor.Bytes32 {
+	if len(it.stack) == 0 {
+		return thor.Bytes32{}
+	}
+	ref, _, dirty := it.stack[len(it.stack)-1].node.cache()
+	if dirty {
+		return thor.Bytes32{}
+	}
+	return thor.BytesToBytes32(ref.hash)
+}
+
 func (it *nodeIterator) Leaf() *Leaf {
 	if len(it.stack) > 0 {
 		if vn, ok := it.stack[len(it.stack)-1].node.(*valueNode); ok {
@@ -154,6 +154,38 @@ func TestNodeIteratorCoverage(t *testing.T) {
 	}
 }
 
+func TestNodeIteratorHash(t *testing.T) {
+	db, tr, _ := makeTestTrie()
+	root := tr.Hash()
+	tr = New(Root{Hash: root, Ver: Version{Major: 1}}, db)
+
+	var (
+		checked int
+		blobs   [][]byte
+	)
+	for it := tr.NodeIterator(nil, Version{}); it.Next(true); {
+		if checked == 0 {
+			assert.Equal(t, root, it.Hash(), "root node")
+		}
+		blob, _, err := it.Blob()
+		assert.Nil(t, err)
+		if len(blob) == 0 || it.Hash().IsZero() {
+			continue
+		}
+		hash, err := HashBlob(blob)
+		assert.Nil(t, err)
+		assert.Equal(t, it.Hash(), hash, "path %x", it.Path())
+		blobs = append(blobs, blob)
+		checked++
+	}
+	assert.NotZero(t, checked)
+
+	// mismatched with another node
+	hash, err := HashBlob(blobs[1])
+	assert.Nil(t, err)
+	assert.NotEqual(t, root, hash)
+}
+
 type kvs struct{ k, v string }
 
 var testdata1 = []kvs{
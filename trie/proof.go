@@ -0,0 +1,682 @@
+// Copyright 2015 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package trie
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/rlp"
+
+	"github.com/vechain/thor/v2/thor"
+)
+
+// Prove constructs a merkle proof for key. The result contains all consensus-encoded nodes
+// on the path to the value at key. The value itself is also included in the last
+// node and can be retrieved by verifying the proof.
+//
+// If the trie does not contain a value for key, the returned proof contains all
+// nodes of the longest existing prefix of the key (at least the root node), ending
+// with the node that proves the absence of the key.
+func (t *Trie) Prove(key []byte) ([][]byte, error) {
+	var (
+		nodes  []node
+		prefix []byte
+		tn     = t.root
+	)
+	key = keybytesToHex(key)
+	for len(key) > 0 && tn != nil {
+		switch n := tn.(type) {
+		case *shortNode:
+			if len(key) < len(n.key) || !bytes.Equal(n.key, key[:len(n.key)]) {
+				// the trie doesn't contain the key
+				tn = nil
+			} else {
+				tn = n.child
+				prefix = append(prefix, n.key...)
+				key = key[len(n.key):]
+			}
+			nodes = append(nodes, n)
+		case *fullNode:
+			tn = n.children[key[0]]
+			prefix = append(prefix, key[0])
+			key = key[1:]
+			nodes = append(nodes, n)
+		case *refNode:
+			var err error
+			if tn, err = t.resolveRef(n, prefix); err != nil {
+				return nil, err
+			}
+		default:
+			panic(fmt.Sprintf("%T: invalid node: %v", tn, tn))
+		}
+	}
+
+	h := hasherPool.Get().(*hasher)
+	defer hasherPool.Put(h)
+
+	proof := make([][]byte, 0, len(nodes))
+	for i, n := range nodes {
+		// leaves can be stored embedded in their parents without hash, hash children
+		// to have them referenced by hash in the consensus encoding
+		switch n := n.(type) {
+		case *fullNode:
+			for _, cn := range n.children[:16] {
+				if cn != nil {
+					h.hash(cn, false)
+				}
+			}
+		case *shortNode:
+			h.hash(n.child, false)
+		}
+		// nodes smaller than 32 bytes are embedded in their parents
+		if hash := h.hash(n, i == 0); hash != nil {
+			proof = append(proof, n.encodeConsensus(nil))
+		}
+	}
+	return proof, nil
+}
+
+// VerifyProof checks merkle proofs. The given proof must contain the value for
+// key in a trie with the given root hash. VerifyProof returns an error if the
+// proof contains invalid trie nodes or the wrong value.
+// A nil value with nil error is returned if the proof proves the absence of the key.
+func VerifyProof(root thor.Bytes32, key []byte, proof [][]byte) ([]byte, error) {
+	if root == emptyRoot || root.IsZero() {
+		return nil, nil
+	}
+
+	nodes := proofNodes(proof)
+	key = keybytesToHex(key)
+	wantHash := root
+	for i := 0; ; i++ {
+		buf, ok := nodes[wantHash]
+		if !ok {
+			return nil, fmt.Errorf("proof node %d (hash %v) missing", i, wantHash)
+		}
+		n, err := decodeConsensusNode(buf)
+		if err != nil {
+			return nil, fmt.Errorf("bad proof node %d: %v", i, err)
+		}
+		keyrest, cld := get(n, key, true)
+		switch cld := cld.(type) {
+		case nil:
+			// the trie doesn't contain the key
+			return nil, nil
+		case *refNode:
+			key = keyrest
+			wantHash = thor.BytesToBytes32(cld.hash)
+		case *valueNode:
+			return cld.val, nil
+		}
+	}
+}
+
+// VerifyRangeProof checks whether the given leaf nodes and edge proof can prove the
+// given trie leaves range is matched with the specific root.
+//
+// The range starts at origin, which is not necessarily an existing key, and ends at the last
+// key of keys. The keys must be sorted in ascending order and all values must be non-empty.
+// The proof is expected to contain the nodes on the paths of origin and the last key.
+// The special case is an empty proof, then the given leaves are expected to be the
+// whole leaf set of the trie.
+//
+// It returns whether there are more leaves in the trie beyond the range.
+func VerifyRangeProof(root thor.Bytes32, origin []byte, keys, values [][]byte, proof [][]byte) (bool, error) {
+	if len(keys) != len(values) {
+		return false, fmt.Errorf("inconsistent proof data, keys: %d, values: %d", len(keys), len(values))
+	}
+	// ensure the received batch is monotonic increasing and contains no deletions
+	for i := 0; i < len(keys)-1; i++ {
+		if bytes.Compare(keys[i], keys[i+1]) >= 0 {
+			return false, errors.New("range is not monotonically increasing")
+		}
+	}
+	for _, value := range values {
+		if len(value) == 0 {
+			return false, errors.New("range contains deletion")
+		}
+	}
+	if len(keys) > 0 && bytes.Compare(keys[0], origin) < 0 {
+		return false, errors.New("range starts before origin")
+	}
+
+	if root == emptyRoot || root.IsZero() {
+		if len(keys) > 0 {
+			return false, errors.New("more entries than the empty trie")
+		}
+		return false, nil
+	}
+
+	// special case, there is no edge proof at all. The given range is expected
+	// to be the whole leaf set of the trie.
+	if len(proof) == 0 {
+		var tr Trie
+		for i, key := range keys {
+			if err := tr.Update(key, values[i], nil); err != nil {
+				return false, err
+			}
+		}
+		if have := tr.Hash(); have != root {
+			return false, fmt.Errorf("invalid proof, want hash %v, got %v", root, have)
+		}
+		return false, nil
+	}
+
+	nodes := proofNodes(proof)
+
+	// special case, there is a provided edge proof but zero leaves,
+	// ensure there are no more leaves in the trie.
+	if len(keys) == 0 {
+		rn, val, err := proofToPath(root, nil, origin, nodes, true)
+		if err != nil {
+			return false, err
+		}
+		if val != nil || hasRightElement(rn, origin) {
+			return false, errors.New("more entries available")
+		}
+		return false, nil
+	}
+
+	last := keys[len(keys)-1]
+	// special case, there is only one element and two edge keys are same.
+	// In this case, we can't construct two edge paths. So handle it here.
+	if len(keys) == 1 && bytes.Equal(origin, last) {
+		rn, val, err := proofToPath(root, nil, origin, nodes, false)
+		if err != nil {
+			return false, err
+		}
+		if !bytes.Equal(val, values[0]) {
+			return false, errors.New("correct proof but invalid data")
+		}
+		return hasRightElement(rn, origin), nil
+	}
+
+	// in all other cases, two edge paths are required.
+	// first check the validity of edge keys.
+	if bytes.Compare(origin, last) >= 0 {
+		return false, errors.New("invalid edge keys")
+	}
+	if len(origin) != len(last) {
+		return false, errors.New("inconsistent edge keys")
+	}
+	// convert the edge proofs to edge trie paths, then we can
+	// have the same tree architecture with the original one.
+	// for the first edge proof, non-existent proof is allowed.
+	rn, _, err := proofToPath(root, nil, origin, nodes, true)
+	if err != nil {
+		return false, err
+	}
+	// pass the root node here, the second path will be merged
+	// with the first one.
+	rn, _, err = proofToPath(root, rn, last, nodes, false)
+	if err != nil {
+		return false, err
+	}
+	// remove all internal references. All the removed parts should
+	// be re-filled (or re-constructed) by the given leaves range.
+	empty, err := unsetInternal(rn, origin, last)
+	if err != nil {
+		return false, err
+	}
+	// rebuild the trie with the leaves, the shape of trie
+	// should be same with the original one.
+	tr := &Trie{root: rn, db: missingDatabase{}}
+	if empty {
+		tr.root = nil
+	}
+	for i, key := range keys {
+		if err := tr.Update(key, values[i], nil); err != nil {
+			return false, err
+		}
+	}
+	if have := tr.Hash(); have != root {
+		return false, fmt.Errorf("invalid proof, want hash %v, got %v", root, have)
+	}
+	return hasRightElement(tr.root, last), nil
+}
+
+// missingDatabase has no node at all, it backs tries rebuilt from proofs.
+type missingDatabase struct{}
+
+func (missingDatabase) Get(path []byte, ver Version) ([]byte, error) {
+	return nil, errors.New("not in proof")
+}
+
+// proofNodes indexes proof nodes by their hashes.
+func proofNodes(proof [][]byte) map[thor.Bytes32][]byte {
+	nodes := make(map[thor.Bytes32][]byte, len(proof))
+	for _, buf := range proof {
+		nodes[thor.Blake2b(buf)] = buf
+	}
+	return nodes
+}
+
+// proofToPath converts a merkle proof to trie node path. The main purpose of
+// this function is recovering a node path from the merkle proof stream. All
+// necessary nodes will be resolved and leave the remaining as refNode.
+//
+// The given edge proof is allowed to be an existent or non-existent proof.
+func proofToPath(root thor.Bytes32, rn node, key []byte, nodes map[thor.Bytes32][]byte, allowNonExistent bool) (node, []byte, error) {
+	// resolveNode retrieves and resolves trie node from the proof nodes
+	resolveNode := func(hash thor.Bytes32) (node, error) {
+		buf, ok := nodes[hash]
+		if !ok {
+			return nil, fmt.Errorf("proof node (hash %v) missing", hash)
+		}
+		n, err := decodeConsensusNode(buf)
+		if err != nil {
+			return nil, fmt.Errorf("bad proof node %v", err)
+		}
+		return n, nil
+	}
+	// if the root node is empty, resolve it first.
+	// root node must be included in the proof.
+	if rn == nil {
+		n, err := resolveNode(root)
+		if err != nil {
+			return nil, nil, err
+		}
+		rn = n
+	}
+	var (
+		err           error
+		child, parent node
+		keyrest       []byte
+		val           []byte
+	)
+	key, parent = keybytesToHex(key), rn
+	for {
+		keyrest, child = get(parent, key, false)
+		switch cld := child.(type) {
+		case nil:
+			// the trie doesn't contain the key. It's possible
+			// the proof is a non-existing proof, but at least
+			// we can prove all resolved nodes are correct, it's
+			// enough for us to prove range.
+			if allowNonExistent {
+				return rn, nil, nil
+			}
+			return nil, nil, errors.New("the node is not contained in trie")
+		case *shortNode, *fullNode:
+			// already resolved
+			key, parent = keyrest, child
+			continue
+		case *refNode:
+			if child, err = resolveNode(thor.BytesToBytes32(cld.hash)); err != nil {
+				return nil, nil, err
+			}
+		case *valueNode:
+			val = cld.val
+		}
+		// link the parent and child
+		switch pnode := parent.(type) {
+		case *shortNode:
+			pnode.child = child
+		case *fullNode:
+			pnode.children[key[0]] = child
+		default:
+			panic(fmt.Sprintf("%T: invalid node: %v", pnode, pnode))
+		}
+		if len(val) > 0 {
+			// the whole path is resolved
+			return rn, val, nil
+		}
+		key, parent = keyrest, child
+	}
+}
+
+// unsetInternal removes all internal node references (refNode, embedded node).
+// It should be called after a trie is constructed with two edge paths. Also
+// the given boundary keys must be the ones used to construct the edge paths.
+//
+// It's the key step for range proof. All visited nodes should be marked dirty
+// since the node content might be modified. Besides it can happen that some
+// fullnodes only have one child which is disallowed. But if the proof is valid,
+// the missing children will be filled, otherwise it will be thrown anyway.
+//
+// Note we have the assumption here the given boundary keys are different
+// and right is larger than left.
+func unsetInternal(n node, left []byte, right []byte) (bool, error) {
+	left, right = keybytesToHex(left), keybytesToHex(right)
+
+	// step down to the fork point. There are two scenarios can happen:
+	// - the fork point is a shortnode: either the key of left proof or
+	//   right proof doesn't match with shortnode's key.
+	// - the fork point is a fullnode: both two edge proofs are allowed
+	//   to point to a non-existent key.
+	var (
+		pos    = 0
+		parent node
+
+		// fork indicator, 0 means no fork, -1 means proof is less, 1 means proof is greater
+		shortForkLeft, shortForkRight int
+	)
+findFork:
+	for {
+		switch rn := n.(type) {
+		case *shortNode:
+			rn.flags = nodeFlag{dirty: true}
+
+			// if either the key of left proof or right proof doesn't match with
+			// shortnode, stop here and the forkpoint is the shortnode.
+			if len(left)-pos < len(rn.key) {
+				shortForkLeft = bytes.Compare(left[pos:], rn.key)
+			} else {
+				shortForkLeft = bytes.Compare(left[pos:pos+len(rn.key)], rn.key)
+			}
+			if len(right)-pos < len(rn.key) {
+				shortForkRight = bytes.Compare(right[pos:], rn.key)
+			} else {
+				shortForkRight = bytes.Compare(right[pos:pos+len(rn.key)], rn.key)
+			}
+			if shortForkLeft != 0 || shortForkRight != 0 {
+				break findFork
+			}
+			parent = n
+			n, pos = rn.child, pos+len(rn.key)
+		case *fullNode:
+			rn.flags = nodeFlag{dirty: true}
+
+			// if either the node pointed by left proof or right proof is nil,
+			// stop here and the forkpoint is the fullnode.
+			leftnode, rightnode := rn.children[left[pos]], rn.children[right[pos]]
+			if leftnode == nil || rightnode == nil || leftnode != rightnode {
+				break findFork
+			}
+			parent = n
+			n, pos = rn.children[left[pos]], pos+1
+		default:
+			panic(fmt.Sprintf("%T: invalid node: %v", n, n))
+		}
+	}
+	switch rn := n.(type) {
+	case *shortNode:
+		// there can have these five scenarios:
+		// - both proofs are less than the trie path => no valid range
+		// - both proofs are greater than the trie path => no valid range
+		// - left proof is less and right proof is greater => valid range, unset the shortnode entirely
+		// - left proof points to the shortnode, but right proof is greater
+		// - right proof points to the shortnode, but left proof is less
+		if shortForkLeft == -1 && shortForkRight == -1 {
+			return false, errors.New("empty range")
+		}
+		if shortForkLeft == 1 && shortForkRight == 1 {
+			return false, errors.New("empty range")
+		}
+		if shortForkLeft != 0 && shortForkRight != 0 {
+			// the fork point is root node, unset the entire trie
+			if parent == nil {
+				return true, nil
+			}
+			parent.(*fullNode).children[left[pos-1]] = nil
+			return false, nil
+		}
+		// only one proof points to non-existent key.
+		if shortForkRight != 0 {
+			if _, ok := rn.child.(*valueNode); ok {
+				// the fork point is root node, unset the entire trie
+				if parent == nil {
+					return true, nil
+				}
+				parent.(*fullNode).children[left[pos-1]] = nil
+				return false, nil
+			}
+			return false, unset(rn, rn.child, left[pos:], len(rn.key), false)
+		}
+		if shortForkLeft != 0 {
+			if _, ok := rn.child.(*valueNode); ok {
+				// the fork point is root node, unset the entire trie
+				if parent == nil {
+					return true, nil
+				}
+				parent.(*fullNode).children[right[pos-1]] = nil
+				return false, nil
+			}
+			return false, unset(rn, rn.child, right[pos:], len(rn.key), true)
+		}
+		return false, nil
+	case *fullNode:
+		// unset all internal nodes in the forkpoint
+		for i := left[pos] + 1; i < right[pos]; i++ {
+			rn.children[i] = nil
+		}
+		if err := unset(rn, rn.children[left[pos]], left[pos:], 1, false); err != nil {
+			return false, err
+		}
+		if err := unset(rn, rn.children[right[pos]], right[pos:], 1, true); err != nil {
+			return false, err
+		}
+		return false, nil
+	default:
+		panic(fmt.Sprintf("%T: invalid node: %v", n, n))
+	}
+}
+
+// unset removes all internal node references either the left most or right most.
+// It can meet these scenarios:
+//
+//   - The given path is existent in the trie, unset the associated nodes with the
+//     specific direction
+//   - The given path is non-existent in the trie
+//   - the fork point is a fullnode, the corresponding child pointed by path
+//     is nil, return
+//   - the fork point is a shortnode, the shortnode is included in the range,
+//     keep the entire branch and return.
+//   - the fork point is a shortnode, the shortnode is excluded in the range,
+//     unset the entire branch.
+func unset(parent node, child node, key []byte, pos int, removeLeft bool) error {
+	switch cld := child.(type) {
+	case *fullNode:
+		if removeLeft {
+			for i := 0; i < int(key[pos]); i++ {
+				cld.children[i] = nil
+			}
+		} else {
+			for i := key[pos] + 1; i < 16; i++ {
+				cld.children[i] = nil
+			}
+		}
+		cld.flags = nodeFlag{dirty: true}
+		return unset(cld, cld.children[key[pos]], key, pos+1, removeLeft)
+	case *shortNode:
+		if len(key[pos:]) < len(cld.key) || !bytes.Equal(cld.key, key[pos:pos+len(cld.key)]) {
+			// find the fork point, it's a non-existent branch.
+			if removeLeft {
+				if bytes.Compare(cld.key, key[pos:]) < 0 {
+					// the key of fork shortnode is less than the path
+					// (it belongs to the range), unset the entire
+					// branch. The parent must be a fullnode.
+					parent.(*fullNode).children[key[pos-1]] = nil
+				}
+				// otherwise, the key of fork shortnode is greater than the
+				// path (it doesn't belong to the range), keep it.
+			} else {
+				if bytes.Compare(cld.key, key[pos:]) > 0 {
+					// the key of fork shortnode is greater than the
+					// path (it belongs to the range), unset the entire
+					// branch. The parent must be a fullnode.
+					parent.(*fullNode).children[key[pos-1]] = nil
+				}
+				// otherwise, the key of fork shortnode is less than the
+				// path (it doesn't belong to the range), keep it.
+			}
+			return nil
+		}
+		if _, ok := cld.child.(*valueNode); ok {
+			parent.(*fullNode).children[key[pos-1]] = nil
+			return nil
+		}
+		cld.flags = nodeFlag{dirty: true}
+		return unset(cld, cld.child, key, pos+len(cld.key), removeLeft)
+	case nil:
+		// if the node is nil, then it's a child of the fork point
+		// fullnode (it's a non-existent branch).
+		return nil
+	default:
+		panic("it shouldn't happen") // refNode, valueNode
+	}
+}
+
+// hasRightElement returns the indicator whether there exists more elements
+// on the right side of the given path. The given path can point to an existent
+// key or a non-existent one. This function has the assumption that the whole
+// path should already be resolved.
+func hasRightElement(n node, key []byte) bool {
+	pos, key := 0, keybytesToHex(key)
+	for n != nil {
+		switch rn := n.(type) {
+		case *fullNode:
+			for i := key[pos] + 1; i < 16; i++ {
+				if rn.children[i] != nil {
+					return true
+				}
+			}
+			n, pos = rn.children[key[pos]], pos+1
+		case *shortNode:
+			if len(key)-pos < len(rn.key) || !bytes.Equal(rn.key, key[pos:pos+len(rn.key)]) {
+				return bytes.Compare(rn.key, key[pos:]) > 0
+			}
+			n, pos = rn.child, pos+len(rn.key)
+		case *valueNode:
+			// the whole path is resolved
+			return false
+		default:
+			panic(fmt.Sprintf("%T: invalid node: %v", n, n))
+		}
+	}
+	return false
+}
+
+// get returns the child of the given node. Return nil if the
+// node with specified key doesn't exist at all.
+//
+// There is an additional flag `skipResolved`. If it's set then
+// all resolved nodes won't be returned.
+func get(tn node, key []byte, skipResolved bool) ([]byte, node) {
+	for {
+		switch n := tn.(type) {
+		case *shortNode:
+			if len(key) < len(n.key) || !bytes.Equal(n.key, key[:len(n.key)]) {
+				return nil, nil
+			}
+			tn = n.child
+			key = key[len(n.key):]
+			if !skipResolved {
+				return key, tn
+			}
+		case *fullNode:
+			tn = n.children[key[0]]
+			key = key[1:]
+			if !skipResolved {
+				return key, tn
+			}
+		case *refNode:
+			return key, n
+		case nil:
+			return key, nil
+		case *valueNode:
+			return nil, n
+		default:
+			panic(fmt.Sprintf("%T: invalid node: %v", tn, tn))
+		}
+	}
+}
+
+// decodeConsensusNode parses a node in consensus encoding, which is the form in proofs.
+func decodeConsensusNode(buf []byte) (node, error) {
+	elems, rest, err := rlp.SplitList(buf)
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) > 0 {
+		return nil, errors.New("trailing bytes")
+	}
+	switch c, _ := rlp.CountValues(elems); c {
+	case 2:
+		n, err := decodeConsensusShort(elems)
+		return n, wrapError(err, "short")
+	case 17:
+		n, err := decodeConsensusFull(elems)
+		return n, wrapError(err, "full")
+	default:
+		return nil, fmt.Errorf("invalid number of list elements: %v", c)
+	}
+}
+
+func decodeConsensusShort(elems []byte) (*shortNode, error) {
+	kbuf, rest, err := rlp.SplitString(elems)
+	if err != nil {
+		return nil, err
+	}
+	n := &shortNode{key: compactToHex(kbuf), flags: nodeFlag{dirty: true}}
+	if hasTerm(n.key) {
+		val, _, err := rlp.SplitString(rest)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value node: %v", err)
+		}
+		n.child = &valueNode{val: val}
+		return n, nil
+	}
+	if n.child, _, err = decodeConsensusRef(rest); err != nil {
+		return nil, wrapError(err, "val")
+	}
+	return n, nil
+}
+
+func decodeConsensusFull(elems []byte) (*fullNode, error) {
+	n := &fullNode{flags: nodeFlag{dirty: true}}
+	for i := range 16 {
+		cld, rest, err := decodeConsensusRef(elems)
+		if err != nil {
+			return nil, wrapError(err, fmt.Sprintf("[%d]", i))
+		}
+		n.children[i], elems = cld, rest
+	}
+	val, _, err := rlp.SplitString(elems)
+	if err != nil {
+		return nil, err
+	}
+	if len(val) > 0 {
+		n.children[16] = &valueNode{val: val}
+	}
+	return n, nil
+}
+
+func decodeConsensusRef(buf []byte) (node, []byte, error) {
+	kind, val, rest, err := rlp.Split(buf)
+	if err != nil {
+		return nil, buf, err
+	}
+	switch {
+	case kind == rlp.List:
+		// embedded node, whose encoding must be smaller than a hash
+		if size := len(buf) - len(rest); size >= 32 {
+			return nil, buf, fmt.Errorf("oversized embedded node (size is %d bytes, want size < 32)", size)
+		}
+		n, err := decodeConsensusNode(buf[:len(buf)-len(rest)])
+		return n, rest, err
+	case kind == rlp.String && len(val) == 0:
+		// empty node
+		return nil, rest, nil
+	case kind == rlp.String && len(val) == 32:
+		return &refNode{hash: val}, rest, nil
+	default:
+		return nil, nil, fmt.Errorf("invalid RLP string size %d (want 0 or 32)", len(val))
+	}
+}
@@ -0,0 +1,277 @@
+// Copyright 2015 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package trie
+
+import (
+	"bytes"
+	crand "crypto/rand"
+	mrand "math/rand"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/vechain/thor/v2/thor"
+)
+
+type entry struct {
+	k, v []byte
+}
+
+func randBytes(n int) []byte {
+	r := make([]byte, n)
+	crand.Read(r)
+	return r
+}
+
+// randomTrie creates a committed trie with n random 32-byte keys, and returns it along with
+// the sorted entries.
+func randomTrie(t *testing.T, n int) (*Trie, []*entry) {
+	db := newMemDatabase()
+	tr := New(Root{}, db)
+	entries := make([]*entry, 0, n)
+	for range n {
+		e := &entry{randBytes(32), randBytes(mrand.Intn(40) + 1)}
+		tr.Update(e.k, e.v, randBytes(4))
+		entries = append(entries, e)
+	}
+	sort.Slice(entries, func(i, j int) bool { return bytes.Compare(entries[i].k, entries[j].k) < 0 })
+
+	root := tr.Hash()
+	assert.Nil(t, tr.Commit(db, Version{Major: 1}, false))
+	return New(Root{root, Version{Major: 1}}, db), entries
+}
+
+func rangeProof(t *testing.T, tr *Trie, keys ...[]byte) [][]byte {
+	var proof [][]byte
+	for _, key := range keys {
+		p, err := tr.Prove(key)
+		assert.Nil(t, err)
+		proof = append(proof, p...)
+	}
+	return proof
+}
+
+func split(entries []*entry) (keys, values [][]byte) {
+	for _, e := range entries {
+		keys = append(keys, e.k)
+		values = append(values, e.v)
+	}
+	return
+}
+
+func TestProof(t *testing.T) {
+	tr, entries := randomTrie(t, 500)
+	root := tr.Hash()
+	for _, e := range entries {
+		proof, err := tr.Prove(e.k)
+		assert.Nil(t, err)
+		val, err := VerifyProof(root, e.k, proof)
+		assert.Nil(t, err)
+		assert.Equal(t, e.v, val)
+	}
+
+	// absent key
+	key := randBytes(32)
+	proof, err := tr.Prove(key)
+	assert.Nil(t, err)
+	val, err := VerifyProof(root, key, proof)
+	assert.Nil(t, err)
+	assert.Nil(t, val)
+}
+
+func TestProofSmallTrie(t *testing.T) {
+	var tr Trie
+	tr.Update([]byte("k"), []byte("v"), nil)
+	proof, err := tr.Prove([]byte("k"))
+	assert.Nil(t, err)
+	assert.Len(t, proof, 1, "root node is always in the proof")
+
+	val, err := VerifyProof(tr.Hash(), []byte("k"), proof)
+	assert.Nil(t, err)
+	assert.Equal(t, []byte("v"), val)
+
+	val, err = VerifyProof(emptyRoot, []byte("k"), nil)
+	assert.Nil(t, err)
+	assert.Nil(t, val)
+}
+
+func TestBadProof(t *testing.T) {
+	tr, entries := randomTrie(t, 100)
+	root := tr.Hash()
+	for _, e := range entries[:10] {
+		proof, err := tr.Prove(e.k)
+		assert.Nil(t, err)
+
+		i := mrand.Intn(len(proof))
+		bad := append([]byte{}, proof[i]...)
+		bad[mrand.Intn(len(bad))] ^= 0xff
+		proof[i] = bad
+
+		_, err = VerifyProof(root, e.k, proof)
+		assert.NotNil(t, err, "expected error for modified proof")
+	}
+}
+
+func TestRangeProof(t *testing.T) {
+	tr, entries := randomTrie(t, 1000)
+	root := tr.Hash()
+	for range 200 {
+		start := mrand.Intn(len(entries))
+		end := start + mrand.Intn(len(entries)-start)
+		keys, values := split(entries[start : end+1])
+
+		more, err := VerifyRangeProof(root, keys[0], keys, values, rangeProof(t, tr, keys[0], keys[len(keys)-1]))
+		assert.Nil(t, err, "range [%d, %d]", start, end)
+		assert.Equal(t, end != len(entries)-1, more)
+	}
+}
+
+func TestRangeProofWithNonExistentOrigin(t *testing.T) {
+	tr, entries := randomTrie(t, 1000)
+	root := tr.Hash()
+	for range 200 {
+		start := mrand.Intn(len(entries))
+		end := start + mrand.Intn(len(entries)-start)
+
+		// origin between the previous entry and the start entry
+		origin := append([]byte{}, entries[start].k...)
+		for i := len(origin) - 1; i >= 0; i-- {
+			origin[i]--
+			if origin[i] != 0xff {
+				break
+			}
+		}
+		if start > 0 && bytes.Equal(origin, entries[start-1].k) {
+			continue
+		}
+		keys, values := split(entries[start : end+1])
+
+		more, err := VerifyRangeProof(root, origin, keys, values, rangeProof(t, tr, origin, keys[len(keys)-1]))
+		assert.Nil(t, err, "range [%d, %d]", start, end)
+		assert.Equal(t, end != len(entries)-1, more)
+	}
+
+	// zero origin
+	keys, values := split(entries[:10])
+	origin := make([]byte, 32)
+	more, err := VerifyRangeProof(root, origin, keys, values, rangeProof(t, tr, origin, keys[len(keys)-1]))
+	assert.Nil(t, err)
+	assert.True(t, more)
+}
+
+func TestOneElementRangeProof(t *testing.T) {
+	tr, entries := randomTrie(t, 1000)
+	root := tr.Hash()
+
+	keys, values := split(entries[500:501])
+	more, err := VerifyRangeProof(root, keys[0], keys, values, rangeProof(t, tr, keys[0]))
+	assert.Nil(t, err)
+	assert.True(t, more)
+
+	keys, values = split(entries[len(entries)-1:])
+	more, err = VerifyRangeProof(root, keys[0], keys, values, rangeProof(t, tr, keys[0]))
+	assert.Nil(t, err)
+	assert.False(t, more)
+}
+
+func TestAllElementsRangeProof(t *testing.T) {
+	tr, entries := randomTrie(t, 1000)
+	root := tr.Hash()
+	keys, values := split(entries)
+
+	// without proof
+	more, err := VerifyRangeProof(root, make([]byte, 32), keys, values, nil)
+	assert.Nil(t, err)
+	assert.False(t, more)
+
+	// with proof
+	more, err = VerifyRangeProof(root, keys[0], keys, values, rangeProof(t, tr, keys[0], keys[len(keys)-1]))
+	assert.Nil(t, err)
+	assert.False(t, more)
+
+	// missing one without proof
+	_, err = VerifyRangeProof(root, make([]byte, 32), keys[1:], values[1:], nil)
+	assert.NotNil(t, err)
+}
+
+func TestEmptyRangeProof(t *testing.T) {
+	tr, entries := randomTrie(t, 1000)
+	root := tr.Hash()
+
+	// beyond the last key
+	origin := bytes.Repeat([]byte{0xff}, 32)
+	more, err := VerifyRangeProof(root, origin, nil, nil, rangeProof(t, tr, origin))
+	assert.Nil(t, err)
+	assert.False(t, more)
+
+	// there are entries after the origin
+	_, err = VerifyRangeProof(root, entries[0].k, nil, nil, rangeProof(t, tr, entries[0].k))
+	assert.NotNil(t, err)
+
+	// empty trie
+	more, err = VerifyRangeProof(emptyRoot, make([]byte, 32), nil, nil, nil)
+	assert.Nil(t, err)
+	assert.False(t, more)
+}
+
+func TestBadRangeProof(t *testing.T) {
+	tr, entries := randomTrie(t, 1000)
+	root := tr.Hash()
+
+	for i := range 100 {
+		start := mrand.Intn(len(entries) - 2)
+		end := start + 2 + mrand.Intn(len(entries)-start-2)
+		keys, values := split(entries[start : end+1])
+		proof := rangeProof(t, tr, keys[0], keys[len(keys)-1])
+
+		// copy to not pollute the entries
+		keys = append([][]byte{}, keys...)
+		values = append([][]byte{}, values...)
+		index := 1 + mrand.Intn(len(keys)-2)
+		switch i % 4 {
+		case 0: // modified value
+			values[index] = randBytes(20)
+		case 1: // missing an entry in the middle
+			keys = append(keys[:index], keys[index+1:]...)
+			values = append(values[:index], values[index+1:]...)
+		case 2: // out of order
+			keys[index-1], keys[index] = keys[index], keys[index-1]
+			values[index-1], values[index] = values[index], values[index-1]
+		case 3: // an extra entry in the middle
+			k := append([]byte{}, keys[index]...)
+			k[31]++
+			if bytes.Equal(k, keys[index+1]) {
+				continue
+			}
+			keys = append(keys[:index+1], append([][]byte{k}, keys[index+1:]...)...)
+			values = append(values[:index+1], append([][]byte{randBytes(20)}, values[index+1:]...)...)
+		}
+		_, err := VerifyRangeProof(root, keys[0], keys, values, proof)
+		assert.NotNil(t, err, "case %d", i%4)
+	}
+
+	// proof of another trie
+	other, _ := randomTrie(t, 100)
+	keys, values := split(entries[10:20])
+	_, err := VerifyRangeProof(root, keys[0], keys, values, rangeProof(t, other, keys[0], keys[len(keys)-1]))
+	assert.NotNil(t, err)
+
+	// wrong root
+	_, err = VerifyRangeProof(thor.Blake2b([]byte("x")), keys[0], keys, values, rangeProof(t, tr, keys[0], keys[len(keys)-1]))
+	assert.NotNil(t, err)
+}
@@ -187,6 +187,11 @@ func (rs Receipts) RootHash() thor.Bytes32 {
 	return trie.DeriveRoot(derivableReceipts(rs))
 }
 
+// Proof returns the merkle proof of the receipt at index i, against the root returned by RootHash.
+func (rs Receipts) Proof(i int) ([][]byte, error) {
+	return trie.DeriveProof(derivableReceipts(rs), i)
+}
+
 // implements DerivableList
 type derivableReceipts Receipts
 
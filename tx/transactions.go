@@ -24,6 +24,11 @@ func (txs Transactions) RootHash() thor.Bytes32 {
 	return trie.DeriveRoot(derivableTxs(txs))
 }
 
+// Proof returns the merkle proof of the tx at index i, against the root returned by RootHash.
+func (txs Transactions) Proof(i int) ([][]byte, error) {
+	return trie.DeriveProof(derivableTxs(txs), i)
+}
+
 // implements types.DerivableList
 type derivableTxs Transactions
 
@@ -10,6 +10,11 @@ type (
 	txRejectedError struct{ msg string }
 )
 
+var (
+	errPoolFull              = txRejectedError{"pool is full"}
+	errNonExecutablePoolFull = txRejectedError{"non executable pool is full"}
+)
+
 func (e badTxError) Error() string {
 	return "bad tx: " + e.msg
 }
@@ -29,3 +34,9 @@ func IsTxRejected(err error) bool {
 	_, ok := err.(txRejectedError)
 	return ok
 }
+
+// IsPoolFull returns whether the given error indicates tx is rejected because the pool, or its non-executable part, is full.
+// Such a rejection reflects local pool pressure rather than the quality of the tx.
+func IsPoolFull(err error) bool {
+	return err == errPoolFull || err == errNonExecutablePoolFull
+}
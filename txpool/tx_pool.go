@@ -323,7 +323,7 @@ func (p *TxPool) validateNonExecutableLimit(executable bool) error {
 	// Check non-executable pool limit (20% of total)
 	if !executable {
 		if p.all.Len()-len(p.Executables()) >= p.options.Limit*2/10 {
-			return txRejectedError{"non executable pool is full"}
+			return errNonExecutablePoolFull
 		}
 	}
 
@@ -353,10 +353,10 @@ func (p *TxPool) addWhenSynced(
 	// Check pool limits and priority for remote transactions
 	if !localSubmitted {
 		if p.all.Len() >= p.options.Limit*15/10 {
-			return txRejectedError{"pool is full"}
+			return errPoolFull
 		} else if p.all.Len() >= p.options.Limit*12/10 {
 			if !p.checkTxPriority(txObj, executable) {
-				return txRejectedError{"pool is full"}
+				return errPoolFull
 			}
 		}
 	}
@@ -399,7 +399,7 @@ func (p *TxPool) addWhenNotSynced(newTx *tx.Transaction, txObj *TxObject) error
 	// we skip steps that rely on head block when chain is not synced,
 	// but check the pool's limit
 	if p.all.Len() >= p.options.Limit {
-		return txRejectedError{"pool is full"}
+		return errPoolFull
 	}
 
 	// skip pending cost check when chain is not synced
@@ -232,6 +232,7 @@ func FillPoolWithLegacyTxs(pool *TxPool, t *testing.T) {
 
 	err := pool.Add(newTx(tx.TypeLegacy, pool.repo.ChainTag(), nil, 21000, tx.NewBlockRef(10), 100, nil, tx.Features(0), devAccounts[0]))
 	assert.Equal(t, err.Error(), "tx rejected: pool is full")
+	assert.True(t, IsPoolFull(err))
 }
 
 func FillPoolWithDynFeeTxs(pool *TxPool, t *testing.T) {
@@ -1005,6 +1006,7 @@ func TestExecutableAndNonExecutableLimits(t *testing.T) {
 	err = pool.add(trx2, false, false)
 
 	assert.Equal(t, "tx rejected: non executable pool is full", err.Error())
+	assert.True(t, IsPoolFull(err))
 
 	// higher block fails
 	trx2 = newTx(tx.TypeLegacy, pool.repo.ChainTag(), nil, 21000, tx.NewBlockRef(tx.BlockRef{}.Number()+2), 100, nil, tx.Features(0), devAccounts[2])